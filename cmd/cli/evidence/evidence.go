@@ -0,0 +1,38 @@
+package evidence
+
+import (
+	"path/filepath"
+
+	onyx "github.com/B-S-F/onyx/internal/onyx/evidence"
+	"github.com/B-S-F/onyx/pkg/logger"
+	"github.com/spf13/cobra"
+)
+
+func EvidenceCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "evidence",
+		Short: "Verifies evidence archives created by 'onyx exec'",
+	}
+	cmd.AddCommand(verifyCommand())
+	return cmd
+}
+
+func verifyCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "verify <evidence-archive>",
+		Short: "Verifies the files of an evidence archive against its manifest and attestation, works offline",
+		Long:  "The format of the archive is detected by its extension: .zip, .tar.gz or .tar.zst",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			logger.Set(logger.NewCommon(logger.Settings{
+				File: "onyx.log",
+			}))
+			keys, _ := cmd.Flags().GetStringArray("key")
+			result, _ := cmd.Flags().GetString("result")
+			return onyx.Verify(filepath.Clean(args[0]), result, keys)
+		},
+	}
+	cmd.Flags().StringArrayP("key", "k", nil, "PEM encoded public key which has to sign the attestation, can be repeated")
+	cmd.Flags().String("result", "", "result file which is checked against the digest in the manifest, e.g. qg-result.yaml")
+	return cmd
+}
@@ -2,12 +2,20 @@ package exec
 
 import (
 	"errors"
+	"fmt"
 	"path/filepath"
 	"strings"
 	"time"
 
 	onyx "github.com/B-S-F/onyx/internal/onyx/exec"
+	"github.com/B-S-F/onyx/pkg/evidence"
+	"github.com/B-S-F/onyx/pkg/export"
 	"github.com/B-S-F/onyx/pkg/parameter"
+	"github.com/B-S-F/onyx/pkg/repository/registry"
+	resultCommon "github.com/B-S-F/onyx/pkg/result/common"
+	"github.com/B-S-F/onyx/pkg/secrets"
+	"github.com/B-S-F/onyx/pkg/zip"
+	"github.com/dustin/go-humanize"
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
 )
@@ -28,6 +36,20 @@ func ExecCommand() *cobra.Command {
 	cmd.Flags().String("config-name", "qg-config.yaml", "Path to the config file")
 	cmd.Flags().Bool("strict", false, "If set to true, the autopilot will return a ERROR status if the JSON line output is not valid")
 	cmd.Flags().Int("check-timeout", DefaultTimeout, "Timeout for a each check in seconds")
+	cmd.Flags().Int("parallel-installs", registry.DEFAULT_PARALLEL_INSTALLS, "Maximum number of apps which are downloaded and installed at the same time")
+	cmd.Flags().StringArray("secret-provider", nil, "Secret provider as <type>:<key>=<value>,... which replaces the secrets file, can be repeated, overrides 'secret-providers' of onyx.yaml")
+	cmd.Flags().String("evidence-scan", evidence.PolicyRedact, "What to do with secrets found in the evidence files, one of: redact, fail, off")
+	cmd.Flags().StringSlice("evidence-scan-rule", nil, "Additional evidence scan rule, one of: "+strings.Join(evidence.BuiltinRuleNames(), ", ")+" or <name>=<regexp>, can be repeated")
+	cmd.Flags().String("evidence-signing-key", "", "Name of the secret with the PEM encoded ed25519 or ECDSA private key which signs the evidence attestation")
+	cmd.Flags().String("evidence-format", zip.FORMAT_ZIP, "Format of the evidence archive, one of: "+strings.Join(zip.Formats, ", "))
+	cmd.Flags().Bool("evidence-reproducible", false, "Create the same evidence archive for the same files by normalizing modification times and permissions")
+	cmd.Flags().StringSlice("evidence-include", nil, "Glob of the evidence files which are archived, e.g. '**/*.json', can be repeated, defaults to all files")
+	cmd.Flags().StringSlice("evidence-exclude", nil, "Glob of the evidence files which are not archived, e.g. '*.tmp', can be repeated")
+	cmd.Flags().String("evidence-max-file-size", "", "Maximum size of an evidence file, e.g. 100MB, unlimited if empty")
+	cmd.Flags().String("evidence-size-policy", zip.SIZE_POLICY_FAIL, "What to do with evidence files exceeding the maximum file size, one of: fail, truncate")
+	cmd.Flags().String("result-format", resultCommon.FORMAT_YAML, "Format of the result file, one of: "+strings.Join(resultCommon.Formats, ", "))
+	cmd.Flags().Bool("strict-result-validation", false, "If set to true, a result file which does not match the result schema is an error instead of a warning")
+	cmd.Flags().StringSlice("result-export", nil, "Format the result is exported to next to the result file for v2 configurations, one of: "+strings.Join(export.Formats, ", ")+", can be repeated")
 	cmd.Flags().StringP("check", "c", "", "Used with a value in the format <chapterId>_<requirementId>_<checkId> to select a single check to run, others will be skipped")
 	return cmd
 }
@@ -44,16 +66,50 @@ func Run(cmd *cobra.Command, args []string) error {
 	_ = viper.BindPFlag("strict", cmd.Flags().Lookup("strict"))
 	_ = viper.BindPFlag("check-timeout", cmd.Flags().Lookup("check-timeout"))
 	_ = viper.BindPFlag("check", cmd.Flags().Lookup("check"))
+	_ = viper.BindPFlag("parallel-installs", cmd.Flags().Lookup("parallel-installs"))
+	_ = viper.BindPFlag("evidence-scan", cmd.Flags().Lookup("evidence-scan"))
+	_ = viper.BindPFlag("evidence-scan-rule", cmd.Flags().Lookup("evidence-scan-rule"))
+	_ = viper.BindPFlag("evidence-signing-key", cmd.Flags().Lookup("evidence-signing-key"))
+	_ = viper.BindPFlag("result-format", cmd.Flags().Lookup("result-format"))
+	_ = viper.BindPFlag("strict-result-validation", cmd.Flags().Lookup("strict-result-validation"))
+	_ = viper.BindPFlag("result-export", cmd.Flags().Lookup("result-export"))
+	for _, flag := range []string{"evidence-format", "evidence-reproducible", "evidence-include", "evidence-exclude", "evidence-max-file-size", "evidence-size-policy"} {
+		_ = viper.BindPFlag(flag, cmd.Flags().Lookup(flag))
+	}
+
+	secretProviders, err := secretProviders(cmd)
+	if err != nil {
+		return err
+	}
+
+	evidenceScanRules, err := evidence.ParseRules(viper.GetStringSlice("evidence-scan-rule"))
+	if err != nil {
+		return err
+	}
+
+	evidenceArchive, err := evidenceArchiveOptions()
+	if err != nil {
+		return err
+	}
 
 	execParams := parameter.ExecutionParameter{
-		Strict:          viper.GetBool("strict"),
-		InputFolder:     filepath.Clean(inputFolder),
-		OutputFolder:    filepath.Clean(viper.GetString("output-dir")),
-		ConfigName:      viper.GetString("config-name"),
-		VarsName:        viper.GetString("vars-name"),
-		SecretsName:     viper.GetString("secrets-name"),
-		CheckIdentifier: viper.GetString("check"),
-		CheckTimeout:    viper.GetDuration("check-timeout") * time.Second,
+		Strict:                 viper.GetBool("strict"),
+		InputFolder:            filepath.Clean(inputFolder),
+		OutputFolder:           filepath.Clean(viper.GetString("output-dir")),
+		ConfigName:             viper.GetString("config-name"),
+		VarsName:               viper.GetString("vars-name"),
+		SecretsName:            viper.GetString("secrets-name"),
+		CheckIdentifier:        viper.GetString("check"),
+		CheckTimeout:           viper.GetDuration("check-timeout") * time.Second,
+		ParallelInstalls:       viper.GetInt("parallel-installs"),
+		SecretProviders:        secretProviders,
+		EvidenceScanPolicy:     viper.GetString("evidence-scan"),
+		EvidenceScanRules:      evidenceScanRules,
+		EvidenceSigningKey:     viper.GetString("evidence-signing-key"),
+		EvidenceArchive:        evidenceArchive,
+		ResultFormat:           viper.GetString("result-format"),
+		StrictResultValidation: viper.GetBool("strict-result-validation"),
+		ResultExports:          viper.GetStringSlice("result-export"),
 	}
 
 	if !strings.HasPrefix(execParams.SecretsName, onyx.SECRETS_FILE) {
@@ -65,5 +121,69 @@ func Run(cmd *cobra.Command, args []string) error {
 	if execParams.CheckTimeout <= 0 {
 		return errors.New("check-timeout value should be a positive number")
 	}
+	if execParams.ParallelInstalls <= 0 {
+		return errors.New("parallel-installs value should be a positive number")
+	}
+	switch execParams.EvidenceScanPolicy {
+	case evidence.PolicyRedact, evidence.PolicyFail, evidence.PolicyOff:
+	default:
+		return errors.New("evidence-scan value should be one of: redact, fail, off")
+	}
+	if _, err := resultCommon.ResultFiles(onyx.RESULT_FILE, execParams.ResultFormat); err != nil {
+		return err
+	}
+	if err := export.ValidateFormats(execParams.ResultExports); err != nil {
+		return err
+	}
 	return onyx.Exec(execParams)
 }
+
+// evidenceArchiveOptions reads the format, filters and size limit of the evidence archive
+func evidenceArchiveOptions() (zip.Options, error) {
+	options := zip.Options{
+		Format:       viper.GetString("evidence-format"),
+		Reproducible: viper.GetBool("evidence-reproducible"),
+		Include:      viper.GetStringSlice("evidence-include"),
+		Exclude:      viper.GetStringSlice("evidence-exclude"),
+		SizePolicy:   viper.GetString("evidence-size-policy"),
+	}
+	if maxFileSize := viper.GetString("evidence-max-file-size"); maxFileSize != "" {
+		size, err := humanize.ParseBytes(maxFileSize)
+		if err != nil {
+			return zip.Options{}, fmt.Errorf("invalid evidence-max-file-size '%s': %w", maxFileSize, err)
+		}
+		options.MaxFileSize = int64(size)
+	}
+	if err := options.Validate(); err != nil {
+		return zip.Options{}, fmt.Errorf("invalid evidence archive options: %w", err)
+	}
+	return options, nil
+}
+
+// secretProviders reads the providers from the flags or from 'secret-providers' of onyx.yaml
+func secretProviders(cmd *cobra.Command) ([]secrets.ProviderConfig, error) {
+	var providers []secrets.ProviderConfig
+	if cmd.Flags().Changed("secret-provider") {
+		values, err := cmd.Flags().GetStringArray("secret-provider")
+		if err != nil {
+			return nil, err
+		}
+		for _, value := range values {
+			provider, err := secrets.ParseProviderFlag(value)
+			if err != nil {
+				return nil, err
+			}
+			providers = append(providers, provider)
+		}
+		return providers, nil
+	}
+	if err := viper.UnmarshalKey("secret-providers", &providers); err != nil {
+		return nil, fmt.Errorf("invalid 'secret-providers' in onyx.yaml: %w", err)
+	}
+	for index, provider := range providers {
+		if provider.Type == "" {
+			return nil, fmt.Errorf("missing type of secret provider %d in onyx.yaml", index+1)
+		}
+	}
+	return providers, nil
+}
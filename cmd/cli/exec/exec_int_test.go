@@ -29,12 +29,14 @@ const (
 	ignoreBashLine = "(/bin/bash: line [0-9]+:)"
 	// ignoring the date, as it is different on each run
 	ignoreDate = `\d{4}-\d{2}-\d{2}(T\d{2}:\d{2}:\d{2}(?:\+\d{2}:\d{2}|Z)|\s\d{2}:\d{2})`
+	// ignoring the digest of the input folder, as it contains the golden file itself
+	ignoreInputDigest = `inputDigest: sha256:[0-9a-f]+`
 )
 
 func TestExecCommandIntegration(t *testing.T) {
 	versions := []string{"v1", "v2"}
 	appsPath := "testdata/apps"
-	re := regexp.MustCompile(fmt.Sprintf("%s|%s|%s", ignoreTempDir, ignoreBashLine, ignoreDate))
+	re := regexp.MustCompile(fmt.Sprintf("%s|%s|%s|%s", ignoreTempDir, ignoreBashLine, ignoreDate, ignoreInputDigest))
 	serveApps(appsPath, "8081", t)
 
 	for _, version := range versions {
@@ -51,6 +53,7 @@ func TestExecCommandIntegration(t *testing.T) {
 				configPath,
 				"--output-dir", tempDir,
 				"--check-timeout", "3",
+				"--strict-result-validation",
 			})
 			startTime := time.Now()
 			err := cmd.Execute()
@@ -4,9 +4,13 @@ import (
 	"os"
 	"strings"
 
+	"github.com/B-S-F/onyx/cmd/cli/evidence"
 	"github.com/B-S-F/onyx/cmd/cli/exec"
 	"github.com/B-S-F/onyx/cmd/cli/migrate"
+	"github.com/B-S-F/onyx/cmd/cli/report"
+	"github.com/B-S-F/onyx/cmd/cli/result"
 	"github.com/B-S-F/onyx/cmd/cli/schema"
+	"github.com/B-S-F/onyx/cmd/cli/secrets"
 	"github.com/B-S-F/onyx/pkg/helper"
 	"github.com/B-S-F/onyx/pkg/logger"
 	"github.com/spf13/cobra"
@@ -50,6 +54,10 @@ func initFlags(cmd *cobra.Command) {
 	cmd.AddCommand(exec.ExecCommand())
 	cmd.AddCommand(migrate.MigrateCommand())
 	cmd.AddCommand(schema.SchemaCommand())
+	cmd.AddCommand(secrets.SecretsCommand())
+	cmd.AddCommand(evidence.EvidenceCommand())
+	cmd.AddCommand(result.ResultCommand())
+	cmd.AddCommand(report.ReportCommand())
 }
 
 func Execute(cmd *cobra.Command) {
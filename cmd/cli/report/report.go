@@ -0,0 +1,33 @@
+package report
+
+import (
+	"path/filepath"
+	"strings"
+
+	onyx "github.com/B-S-F/onyx/internal/onyx/report"
+	"github.com/B-S-F/onyx/pkg/logger"
+	"github.com/B-S-F/onyx/pkg/report"
+	"github.com/spf13/cobra"
+)
+
+func ReportCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "report <result-file>",
+		Short: "Renders a v2 result file as a self-contained HTML report or a Markdown summary",
+		Long:  "The templates can be replaced with --template, a html/template file for html and a text/template file for markdown",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			logger.Set(logger.NewCommon(logger.Settings{
+				File: "onyx.log",
+			}))
+			format, _ := cmd.Flags().GetString("format")
+			template, _ := cmd.Flags().GetString("template")
+			output, _ := cmd.Flags().GetString("output")
+			return onyx.Report(filepath.Clean(args[0]), format, template, output)
+		},
+	}
+	cmd.Flags().String("format", report.FORMAT_HTML, "Format of the report, one of: "+strings.Join(report.Formats, ", "))
+	cmd.Flags().String("template", "", "Template file which replaces the default template of the format")
+	cmd.Flags().String("output", "stdout", "output file, defaults to stdout")
+	return cmd
+}
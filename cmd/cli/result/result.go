@@ -0,0 +1,83 @@
+package result
+
+import (
+	"path/filepath"
+	"strings"
+
+	onyx "github.com/B-S-F/onyx/internal/onyx/result"
+	"github.com/B-S-F/onyx/pkg/diff"
+	"github.com/B-S-F/onyx/pkg/export"
+	"github.com/B-S-F/onyx/pkg/logger"
+	"github.com/spf13/cobra"
+)
+
+func ResultCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "result",
+		Short: "Works with result files created by 'onyx exec'",
+	}
+	cmd.AddCommand(validateCommand())
+	cmd.AddCommand(exportCommand())
+	cmd.AddCommand(diffCommand())
+	return cmd
+}
+
+func validateCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "validate <result-file>",
+		Short: "Validates a result file against the schema of its version",
+		Long:  "The version is read from the metadata of the result file, v1 and v2 results in YAML or JSON are supported",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			logger.Set(logger.NewCommon(logger.Settings{
+				File: "onyx.log",
+			}))
+			return onyx.Validate(filepath.Clean(args[0]))
+		},
+	}
+	return cmd
+}
+
+func exportCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "export <result-file>",
+		Short: "Exports a v2 result file as JUnit XML or SARIF",
+		Long:  "With junit, every requirement is a test suite and every check a test case. With sarif, every unfulfilled criterion is a result carrying its metadata",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			logger.Set(logger.NewCommon(logger.Settings{
+				File: "onyx.log",
+			}))
+			format, _ := cmd.Flags().GetString("format")
+			output, _ := cmd.Flags().GetString("output")
+			return onyx.Export(filepath.Clean(args[0]), format, output)
+		},
+	}
+	cmd.Flags().String("format", export.FORMAT_JUNIT, "Format of the export, one of: "+strings.Join(export.Formats, ", "))
+	cmd.Flags().String("output", "stdout", "output file, defaults to stdout")
+	return cmd
+}
+
+func diffCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "diff <old-result-file> <new-result-file>",
+		Short: "Compares two v2 result files, e.g. of the last release and the current run",
+		Long:  "Lists status transitions of chapters, requirements and checks, new and resolved failed criteria, changed reasons and statistics deltas. Failed criteria are matched by their text and metadata",
+		Args:  cobra.ExactArgs(2),
+		// a regression is no usage error
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			logger.Set(logger.NewCommon(logger.Settings{
+				File: "onyx.log",
+			}))
+			format, _ := cmd.Flags().GetString("format")
+			output, _ := cmd.Flags().GetString("output")
+			failOnRegression, _ := cmd.Flags().GetBool("fail-on-regression")
+			return onyx.Diff(filepath.Clean(args[0]), filepath.Clean(args[1]), format, output, failOnRegression)
+		},
+	}
+	cmd.Flags().String("format", diff.FORMAT_TEXT, "Format of the diff, one of: "+strings.Join(diff.Formats, ", "))
+	cmd.Flags().String("output", "stdout", "output file, defaults to stdout")
+	cmd.Flags().Bool("fail-on-regression", false, "Fail if the overall status or a check got worse or a criterion failed")
+	return cmd
+}
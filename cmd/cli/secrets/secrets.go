@@ -0,0 +1,103 @@
+package secrets
+
+import (
+	"os"
+	"path/filepath"
+
+	onyxExec "github.com/B-S-F/onyx/internal/onyx/exec"
+	onyx "github.com/B-S-F/onyx/internal/onyx/secrets"
+	"github.com/B-S-F/onyx/pkg/logger"
+	"github.com/B-S-F/onyx/pkg/secrets"
+	"github.com/spf13/cobra"
+)
+
+func SecretsCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "secrets",
+		Short: "Encrypts, decrypts and edits secrets files with age",
+		Long: "Encrypted secrets files like .secrets.age are decrypted in memory by 'onyx exec' if the plain secrets file does not exist.\n" +
+			"The age identities are read from the file in " + secrets.AGE_IDENTITY_FILE_ENV + " or from " + secrets.AGE_IDENTITY_ENV + ".",
+	}
+	cmd.AddCommand(encryptCommand(), decryptCommand(), editCommand())
+	return cmd
+}
+
+func encryptCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "encrypt [secrets-file]",
+		Short: "Encrypts a secrets file, defaults to " + onyxExec.SECRETS_FILE,
+		Args:  cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			setLogger()
+			recipients, armored := recipientFlags(cmd)
+			output, _ := cmd.Flags().GetString("output")
+			return onyx.Encrypt(fileArg(args, onyxExec.SECRETS_FILE), output, recipients, armored)
+		},
+	}
+	addRecipientFlags(cmd)
+	cmd.Flags().String("output", "", "encrypted file, defaults to the secrets file with the suffix "+secrets.ENCRYPTED_SUFFIX)
+	return cmd
+}
+
+func decryptCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "decrypt [encrypted-file]",
+		Short: "Decrypts a secrets file, defaults to " + onyxExec.SECRETS_FILE + secrets.ENCRYPTED_SUFFIX,
+		Args:  cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			setLogger()
+			output, _ := cmd.Flags().GetString("output")
+			identity, _ := cmd.Flags().GetString("identity")
+			return onyx.Decrypt(fileArg(args, onyxExec.SECRETS_FILE+secrets.ENCRYPTED_SUFFIX), output, identity)
+		},
+	}
+	cmd.Flags().String("output", "stdout", "output file, defaults to stdout")
+	cmd.Flags().StringP("identity", "i", "", "age identity file, defaults to "+secrets.AGE_IDENTITY_FILE_ENV+" or "+secrets.AGE_IDENTITY_ENV)
+	return cmd
+}
+
+func editCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "edit [encrypted-file]",
+		Short: "Edits a secrets file in $EDITOR without storing it unencrypted next to the config, defaults to " + onyxExec.SECRETS_FILE + secrets.ENCRYPTED_SUFFIX,
+		Long: "Edits a secrets file in $EDITOR without storing it unencrypted next to the config, defaults to " + onyxExec.SECRETS_FILE + secrets.ENCRYPTED_SUFFIX + ".\n" +
+			"The edited secrets are encrypted for the given recipients or, without recipients, for the recipients in the file " +
+			"next to the secrets file, e.g. " + onyxExec.SECRETS_FILE + secrets.ENCRYPTED_SUFFIX + onyx.RECIPIENTS_SUFFIX + ".",
+		Args: cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			setLogger()
+			recipients, armored := recipientFlags(cmd)
+			identity, _ := cmd.Flags().GetString("identity")
+			return onyx.Edit(fileArg(args, onyxExec.SECRETS_FILE+secrets.ENCRYPTED_SUFFIX), identity, recipients, os.Getenv("EDITOR"), armored)
+		},
+	}
+	addRecipientFlags(cmd)
+	cmd.Flags().StringP("identity", "i", "", "age identity file, defaults to "+secrets.AGE_IDENTITY_FILE_ENV+" or "+secrets.AGE_IDENTITY_ENV)
+	return cmd
+}
+
+func addRecipientFlags(cmd *cobra.Command) {
+	cmd.Flags().StringArrayP("recipient", "r", nil, "age recipient like age1..., can be repeated")
+	cmd.Flags().StringArrayP("recipients-file", "R", nil, "file with one age recipient per line, can be repeated")
+	cmd.Flags().Bool("armor", true, "write the encrypted file in the text based armored format")
+}
+
+func recipientFlags(cmd *cobra.Command) (onyx.Recipients, bool) {
+	values, _ := cmd.Flags().GetStringArray("recipient")
+	files, _ := cmd.Flags().GetStringArray("recipients-file")
+	armored, _ := cmd.Flags().GetBool("armor")
+	return onyx.Recipients{Values: values, Files: files}, armored
+}
+
+func fileArg(args []string, defaultFile string) string {
+	if len(args) == 0 {
+		return defaultFile
+	}
+	return filepath.Clean(args[0])
+}
+
+func setLogger() {
+	logger.Set(logger.NewCommon(logger.Settings{
+		File: "onyx.log",
+	}))
+}
@@ -68,3 +68,13 @@ func SelectOutputWriter(output string) io.WriteCloser {
 	}
 	return writer
 }
+
+// WriteOutput writes the content to the output file or stdout, stdout is not closed as the logger writes to it as well
+func WriteOutput(output string, content []byte) error {
+	out := SelectOutputWriter(output)
+	if out != os.Stdout {
+		defer out.Close()
+	}
+	_, err := out.Write(content)
+	return err
+}
@@ -0,0 +1,51 @@
+package evidence
+
+import (
+	"crypto"
+	"os"
+
+	"github.com/B-S-F/onyx/pkg/evidence"
+	"github.com/B-S-F/onyx/pkg/logger"
+	"github.com/pkg/errors"
+)
+
+// Verify checks an evidence archive against its manifest and attestation, the signature is verified if key files are given
+func Verify(file, resultFile string, keyFiles []string) error {
+	keys := make([]crypto.PublicKey, 0, len(keyFiles))
+	for _, keyFile := range keyFiles {
+		content, err := os.ReadFile(keyFile)
+		if err != nil {
+			return errors.Wrapf(err, "error reading key file %s", keyFile)
+		}
+		key, err := evidence.ParsePublicKey(content)
+		if err != nil {
+			return errors.Wrapf(err, "invalid key file %s", keyFile)
+		}
+		keys = append(keys, key)
+	}
+
+	verification, err := evidence.Verify(file, resultFile, keys)
+	if err != nil {
+		return err
+	}
+	log := logger.Get()
+	for _, problem := range verification.Problems {
+		log.Error(problem)
+	}
+	if len(verification.Problems) > 0 {
+		return errors.Errorf("evidence '%s' is not valid, found %d problems", file, len(verification.Problems))
+	}
+	log.Infof("verified %d files of evidence '%s' against '%s'", verification.Files, file, evidence.MANIFEST_FILE)
+	if verification.Result != nil {
+		log.Infof("result file '%s' has sha256 %s", verification.Result.Path, verification.Result.SHA256)
+	}
+	switch {
+	case verification.KeyID != "":
+		log.Infof("attestation is signed by key %s", verification.KeyID)
+	case verification.Attested:
+		log.Warn("attestation matches the manifest, but its signature is not verified without a key")
+	default:
+		log.Warn("evidence has no attestation")
+	}
+	return nil
+}
@@ -4,22 +4,28 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"strings"
 
 	"github.com/B-S-F/onyx/internal/onyx/common"
 	"github.com/B-S-F/onyx/pkg/configuration"
+	"github.com/B-S-F/onyx/pkg/evidence"
+	"github.com/B-S-F/onyx/pkg/export"
 	"github.com/B-S-F/onyx/pkg/finalize"
 	"github.com/B-S-F/onyx/pkg/helper"
 	"github.com/B-S-F/onyx/pkg/item"
 	"github.com/B-S-F/onyx/pkg/logger"
 	"github.com/B-S-F/onyx/pkg/parameter"
+	"github.com/B-S-F/onyx/pkg/publish"
 	"github.com/B-S-F/onyx/pkg/reader"
 	"github.com/B-S-F/onyx/pkg/replacer"
 	"github.com/B-S-F/onyx/pkg/repository"
 	"github.com/B-S-F/onyx/pkg/repository/app"
 	"github.com/B-S-F/onyx/pkg/repository/registry"
 	"github.com/B-S-F/onyx/pkg/result"
+	resultCommon "github.com/B-S-F/onyx/pkg/result/common"
 	v1Result "github.com/B-S-F/onyx/pkg/result/v1"
 	"github.com/B-S-F/onyx/pkg/schema"
+	"github.com/B-S-F/onyx/pkg/secrets"
 	"github.com/B-S-F/onyx/pkg/tempdir"
 	"github.com/B-S-F/onyx/pkg/transformer"
 	v2 "github.com/B-S-F/onyx/pkg/v2/config"
@@ -36,13 +42,12 @@ import (
 	"github.com/pkg/errors"
 	"github.com/spf13/afero"
 	"go.uber.org/zap"
-	yaml "gopkg.in/yaml.v3"
 )
 
 const (
 	CONFIG_FILE   = "qg-config.yaml"
 	RESULT_FILE   = "qg-result.yaml"
-	EVIDENCE_FILE = "evidence.zip"
+	EVIDENCE_NAME = "evidence"
 	VARS_FILE     = ".vars"
 	SECRETS_FILE  = ".secrets"
 )
@@ -68,6 +73,10 @@ type exec struct {
 	transformerV2   []transformerV2.Transformer
 	logger          logger.Logger
 	execParams      parameter.ExecutionParameter
+	// signer of the evidence attestation, nil if the evidence is not signed
+	signer *evidence.Signer
+	// targets the result file and the evidence are uploaded to after the run
+	publishTargets []publish.Target
 }
 
 func newExec(execParams parameter.ExecutionParameter) *exec {
@@ -104,7 +113,17 @@ func Exec(execParams parameter.ExecutionParameter) error {
 		File:    filepath.Join(ROOT_WORK_DIRECTORY, "onyx.log"),
 	}) // this logger prevents secrets from being logged
 	logger.Set(defaultLogger)
+	if _, err := resultCommon.ResultFiles(RESULT_FILE, execParams.ResultFormat); err != nil {
+		return err
+	}
+	if err := export.ValidateFormats(execParams.ResultExports); err != nil {
+		return err
+	}
 	e := newExec(execParams)
+	e.signer, err = evidenceSigner(execParams, secrets)
+	if err != nil {
+		return err
+	}
 	err = e.prepareRootFolder(ROOT_WORK_DIRECTORY, execParams.InputFolder)
 	if err != nil {
 		return errors.Wrap(err, "error setting up root directory")
@@ -153,13 +172,16 @@ func Exec(execParams parameter.ExecutionParameter) error {
 }
 
 func (e *exec) execPlanV1(ep *configuration.ExecutionPlan, vars map[string]string, secrets map[string]string) error {
+	if len(e.execParams.ResultExports) > 0 {
+		e.logger.Warnf("result exports are only supported for v2 configurations, '%s' is not exported", RESULT_FILE)
+		e.execParams.ResultExports = nil
+	}
 	e.logger.Info("[ RUN EXECUTION PLAN ]")
 	err := e.executePlan(ep, vars, secrets)
 	if err != nil {
 		return errors.Wrap(err, "error executing execution plan")
 	}
-	resultFilePath := filepath.Join(ROOT_WORK_DIRECTORY, RESULT_FILE)
-	err = e.storeResultFile(e.resultEngine.GetResult(), resultFilePath)
+	err = e.storeResultFiles(e.resultEngine.GetResult())
 	if err != nil {
 		return errors.Wrap(err, "error storing result file")
 	}
@@ -168,31 +190,40 @@ func (e *exec) execPlanV1(ep *configuration.ExecutionPlan, vars map[string]strin
 	if err != nil {
 		return errors.Wrap(err, "error executing finalizer")
 	}
-	err = e.storeResultFile(e.resultEngine.GetResult(), resultFilePath)
+	err = e.storeResultFiles(e.resultEngine.GetResult())
 	if err != nil {
 		return errors.Wrap(err, "error storing result file")
 	}
-	err = e.provideResultFiles()
+	// the v1 result has no place for the findings, they are only logged
+	findings, err := e.scanEvidence(secrets)
 	if err != nil {
-		return errors.Wrap(err, "error providing result files")
+		return err
 	}
-	return nil
+	return e.provideResults(findings)
 }
 
 func (e *exec) execPlanV2(ep *model.ExecutionPlan, secrets map[string]string) error {
+	provenance, err := resultV2.NewProvenance(e.provenanceInputs())
+	if err != nil {
+		return errors.Wrap(err, "error recording provenance")
+	}
 	e.logger.Info("[ RUN EXECUTION PLAN ]")
 	orchestrator := orchestrator.New(ROOT_WORK_DIRECTORY, e.execParams.Strict, e.execParams.CheckTimeout, e.logger)
 	runResult, err := orchestrator.Run(ep.ManualChecks, ep.AutopilotChecks, ep.Env, secrets)
 	if err != nil {
 		return errors.Wrap(err, "error executing execution plan")
 	}
-	resFilePath := filepath.Join(ROOT_WORK_DIRECTORY, RESULT_FILE)
 	resCreator := resultV2.New(e.logger)
+	resCreator.StrictValidation = e.execParams.StrictResultValidation
 	createdResult, err := resCreator.Create(*ep, runResult)
 	if err != nil {
 		return errors.Wrap(err, "error creating execution result")
 	}
-	err = resCreator.WriteResultFile(*createdResult, resFilePath)
+	resCreator.AppendProvenance(createdResult, *ep, *provenance)
+	// the locations are recorded before the result file is archived, so the result in the evidence lists them as well
+	uploads := publish.Plan(e.publishTargets, append([]string{e.evidenceFile()}, e.outputFiles()...))
+	resCreator.AppendPublish(createdResult, uploads)
+	err = e.writeResultFiles(resCreator, createdResult)
 	if err != nil {
 		return errors.Wrap(err, "error writing result file")
 	}
@@ -208,18 +239,185 @@ func (e *exec) execPlanV2(ep *model.ExecutionPlan, secrets map[string]string) er
 			return err
 		}
 
-		err = resCreator.WriteResultFile(*createdResult, resFilePath)
+		err = e.writeResultFiles(resCreator, createdResult)
+		if err != nil {
+			return errors.Wrap(err, "error writing result file")
+		}
+	}
+	findings, err := e.scanEvidence(secrets)
+	if err != nil {
+		return err
+	}
+	if len(findings) > 0 {
+		resCreator.AppendEvidenceScan(createdResult, e.evidenceScanPolicy(), findings)
+		err = e.writeResultFiles(resCreator, createdResult)
 		if err != nil {
 			return errors.Wrap(err, "error writing result file")
 		}
 	}
-	err = e.provideResultFiles()
+	err = e.provideResults(findings)
+	if err != nil {
+		return err
+	}
+	return e.publishResults(uploads)
+}
+
+// publishResults uploads the provided result and evidence files, the evidence is uploaded before the result file
+func (e *exec) publishResults(uploads []publish.Upload) error {
+	if len(uploads) == 0 {
+		return nil
+	}
+	e.logger.Info("[ PUBLISH RESULTS ]")
+	err := publish.NewPublisher(publish.DEFAULT_RETRIES).Publish(uploads, e.execParams.OutputFolder)
+	if err != nil {
+		return errors.Wrap(err, "error publishing results")
+	}
+	return nil
+}
+
+// resultFiles are the names of the result files in the configured formats, the first one is the main result file
+func (e *exec) resultFiles() []string {
+	files, err := resultCommon.ResultFiles(RESULT_FILE, e.execParams.ResultFormat)
+	if err != nil {
+		return []string{RESULT_FILE}
+	}
+	return files
+}
+
+// outputFiles are the result files followed by the exports of the result
+func (e *exec) outputFiles() []string {
+	files := e.resultFiles()
+	for _, format := range e.execParams.ResultExports {
+		files = append(files, export.FileName(RESULT_FILE, format))
+	}
+	return files
+}
+
+func (e *exec) writeResultFiles(resCreator *resultV2.Creator, createdResult *resultV2.Result) error {
+	for _, file := range e.resultFiles() {
+		err := resCreator.WriteResultFile(*createdResult, filepath.Join(ROOT_WORK_DIRECTORY, file))
+		if err != nil {
+			return err
+		}
+	}
+	for _, format := range e.execParams.ResultExports {
+		err := export.WriteFile(createdResult, format, filepath.Join(ROOT_WORK_DIRECTORY, export.FileName(RESULT_FILE, format)))
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (e *exec) evidenceScanPolicy() string {
+	if e.execParams.EvidenceScanPolicy == "" {
+		return evidence.PolicyRedact
+	}
+	return e.execParams.EvidenceScanPolicy
+}
+
+// scanEvidence finds secrets in the files of the evidence before they are archived,
+// the result file is masked already, findings in it are reported but it is not redacted
+func (e *exec) scanEvidence(secrets map[string]string) ([]evidence.Finding, error) {
+	policy := e.evidenceScanPolicy()
+	if policy == evidence.PolicyOff {
+		return nil, nil
+	}
+	e.logger.Info("[ SCAN EVIDENCE ]")
+	scanner := evidence.NewScanner(secrets, e.execParams.EvidenceScanRules, policy, e.outputFiles())
+	findings, err := scanner.Scan(ROOT_WORK_DIRECTORY)
+	if err != nil {
+		return nil, errors.Wrap(err, "error scanning evidence")
+	}
+	for _, finding := range findings {
+		found := finding.Rule
+		if finding.Secret != "" {
+			found = fmt.Sprintf("secret '%s'", finding.Secret)
+		}
+		if finding.Redacted {
+			e.logger.Warnf("redacted %s in evidence file '%s' at offset %d", found, finding.File, finding.Offset)
+		} else {
+			e.logger.Errorf("found %s in evidence file '%s' at offset %d", found, finding.File, finding.Offset)
+		}
+	}
+	return findings, nil
+}
+
+// provideResults provides the result and evidence files, the evidence is withheld if it contains secrets which were not redacted
+func (e *exec) provideResults(findings []evidence.Finding) error {
+	if withheld := e.unredactedFindings(findings); withheld > 0 {
+		err := e.provideResultFile()
+		if err != nil {
+			return errors.Wrap(err, "error providing result files")
+		}
+		return errors.Errorf("found %d secrets in the evidence files, '%s' is not created", withheld, e.evidenceFile())
+	}
+	err := e.provideResultFiles()
 	if err != nil {
 		return errors.Wrap(err, "error providing result files")
 	}
 	return nil
 }
 
+// unredactedFindings counts the findings which remain in the evidence, all of them with the fail policy,
+// otherwise the ones which could not be redacted, e.g. in binary files, except for the findings in the output files
+func (e *exec) unredactedFindings(findings []evidence.Finding) int {
+	if e.evidenceScanPolicy() == evidence.PolicyFail {
+		return len(findings)
+	}
+	outputFiles := make(map[string]bool)
+	for _, file := range e.outputFiles() {
+		outputFiles[filepath.ToSlash(filepath.Clean(file))] = true
+	}
+	unredacted := 0
+	for _, finding := range findings {
+		if !finding.Redacted && !outputFiles[finding.File] {
+			unredacted++
+		}
+	}
+	return unredacted
+}
+
+// evidenceSigner parses the signing key of the evidence attestation, which is read from the secrets
+func evidenceSigner(execParams parameter.ExecutionParameter, secrets map[string]string) (*evidence.Signer, error) {
+	if execParams.EvidenceSigningKey == "" {
+		return nil, nil
+	}
+	key, ok := secrets[execParams.EvidenceSigningKey]
+	if !ok {
+		return nil, errors.Errorf("evidence signing key '%s' is not a secret", execParams.EvidenceSigningKey)
+	}
+	signer, err := evidence.NewSigner([]byte(key))
+	if err != nil {
+		return nil, errors.Wrapf(err, "invalid evidence signing key '%s'", execParams.EvidenceSigningKey)
+	}
+	return signer, nil
+}
+
+// provenanceInputs are the files of the input folder which determine the result,
+// the outputs of earlier runs are excluded if they are written into the input folder
+func (e *exec) provenanceInputs() resultV2.Inputs {
+	inputs := resultV2.Inputs{
+		ConfigFile:   filepath.Join(e.execParams.InputFolder, e.execParams.ConfigName),
+		InputFolder:  e.execParams.InputFolder,
+		IgnoredFiles: []string{VARS_FILE, SECRETS_FILE, e.execParams.SecretsName, e.execParams.SecretsName + secrets.ENCRYPTED_SUFFIX},
+	}
+	if e.execParams.VarsName != "" {
+		inputs.VarsFile = filepath.Join(e.execParams.InputFolder, e.execParams.VarsName)
+		inputs.IgnoredFiles = append(inputs.IgnoredFiles, e.execParams.VarsName)
+	}
+	outputFolder, err := filepath.Rel(e.execParams.InputFolder, e.execParams.OutputFolder)
+	if err != nil || outputFolder == ".." || strings.HasPrefix(outputFolder, ".."+string(filepath.Separator)) {
+		return inputs
+	}
+	if outputFolder == "." {
+		inputs.IgnoredFiles = append(append(inputs.IgnoredFiles, e.resultFiles()...), e.evidenceFile())
+	} else {
+		inputs.IgnoredFiles = append(inputs.IgnoredFiles, outputFolder)
+	}
+	return inputs
+}
+
 func (e *exec) prepareRootFolder(rootFolder, inputFolder string) error {
 	rootPath, err := e.wdUtils.CreateDir(rootFolder)
 	if err != nil {
@@ -267,7 +465,7 @@ func (e *exec) initPlanV1(config configuration.Config, vars, secrets map[string]
 		return nil, errors.Wrap(err, "error parsing repositories")
 	}
 	e.logger.Info("initializing app registry")
-	appRegistry, err := initializeAppRegistry(ep, repositories)
+	appRegistry, err := initializeAppRegistry(ep, repositories, e.execParams.ParallelInstalls)
 	if err != nil {
 		return nil, errors.Wrap(err, "error initializing app registry")
 	}
@@ -318,8 +516,14 @@ func (e *exec) initPlanV2(config *v2.Config, vars, secrets map[string]string) (*
 		return nil, errors.Wrap(err, "error parsing repositories")
 	}
 
+	e.logger.Info("initializing publish targets")
+	e.publishTargets, err = initializePublishTargets(ep.Publish)
+	if err != nil {
+		return nil, errors.Wrap(err, "error initializing publish targets")
+	}
+
 	e.logger.Info("initializing app registry")
-	registry, err := registryV2.Initialize(ep, repositories)
+	registry, err := registryV2.Initialize(ep, repositories, e.execParams.ParallelInstalls)
 	if err != nil {
 		return nil, errors.Wrap(err, "error initializing app registry")
 	}
@@ -355,14 +559,16 @@ func validateSchema(schema schema.SchemaHandler, config interface{}, content []b
 	return schema.Validate(content)
 }
 
-func initializeAppRegistry(ep *configuration.ExecutionPlan, repositories []repository.Repository) (*registry.Registry, error) {
+func initializeAppRegistry(ep *configuration.ExecutionPlan, repositories []repository.Repository, parallelInstalls int) (*registry.Registry, error) {
 	appReferences := allAppReferences(ep)
-	appRegistry := registry.NewRegistry(repositories)
-	for _, appReference := range appReferences {
-		err := appRegistry.Install(appReference)
-		if err != nil {
-			return nil, errors.Wrap(err, "error adding app to registry")
-		}
+	resolution, err := registry.ParseResolution(ep.Resolution)
+	if err != nil {
+		return nil, err
+	}
+	appRegistry := registry.NewRegistry(repositories, resolution)
+	err = appRegistry.InstallAll(appReferences, parallelInstalls)
+	if err != nil {
+		return nil, errors.Wrap(err, "error adding app to registry")
 	}
 	return appRegistry, nil
 }
@@ -388,7 +594,6 @@ func (e *exec) initializeItemApps(ep *configuration.ExecutionPlan, appRegistry *
 				return errors.Wrap(err, "error getting app")
 			}
 
-			appExecutablePath := app.ExecutablePath()
 			checkReference := fmt.Sprintf("%s_%s_%s", item.Chapter.Id, item.Requirement.Id, item.Check.Id)
 			checkAppDirectory := filepath.Join(APP_DIRECTORY, checkReference)
 			err = os.MkdirAll(checkAppDirectory, 0755)
@@ -397,10 +602,11 @@ func (e *exec) initializeItemApps(ep *configuration.ExecutionPlan, appRegistry *
 			}
 			e.logger.Infof("configured app %s with checksum %s for check %s", app.Reference(), app.Checksum(), checkReference)
 
-			symlinks := app.PossibleReferences()
-			err = helper.CreateSymlinks(appExecutablePath, checkAppDirectory, symlinks)
-			if err != nil {
-				return errors.Wrap(err, "error creating symlinks")
+			for _, executable := range app.Executables() {
+				err = helper.CreateSymlinks(executable.Path, checkAppDirectory, executable.References)
+				if err != nil {
+					return errors.Wrap(err, "error creating symlinks")
+				}
 			}
 			item.AppPath = checkAppDirectory
 		}
@@ -436,14 +642,32 @@ func (e *exec) executeFinalizer(ep *configuration.ExecutionPlan, secrets map[str
 	return nil
 }
 
+func (e *exec) storeResultFiles(data *v1Result.Result) error {
+	for _, file := range e.resultFiles() {
+		err := e.storeResultFile(data, filepath.Join(ROOT_WORK_DIRECTORY, file))
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// storeResultFile writes the result as JSON if the path ends with .json, otherwise as YAML
 func (e *exec) storeResultFile(data *v1Result.Result, path string) error {
 	e.logger.Info(fmt.Sprintf("storing results in result file '%s'", filepath.Base(path)))
-	resultYaml, err := yaml.Marshal(data)
+	content, err := resultCommon.Marshal(data, resultCommon.FormatOf(path))
 	if err != nil {
 		return errors.Wrap(err, "error marshalling result")
 	}
+	err = v1Result.Validate(content)
+	if err != nil {
+		if e.execParams.StrictResultValidation {
+			return err
+		}
+		e.logger.Warnf("%s", err)
+	}
 	out := common.SelectOutputWriter(path)
-	_, err = out.Write(resultYaml)
+	_, err = out.Write(content)
 	if err != nil {
 		return err
 	}
@@ -457,23 +681,43 @@ func (e *exec) storeResultFile(data *v1Result.Result, path string) error {
 }
 
 func (e *exec) provideResultFiles() error {
-	e.logger.Info(fmt.Sprintf("providing evidences in '%s'", EVIDENCE_FILE))
+	e.logger.Info(fmt.Sprintf("providing evidences in '%s'", e.evidenceFile()))
+	rerr := e.provideResultFile()
+	// the manifest and the attestation are created while archiving, so later changes of the log file are not missed
+	resultFile := e.resultFiles()[0]
+	appendices := []zip.Appendix{evidence.ManifestAppendix(resultFile)}
+	if e.signer != nil {
+		appendices = append(appendices, evidence.AttestationAppendix(e.signer, resultFile))
+	}
+	zipper := zip.NewWithOptions(afero.NewOsFs(), e.execParams.EvidenceArchive)
+	eerr := zipper.Directory(ROOT_WORK_DIRECTORY, filepath.Join(e.execParams.OutputFolder, e.evidenceFile()), appendices...)
+	if eerr != nil {
+		eerr = errors.Wrap(eerr, "error archiving evidence")
+	}
+	return helper.Join(rerr, eerr)
+}
+
+// evidenceFile is the name of the evidence archive, e.g. evidence.zip or evidence.tar.zst
+func (e *exec) evidenceFile() string {
+	return EVIDENCE_NAME + zip.Extension(e.execParams.EvidenceArchive.Format)
+}
+
+func (e *exec) provideResultFile() error {
 	if _, err := os.Stat(e.execParams.OutputFolder); os.IsNotExist(err) {
 		err = os.MkdirAll(e.execParams.OutputFolder, 0755)
 		if err != nil {
 			return errors.Wrap(err, "error creating output directory")
 		}
 	}
-	data, rerr := os.ReadFile(filepath.Join(ROOT_WORK_DIRECTORY, RESULT_FILE))
-	if rerr != nil {
-		rerr = errors.Wrap(rerr, "error copying result file")
-	} else {
-		rerr = os.WriteFile(filepath.Join(e.execParams.OutputFolder, RESULT_FILE), data, 0644)
-	}
-	zip := zip.New(afero.NewOsFs())
-	eerr := zip.Directory(ROOT_WORK_DIRECTORY, filepath.Join(e.execParams.OutputFolder, EVIDENCE_FILE))
-	if eerr != nil {
-		eerr = errors.Wrap(eerr, "error zipping evidence")
+	for _, file := range e.outputFiles() {
+		data, err := os.ReadFile(filepath.Join(ROOT_WORK_DIRECTORY, file))
+		if err != nil {
+			return errors.Wrap(err, "error copying result file")
+		}
+		err = os.WriteFile(filepath.Join(e.execParams.OutputFolder, file), data, 0644)
+		if err != nil {
+			return errors.Wrap(err, "error copying result file")
+		}
 	}
-	return helper.Join(rerr, eerr)
+	return nil
 }
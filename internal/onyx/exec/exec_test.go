@@ -4,6 +4,10 @@
 package exec
 
 import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/x509"
+	"encoding/pem"
 	"errors"
 	"os"
 	"path/filepath"
@@ -14,14 +18,17 @@ import (
 	"github.com/B-S-F/onyx/internal/onyx/common"
 	"github.com/B-S-F/onyx/pkg/configuration"
 	v1 "github.com/B-S-F/onyx/pkg/configuration/versions/v1"
+	"github.com/B-S-F/onyx/pkg/evidence"
 	"github.com/B-S-F/onyx/pkg/finalize"
 	"github.com/B-S-F/onyx/pkg/item"
 	"github.com/B-S-F/onyx/pkg/logger"
 	"github.com/B-S-F/onyx/pkg/parameter"
+	"github.com/B-S-F/onyx/pkg/publish"
 	"github.com/B-S-F/onyx/pkg/result"
 	resultv1 "github.com/B-S-F/onyx/pkg/result/v1"
 	"github.com/B-S-F/onyx/pkg/transformer"
 	"github.com/B-S-F/onyx/pkg/v2/config"
+	"github.com/B-S-F/onyx/pkg/v2/model"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/require"
@@ -325,11 +332,80 @@ func TestAllAppReferences(t *testing.T) {
 	assert.Equal(t, app1NoRepo.Repository, appReferences[2].Repository)
 }
 
+func TestInitializeRepositoryOrder(t *testing.T) {
+	config := map[string]interface{}{"url": "https://example.com/{name}-{version}"}
+	repositories, err := initializeRepository([]configuration.Repository{
+		{Name: "default1", Type: "curl", Config: config},
+		{Name: "preferred", Type: "curl", Config: config, Priority: 10},
+		{Name: "default2", Type: "curl", Config: config},
+		{Name: "fallback", Type: "curl", Config: config, Priority: -1},
+	})
+	assert.NoError(t, err)
+
+	var names []string
+	for _, repository := range repositories {
+		names = append(names, repository.Name())
+	}
+	assert.Equal(t, []string{"preferred", "default1", "default2", "fallback"}, names)
+}
+
+func TestProvenanceInputsIgnoreOutputs(t *testing.T) {
+	testCases := map[string]struct {
+		outputFolder string
+		ignored      []string
+		notIgnored   []string
+	}{
+		"output folder is the input folder":  {outputFolder: "input", ignored: []string{RESULT_FILE, EVIDENCE_NAME + ".zip"}},
+		"output folder inside input folder":  {outputFolder: filepath.Join("input", "output"), ignored: []string{"output"}, notIgnored: []string{RESULT_FILE}},
+		"output folder outside input folder": {outputFolder: "output", notIgnored: []string{"output", RESULT_FILE, EVIDENCE_NAME + ".zip"}},
+	}
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			e := &exec{execParams: parameter.ExecutionParameter{
+				InputFolder:  "input",
+				OutputFolder: tc.outputFolder,
+				ConfigName:   "qg-config.yaml",
+				SecretsName:  ".secrets",
+			}}
+
+			inputs := e.provenanceInputs()
+
+			assert.Subset(t, inputs.IgnoredFiles, append([]string{VARS_FILE, SECRETS_FILE}, tc.ignored...))
+			for _, file := range tc.notIgnored {
+				assert.NotContains(t, inputs.IgnoredFiles, file)
+			}
+		})
+	}
+}
+
+func TestPublishResults(t *testing.T) {
+	outputFolder := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(outputFolder, RESULT_FILE), []byte("overallStatus: GREEN"), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(outputFolder, "evidence.zip"), []byte("evidence"), 0644))
+	share := filepath.Join(t.TempDir(), "share")
+	targets, err := initializePublishTargets([]model.PublishTarget{{Name: "share", Type: "directory", Config: map[string]interface{}{"path": share}}})
+	require.NoError(t, err)
+	e := &exec{logger: logger.Get(), execParams: parameter.ExecutionParameter{OutputFolder: outputFolder}}
+
+	uploads := publish.Plan(targets, []string{e.evidenceFile(), RESULT_FILE})
+	err = e.publishResults(uploads)
+
+	require.NoError(t, err)
+	assert.Equal(t, filepath.Join(share, "evidence.zip"), uploads[0].Location)
+	for _, name := range []string{"evidence.zip", RESULT_FILE} {
+		assert.FileExists(t, filepath.Join(share, name))
+	}
+
+	_, err = initializePublishTargets([]model.PublishTarget{{Name: "unknown", Type: "ftp"}})
+	assert.EqualError(t, err, "error creating publish target unknown: unsupported publish target type: ftp")
+}
+
 func TestStoreResultFile(t *testing.T) {
 	resultData := &resultv1.Result{
 		Metadata: resultv1.Metadata{
 			Version: "v1",
 		},
+		OverallStatus: "GREEN",
 		Chapters: map[string]*resultv1.Chapter{
 			"1": {
 				Title:  "chapter 1",
@@ -342,7 +418,7 @@ func TestStoreResultFile(t *testing.T) {
 							"1": {
 								Title:  "check 1",
 								Status: "GREEN",
-								Type:   "autopilot",
+								Type:   "Automation",
 								Evaluation: resultv1.CheckResult{
 									Status: "GREEN",
 									Reason: "This is my reason",
@@ -374,12 +450,48 @@ func TestStoreResultFile(t *testing.T) {
 		itemEngine:      item.NewEngine(tmpDir, false, timeout),
 		finalizerEngine: finalize.NewEngine(tmpDir, timeout),
 		logger:          logger.Get(),
+		execParams:      parameter.ExecutionParameter{StrictResultValidation: true},
 	}
 
 	err := e.storeResultFile(resultData, filepath.Join(tmpDir, "qg-result.json"))
 	assert.NoError(t, err)
-	_, err = os.Stat(filepath.Join(tmpDir, "qg-result.json"))
+	content, err := os.ReadFile(filepath.Join(tmpDir, "qg-result.json"))
 	assert.NoError(t, err)
+	assert.Contains(t, string(content), `"justification": "Line 1\n  Line 2\nLine 3"`)
+}
+
+func TestResultFiles(t *testing.T) {
+	testCases := map[string][]string{
+		"":     {"qg-result.yaml"},
+		"json": {"qg-result.json"},
+		"both": {"qg-result.yaml", "qg-result.json"},
+	}
+	for format, expected := range testCases {
+		e := &exec{execParams: parameter.ExecutionParameter{ResultFormat: format}}
+		assert.Equal(t, expected, e.resultFiles(), format)
+	}
+}
+
+func TestOutputFiles(t *testing.T) {
+	e := &exec{execParams: parameter.ExecutionParameter{ResultFormat: "json", ResultExports: []string{"junit", "sarif"}}}
+	assert.Equal(t, []string{"qg-result.json", "qg-result.junit.xml", "qg-result.sarif"}, e.outputFiles())
+	assert.Equal(t, []string{"qg-result.json"}, e.resultFiles())
+}
+
+func TestUnredactedFindings(t *testing.T) {
+	findings := []evidence.Finding{
+		{File: "1_1/data.json", Rule: evidence.SecretRule, Secret: "TOKEN", Redacted: true},
+		{File: "1_1/screenshot.png", Rule: evidence.SecretRule, Secret: "TOKEN"},
+		{File: "qg-result.yaml", Rule: "jwt"},
+	}
+	testCases := map[string]int{
+		evidence.PolicyRedact: 1,
+		evidence.PolicyFail:   3,
+	}
+	for policy, expected := range testCases {
+		e := &exec{execParams: parameter.ExecutionParameter{EvidenceScanPolicy: policy}}
+		assert.Equal(t, expected, e.unredactedFindings(findings), policy)
+	}
 }
 
 func TestExecErrors(t *testing.T) {
@@ -601,9 +713,12 @@ func TestExecBackwardsCompatibilityQGConfigV1(t *testing.T) {
 	_, err = os.Stat(filepath.Join(tmpDir, "qg-result.yaml"))
 	assert.NoError(t, err)
 
-	// evidence.zip should exist
+	// evidence.zip should exist and match its manifest
 	_, err = os.Stat(filepath.Join(tmpDir, "evidence.zip"))
 	assert.NoError(t, err)
+	verification, err := evidence.Verify(filepath.Join(tmpDir, "evidence.zip"), filepath.Join(tmpDir, "qg-result.yaml"), nil)
+	assert.NoError(t, err)
+	assert.Empty(t, verification.Problems)
 
 	resFile, err := os.ReadFile(filepath.Join(tmpDir, "qg-result.yaml"))
 	assert.NoError(t, err)
@@ -650,6 +765,40 @@ func TestExecBackwardsCompatibilityQGConfigV1(t *testing.T) {
 	assert.Equal(t, result.Chapters["1"].Requirements["1"].Checks["1"].Evaluation.Outputs["output2"], "out2")
 }
 
+func TestEvidenceSigner(t *testing.T) {
+	_, key, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+	der, err := x509.MarshalPKCS8PrivateKey(key)
+	require.NoError(t, err)
+	secrets := map[string]string{
+		"SIGNING_KEY": string(pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der})),
+		"TOKEN":       "token",
+	}
+
+	testCases := map[string]struct {
+		keyName    string
+		wantSigner bool
+		wantErr    string
+	}{
+		"should not sign without key":              {keyName: ""},
+		"should sign with the key of the secret":   {keyName: "SIGNING_KEY", wantSigner: true},
+		"should fail if the secret does not exist": {keyName: "MISSING", wantErr: "evidence signing key 'MISSING' is not a secret"},
+		"should fail if the secret is not a key":   {keyName: "TOKEN", wantErr: "invalid evidence signing key 'TOKEN'"},
+	}
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			signer, err := evidenceSigner(parameter.ExecutionParameter{EvidenceSigningKey: tc.keyName}, secrets)
+
+			if tc.wantErr != "" {
+				assert.ErrorContains(t, err, tc.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tc.wantSigner, signer != nil)
+		})
+	}
+}
+
 func TestExecQGConfigV2(t *testing.T) {
 	// TODO
 	t.Skip("execution of qg-config v2 needs to be implemented")
@@ -0,0 +1,30 @@
+package exec
+
+import (
+	"fmt"
+
+	"github.com/B-S-F/onyx/pkg/publish"
+	"github.com/B-S-F/onyx/pkg/publish/types/azblob"
+	"github.com/B-S-F/onyx/pkg/publish/types/directory"
+	"github.com/B-S-F/onyx/pkg/publish/types/http"
+	"github.com/B-S-F/onyx/pkg/publish/types/s3"
+	model "github.com/B-S-F/onyx/pkg/v2/model"
+)
+
+// initializePublishTargets creates the targets the result file and the evidence are uploaded to
+func initializePublishTargets(targets []model.PublishTarget) ([]publish.Target, error) {
+	targetFactory := publish.NewTargetFactory()
+	targetFactory.Register("directory", directory.NewTarget)
+	targetFactory.Register("azure-blob-storage", azblob.NewTarget)
+	targetFactory.Register("s3", s3.NewTarget)
+	targetFactory.Register("http", http.NewTarget)
+	publishTargets := make([]publish.Target, 0, len(targets))
+	for _, target := range targets {
+		publishTarget, err := targetFactory.New(target.Name, target.Type, target.Config)
+		if err != nil {
+			return nil, fmt.Errorf("error creating publish target %s: %w", target.Name, err)
+		}
+		publishTargets = append(publishTargets, publishTarget)
+	}
+	return publishTargets, nil
+}
@@ -2,19 +2,35 @@ package exec
 
 import (
 	"fmt"
+	"os"
+	"sort"
 
 	"github.com/B-S-F/onyx/pkg/configuration"
+	"github.com/B-S-F/onyx/pkg/logger"
 	"github.com/B-S-F/onyx/pkg/repository"
 	"github.com/B-S-F/onyx/pkg/repository/types/azblob"
 	"github.com/B-S-F/onyx/pkg/repository/types/curl"
+	"github.com/B-S-F/onyx/pkg/repository/types/git"
+	"github.com/B-S-F/onyx/pkg/repository/types/oci"
+	"github.com/B-S-F/onyx/pkg/repository/types/plugin"
+	"github.com/B-S-F/onyx/pkg/repository/types/s3"
 )
 
+// initializeRepository creates the repositories ordered by their priority, repositories with the same priority keep their declared order
 func initializeRepository(repositories []configuration.Repository) ([]repository.Repository, error) {
+	repositories = append([]configuration.Repository(nil), repositories...)
+	sort.SliceStable(repositories, func(i, j int) bool {
+		return repositories[i].Priority > repositories[j].Priority
+	})
 	var parseErrs []error
 	var registryRepositories []repository.Repository
 	repositoryFactory := repository.NewRepositoryFactory()
 	repositoryFactory.Register("curl", curl.NewRepository)
 	repositoryFactory.Register("azure-blob-storage", azblob.NewRepository)
+	repositoryFactory.Register("oci", oci.NewRepository)
+	repositoryFactory.Register("s3", s3.NewRepository)
+	repositoryFactory.Register("git", git.NewRepository)
+	registerPlugins(repositoryFactory, os.Getenv("PATH"))
 	for index := range repositories {
 		configRepository := repositories[index]
 		repository, err := repositoryFactory.New(configRepository.Name, configRepository.Type, configRepository.Config)
@@ -29,3 +45,15 @@ func initializeRepository(repositories []configuration.Repository) ([]repository
 	}
 	return registryRepositories, nil
 }
+
+// registerPlugins registers the onyx-repository-<type> executables found in the PATH, built-in types can't be replaced
+func registerPlugins(repositoryFactory *repository.RepositoryFactory, path string) {
+	for repositoryType, executable := range plugin.Discover(path) {
+		if repositoryFactory.Supports(repositoryType) {
+			logger.Get().Warnf("ignoring plugin %s, repository type '%s' is built-in", executable, repositoryType)
+			continue
+		}
+		logger.Get().Debugf("registering plugin %s for repository type '%s'", executable, repositoryType)
+		repositoryFactory.Register(repositoryType, plugin.NewFactory(repositoryType, executable))
+	}
+}
@@ -0,0 +1,26 @@
+package exec
+
+import (
+	"github.com/B-S-F/onyx/pkg/parameter"
+	"github.com/B-S-F/onyx/pkg/secrets"
+	"github.com/B-S-F/onyx/pkg/secrets/types/env"
+	secretsExec "github.com/B-S-F/onyx/pkg/secrets/types/exec"
+	"github.com/B-S-F/onyx/pkg/secrets/types/file"
+	"github.com/B-S-F/onyx/pkg/secrets/types/sops"
+	"github.com/B-S-F/onyx/pkg/secrets/types/vault"
+)
+
+// readSecrets reads the secrets of the configured providers, the file provider defaults to the secrets file of the input folder
+func readSecrets(execParams parameter.ExecutionParameter) (map[string]string, error) {
+	providerFactory := secrets.NewProviderFactory()
+	providerFactory.Register("file", file.NewFactory(secretsFile(execParams)))
+	providerFactory.Register("env", env.NewProvider)
+	providerFactory.Register("exec", secretsExec.NewProvider)
+	providerFactory.Register("sops", sops.NewProvider)
+	providerFactory.Register("vault", vault.NewProvider)
+	providers, err := providerFactory.NewProviders(execParams.SecretProviders)
+	if err != nil {
+		return nil, err
+	}
+	return secrets.Load(providers)
+}
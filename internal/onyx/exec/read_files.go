@@ -1,10 +1,12 @@
 package exec
 
 import (
+	"os"
 	"path/filepath"
 
 	"github.com/B-S-F/onyx/pkg/parameter"
 	"github.com/B-S-F/onyx/pkg/reader"
+	"github.com/B-S-F/onyx/pkg/secrets"
 )
 
 func ReadFiles(execParams parameter.ExecutionParameter, reader reader.FileReader) ([]byte, map[string]string, map[string]string, error) {
@@ -12,7 +14,7 @@ func ReadFiles(execParams parameter.ExecutionParameter, reader reader.FileReader
 	secrets := make(map[string]string)
 	configFile := filepath.Join(execParams.InputFolder, execParams.ConfigName)
 	varsFile := filepath.Join(execParams.InputFolder, execParams.VarsName)
-	secretsFile := filepath.Join(execParams.InputFolder, execParams.SecretsName)
+	secretsFile := secretsFile(execParams)
 	config, err := reader.Read(configFile)
 	if err != nil {
 		return config, vars, secrets, err
@@ -23,7 +25,12 @@ func ReadFiles(execParams parameter.ExecutionParameter, reader reader.FileReader
 			return config, vars, secrets, err
 		}
 	}
-	if secretsFile != "" {
+	if len(execParams.SecretProviders) > 0 {
+		secrets, err = readSecrets(execParams)
+		if err != nil {
+			return config, vars, secrets, err
+		}
+	} else if secretsFile != "" {
 		secrets, err = reader.ReadJsonMap(secretsFile)
 		if err != nil {
 			return config, vars, secrets, err
@@ -31,3 +38,16 @@ func ReadFiles(execParams parameter.ExecutionParameter, reader reader.FileReader
 	}
 	return config, vars, secrets, nil
 }
+
+// secretsFile returns the age encrypted secrets file, e.g. .secrets.age, if the plain secrets file does not exist
+func secretsFile(execParams parameter.ExecutionParameter) string {
+	plain := filepath.Join(execParams.InputFolder, execParams.SecretsName)
+	if _, err := os.Stat(plain); err == nil {
+		return plain
+	}
+	encrypted := plain + secrets.ENCRYPTED_SUFFIX
+	if _, err := os.Stat(encrypted); err == nil {
+		return encrypted
+	}
+	return plain
+}
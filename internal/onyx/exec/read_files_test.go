@@ -4,9 +4,12 @@
 package exec
 
 import (
+	"os"
+	"path/filepath"
 	"testing"
 
 	"github.com/B-S-F/onyx/pkg/parameter"
+	"github.com/B-S-F/onyx/pkg/secrets"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 )
@@ -85,3 +88,65 @@ func TestReadFiles(t *testing.T) {
 		})
 	}
 }
+
+func TestReadFilesWithSecretProviders(t *testing.T) {
+	t.Setenv("ONYX_TEST_SECRET_TOKEN", "token")
+	execParams := parameter.ExecutionParameter{
+		InputFolder:     "test",
+		ConfigName:      "config",
+		VarsName:        "vars",
+		SecretsName:     "secrets",
+		SecretProviders: []secrets.ProviderConfig{{Type: "env", Config: map[string]interface{}{"prefix": "ONYX_TEST_SECRET_"}}},
+	}
+
+	t.Run("should read the secrets from the providers instead of the secrets file", func(t *testing.T) {
+		// arrange
+		mock := &mockReader{}
+		mock.On("Read", "test/config").Return([]byte("config"), nil)
+		mock.On("ReadJsonMap", "test/vars").Return(map[string]string{"VAR": "var"}, nil)
+
+		// act
+		_, _, secrets, err := ReadFiles(execParams, mock)
+
+		// assert
+		assert.NoError(t, err)
+		assert.Equal(t, map[string]string{"TOKEN": "token"}, secrets)
+		mock.AssertNotCalled(t, "ReadJsonMap", "test/secrets")
+	})
+
+	t.Run("should return an error naming the failing provider", func(t *testing.T) {
+		// arrange
+		mock := &mockReader{}
+		mock.On("Read", "test/config").Return([]byte("config"), nil)
+		mock.On("ReadJsonMap", "test/vars").Return(map[string]string{"VAR": "var"}, nil)
+		params := execParams
+		params.SecretProviders = []secrets.ProviderConfig{{Name: "team-vault", Type: "vault", Config: map[string]interface{}{}}}
+
+		// act
+		_, _, _, err := ReadFiles(params, mock)
+
+		// assert
+		assert.ErrorContains(t, err, "secret provider 'team-vault'")
+	})
+}
+
+func TestSecretsFile(t *testing.T) {
+	inputFolder := t.TempDir()
+	execParams := parameter.ExecutionParameter{InputFolder: inputFolder, SecretsName: ".secrets"}
+
+	t.Run("should use the plain secrets file if no encrypted file exists", func(t *testing.T) {
+		assert.Equal(t, filepath.Join(inputFolder, ".secrets"), secretsFile(execParams))
+	})
+
+	t.Run("should use the encrypted secrets file if no plain file exists", func(t *testing.T) {
+		assert.NoError(t, os.WriteFile(filepath.Join(inputFolder, ".secrets.age"), []byte{}, 0644))
+
+		assert.Equal(t, filepath.Join(inputFolder, ".secrets.age"), secretsFile(execParams))
+	})
+
+	t.Run("should prefer the plain secrets file", func(t *testing.T) {
+		assert.NoError(t, os.WriteFile(filepath.Join(inputFolder, ".secrets"), []byte{}, 0644))
+
+		assert.Equal(t, filepath.Join(inputFolder, ".secrets"), secretsFile(execParams))
+	})
+}
@@ -0,0 +1,28 @@
+package report
+
+import (
+	"github.com/B-S-F/onyx/internal/onyx/common"
+	"github.com/B-S-F/onyx/pkg/report"
+	"github.com/B-S-F/onyx/pkg/result"
+	"github.com/pkg/errors"
+)
+
+// Report renders a v2 result file in the format and writes it to the output, which is stdout by default
+func Report(file, format, templateFile, output string) error {
+	loaded, err := result.Load(file)
+	if err != nil {
+		return err
+	}
+	if loaded.V2 == nil {
+		return errors.Errorf("result file '%s' is a %s result, only %s results can be rendered", file, loaded.Version, result.VERSION_V2)
+	}
+	content, err := report.Render(loaded.V2, format, templateFile)
+	if err != nil {
+		return errors.Wrapf(err, "error rendering report of result file '%s'", file)
+	}
+	err = common.WriteOutput(output, content)
+	if err != nil {
+		return errors.Wrap(err, "error writing report to output")
+	}
+	return nil
+}
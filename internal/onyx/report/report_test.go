@@ -0,0 +1,41 @@
+//go:build unit
+// +build unit
+
+package report
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func writeResult(t *testing.T, version string) string {
+	path := filepath.Join(t.TempDir(), "qg-result.yaml")
+	content := "metadata:\n  version: " + version + "\nheader:\n  name: My Project\noverallStatus: GREEN\n"
+	require.NoError(t, os.WriteFile(path, []byte(content), 0644))
+	return path
+}
+
+func TestReport(t *testing.T) {
+	t.Run("should write the report to the output", func(t *testing.T) {
+		output := filepath.Join(t.TempDir(), "qg-report.md")
+
+		err := Report(writeResult(t, "v2"), "markdown", "", output)
+
+		require.NoError(t, err)
+		content, err := os.ReadFile(output)
+		require.NoError(t, err)
+		assert.Contains(t, string(content), "My Project")
+	})
+
+	t.Run("should reject v1 results", func(t *testing.T) {
+		file := writeResult(t, "v1")
+
+		err := Report(file, "html", "", "")
+
+		assert.EqualError(t, err, "result file '"+file+"' is a v1 result, only v2 results can be rendered")
+	})
+}
@@ -0,0 +1,52 @@
+package result
+
+import (
+	"github.com/B-S-F/onyx/internal/onyx/common"
+	"github.com/B-S-F/onyx/pkg/diff"
+	"github.com/B-S-F/onyx/pkg/logger"
+	"github.com/B-S-F/onyx/pkg/result"
+	resultV2 "github.com/B-S-F/onyx/pkg/v2/result"
+	"github.com/pkg/errors"
+)
+
+// Diff compares two v2 result files and writes the changes in the format to the output, which is stdout by default
+// With failOnRegression, an error is returned if a check or the overall status got worse or a criterion failed
+func Diff(oldFile, newFile, format, output string, failOnRegression bool) error {
+	oldResult, err := loadV2(oldFile)
+	if err != nil {
+		return err
+	}
+	newResult, err := loadV2(newFile)
+	if err != nil {
+		return err
+	}
+	changes := diff.Compare(oldResult, newResult)
+	content, err := diff.Render(changes, format)
+	if err != nil {
+		return err
+	}
+	err = common.WriteOutput(output, content)
+	if err != nil {
+		return errors.Wrap(err, "error writing diff to output")
+	}
+	regressions := changes.Regressions()
+	if len(regressions) == 0 {
+		return nil
+	}
+	if failOnRegression {
+		return errors.Errorf("found %d regressions between '%s' and '%s'", len(regressions), oldFile, newFile)
+	}
+	logger.Get().Warnf("found %d regressions between '%s' and '%s'", len(regressions), oldFile, newFile)
+	return nil
+}
+
+func loadV2(file string) (*resultV2.Result, error) {
+	loaded, err := result.Load(file)
+	if err != nil {
+		return nil, err
+	}
+	if loaded.V2 == nil {
+		return nil, errors.Errorf("result file '%s' is a %s result, only %s results can be compared", file, loaded.Version, result.VERSION_V2)
+	}
+	return loaded.V2, nil
+}
@@ -0,0 +1,58 @@
+//go:build unit
+// +build unit
+
+package result
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// regressedResult copies the v2 test result with check 1_1_1 turned from GREEN to RED
+func regressedResult(t *testing.T) string {
+	content, err := os.ReadFile("testdata/qg-result-v2.yaml")
+	require.NoError(t, err)
+	regressed := strings.Replace(string(content), "evaluation:\n                            status: GREEN", "evaluation:\n                            status: RED", 1)
+	require.NotEqual(t, string(content), regressed)
+	path := filepath.Join(t.TempDir(), "qg-result.yaml")
+	require.NoError(t, os.WriteFile(path, []byte(regressed), 0644))
+	return path
+}
+
+func TestDiff(t *testing.T) {
+	t.Run("should write the diff to the output", func(t *testing.T) {
+		output := filepath.Join(t.TempDir(), "diff.md")
+
+		err := Diff("testdata/qg-result-v2.yaml", regressedResult(t), "markdown", output, false)
+
+		require.NoError(t, err)
+		content, err := os.ReadFile(output)
+		require.NoError(t, err)
+		assert.Contains(t, string(content), "**1 regressions**: 1_1_1")
+	})
+
+	t.Run("should fail on regressions", func(t *testing.T) {
+		newFile := regressedResult(t)
+
+		err := Diff("testdata/qg-result-v2.yaml", newFile, "text", filepath.Join(t.TempDir(), "diff.txt"), true)
+
+		assert.EqualError(t, err, "found 1 regressions between 'testdata/qg-result-v2.yaml' and '"+newFile+"'")
+	})
+
+	t.Run("should not fail without changes", func(t *testing.T) {
+		err := Diff("testdata/qg-result-v2.yaml", "testdata/qg-result-v2.yaml", "json", filepath.Join(t.TempDir(), "diff.json"), true)
+
+		assert.NoError(t, err)
+	})
+
+	t.Run("should reject v1 results", func(t *testing.T) {
+		err := Diff("testdata/qg-result-v1.yaml", "testdata/qg-result-v2.yaml", "text", "", false)
+
+		assert.EqualError(t, err, "result file 'testdata/qg-result-v1.yaml' is a v1 result, only v2 results can be compared")
+	})
+}
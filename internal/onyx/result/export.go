@@ -0,0 +1,32 @@
+package result
+
+import (
+	"github.com/B-S-F/onyx/internal/onyx/common"
+	"github.com/B-S-F/onyx/pkg/export"
+	"github.com/B-S-F/onyx/pkg/result"
+	"github.com/pkg/errors"
+)
+
+// Export converts a v2 result file into the format and writes it to the output, which is stdout by default
+func Export(file, format, output string) error {
+	err := export.ValidateFormats([]string{format})
+	if err != nil {
+		return err
+	}
+	loaded, err := result.Load(file)
+	if err != nil {
+		return err
+	}
+	if loaded.V2 == nil {
+		return errors.Errorf("result file '%s' is a %s result, only %s results can be exported", file, loaded.Version, result.VERSION_V2)
+	}
+	content, err := export.Export(loaded.V2, format)
+	if err != nil {
+		return errors.Wrapf(err, "error exporting result file '%s' as %s", file, format)
+	}
+	err = common.WriteOutput(output, content)
+	if err != nil {
+		return errors.Wrap(err, "error writing export to output")
+	}
+	return nil
+}
@@ -0,0 +1,38 @@
+//go:build unit
+// +build unit
+
+package result
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExport(t *testing.T) {
+	t.Run("should export a v2 result", func(t *testing.T) {
+		output := filepath.Join(t.TempDir(), "qg-result.junit.xml")
+
+		err := Export("testdata/qg-result-v2.yaml", "junit", output)
+
+		require.NoError(t, err)
+		content, err := os.ReadFile(output)
+		require.NoError(t, err)
+		assert.Contains(t, string(content), `<testsuite name="Manual Answers / RED answer" id="2_3"`)
+	})
+
+	t.Run("should reject v1 results", func(t *testing.T) {
+		err := Export("testdata/qg-result-v1.yaml", "sarif", "")
+
+		assert.EqualError(t, err, "result file 'testdata/qg-result-v1.yaml' is a v1 result, only v2 results can be exported")
+	})
+
+	t.Run("should reject unsupported formats", func(t *testing.T) {
+		err := Export("testdata/qg-result-v2.yaml", "csv", "")
+
+		assert.ErrorContains(t, err, "unsupported export format 'csv'")
+	})
+}
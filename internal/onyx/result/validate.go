@@ -0,0 +1,21 @@
+package result
+
+import (
+	"github.com/B-S-F/onyx/pkg/logger"
+	"github.com/B-S-F/onyx/pkg/result"
+	"github.com/pkg/errors"
+)
+
+// Validate checks a result file against the schema of the version in its metadata
+func Validate(file string) error {
+	loaded, err := result.Load(file)
+	if err != nil {
+		return err
+	}
+	err = loaded.ValidateSchema()
+	if err != nil {
+		return errors.Wrapf(err, "result file '%s' is not valid", file)
+	}
+	logger.Get().Infof("result file '%s' is a valid %s result", file, loaded.Version)
+	return nil
+}
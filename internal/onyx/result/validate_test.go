@@ -0,0 +1,37 @@
+//go:build unit
+// +build unit
+
+package result
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidate(t *testing.T) {
+	testCases := map[string]struct {
+		file string
+		err  []string
+	}{
+		"v1 result": {file: "testdata/qg-result-v1.yaml"},
+		"v2 result": {file: "testdata/qg-result-v2.yaml"},
+		"invalid v2 json result": {
+			file: "testdata/qg-result-invalid.json",
+			err:  []string{"result file 'testdata/qg-result-invalid.json' is not valid", "overallStatus must be one of the following", "statistics is required"},
+		},
+		"missing file": {file: "testdata/missing.yaml", err: []string{"error reading result file 'testdata/missing.yaml'"}},
+	}
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			err := Validate(tc.file)
+			if len(tc.err) == 0 {
+				assert.NoError(t, err)
+				return
+			}
+			for _, msg := range tc.err {
+				assert.ErrorContains(t, err, msg)
+			}
+		})
+	}
+}
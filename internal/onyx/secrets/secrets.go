@@ -0,0 +1,188 @@
+package secrets
+
+import (
+	"encoding/json"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/B-S-F/onyx/internal/onyx/common"
+	"github.com/B-S-F/onyx/pkg/logger"
+	"github.com/B-S-F/onyx/pkg/secrets"
+	"github.com/pkg/errors"
+)
+
+const (
+	DEFAULT_EDITOR = "vi"
+	// RECIPIENTS_SUFFIX is appended to an encrypted secrets file to get the recipients file which is used by edit by default
+	RECIPIENTS_SUFFIX = ".recipients"
+)
+
+// Recipients of an encrypted secrets file
+type Recipients struct {
+	Values []string
+	Files  []string
+}
+
+func (r Recipients) empty() bool {
+	return len(r.Values) == 0 && len(r.Files) == 0
+}
+
+// Encrypt encrypts a plain secrets file for the recipients
+func Encrypt(file, output string, recipients Recipients, armored bool) error {
+	content, err := os.ReadFile(file)
+	if err != nil {
+		return errors.Wrapf(err, "error reading secrets file %s", file)
+	}
+	if secrets.IsEncrypted(content) {
+		return errors.Errorf("secrets file %s is already encrypted", file)
+	}
+	if err := validate(content); err != nil {
+		return errors.Wrapf(err, "invalid secrets file %s", file)
+	}
+	parsedRecipients, err := secrets.Recipients(recipients.Values, recipients.Files)
+	if err != nil {
+		return err
+	}
+	encrypted, err := secrets.Encrypt(content, parsedRecipients, armored)
+	if err != nil {
+		return err
+	}
+	if output == "" {
+		output = file + secrets.ENCRYPTED_SUFFIX
+	}
+	if err := writeFile(output, encrypted); err != nil {
+		return errors.Wrapf(err, "error writing encrypted secrets file %s", output)
+	}
+	logger.Get().Infof("secrets file '%s' encrypted to '%s'", file, output)
+	return nil
+}
+
+// Decrypt decrypts an encrypted secrets file to the output, which defaults to stdout
+func Decrypt(file, output, identityFile string) error {
+	plain, err := decrypt(file, identityFile)
+	if err != nil {
+		return err
+	}
+	writer := common.SelectOutputWriter(output)
+	defer writer.Close()
+	_, err = writer.Write(plain)
+	if err != nil {
+		return errors.Wrap(err, "error writing decrypted secrets to output")
+	}
+	return nil
+}
+
+// Edit decrypts the secrets file to a private temporary file, opens it in the editor and encrypts the result again
+// Without recipients the file is encrypted for the recipients in the recipients file next to it, e.g. .secrets.age.recipients
+func Edit(file, identityFile string, recipients Recipients, editor string, armored bool) error {
+	if recipients.empty() {
+		recipientsFile := file + RECIPIENTS_SUFFIX
+		if _, err := os.Stat(recipientsFile); err != nil {
+			return errors.Errorf("no recipients given and no recipients file %s found", recipientsFile)
+		}
+		recipients.Files = []string{recipientsFile}
+	}
+	parsedRecipients, err := secrets.Recipients(recipients.Values, recipients.Files)
+	if err != nil {
+		return err
+	}
+	plain, err := decrypt(file, identityFile)
+	if err != nil {
+		return err
+	}
+
+	tempDir, err := os.MkdirTemp("", "onyx-secrets-")
+	if err != nil {
+		return errors.Wrap(err, "error creating temporary directory")
+	}
+	defer os.RemoveAll(tempDir)
+	tempFile := filepath.Join(tempDir, strings.TrimSuffix(filepath.Base(file), secrets.ENCRYPTED_SUFFIX)+".json")
+	if err := os.WriteFile(tempFile, plain, 0600); err != nil {
+		return errors.Wrap(err, "error writing temporary file")
+	}
+	if err := runEditor(editor, tempFile); err != nil {
+		return err
+	}
+	edited, err := os.ReadFile(tempFile)
+	if err != nil {
+		return errors.Wrap(err, "error reading edited secrets")
+	}
+	if err := validate(edited); err != nil {
+		return errors.Wrap(err, "invalid edited secrets, the secrets file is not changed")
+	}
+	encrypted, err := secrets.Encrypt(edited, parsedRecipients, armored)
+	if err != nil {
+		return err
+	}
+	if err := writeFile(file, encrypted); err != nil {
+		return errors.Wrapf(err, "error writing encrypted secrets file %s", file)
+	}
+	logger.Get().Infof("secrets file '%s' updated", file)
+	return nil
+}
+
+func decrypt(file, identityFile string) ([]byte, error) {
+	content, err := os.ReadFile(file)
+	if err != nil {
+		return nil, errors.Wrapf(err, "error reading secrets file %s", file)
+	}
+	if !secrets.IsEncrypted(content) {
+		return nil, errors.Errorf("secrets file %s is not encrypted with age", file)
+	}
+	identities, err := secrets.Identities(identityFile)
+	if err != nil {
+		return nil, err
+	}
+	plain, err := secrets.Decrypt(content, identities)
+	if err != nil {
+		return nil, errors.Wrapf(err, "error decrypting secrets file %s", file)
+	}
+	return plain, nil
+}
+
+// validate ensures that the secrets can be read by onyx exec
+func validate(content []byte) error {
+	secretsMap := make(map[string]string)
+	if len(content) == 0 {
+		return nil
+	}
+	return json.Unmarshal(content, &secretsMap)
+}
+
+// runEditor runs the editor command like git does, it may contain arguments, e.g. "code --wait"
+func runEditor(editor, file string) error {
+	if editor == "" {
+		editor = DEFAULT_EDITOR
+	}
+	parts := strings.Fields(editor)
+	cmd := exec.Command(parts[0], append(parts[1:], file)...)
+	cmd.Stdin = os.Stdin
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	if err := cmd.Run(); err != nil {
+		return errors.Wrapf(err, "error running editor %s", editor)
+	}
+	return nil
+}
+
+// writeFile replaces the file atomically, so it is never left partially written
+func writeFile(file string, content []byte) error {
+	temp, err := os.CreateTemp(filepath.Dir(file), "."+filepath.Base(file)+".tmp-")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(temp.Name())
+	if _, err := temp.Write(content); err != nil {
+		temp.Close()
+		return err
+	}
+	if err := temp.Close(); err != nil {
+		return err
+	}
+	if err := os.Chmod(temp.Name(), 0644); err != nil {
+		return err
+	}
+	return os.Rename(temp.Name(), file)
+}
@@ -0,0 +1,94 @@
+//go:build integration
+// +build integration
+
+package secrets
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"filippo.io/age"
+	"github.com/B-S-F/onyx/pkg/secrets"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// writeEditor creates an editor which replaces the edited file with the content
+func writeEditor(t *testing.T, content string) string {
+	path := filepath.Join(t.TempDir(), "editor")
+	require.NoError(t, os.WriteFile(path, []byte("#!/bin/sh\nprintf '%s' '"+content+"' > \"$1\"\n"), 0755))
+	return path
+}
+
+func TestEdit(t *testing.T) {
+	identity, err := age.GenerateX25519Identity()
+	require.NoError(t, err)
+	identityFile := filepath.Join(t.TempDir(), "keys.txt")
+	require.NoError(t, os.WriteFile(identityFile, []byte(identity.String()+"\n"), 0600))
+
+	encryptedFile := func(t *testing.T) string {
+		encrypted, err := secrets.Encrypt([]byte(`{"TOKEN":"abc"}`), []age.Recipient{identity.Recipient()}, true)
+		require.NoError(t, err)
+		file := filepath.Join(t.TempDir(), ".secrets.age")
+		require.NoError(t, os.WriteFile(file, encrypted, 0644))
+		return file
+	}
+
+	t.Run("should encrypt the edited secrets for the given recipients", func(t *testing.T) {
+		file := encryptedFile(t)
+		other, err := age.GenerateX25519Identity()
+		require.NoError(t, err)
+
+		err = Edit(file, identityFile, Recipients{Values: []string{other.Recipient().String()}}, writeEditor(t, `{"TOKEN":"def"}`), true)
+
+		require.NoError(t, err)
+		content, err := os.ReadFile(file)
+		require.NoError(t, err)
+		plain, err := secrets.Decrypt(content, []age.Identity{other})
+		require.NoError(t, err)
+		assert.Equal(t, `{"TOKEN":"def"}`, string(plain))
+		_, err = secrets.Decrypt(content, []age.Identity{identity})
+		assert.Error(t, err)
+	})
+
+	t.Run("should encrypt the edited secrets for the recipients file next to the secrets file", func(t *testing.T) {
+		file := encryptedFile(t)
+		require.NoError(t, os.WriteFile(file+RECIPIENTS_SUFFIX, []byte(identity.Recipient().String()+"\n"), 0644))
+
+		err := Edit(file, identityFile, Recipients{}, writeEditor(t, `{"TOKEN":"def"}`), true)
+
+		require.NoError(t, err)
+		content, err := os.ReadFile(file)
+		require.NoError(t, err)
+		plain, err := secrets.Decrypt(content, []age.Identity{identity})
+		require.NoError(t, err)
+		assert.Equal(t, `{"TOKEN":"def"}`, string(plain))
+	})
+
+	t.Run("should fail without recipients", func(t *testing.T) {
+		file := encryptedFile(t)
+		before, err := os.ReadFile(file)
+		require.NoError(t, err)
+
+		err = Edit(file, identityFile, Recipients{}, writeEditor(t, `{"TOKEN":"def"}`), true)
+
+		assert.ErrorContains(t, err, "no recipients given and no recipients file "+file+RECIPIENTS_SUFFIX+" found")
+		after, err := os.ReadFile(file)
+		require.NoError(t, err)
+		assert.Equal(t, before, after)
+	})
+
+	t.Run("should keep the file if the edited secrets are invalid", func(t *testing.T) {
+		file := encryptedFile(t)
+		before, err := os.ReadFile(file)
+		require.NoError(t, err)
+
+		err = Edit(file, identityFile, Recipients{Values: []string{identity.Recipient().String()}}, writeEditor(t, `TOKEN=def`), true)
+
+		assert.ErrorContains(t, err, "invalid edited secrets")
+		after, err := os.ReadFile(file)
+		require.NoError(t, err)
+		assert.Equal(t, before, after)
+	})
+}
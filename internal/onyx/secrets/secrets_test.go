@@ -0,0 +1,77 @@
+//go:build unit
+// +build unit
+
+package secrets
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"filippo.io/age"
+	"github.com/B-S-F/onyx/pkg/secrets"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// writeIdentity creates an identity file and returns its path and recipient
+func writeIdentity(t *testing.T) (string, string) {
+	identity, err := age.GenerateX25519Identity()
+	require.NoError(t, err)
+	identityFile := filepath.Join(t.TempDir(), "keys.txt")
+	require.NoError(t, os.WriteFile(identityFile, []byte(identity.String()+"\n"), 0600))
+	return identityFile, identity.Recipient().String()
+}
+
+func TestEncryptDecrypt(t *testing.T) {
+	identityFile, recipient := writeIdentity(t)
+	folder := t.TempDir()
+	secretsFile := filepath.Join(folder, ".secrets")
+	require.NoError(t, os.WriteFile(secretsFile, []byte(`{"TOKEN":"abc"}`), 0600))
+
+	t.Run("should encrypt next to the plain file and decrypt it again", func(t *testing.T) {
+		// act
+		err := Encrypt(secretsFile, "", Recipients{Values: []string{recipient}}, true)
+
+		// assert
+		require.NoError(t, err)
+		encrypted, err := os.ReadFile(secretsFile + secrets.ENCRYPTED_SUFFIX)
+		require.NoError(t, err)
+		assert.True(t, secrets.IsEncrypted(encrypted))
+
+		output := filepath.Join(folder, "decrypted.json")
+		err = Decrypt(secretsFile+secrets.ENCRYPTED_SUFFIX, output, identityFile)
+		require.NoError(t, err)
+		decrypted, err := os.ReadFile(output)
+		require.NoError(t, err)
+		assert.Equal(t, `{"TOKEN":"abc"}`, string(decrypted))
+	})
+
+	t.Run("should reject invalid secrets", func(t *testing.T) {
+		// arrange
+		invalidFile := filepath.Join(folder, ".secrets.invalid")
+		require.NoError(t, os.WriteFile(invalidFile, []byte(`{"TOKEN":{"nested":true}}`), 0600))
+
+		// act
+		err := Encrypt(invalidFile, "", Recipients{Values: []string{recipient}}, true)
+
+		// assert
+		assert.ErrorContains(t, err, "invalid secrets file")
+	})
+
+	t.Run("should not decrypt plain files", func(t *testing.T) {
+		// act
+		err := Decrypt(secretsFile, "", identityFile)
+
+		// assert
+		assert.ErrorContains(t, err, "is not encrypted with age")
+	})
+
+	t.Run("should not encrypt twice", func(t *testing.T) {
+		// act
+		err := Encrypt(secretsFile+secrets.ENCRYPTED_SUFFIX, filepath.Join(folder, "twice"), Recipients{Values: []string{recipient}}, true)
+
+		// assert
+		assert.ErrorContains(t, err, "is already encrypted")
+	})
+}
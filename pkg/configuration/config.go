@@ -98,6 +98,10 @@ func (a *AppReference) verify() error {
 	if a.Version == "" {
 		return fmt.Errorf("app version must be set in app reference")
 	}
+	// content digests (e.g. of OCI artifacts) are allowed as version
+	if regexp.MustCompile(`^sha256:[a-f0-9]{64}$`).MatchString(a.Version) {
+		return nil
+	}
 	if invalid := regexReservedCharacters.FindAllString(a.Version, -1); invalid != nil {
 		return fmt.Errorf("app version contains reserved characters %v", invalid)
 	}
@@ -108,9 +112,10 @@ func (a *AppReference) verify() error {
 }
 
 type Repository struct {
-	Name   string
-	Type   string
-	Config map[string]interface{}
+	Name     string
+	Type     string
+	Config   map[string]interface{}
+	Priority int
 }
 
 type Item struct {
@@ -144,6 +149,7 @@ type ExecutionPlan struct {
 	Items        []Item
 	Finalize     Item
 	Repositories []Repository
+	Resolution   string
 }
 
 func (e *ExecutionPlan) String() string {
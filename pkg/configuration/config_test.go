@@ -108,3 +108,43 @@ func TestExecutionPlan_String(t *testing.T) {
 	got := ep.String()
 	assert.Equal(t, want, got)
 }
+
+func TestNewAppReference(t *testing.T) {
+	testCases := map[string]struct {
+		reference string
+		want      *AppReference
+		wantErr   bool
+	}{
+		"should parse name and version": {
+			reference: "app@1.0.0",
+			want:      &AppReference{Name: "app", Version: "1.0.0"},
+		},
+		"should parse repository, name and version": {
+			reference: "repo::app@1.0.0",
+			want:      &AppReference{Repository: "repo", Name: "app", Version: "1.0.0"},
+		},
+		"should allow a content digest as version": {
+			reference: "repo::app@sha256:0123456789abcdef0123456789abcdef0123456789abcdef0123456789abcdef",
+			want:      &AppReference{Repository: "repo", Name: "app", Version: "sha256:0123456789abcdef0123456789abcdef0123456789abcdef0123456789abcdef"},
+		},
+		"should fail for a malformed digest": {
+			reference: "app@sha256:xyz",
+			wantErr:   true,
+		},
+		"should fail without version": {
+			reference: "app",
+			wantErr:   true,
+		},
+	}
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			got, err := NewAppReference(tc.reference)
+			if tc.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tc.want, got)
+		})
+	}
+}
@@ -167,6 +167,10 @@ type AppRepository struct {
 	// 		username: "my-username"
 	//		password: "my-password"
 	Config map[string]interface{} `yaml:"configuration" json:"configuration" jsonschema:"required"`
+	// Priority of the repository for apps without repository prefix, repositories with a higher priority are asked first.
+	// Repositories with the same priority are asked in the order of declaration.
+	// Example 10
+	Priority int `yaml:"priority,omitempty" json:"priority,omitempty" jsonschema:"optional"`
 }
 
 // Contains the configuration of the project
@@ -181,6 +185,10 @@ type Config struct {
 	Env map[string]string `yaml:"env,omitempty" json:"env,omitempty" jsonschema:"optional"`
 	// Extra dependencies to be installed
 	Repositories []AppRepository `yaml:"repositories,omitempty" json:"repositories,omitempty" jsonschema:"optional"`
+	// Resolution of apps without repository prefix which are provided by multiple repositories,
+	// 'first' uses the first repository, 'strict' fails. Defaults to 'first'
+	// Example "strict"
+	Resolution string `yaml:"resolution,omitempty" json:"resolution,omitempty" jsonschema:"optional,enum=first,enum=strict"`
 	// Autopilot configurations
 	Autopilots map[string]Autopilot `yaml:"autopilots,omitempty" json:"autopilots" jsonschema:"optional"`
 	// Finalize configuration
@@ -211,11 +219,13 @@ func (c *Config) Migrate() ([]byte, error) {
 	newConfig.Env = c.Env
 	for _, repo := range c.Repositories {
 		newConfig.Repositories = append(newConfig.Repositories, v2.Repository{
-			Name:   repo.Name,
-			Type:   repo.Type,
-			Config: repo.Config,
+			Name:     repo.Name,
+			Type:     repo.Type,
+			Config:   repo.Config,
+			Priority: repo.Priority,
 		})
 	}
+	newConfig.Resolution = c.Resolution
 	newConfig.Autopilots = make(map[string]v2.Autopilot)
 	for name, autopilot := range c.Autopilots {
 		newConfig.Autopilots[name] = v2.Autopilot{
@@ -308,11 +318,13 @@ func (c *Config) Parse() (*configuration.ExecutionPlan, error) {
 		}
 		repositoryNames[repo.Name] = true
 		plan.Repositories = append(plan.Repositories, configuration.Repository{
-			Name:   repo.Name,
-			Type:   repo.Type,
-			Config: repo.Config,
+			Name:     repo.Name,
+			Type:     repo.Type,
+			Config:   repo.Config,
+			Priority: repo.Priority,
 		})
 	}
+	plan.Resolution = c.Resolution
 	for chapKey, v1chap := range c.Chapters {
 		chapter := configuration.Chapter{
 			Id:    chapKey,
@@ -0,0 +1,263 @@
+package diff
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/B-S-F/onyx/pkg/v2/result"
+)
+
+// Diff lists the changes between an old and a new result
+type Diff struct {
+	Old           Run           `json:"old"`
+	New           Run           `json:"new"`
+	OverallStatus Transition    `json:"overallStatus"`
+	Statistics    []Statistic   `json:"statistics"`
+	Chapters      []Change      `json:"chapters"`
+	Requirements  []Change      `json:"requirements"`
+	Checks        []CheckChange `json:"checks"`
+}
+
+// Run identifies the result of a run
+type Run struct {
+	Name    string `json:"name"`
+	Version string `json:"version"`
+	Date    string `json:"date"`
+}
+
+// Transition of a status, the old or the new status is empty if the item was added or removed
+type Transition struct {
+	From string `json:"from"`
+	To   string `json:"to"`
+}
+
+// Regression is set if the new status is worse than the old one
+func (t Transition) Regression() bool {
+	return rank(t.To) > rank(t.From)
+}
+
+// Statistic is the change of a statistic value
+type Statistic struct {
+	Name  string  `json:"name"`
+	Old   float64 `json:"old"`
+	New   float64 `json:"new"`
+	Delta float64 `json:"delta"`
+}
+
+// Change of a chapter or requirement, the id is <chapter> or <chapter>_<requirement>
+type Change struct {
+	ID    string `json:"id"`
+	Title string `json:"title"`
+	Transition
+}
+
+// CheckChange is the change of a check and its failed criteria, the id is <chapter>_<requirement>_<check>
+type CheckChange struct {
+	Change
+	// OldReason and NewReason are set if the reason changed
+	OldReason string `json:"oldReason,omitempty"`
+	NewReason string `json:"newReason,omitempty"`
+	// Failed lists the criteria which are not fulfilled in the new result, but were in the old result
+	Failed []Criterion `json:"failed,omitempty"`
+	// Resolved lists the criteria which were not fulfilled in the old result, but are in the new result
+	Resolved []Criterion `json:"resolved,omitempty"`
+}
+
+// Regression is set if the status of the check got worse or a criterion failed
+func (c CheckChange) Regression() bool {
+	return c.Transition.Regression() || len(c.Failed) > 0
+}
+
+// Criterion is a criterion which is not fulfilled, criteria are matched by their text and metadata
+type Criterion struct {
+	Criterion     string            `json:"criterion"`
+	Justification string            `json:"justification,omitempty"`
+	Metadata      map[string]string `json:"metadata,omitempty"`
+}
+
+func (c Criterion) key() string {
+	var builder strings.Builder
+	builder.WriteString(c.Criterion)
+	for _, key := range sortedKeys(c.Metadata) {
+		fmt.Fprintf(&builder, "\x00%s=%s", key, c.Metadata[key])
+	}
+	return builder.String()
+}
+
+// ranks orders the statuses from good to bad, statuses without a decision are ranked like GREEN
+var ranks = map[string]int{
+	"GREEN":      0,
+	"NA":         0,
+	"SKIPPED":    0,
+	"UNANSWERED": 1,
+	"YELLOW":     1,
+	"RED":        2,
+	"ERROR":      3,
+}
+
+func rank(status string) int {
+	return ranks[status]
+}
+
+// Compare compares two results, only changed chapters, requirements and checks are listed
+func Compare(oldResult, newResult *result.Result) *Diff {
+	diff := &Diff{
+		Old:           Run{Name: oldResult.Header.Name, Version: oldResult.Header.Version, Date: oldResult.Header.Date},
+		New:           Run{Name: newResult.Header.Name, Version: newResult.Header.Version, Date: newResult.Header.Date},
+		OverallStatus: Transition{From: oldResult.OverallStatus, To: newResult.OverallStatus},
+		Statistics:    compareStatistics(oldResult.Statistics, newResult.Statistics),
+		Chapters:      []Change{},
+		Requirements:  []Change{},
+		Checks:        []CheckChange{},
+	}
+	for _, chapterId := range union(oldResult.Chapters, newResult.Chapters) {
+		oldChapter, newChapter := oldResult.Chapters[chapterId], newResult.Chapters[chapterId]
+		if oldChapter == nil {
+			oldChapter = &result.Chapter{}
+		}
+		if newChapter == nil {
+			newChapter = &result.Chapter{}
+		}
+		if oldChapter.Status != newChapter.Status {
+			diff.Chapters = append(diff.Chapters, Change{ID: chapterId, Title: title(oldChapter.Title, newChapter.Title), Transition: Transition{From: oldChapter.Status, To: newChapter.Status}})
+		}
+		for _, requirementId := range union(oldChapter.Requirements, newChapter.Requirements) {
+			oldRequirement, newRequirement := oldChapter.Requirements[requirementId], newChapter.Requirements[requirementId]
+			if oldRequirement == nil {
+				oldRequirement = &result.Requirement{}
+			}
+			if newRequirement == nil {
+				newRequirement = &result.Requirement{}
+			}
+			id := chapterId + "_" + requirementId
+			if oldRequirement.Status != newRequirement.Status {
+				diff.Requirements = append(diff.Requirements, Change{ID: id, Title: title(oldRequirement.Title, newRequirement.Title), Transition: Transition{From: oldRequirement.Status, To: newRequirement.Status}})
+			}
+			for _, checkId := range union(oldRequirement.Checks, newRequirement.Checks) {
+				change, changed := compareCheck(id+"_"+checkId, oldRequirement.Checks[checkId], newRequirement.Checks[checkId])
+				if changed {
+					diff.Checks = append(diff.Checks, change)
+				}
+			}
+		}
+	}
+	return diff
+}
+
+func compareCheck(id string, oldCheck, newCheck *result.Check) (CheckChange, bool) {
+	if oldCheck == nil {
+		oldCheck = &result.Check{}
+	}
+	if newCheck == nil {
+		newCheck = &result.Check{}
+	}
+	change := CheckChange{Change: Change{ID: id, Title: title(oldCheck.Title, newCheck.Title), Transition: Transition{From: oldCheck.Evaluation.Status, To: newCheck.Evaluation.Status}}}
+	oldReason, newReason := strings.TrimSpace(oldCheck.Evaluation.Reason), strings.TrimSpace(newCheck.Evaluation.Reason)
+	if oldReason != newReason {
+		change.OldReason, change.NewReason = oldReason, newReason
+	}
+	oldFailed, newFailed := failedCriteria(oldCheck), failedCriteria(newCheck)
+	for key, criterion := range newFailed {
+		if _, ok := oldFailed[key]; !ok {
+			change.Failed = append(change.Failed, criterion)
+		}
+	}
+	for key, criterion := range oldFailed {
+		if _, ok := newFailed[key]; !ok {
+			change.Resolved = append(change.Resolved, criterion)
+		}
+	}
+	sortCriteria(change.Failed)
+	sortCriteria(change.Resolved)
+	changed := change.From != change.To || change.OldReason != change.NewReason || len(change.Failed) > 0 || len(change.Resolved) > 0
+	return change, changed
+}
+
+func failedCriteria(check *result.Check) map[string]Criterion {
+	criteria := make(map[string]Criterion)
+	for _, r := range check.Evaluation.Results {
+		if r.Fulfilled {
+			continue
+		}
+		criterion := Criterion{
+			Criterion:     strings.TrimSpace(string(r.Criterion)),
+			Justification: strings.TrimSpace(string(r.Justification)),
+			Metadata:      r.Metadata,
+		}
+		criteria[criterion.key()] = criterion
+	}
+	return criteria
+}
+
+func sortCriteria(criteria []Criterion) {
+	sort.Slice(criteria, func(i, j int) bool {
+		return criteria[i].key() < criteria[j].key()
+	})
+}
+
+func compareStatistics(oldStatistics, newStatistics result.Statistics) []Statistic {
+	values := []struct {
+		name     string
+		old, new float64
+	}{
+		{"counted-checks", float64(oldStatistics.CountChecks), float64(newStatistics.CountChecks)},
+		{"counted-automated-checks", float64(oldStatistics.CountAutomatedChecks), float64(newStatistics.CountAutomatedChecks)},
+		{"counted-manual-check", float64(oldStatistics.CountManualChecks), float64(newStatistics.CountManualChecks)},
+		{"counted-unanswered-checks", float64(oldStatistics.CountUnansweredChecks), float64(newStatistics.CountUnansweredChecks)},
+		{"counted-skipped-checks", float64(oldStatistics.CountSkippedChecks), float64(newStatistics.CountSkippedChecks)},
+		{"degree-of-automation", oldStatistics.PercentageAutomated, newStatistics.PercentageAutomated},
+		{"degree-of-completion", oldStatistics.PercentageDone, newStatistics.PercentageDone},
+	}
+	statistics := []Statistic{}
+	for _, value := range values {
+		if value.old != value.new {
+			statistics = append(statistics, Statistic{Name: value.name, Old: value.old, New: value.new, Delta: value.new - value.old})
+		}
+	}
+	return statistics
+}
+
+// Regressions returns the ids of the checks which regressed, "overall" if the overall status got worse
+func (d *Diff) Regressions() []string {
+	var regressions []string
+	if d.OverallStatus.Regression() {
+		regressions = append(regressions, "overall")
+	}
+	for _, check := range d.Checks {
+		if check.Regression() {
+			regressions = append(regressions, check.ID)
+		}
+	}
+	return regressions
+}
+
+func union[T any](oldIds, newIds map[string]T) []string {
+	ids := make([]string, 0, len(oldIds)+len(newIds))
+	for id := range oldIds {
+		ids = append(ids, id)
+	}
+	for id := range newIds {
+		if _, ok := oldIds[id]; !ok {
+			ids = append(ids, id)
+		}
+	}
+	result.SortIDs(ids)
+	return ids
+}
+
+func sortedKeys(m map[string]string) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func title(oldTitle, newTitle string) string {
+	if newTitle != "" {
+		return newTitle
+	}
+	return oldTitle
+}
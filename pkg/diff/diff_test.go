@@ -0,0 +1,160 @@
+package diff
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/B-S-F/onyx/pkg/result/common"
+	"github.com/B-S-F/onyx/pkg/v2/result"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func check(status string, reason string, results ...result.EvaluationResult) *result.Check {
+	return &result.Check{Title: "check", Type: "automation", Evaluation: result.Evaluation{Status: status, Reason: reason, Results: results}}
+}
+
+func ticket(id string, fulfilled bool) result.EvaluationResult {
+	return result.EvaluationResult{Criterion: "No open tickets", Justification: common.MultilineString("ticket " + id), Fulfilled: fulfilled, Metadata: common.StringMap{"ticket": id}}
+}
+
+func testResult(version, overallStatus string, checks map[string]*result.Check) *result.Result {
+	return &result.Result{
+		Header:        result.Header{Name: "My Project", Version: version, Date: "2024-01-01"},
+		OverallStatus: overallStatus,
+		Statistics:    result.Statistics{CountChecks: uint(len(checks)), PercentageDone: 50},
+		Chapters: map[string]*result.Chapter{
+			"1": {Title: "Chapter", Status: overallStatus, Requirements: map[string]*result.Requirement{
+				"1": {Title: "Requirement", Status: overallStatus, Checks: checks},
+			}},
+		},
+	}
+}
+
+func TestCompare(t *testing.T) {
+	oldResult := testResult("1.0", "YELLOW", map[string]*result.Check{
+		"1":  check("GREEN", "all good"),
+		"2":  check("RED", "one ticket", ticket("1", false)),
+		"10": check("YELLOW", "warning"),
+		"3":  check("GREEN", "removed"),
+	})
+	newResult := testResult("1.1", "RED", map[string]*result.Check{
+		"1":  check("GREEN", "all good"),
+		"2":  check("RED", "one ticket", ticket("1", true), ticket("2", false)),
+		"10": check("GREEN", "fixed"),
+		"4":  check("ERROR", "added"),
+	})
+	newResult.Statistics.PercentageDone = 75
+
+	diff := Compare(oldResult, newResult)
+
+	assert.Equal(t, Run{Name: "My Project", Version: "1.0", Date: "2024-01-01"}, diff.Old)
+	assert.Equal(t, Transition{From: "YELLOW", To: "RED"}, diff.OverallStatus)
+	assert.Equal(t, []Statistic{{Name: "degree-of-completion", Old: 50, New: 75, Delta: 25}}, diff.Statistics)
+	assert.Equal(t, []Change{{ID: "1", Title: "Chapter", Transition: Transition{From: "YELLOW", To: "RED"}}}, diff.Chapters)
+	assert.Equal(t, []Change{{ID: "1_1", Title: "Requirement", Transition: Transition{From: "YELLOW", To: "RED"}}}, diff.Requirements)
+
+	ids := []string{}
+	for _, c := range diff.Checks {
+		ids = append(ids, c.ID)
+	}
+	require.Equal(t, []string{"1_1_2", "1_1_3", "1_1_4", "1_1_10"}, ids)
+
+	tickets := diff.Checks[0]
+	assert.Equal(t, Transition{From: "RED", To: "RED"}, tickets.Transition)
+	assert.Equal(t, []Criterion{{Criterion: "No open tickets", Justification: "ticket 2", Metadata: map[string]string{"ticket": "2"}}}, tickets.Failed)
+	assert.Equal(t, []Criterion{{Criterion: "No open tickets", Justification: "ticket 1", Metadata: map[string]string{"ticket": "1"}}}, tickets.Resolved)
+	assert.True(t, tickets.Regression())
+
+	removed := diff.Checks[1]
+	assert.Equal(t, Transition{From: "GREEN", To: ""}, removed.Transition)
+	assert.False(t, removed.Regression())
+
+	added := diff.Checks[2]
+	assert.Equal(t, Transition{From: "", To: "ERROR"}, added.Transition)
+	assert.True(t, added.Regression())
+
+	fixed := diff.Checks[3]
+	assert.Equal(t, "warning", fixed.OldReason)
+	assert.Equal(t, "fixed", fixed.NewReason)
+	assert.False(t, fixed.Regression())
+
+	assert.Equal(t, []string{"overall", "1_1_2", "1_1_4"}, diff.Regressions())
+}
+
+func TestCompareUnchanged(t *testing.T) {
+	res := testResult("1.0", "GREEN", map[string]*result.Check{"1": check("GREEN", "all good", ticket("1", true))})
+
+	diff := Compare(res, res)
+
+	assert.Empty(t, diff.Statistics)
+	assert.Empty(t, diff.Chapters)
+	assert.Empty(t, diff.Requirements)
+	assert.Empty(t, diff.Checks)
+	assert.Empty(t, diff.Regressions())
+}
+
+func TestTransitionRegression(t *testing.T) {
+	testCases := map[string]struct {
+		transition Transition
+		regression bool
+	}{
+		"green to red":        {Transition{From: "GREEN", To: "RED"}, true},
+		"red to error":        {Transition{From: "RED", To: "ERROR"}, true},
+		"na to unanswered":    {Transition{From: "NA", To: "UNANSWERED"}, true},
+		"red to yellow":       {Transition{From: "RED", To: "YELLOW"}, false},
+		"green to skipped":    {Transition{From: "GREEN", To: "SKIPPED"}, false},
+		"red removed":         {Transition{From: "RED", To: ""}, false},
+		"added as yellow":     {Transition{From: "", To: "YELLOW"}, true},
+		"unchanged as yellow": {Transition{From: "YELLOW", To: "YELLOW"}, false},
+	}
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			assert.Equal(t, tc.regression, tc.transition.Regression())
+		})
+	}
+}
+
+func TestRender(t *testing.T) {
+	oldResult := testResult("1.0", "GREEN", map[string]*result.Check{"1": check("GREEN", "all good", ticket("1", true))})
+	newResult := testResult("1.1", "RED", map[string]*result.Check{"1": check("RED", "open | tickets", ticket("1", false))})
+	diff := Compare(oldResult, newResult)
+
+	t.Run("should render text", func(t *testing.T) {
+		content, err := Render(diff, FORMAT_TEXT)
+
+		require.NoError(t, err)
+		text := string(content)
+		assert.Contains(t, text, "My Project 1.0 (2024-01-01) -> My Project 1.1 (2024-01-01)\n")
+		assert.Contains(t, text, "overall status: GREEN -> RED\n")
+		assert.Contains(t, text, "! 1_1_1 check: GREEN -> RED\n")
+		assert.Contains(t, text, `    reason: "all good" -> "open | tickets"`)
+		assert.Contains(t, text, "    + failed: No open tickets: ticket 1 [ticket=1]\n")
+		assert.Contains(t, text, "2 regressions: overall, 1_1_1\n")
+	})
+
+	t.Run("should render markdown", func(t *testing.T) {
+		content, err := Render(diff, FORMAT_MARKDOWN)
+
+		require.NoError(t, err)
+		markdown := string(content)
+		assert.Contains(t, markdown, "**2 regressions**: overall, 1_1_1\n")
+		assert.Contains(t, markdown, "| 1_1 Requirement | GREEN -> RED |\n")
+		assert.Contains(t, markdown, "| **1_1_1** check | GREEN -> RED | reason: all good → open \\| tickets<br>❌ No open tickets: ticket 1 [ticket=1] |\n")
+	})
+
+	t.Run("should render json", func(t *testing.T) {
+		content, err := Render(diff, FORMAT_JSON)
+
+		require.NoError(t, err)
+		var decoded Diff
+		require.NoError(t, json.Unmarshal(content, &decoded))
+		assert.Equal(t, *diff, decoded)
+	})
+
+	t.Run("should reject unsupported formats", func(t *testing.T) {
+		_, err := Render(diff, "html")
+
+		assert.EqualError(t, err, "unsupported diff format 'html', must be one of: text, json, markdown")
+	})
+}
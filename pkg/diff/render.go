@@ -0,0 +1,177 @@
+package diff
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+const (
+	FORMAT_TEXT     = "text"
+	FORMAT_JSON     = "json"
+	FORMAT_MARKDOWN = "markdown"
+)
+
+// Formats lists the formats a diff can be rendered in
+var Formats = []string{FORMAT_TEXT, FORMAT_JSON, FORMAT_MARKDOWN}
+
+// Render renders the diff in the format
+func Render(d *Diff, format string) ([]byte, error) {
+	switch format {
+	case FORMAT_TEXT:
+		return []byte(d.Text()), nil
+	case FORMAT_JSON:
+		content, err := json.MarshalIndent(d, "", "  ")
+		if err != nil {
+			return nil, err
+		}
+		return append(content, '\n'), nil
+	case FORMAT_MARKDOWN:
+		return []byte(d.Markdown()), nil
+	default:
+		return nil, fmt.Errorf("unsupported diff format '%s', must be one of: %s", format, strings.Join(Formats, ", "))
+	}
+}
+
+// Text renders the diff as plain text
+func (d *Diff) Text() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "%s -> %s\n", d.Old, d.New)
+	fmt.Fprintf(&b, "overall status: %s\n", transition(d.OverallStatus))
+	if len(d.Statistics) > 0 {
+		b.WriteString("\nstatistics:\n")
+		for _, s := range d.Statistics {
+			fmt.Fprintf(&b, "  %s: %g -> %g (%+g)\n", s.Name, s.Old, s.New, s.Delta)
+		}
+	}
+	for _, section := range []struct {
+		name    string
+		changes []Change
+	}{{"chapters", d.Chapters}, {"requirements", d.Requirements}} {
+		if len(section.changes) == 0 {
+			continue
+		}
+		fmt.Fprintf(&b, "\n%s:\n", section.name)
+		for _, c := range section.changes {
+			fmt.Fprintf(&b, "  %s: %s\n", label(c.ID, c.Title), transition(c.Transition))
+		}
+	}
+	if len(d.Checks) > 0 {
+		b.WriteString("\nchecks:\n")
+		for _, c := range d.Checks {
+			marker := " "
+			if c.Regression() {
+				marker = "!"
+			}
+			fmt.Fprintf(&b, "%s %s: %s\n", marker, label(c.ID, c.Title), transition(c.Transition))
+			if c.OldReason != c.NewReason {
+				fmt.Fprintf(&b, "    reason: %q -> %q\n", c.OldReason, c.NewReason)
+			}
+			for _, criterion := range c.Failed {
+				fmt.Fprintf(&b, "    + failed: %s\n", describe(criterion))
+			}
+			for _, criterion := range c.Resolved {
+				fmt.Fprintf(&b, "    - resolved: %s\n", describe(criterion))
+			}
+		}
+	}
+	if regressions := d.Regressions(); len(regressions) > 0 {
+		fmt.Fprintf(&b, "\n%d regressions: %s\n", len(regressions), strings.Join(regressions, ", "))
+	} else {
+		b.WriteString("\nno regressions\n")
+	}
+	return b.String()
+}
+
+// Markdown renders the diff as Markdown for pull request comments
+func (d *Diff) Markdown() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "## %s → %s: %s\n\n", d.Old, d.New, transition(d.OverallStatus))
+	if regressions := d.Regressions(); len(regressions) > 0 {
+		fmt.Fprintf(&b, "**%d regressions**: %s\n\n", len(regressions), strings.Join(regressions, ", "))
+	} else {
+		b.WriteString("No regressions\n\n")
+	}
+	if len(d.Statistics) > 0 {
+		b.WriteString("| Statistic | Old | New | Delta |\n| --- | --- | --- | --- |\n")
+		for _, s := range d.Statistics {
+			fmt.Fprintf(&b, "| %s | %g | %g | %+g |\n", s.Name, s.Old, s.New, s.Delta)
+		}
+		b.WriteString("\n")
+	}
+	if len(d.Chapters)+len(d.Requirements) > 0 {
+		b.WriteString("| Chapter / Requirement | Status |\n| --- | --- |\n")
+		for _, c := range append(append([]Change{}, d.Chapters...), d.Requirements...) {
+			fmt.Fprintf(&b, "| %s | %s |\n", cell(label(c.ID, c.Title)), transition(c.Transition))
+		}
+		b.WriteString("\n")
+	}
+	if len(d.Checks) > 0 {
+		b.WriteString("| Check | Status | Changes |\n| --- | --- | --- |\n")
+		for _, c := range d.Checks {
+			var changes []string
+			if c.OldReason != c.NewReason {
+				changes = append(changes, fmt.Sprintf("reason: %s → %s", cell(c.OldReason), cell(c.NewReason)))
+			}
+			for _, criterion := range c.Failed {
+				changes = append(changes, "❌ "+cell(describe(criterion)))
+			}
+			for _, criterion := range c.Resolved {
+				changes = append(changes, "✅ "+cell(describe(criterion)))
+			}
+			id := c.ID
+			if c.Regression() {
+				id = "**" + id + "**"
+			}
+			fmt.Fprintf(&b, "| %s | %s | %s |\n", cell(label(id, c.Title)), transition(c.Transition), strings.Join(changes, "<br>"))
+		}
+	}
+	return b.String()
+}
+
+// String returns the name, version and date of the run
+func (r Run) String() string {
+	s := strings.TrimSpace(r.Name + " " + r.Version)
+	if r.Date != "" {
+		s += " (" + r.Date + ")"
+	}
+	return s
+}
+
+func label(id, title string) string {
+	return strings.TrimSpace(id + " " + title)
+}
+
+func transition(t Transition) string {
+	from, to := t.From, t.To
+	if from == "" {
+		from = "(new)"
+	}
+	if to == "" {
+		to = "(removed)"
+	}
+	if t.From == t.To {
+		return to
+	}
+	return from + " -> " + to
+}
+
+func describe(c Criterion) string {
+	var metadata []string
+	for _, key := range sortedKeys(c.Metadata) {
+		metadata = append(metadata, key+"="+c.Metadata[key])
+	}
+	text := c.Criterion
+	if c.Justification != "" {
+		text += ": " + c.Justification
+	}
+	if len(metadata) > 0 {
+		text += " [" + strings.Join(metadata, ", ") + "]"
+	}
+	return text
+}
+
+// cell escapes a value for a Markdown table cell, line breaks become spaces
+func cell(value string) string {
+	return strings.Join(strings.Fields(strings.ReplaceAll(value, "|", "\\|")), " ")
+}
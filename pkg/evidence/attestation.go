@@ -0,0 +1,213 @@
+package evidence
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	_ "crypto/sha512" // registers SHA-384 and SHA-512 for ECDSA keys
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+
+	"github.com/B-S-F/onyx/pkg/helper"
+	"github.com/B-S-F/onyx/pkg/zip"
+)
+
+const (
+	STATEMENT_TYPE = "https://in-toto.io/Statement/v1"
+	PREDICATE_TYPE = "https://github.com/B-S-F/onyx/evidence-manifest/v1"
+	// PAYLOAD_TYPE is the type of the payload of the DSSE envelope
+	PAYLOAD_TYPE = "application/vnd.in-toto+json"
+)
+
+// Statement is an in-toto statement about the manifest and the result file of the evidence
+type Statement struct {
+	Type          string    `json:"_type"`
+	Subject       []Subject `json:"subject"`
+	PredicateType string    `json:"predicateType"`
+	Predicate     Predicate `json:"predicate"`
+}
+
+type Subject struct {
+	Name   string            `json:"name"`
+	Digest map[string]string `json:"digest"`
+}
+
+type Predicate struct {
+	OnyxVersion string `json:"onyxVersion"`
+	// Manifest is the path of the manifest in the evidence archive
+	Manifest string `json:"manifest"`
+}
+
+// Envelope is a DSSE envelope, see https://github.com/secure-systems-lab/dsse
+type Envelope struct {
+	PayloadType string      `json:"payloadType"`
+	Payload     string      `json:"payload"`
+	Signatures  []Signature `json:"signatures"`
+}
+
+type Signature struct {
+	// KeyID is the hex encoded SHA-256 of the DER encoded public key
+	KeyID string `json:"keyid"`
+	Sig   string `json:"sig"`
+}
+
+// NewStatement creates the statement about the archived manifest and result file
+func NewStatement(entries []zip.Entry, resultFile string) (Statement, error) {
+	statement := Statement{
+		Type:          STATEMENT_TYPE,
+		PredicateType: PREDICATE_TYPE,
+		Predicate:     Predicate{OnyxVersion: helper.ToolVersion, Manifest: MANIFEST_FILE},
+	}
+	var manifestFound bool
+	for _, entry := range entries {
+		if entry.Name != MANIFEST_FILE && entry.Name != resultFile {
+			continue
+		}
+		manifestFound = manifestFound || entry.Name == MANIFEST_FILE
+		statement.Subject = append(statement.Subject, Subject{Name: entry.Name, Digest: map[string]string{"sha256": entry.SHA256}})
+	}
+	if !manifestFound {
+		return Statement{}, fmt.Errorf("evidence manifest '%s' is not archived", MANIFEST_FILE)
+	}
+	return statement, nil
+}
+
+// Signer signs attestations with an ed25519 or ECDSA private key
+type Signer struct {
+	key   crypto.Signer
+	keyID string
+}
+
+// NewSigner parses a PEM encoded PKCS #8 or SEC 1 private key
+func NewSigner(key []byte) (*Signer, error) {
+	block, _ := pem.Decode(key)
+	if block == nil {
+		return nil, fmt.Errorf("signing key is not PEM encoded")
+	}
+	var parsed interface{}
+	var err error
+	switch block.Type {
+	case "PRIVATE KEY":
+		parsed, err = x509.ParsePKCS8PrivateKey(block.Bytes)
+	case "EC PRIVATE KEY":
+		parsed, err = x509.ParseECPrivateKey(block.Bytes)
+	default:
+		return nil, fmt.Errorf("unsupported PEM block '%s' of signing key, expected a private key", block.Type)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error parsing signing key: %w", err)
+	}
+	var signer crypto.Signer
+	switch key := parsed.(type) {
+	case ed25519.PrivateKey:
+		signer = key
+	case *ecdsa.PrivateKey:
+		signer = key
+	default:
+		return nil, fmt.Errorf("unsupported signing key type %T, expected an ed25519 or ECDSA key", parsed)
+	}
+	keyID, err := KeyID(signer.Public())
+	if err != nil {
+		return nil, err
+	}
+	return &Signer{key: signer, keyID: keyID}, nil
+}
+
+// KeyID returns the id of a public key as used in the signatures
+func KeyID(key crypto.PublicKey) (string, error) {
+	der, err := x509.MarshalPKIXPublicKey(key)
+	if err != nil {
+		return "", fmt.Errorf("error encoding public key: %w", err)
+	}
+	digest := sha256.Sum256(der)
+	return hex.EncodeToString(digest[:]), nil
+}
+
+// Sign returns the DSSE envelope with the signed statement
+func (s *Signer) Sign(statement Statement) ([]byte, error) {
+	payload, err := json.Marshal(statement)
+	if err != nil {
+		return nil, err
+	}
+	message := pae(PAYLOAD_TYPE, payload)
+	var signature []byte
+	switch key := s.key.(type) {
+	case ed25519.PrivateKey:
+		signature, err = key.Sign(rand.Reader, message, crypto.Hash(0))
+	case *ecdsa.PrivateKey:
+		hash := ecdsaHash(key.Curve)
+		digest := hash.New()
+		digest.Write(message)
+		signature, err = key.Sign(rand.Reader, digest.Sum(nil), hash)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return json.MarshalIndent(Envelope{
+		PayloadType: PAYLOAD_TYPE,
+		Payload:     base64.StdEncoding.EncodeToString(payload),
+		Signatures:  []Signature{{KeyID: s.keyID, Sig: base64.StdEncoding.EncodeToString(signature)}},
+	}, "", "  ")
+}
+
+// ParsePublicKey parses a PEM encoded PKIX public key, the public key of a private key is accepted as well
+func ParsePublicKey(key []byte) (crypto.PublicKey, error) {
+	block, _ := pem.Decode(key)
+	if block == nil {
+		return nil, fmt.Errorf("public key is not PEM encoded")
+	}
+	if block.Type != "PUBLIC KEY" {
+		signer, err := NewSigner(key)
+		if err != nil {
+			return nil, err
+		}
+		return signer.key.Public(), nil
+	}
+	parsed, err := x509.ParsePKIXPublicKey(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("error parsing public key: %w", err)
+	}
+	switch parsed.(type) {
+	case ed25519.PublicKey, *ecdsa.PublicKey:
+		return parsed, nil
+	default:
+		return nil, fmt.Errorf("unsupported public key type %T, expected an ed25519 or ECDSA key", parsed)
+	}
+}
+
+// verifySignature checks a signature of the envelope payload
+func verifySignature(key crypto.PublicKey, payloadType string, payload []byte, signature []byte) bool {
+	message := pae(payloadType, payload)
+	switch key := key.(type) {
+	case ed25519.PublicKey:
+		return ed25519.Verify(key, message, signature)
+	case *ecdsa.PublicKey:
+		digest := ecdsaHash(key.Curve).New()
+		digest.Write(message)
+		return ecdsa.VerifyASN1(key, digest.Sum(nil), signature)
+	}
+	return false
+}
+
+// pae is the pre-authentication encoding of DSSE, which is signed instead of the payload
+func pae(payloadType string, payload []byte) []byte {
+	return []byte(fmt.Sprintf("DSSEv1 %d %s %d %s", len(payloadType), payloadType, len(payload), payload))
+}
+
+func ecdsaHash(curve elliptic.Curve) crypto.Hash {
+	switch curve.Params().BitSize {
+	case 384:
+		return crypto.SHA384
+	case 521:
+		return crypto.SHA512
+	default:
+		return crypto.SHA256
+	}
+}
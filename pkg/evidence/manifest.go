@@ -0,0 +1,90 @@
+package evidence
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+
+	"github.com/B-S-F/onyx/pkg/zip"
+)
+
+const (
+	// MANIFEST_FILE lists the digests of all files of the evidence, it follows the files of the evidence archive
+	MANIFEST_FILE = "evidence-manifest.json"
+	// ATTESTATION_FILE is the signed in-toto statement about the manifest and the result file
+	ATTESTATION_FILE = "evidence-attestation.json"
+	// MANIFEST_VERSION is the version of the manifest format
+	MANIFEST_VERSION = "v1"
+)
+
+// Manifest lists the files of the evidence with their digests
+type Manifest struct {
+	Version string `json:"version"`
+	// Result is the result file of the run, it is also listed in the files
+	Result *File `json:"result,omitempty"`
+	// Files sorted by their path
+	Files []File `json:"files"`
+}
+
+// File is a file of the evidence
+type File struct {
+	// Path relative to the root of the evidence, separated by slashes
+	Path   string `json:"path"`
+	Size   int64  `json:"size"`
+	SHA256 string `json:"sha256"`
+	// Truncated is set if only the first bytes of the file are archived because of the maximum file size
+	Truncated bool `json:"truncated,omitempty"`
+}
+
+// NewManifest creates the manifest of the archived entries, the result file is recorded separately if it is archived
+func NewManifest(entries []zip.Entry, resultFile string) Manifest {
+	manifest := Manifest{Version: MANIFEST_VERSION, Files: make([]File, 0, len(entries))}
+	for _, entry := range entries {
+		file := File{Path: entry.Name, Size: entry.Size, SHA256: entry.SHA256, Truncated: entry.Truncated}
+		manifest.Files = append(manifest.Files, file)
+		if entry.Name == resultFile {
+			result := file
+			manifest.Result = &result
+		}
+	}
+	sort.Slice(manifest.Files, func(i, j int) bool {
+		return manifest.Files[i].Path < manifest.Files[j].Path
+	})
+	return manifest
+}
+
+// File returns the file of the manifest with the path
+func (m Manifest) File(path string) (File, bool) {
+	for _, file := range m.Files {
+		if file.Path == path {
+			return file, true
+		}
+	}
+	return File{}, false
+}
+
+// ManifestAppendix adds the manifest of all files which are archived before it
+func ManifestAppendix(resultFile string) zip.Appendix {
+	return func(entries []zip.Entry) (string, []byte, error) {
+		content, err := json.MarshalIndent(NewManifest(entries, resultFile), "", "  ")
+		if err != nil {
+			return "", nil, fmt.Errorf("error creating evidence manifest: %w", err)
+		}
+		return MANIFEST_FILE, content, nil
+	}
+}
+
+// AttestationAppendix adds the attestation of the manifest, it has to follow the manifest appendix
+func AttestationAppendix(signer *Signer, resultFile string) zip.Appendix {
+	return func(entries []zip.Entry) (string, []byte, error) {
+		statement, err := NewStatement(entries, resultFile)
+		if err != nil {
+			return "", nil, err
+		}
+		content, err := signer.Sign(statement)
+		if err != nil {
+			return "", nil, fmt.Errorf("error signing evidence attestation: %w", err)
+		}
+		return ATTESTATION_FILE, content, nil
+	}
+}
@@ -0,0 +1,247 @@
+package evidence
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/B-S-F/onyx/pkg/helper"
+)
+
+const (
+	// PolicyRedact replaces the findings in the evidence files
+	PolicyRedact = "redact"
+	// PolicyFail keeps the evidence files and fails the run without providing the evidence
+	PolicyFail = "fail"
+	// PolicyOff disables the scan
+	PolicyOff = "off"
+
+	// SecretRule is the rule of findings of known secret values
+	SecretRule = "secret"
+	// UnscannedRule is the rule of findings of files which are too large to be scanned
+	UnscannedRule = "unscanned"
+
+	// binarySniffSize is the length of the prefix which is checked for NUL bytes, like git detects binary files
+	binarySniffSize = 8000
+)
+
+// maxFileSize is the size of the largest file which is read for the scan
+var maxFileSize int64 = 64 << 20
+
+// BuiltinRules are patterns of well known credentials which can be enabled by name
+var BuiltinRules = map[string]string{
+	"private-key":    `(?s)-----BEGIN[A-Z0-9 ]*PRIVATE KEY( BLOCK)?-----(.*?-----END[A-Z0-9 ]*PRIVATE KEY( BLOCK)?-----)?`,
+	"jwt":            `eyJ[A-Za-z0-9_-]{4,}\.eyJ[A-Za-z0-9_-]{4,}\.[A-Za-z0-9_-]{8,}`,
+	"aws-access-key": `\b(AKIA|ASIA)[0-9A-Z]{16}\b`,
+	"github-token":   `\b(gh[pousr]_[A-Za-z0-9]{36,}|github_pat_[A-Za-z0-9_]{22,})\b`,
+}
+
+// Rule finds credentials which are not known secret values
+type Rule struct {
+	Name    string
+	Pattern *regexp.Regexp
+}
+
+// ParseRules parses the names of builtin rules and custom rules given as <name>=<regexp>
+func ParseRules(values []string) ([]Rule, error) {
+	rules := make([]Rule, 0, len(values))
+	for _, value := range values {
+		name, pattern, custom := strings.Cut(value, "=")
+		name = strings.TrimSpace(name)
+		if !custom {
+			builtin, ok := BuiltinRules[name]
+			if !ok {
+				return nil, fmt.Errorf("unknown evidence scan rule '%s', expected one of %s or <name>=<regexp>", name, strings.Join(BuiltinRuleNames(), ", "))
+			}
+			pattern = builtin
+		}
+		if name == "" || name == SecretRule {
+			return nil, fmt.Errorf("invalid name of evidence scan rule '%s'", value)
+		}
+		compiled, err := regexp.Compile(pattern)
+		if err != nil {
+			return nil, fmt.Errorf("invalid pattern of evidence scan rule '%s': %w", name, err)
+		}
+		rules = append(rules, Rule{Name: name, Pattern: compiled})
+	}
+	return rules, nil
+}
+
+// BuiltinRuleNames returns the sorted names of the builtin rules
+func BuiltinRuleNames() []string {
+	names := make([]string, 0, len(BuiltinRules))
+	for name := range BuiltinRules {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Finding is a secret in an evidence file, the value itself is never reported
+type Finding struct {
+	// File path relative to the scanned directory
+	File string
+	// Offset of the secret in bytes in the original file
+	Offset int
+	// Rule which found the secret
+	Rule string
+	// Secret is the name of the secret for findings of the secret rule
+	Secret string
+	// Redacted is set if the secret was replaced in the file
+	Redacted bool
+}
+
+type Scanner struct {
+	masker *helper.Masker
+	rules  []Rule
+	policy string
+	keep   []string
+}
+
+// NewScanner creates a scanner for the secret values and rules, kept files are relative to the scanned directory,
+// they are scanned but never redacted, e.g. the result file which is written by onyx itself
+func NewScanner(secrets map[string]string, rules []Rule, policy string, keep []string) *Scanner {
+	return &Scanner{
+		masker: helper.NewMasker(secrets),
+		rules:  rules,
+		policy: policy,
+		keep:   keep,
+	}
+}
+
+// Scan checks all regular files in the directory, findings are redacted if the policy is redact
+func (s *Scanner) Scan(directory string) ([]Finding, error) {
+	var findings []Finding
+	if s.policy == PolicyOff {
+		return findings, nil
+	}
+	err := filepath.WalkDir(directory, func(path string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if !entry.Type().IsRegular() {
+			return nil
+		}
+		relative, err := filepath.Rel(directory, path)
+		if err != nil {
+			return err
+		}
+		fileFindings, err := s.scanFile(path, filepath.ToSlash(relative), !s.kept(relative))
+		if err != nil {
+			return fmt.Errorf("error scanning %s: %w", relative, err)
+		}
+		findings = append(findings, fileFindings...)
+		return nil
+	})
+	return findings, err
+}
+
+func (s *Scanner) kept(relative string) bool {
+	for _, kept := range s.keep {
+		if filepath.Clean(kept) == relative {
+			return true
+		}
+	}
+	return false
+}
+
+type span struct {
+	start       int
+	end         int
+	replacement string
+}
+
+// scanFile finds the secrets in the file, binary files are never redacted as replacing bytes would corrupt them
+func (s *Scanner) scanFile(path string, relative string, redactable bool) ([]Finding, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+	content, err := io.ReadAll(io.LimitReader(file, maxFileSize+1))
+	if err != nil {
+		return nil, err
+	}
+	if int64(len(content)) > maxFileSize {
+		return []Finding{{File: relative, Rule: UnscannedRule}}, nil
+	}
+	if bytes.IndexByte(content[:min(len(content), binarySniffSize)], 0) >= 0 {
+		redactable = false
+	}
+	text := string(content)
+	var findings []Finding
+	var spans []span
+	for _, match := range s.masker.Find(text) {
+		findings = append(findings, Finding{File: relative, Offset: match.Start, Rule: SecretRule, Secret: match.Name})
+		spans = append(spans, span{match.Start, match.End, fmt.Sprintf("***%s***", match.Name)})
+	}
+	for _, rule := range s.rules {
+		for _, match := range rule.Pattern.FindAllStringIndex(text, -1) {
+			if match[0] == match[1] {
+				continue
+			}
+			findings = append(findings, Finding{File: relative, Offset: match[0], Rule: rule.Name})
+			spans = append(spans, span{match[0], match[1], fmt.Sprintf("***%s***", rule.Name)})
+		}
+	}
+	if len(findings) == 0 {
+		return nil, nil
+	}
+	sort.SliceStable(findings, func(i, j int) bool {
+		return findings[i].Offset < findings[j].Offset
+	})
+	if s.policy != PolicyRedact || !redactable {
+		return findings, nil
+	}
+	if err := redact(path, text, spans); err != nil {
+		return nil, err
+	}
+	for i := range findings {
+		findings[i].Redacted = true
+	}
+	return findings, nil
+}
+
+// redact replaces the spans in the file, overlapping spans are replaced together, read-only files stay read-only
+func redact(path string, text string, spans []span) error {
+	sort.Slice(spans, func(i, j int) bool {
+		if spans[i].start != spans[j].start {
+			return spans[i].start < spans[j].start
+		}
+		return spans[i].end > spans[j].end
+	})
+	var redacted strings.Builder
+	position := 0
+	for i := 0; i < len(spans); {
+		current := spans[i]
+		i++
+		for i < len(spans) && spans[i].start < current.end {
+			if spans[i].end > current.end {
+				current.end = spans[i].end
+			}
+			i++
+		}
+		redacted.WriteString(text[position:current.start])
+		redacted.WriteString(current.replacement)
+		position = current.end
+	}
+	redacted.WriteString(text[position:])
+
+	info, err := os.Stat(path)
+	if err != nil {
+		return err
+	}
+	if err := os.Chmod(path, info.Mode().Perm()|0200); err != nil {
+		return err
+	}
+	if err := os.WriteFile(path, []byte(redacted.String()), info.Mode().Perm()); err != nil {
+		return err
+	}
+	return os.Chmod(path, info.Mode().Perm())
+}
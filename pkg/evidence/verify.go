@@ -0,0 +1,181 @@
+package evidence
+
+import (
+	"bytes"
+	"crypto"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/B-S-F/onyx/pkg/zip"
+)
+
+// Verification is the outcome of verifying an evidence archive, the evidence is valid if there are no problems
+type Verification struct {
+	// Files is the number of files listed in the manifest
+	Files int
+	// Result is the result file recorded in the manifest
+	Result *File
+	// Attested is set if the evidence contains an attestation which matches the manifest
+	Attested bool
+	// KeyID of the key which verified the signature of the attestation, empty if no key was given
+	KeyID    string
+	Problems []string
+}
+
+func (v *Verification) problem(format string, args ...interface{}) {
+	v.Problems = append(v.Problems, fmt.Sprintf(format, args...))
+}
+
+// Verify checks the files of the evidence archive against its manifest and the attestation against the manifest
+// The result file is checked against the digest in the manifest if given. With keys, a valid signature is required
+func Verify(evidenceFile string, resultFile string, keys []crypto.PublicKey) (*Verification, error) {
+	digests := make(map[string]File)
+	contents := make(map[string][]byte)
+	verification := &Verification{}
+	err := zip.ReadEntries(evidenceFile, func(name string, content io.Reader) error {
+		if _, ok := digests[name]; ok {
+			verification.problem("file '%s' is archived more than once", name)
+			return nil
+		}
+		hash := sha256.New()
+		writer := io.Writer(hash)
+		var buffer bytes.Buffer
+		if name == MANIFEST_FILE || name == ATTESTATION_FILE {
+			writer = io.MultiWriter(hash, &buffer)
+		}
+		size, err := io.Copy(writer, content)
+		if err != nil {
+			return fmt.Errorf("error reading '%s' of evidence: %w", name, err)
+		}
+		digests[name] = File{Path: name, Size: size, SHA256: hex.EncodeToString(hash.Sum(nil))}
+		contents[name] = buffer.Bytes()
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error reading evidence '%s': %w", evidenceFile, err)
+	}
+
+	manifestContent, ok := contents[MANIFEST_FILE]
+	if !ok {
+		return nil, fmt.Errorf("error reading evidence manifest: '%s' does not exist", MANIFEST_FILE)
+	}
+	var manifest Manifest
+	if err := json.Unmarshal(manifestContent, &manifest); err != nil {
+		return nil, fmt.Errorf("error parsing evidence manifest: %w", err)
+	}
+	if manifest.Version != MANIFEST_VERSION {
+		return nil, fmt.Errorf("unsupported evidence manifest version '%s'", manifest.Version)
+	}
+	verification.Files = len(manifest.Files)
+	verification.Result = manifest.Result
+	verifyFiles(verification, manifest, digests)
+	if resultFile != "" {
+		verifyResultFile(verification, manifest, resultFile)
+	}
+
+	if _, ok := digests[ATTESTATION_FILE]; !ok {
+		if len(keys) > 0 {
+			verification.problem("evidence has no attestation '%s'", ATTESTATION_FILE)
+		}
+		return verification, nil
+	}
+	verifyAttestation(verification, contents[ATTESTATION_FILE], digests, manifest, keys)
+	return verification, nil
+}
+
+func verifyFiles(verification *Verification, manifest Manifest, digests map[string]File) {
+	listed := make(map[string]bool, len(manifest.Files))
+	for _, file := range manifest.Files {
+		listed[file.Path] = true
+		archived, ok := digests[file.Path]
+		switch {
+		case !ok:
+			verification.problem("file '%s' is missing", file.Path)
+		case archived.Size != file.Size || archived.SHA256 != file.SHA256:
+			verification.problem("file '%s' was modified, expected sha256 %s but got %s", file.Path, file.SHA256, archived.SHA256)
+		}
+	}
+	for path := range digests {
+		if !listed[path] && path != MANIFEST_FILE && path != ATTESTATION_FILE {
+			verification.problem("file '%s' is not listed in the manifest", path)
+		}
+	}
+	if manifest.Result != nil {
+		if file, ok := manifest.File(manifest.Result.Path); !ok || file != *manifest.Result {
+			verification.problem("result file '%s' does not match the files of the manifest", manifest.Result.Path)
+		}
+	}
+}
+
+func verifyResultFile(verification *Verification, manifest Manifest, resultFile string) {
+	if manifest.Result == nil {
+		verification.problem("manifest has no result file")
+		return
+	}
+	content, err := os.ReadFile(resultFile)
+	if err != nil {
+		verification.problem("error reading result file '%s': %s", resultFile, err)
+		return
+	}
+	digest := sha256.Sum256(content)
+	if hex.EncodeToString(digest[:]) != manifest.Result.SHA256 {
+		verification.problem("result file '%s' was modified, expected sha256 %s", resultFile, manifest.Result.SHA256)
+	}
+}
+
+func verifyAttestation(verification *Verification, content []byte, digests map[string]File, manifest Manifest, keys []crypto.PublicKey) {
+	var envelope Envelope
+	if err := json.Unmarshal(content, &envelope); err != nil {
+		verification.problem("error parsing attestation: %s", err)
+		return
+	}
+	payload, err := base64.StdEncoding.DecodeString(envelope.Payload)
+	if err != nil || envelope.PayloadType != PAYLOAD_TYPE {
+		verification.problem("attestation has an invalid payload")
+		return
+	}
+	var statement Statement
+	if err := json.Unmarshal(payload, &statement); err != nil || statement.Type != STATEMENT_TYPE || statement.PredicateType != PREDICATE_TYPE {
+		verification.problem("attestation has an invalid statement")
+		return
+	}
+
+	attested := true
+	subjects := make(map[string]string, len(statement.Subject))
+	for _, subject := range statement.Subject {
+		subjects[subject.Name] = subject.Digest["sha256"]
+	}
+	expected := []string{MANIFEST_FILE}
+	if manifest.Result != nil {
+		expected = append(expected, manifest.Result.Path)
+	}
+	for _, name := range expected {
+		if subjects[name] != digests[name].SHA256 {
+			verification.problem("attestation does not match '%s'", name)
+			attested = false
+		}
+	}
+	verification.Attested = attested
+	if len(keys) == 0 {
+		return
+	}
+
+	for _, signature := range envelope.Signatures {
+		sig, err := base64.StdEncoding.DecodeString(signature.Sig)
+		if err != nil {
+			continue
+		}
+		for _, key := range keys {
+			if verifySignature(key, envelope.PayloadType, payload, sig) {
+				verification.KeyID, _ = KeyID(key)
+				return
+			}
+		}
+	}
+	verification.problem("attestation is not signed by any of the keys")
+}
@@ -0,0 +1,91 @@
+package export
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/B-S-F/onyx/pkg/v2/result"
+)
+
+const (
+	FORMAT_JUNIT = "junit"
+	FORMAT_SARIF = "sarif"
+)
+
+// Formats lists the formats a result can be exported to
+var Formats = []string{FORMAT_JUNIT, FORMAT_SARIF}
+
+var extensions = map[string]string{
+	FORMAT_JUNIT: ".junit.xml",
+	FORMAT_SARIF: ".sarif",
+}
+
+// ValidateFormats checks that all formats are supported
+func ValidateFormats(formats []string) error {
+	for _, format := range formats {
+		if _, ok := extensions[format]; !ok {
+			return fmt.Errorf("unsupported export format '%s', must be one of: %s", format, strings.Join(Formats, ", "))
+		}
+	}
+	return nil
+}
+
+// FileName returns the name of the export of a result file, e.g. qg-result.junit.xml for qg-result.yaml
+func FileName(resultFile string, format string) string {
+	return strings.TrimSuffix(resultFile, filepath.Ext(resultFile)) + extensions[format]
+}
+
+// Export converts the result into the format
+func Export(res *result.Result, format string) ([]byte, error) {
+	switch format {
+	case FORMAT_JUNIT:
+		return JUnit(res)
+	case FORMAT_SARIF:
+		return SARIF(res)
+	default:
+		return nil, ValidateFormats([]string{format})
+	}
+}
+
+// WriteFile exports the result into the file
+func WriteFile(res *result.Result, format string, path string) error {
+	content, err := Export(res, format)
+	if err != nil {
+		return fmt.Errorf("error exporting result as %s: %w", format, err)
+	}
+	err = os.WriteFile(path, content, 0644)
+	if err != nil {
+		return fmt.Errorf("error writing %s export: %w", format, err)
+	}
+	return nil
+}
+
+// unfulfilled returns the results of the evaluation which are not fulfilled
+func unfulfilled(check *result.Check) []result.EvaluationResult {
+	var results []result.EvaluationResult
+	for _, r := range check.Evaluation.Results {
+		if !r.Fulfilled {
+			results = append(results, r)
+		}
+	}
+	return results
+}
+
+// describe joins the criterion and the justification of an evaluation result
+func describe(r result.EvaluationResult) string {
+	criterion := strings.TrimSpace(string(r.Criterion))
+	justification := strings.TrimSpace(string(r.Justification))
+	if justification == "" {
+		return criterion
+	}
+	return criterion + ": " + justification
+}
+
+func title(title string, id string) string {
+	if title == "" {
+		return id
+	}
+	return title
+}
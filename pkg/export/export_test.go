@@ -0,0 +1,109 @@
+package export
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/B-S-F/onyx/pkg/result/common"
+	"github.com/B-S-F/onyx/pkg/v2/result"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func check(status string, reason string, results ...result.EvaluationResult) *result.Check {
+	return &result.Check{Title: status + " check", Type: "automation", Evaluation: result.Evaluation{Status: status, Reason: reason, Results: results}}
+}
+
+func testResult() *result.Result {
+	return &result.Result{
+		Header:        result.Header{Name: "My Project", Version: "1.0"},
+		OverallStatus: "ERROR",
+		Chapters: map[string]*result.Chapter{
+			"1": {Title: "Chapter", Requirements: map[string]*result.Requirement{
+				"1": {Title: "Requirement", Text: "All checks have to pass", Status: "ERROR", Checks: map[string]*result.Check{
+					"1": check("GREEN", "all good", result.EvaluationResult{Criterion: "fine", Fulfilled: true}),
+					"2": check("RED", "findings", result.EvaluationResult{
+						Criterion:     "No open tickets",
+						Justification: "ticket 42 is open",
+						Metadata:      common.StringMap{"ticket": "42"},
+					}, result.EvaluationResult{Criterion: "fine", Fulfilled: true}),
+					"3": check("ERROR", "autopilot failed"),
+				}},
+				"2": {Title: "Manual", Status: "NA", Checks: map[string]*result.Check{
+					"1": {Title: "manual check", Type: "manual", Evaluation: result.Evaluation{Status: "NA", Reason: "not applicable"}},
+				}},
+			}},
+		},
+	}
+}
+
+func TestJUnit(t *testing.T) {
+	content, err := JUnit(testResult())
+	require.NoError(t, err)
+
+	var suites junitTestSuites
+	require.NoError(t, xml.Unmarshal(content, &suites))
+	assert.Equal(t, "My Project", suites.Name)
+	assert.Equal(t, []int{4, 1, 1, 1}, []int{suites.Tests, suites.Failures, suites.Errors, suites.Skipped})
+	require.Len(t, suites.Suites, 2)
+	assert.Equal(t, "Chapter / Requirement", suites.Suites[0].Name)
+	assert.Equal(t, "1_1", suites.Suites[0].ID)
+
+	cases := suites.Suites[0].Cases
+	require.Len(t, cases, 3)
+	assert.Equal(t, "1_1_1 GREEN check", cases[0].Name)
+	assert.Equal(t, "1_1", cases[0].Classname)
+	assert.Nil(t, cases[0].Failure)
+	assert.Equal(t, &junitProblem{Message: "findings", Type: "RED", Text: "No open tickets: ticket 42 is open"}, cases[1].Failure)
+	assert.Equal(t, "autopilot failed", cases[2].Error.Message)
+	assert.Equal(t, "NA: not applicable", suites.Suites[1].Cases[0].Skipped.Message)
+}
+
+func TestSARIF(t *testing.T) {
+	content, err := SARIF(testResult())
+	require.NoError(t, err)
+
+	var log map[string]interface{}
+	require.NoError(t, json.Unmarshal(content, &log))
+	assert.Equal(t, SARIF_VERSION, log["version"])
+
+	var sarif sarifLog
+	require.NoError(t, json.Unmarshal(content, &sarif))
+	run := sarif.Runs[0]
+	assert.Equal(t, "onyx", run.Tool.Driver.Name)
+	require.Len(t, run.Tool.Driver.Rules, 4)
+	assert.Equal(t, "1_1_2", run.Tool.Driver.Rules[1].ID)
+	assert.Equal(t, "All checks have to pass", run.Tool.Driver.Rules[1].FullDescription.Text)
+
+	require.Len(t, run.Results, 2)
+	assert.Equal(t, sarifResult{
+		RuleID:    "1_1_2",
+		RuleIndex: 1,
+		Level:     "error",
+		Message:   sarifMessage{Text: "No open tickets: ticket 42 is open"},
+		Properties: map[string]interface{}{
+			"status":    "RED",
+			"criterion": "No open tickets",
+			"metadata":  map[string]interface{}{"ticket": "42"},
+		},
+	}, run.Results[0])
+	assert.Equal(t, "1_1_3", run.Results[1].RuleID)
+	assert.Equal(t, "autopilot failed", run.Results[1].Message.Text)
+}
+
+func TestWriteFile(t *testing.T) {
+	assert.Equal(t, "qg-result.junit.xml", FileName("qg-result.yaml", FORMAT_JUNIT))
+	assert.Equal(t, "qg-result.sarif", FileName("qg-result.yaml", FORMAT_SARIF))
+
+	path := filepath.Join(t.TempDir(), "qg-result.sarif")
+	require.NoError(t, WriteFile(testResult(), FORMAT_SARIF, path))
+	assert.FileExists(t, path)
+
+	err := WriteFile(testResult(), "html", path)
+	assert.ErrorContains(t, err, "unsupported export format 'html', must be one of: junit, sarif")
+	_, err = os.Stat(path)
+	assert.NoError(t, err)
+}
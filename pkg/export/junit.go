@@ -0,0 +1,117 @@
+package export
+
+import (
+	"encoding/xml"
+	"fmt"
+	"strings"
+
+	"github.com/B-S-F/onyx/pkg/v2/result"
+)
+
+type junitTestSuites struct {
+	XMLName  xml.Name         `xml:"testsuites"`
+	Name     string           `xml:"name,attr"`
+	Tests    int              `xml:"tests,attr"`
+	Failures int              `xml:"failures,attr"`
+	Errors   int              `xml:"errors,attr"`
+	Skipped  int              `xml:"skipped,attr"`
+	Suites   []junitTestSuite `xml:"testsuite"`
+}
+
+type junitTestSuite struct {
+	Name       string          `xml:"name,attr"`
+	ID         string          `xml:"id,attr"`
+	Tests      int             `xml:"tests,attr"`
+	Failures   int             `xml:"failures,attr"`
+	Errors     int             `xml:"errors,attr"`
+	Skipped    int             `xml:"skipped,attr"`
+	Properties []junitProperty `xml:"properties>property,omitempty"`
+	Cases      []junitTestCase `xml:"testcase"`
+}
+
+type junitProperty struct {
+	Name  string `xml:"name,attr"`
+	Value string `xml:"value,attr"`
+}
+
+type junitTestCase struct {
+	Name      string        `xml:"name,attr"`
+	Classname string        `xml:"classname,attr"`
+	Failure   *junitProblem `xml:"failure,omitempty"`
+	Error     *junitProblem `xml:"error,omitempty"`
+	Skipped   *junitProblem `xml:"skipped,omitempty"`
+	SystemOut *junitText    `xml:"system-out,omitempty"`
+}
+
+// junitText keeps the line breaks of multiline reasons and justifications readable
+type junitText struct {
+	Text string `xml:",cdata"`
+}
+
+type junitProblem struct {
+	Message string `xml:"message,attr"`
+	Type    string `xml:"type,attr,omitempty"`
+	Text    string `xml:",cdata"`
+}
+
+// JUnit exports the result as JUnit XML, every requirement is a test suite and every check a test case.
+// RED checks are failures, ERROR checks errors, and NA, SKIPPED and UNANSWERED checks are skipped
+func JUnit(res *result.Result) ([]byte, error) {
+	suites := junitTestSuites{Name: res.Header.Name}
+	var suite *junitTestSuite
+	err := res.Walk(func(ref result.CheckRef, chapter *result.Chapter, requirement *result.Requirement, check *result.Check) error {
+		id := ref.Chapter + "_" + ref.Requirement
+		if suite == nil || suite.ID != id {
+			suites.Suites = append(suites.Suites, junitTestSuite{
+				Name: title(chapter.Title, ref.Chapter) + " / " + title(requirement.Title, ref.Requirement),
+				ID:   id,
+				Properties: []junitProperty{
+					{Name: "chapter", Value: ref.Chapter},
+					{Name: "requirement", Value: ref.Requirement},
+					{Name: "status", Value: requirement.Status},
+				},
+			})
+			suite = &suites.Suites[len(suites.Suites)-1]
+		}
+		testCase := junitTestCase{
+			Name:      ref.String() + " " + title(check.Title, ref.Check),
+			Classname: id,
+		}
+		status := check.Evaluation.Status
+		reason := strings.TrimSpace(check.Evaluation.Reason)
+		var details []string
+		for _, r := range unfulfilled(check) {
+			details = append(details, describe(r))
+		}
+		switch status {
+		case "RED":
+			testCase.Failure = &junitProblem{Message: reason, Type: status, Text: strings.Join(details, "\n")}
+			suite.Failures++
+		case "ERROR":
+			testCase.Error = &junitProblem{Message: reason, Type: status, Text: strings.Join(append(details, check.Evaluation.Logs...), "\n")}
+			suite.Errors++
+		case "NA", "SKIPPED", "UNANSWERED":
+			testCase.Skipped = &junitProblem{Message: fmt.Sprintf("%s: %s", status, reason)}
+			suite.Skipped++
+		default:
+			testCase.SystemOut = &junitText{Text: strings.Join(append([]string{fmt.Sprintf("%s: %s", status, reason)}, details...), "\n")}
+		}
+		suite.Tests++
+		suite.Cases = append(suite.Cases, testCase)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	for _, s := range suites.Suites {
+		suites.Tests += s.Tests
+		suites.Failures += s.Failures
+		suites.Errors += s.Errors
+		suites.Skipped += s.Skipped
+	}
+	content, err := xml.MarshalIndent(suites, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	return append([]byte(xml.Header), append(content, '\n')...), nil
+}
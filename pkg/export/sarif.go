@@ -0,0 +1,143 @@
+package export
+
+import (
+	"encoding/json"
+	"strings"
+
+	"github.com/B-S-F/onyx/pkg/helper"
+	"github.com/B-S-F/onyx/pkg/v2/result"
+)
+
+const (
+	SARIF_VERSION = "2.1.0"
+	SARIF_SCHEMA  = "https://json.schemastore.org/sarif-2.1.0.json"
+)
+
+type sarifLog struct {
+	Schema  string     `json:"$schema"`
+	Version string     `json:"version"`
+	Runs    []sarifRun `json:"runs"`
+}
+
+type sarifRun struct {
+	Tool       sarifTool              `json:"tool"`
+	Results    []sarifResult          `json:"results"`
+	Properties map[string]interface{} `json:"properties,omitempty"`
+}
+
+type sarifTool struct {
+	Driver sarifDriver `json:"driver"`
+}
+
+type sarifDriver struct {
+	Name           string      `json:"name"`
+	Version        string      `json:"version,omitempty"`
+	InformationURI string      `json:"informationUri"`
+	Rules          []sarifRule `json:"rules"`
+}
+
+type sarifRule struct {
+	ID               string                 `json:"id"`
+	Name             string                 `json:"name,omitempty"`
+	ShortDescription sarifMessage           `json:"shortDescription"`
+	FullDescription  *sarifMessage          `json:"fullDescription,omitempty"`
+	Properties       map[string]interface{} `json:"properties,omitempty"`
+}
+
+type sarifMessage struct {
+	Text string `json:"text"`
+}
+
+type sarifResult struct {
+	RuleID     string                 `json:"ruleId"`
+	RuleIndex  int                    `json:"ruleIndex"`
+	Level      string                 `json:"level"`
+	Message    sarifMessage           `json:"message"`
+	Properties map[string]interface{} `json:"properties,omitempty"`
+}
+
+// SARIF exports the result as SARIF 2.1.0, every check is a rule and every unfulfilled criterion a result with its metadata.
+// RED, YELLOW and ERROR checks without unfulfilled criteria are reported with the reason of the evaluation
+func SARIF(res *result.Result) ([]byte, error) {
+	run := sarifRun{
+		Tool: sarifTool{Driver: sarifDriver{
+			Name:           "onyx",
+			Version:        helper.ToolVersion,
+			InformationURI: "https://github.com/B-S-F/onyx",
+			Rules:          []sarifRule{},
+		}},
+		Results: []sarifResult{},
+		Properties: map[string]interface{}{
+			"project":        res.Header.Name,
+			"projectVersion": res.Header.Version,
+			"overallStatus":  res.OverallStatus,
+		},
+	}
+	err := res.Walk(func(ref result.CheckRef, chapter *result.Chapter, requirement *result.Requirement, check *result.Check) error {
+		rule := sarifRule{
+			ID:               ref.String(),
+			Name:             check.Title,
+			ShortDescription: sarifMessage{Text: title(check.Title, ref.String())},
+			Properties: map[string]interface{}{
+				"chapter":     title(chapter.Title, ref.Chapter),
+				"requirement": title(requirement.Title, ref.Requirement),
+				"type":        check.Type,
+			},
+		}
+		if text := strings.TrimSpace(requirement.Text); text != "" {
+			rule.FullDescription = &sarifMessage{Text: text}
+		}
+		ruleIndex := len(run.Tool.Driver.Rules)
+		run.Tool.Driver.Rules = append(run.Tool.Driver.Rules, rule)
+
+		status := check.Evaluation.Status
+		level := sarifLevel(status)
+		results := unfulfilled(check)
+		for _, r := range results {
+			properties := map[string]interface{}{
+				"status":    status,
+				"criterion": strings.TrimSpace(string(r.Criterion)),
+			}
+			if len(r.Metadata) > 0 {
+				properties["metadata"] = r.Metadata
+			}
+			run.Results = append(run.Results, sarifResult{
+				RuleID:     rule.ID,
+				RuleIndex:  ruleIndex,
+				Level:      level,
+				Message:    sarifMessage{Text: describe(r)},
+				Properties: properties,
+			})
+		}
+		if len(results) == 0 && level != "note" {
+			run.Results = append(run.Results, sarifResult{
+				RuleID:     rule.ID,
+				RuleIndex:  ruleIndex,
+				Level:      level,
+				Message:    sarifMessage{Text: title(strings.TrimSpace(check.Evaluation.Reason), status)},
+				Properties: map[string]interface{}{"status": status},
+			})
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	content, err := json.MarshalIndent(sarifLog{Schema: SARIF_SCHEMA, Version: SARIF_VERSION, Runs: []sarifRun{run}}, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	return append(content, '\n'), nil
+}
+
+// sarifLevel maps the status of a check to the level of its results
+func sarifLevel(status string) string {
+	switch status {
+	case "RED", "ERROR":
+		return "error"
+	case "YELLOW":
+		return "warning"
+	default:
+		return "note"
+	}
+}
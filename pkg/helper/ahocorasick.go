@@ -0,0 +1,85 @@
+package helper
+
+// ahoCorasick finds all occurrences of many patterns in a single pass over the content
+type ahoCorasick struct {
+	nodes   []acNode
+	lengths []int
+}
+
+type acNode struct {
+	next map[byte]int
+	fail int
+	// patterns ending in this node, including the ones reachable by fail links
+	outputs []int
+}
+
+type acMatch struct {
+	pattern int
+	start   int
+	end     int
+}
+
+func newAhoCorasick(patterns []string) *ahoCorasick {
+	ac := &ahoCorasick{nodes: []acNode{{next: map[byte]int{}}}, lengths: make([]int, len(patterns))}
+	for index, pattern := range patterns {
+		ac.lengths[index] = len(pattern)
+		node := 0
+		for i := 0; i < len(pattern); i++ {
+			child, ok := ac.nodes[node].next[pattern[i]]
+			if !ok {
+				child = len(ac.nodes)
+				ac.nodes = append(ac.nodes, acNode{next: map[byte]int{}})
+				ac.nodes[node].next[pattern[i]] = child
+			}
+			node = child
+		}
+		ac.nodes[node].outputs = append(ac.nodes[node].outputs, index)
+	}
+
+	// breadth first, so the fail link of a node is complete before its children are processed
+	queue := make([]int, 0, len(ac.nodes))
+	for _, child := range ac.nodes[0].next {
+		queue = append(queue, child)
+	}
+	for len(queue) > 0 {
+		node := queue[0]
+		queue = queue[1:]
+		for b, child := range ac.nodes[node].next {
+			fail := ac.nodes[node].fail
+			for fail != 0 {
+				if _, ok := ac.nodes[fail].next[b]; ok {
+					break
+				}
+				fail = ac.nodes[fail].fail
+			}
+			if target, ok := ac.nodes[fail].next[b]; ok && target != child {
+				ac.nodes[child].fail = target
+			}
+			ac.nodes[child].outputs = append(ac.nodes[child].outputs, ac.nodes[ac.nodes[child].fail].outputs...)
+			queue = append(queue, child)
+		}
+	}
+	return ac
+}
+
+// findAll returns the matches of all patterns ordered by their end, overlapping matches are included
+func (ac *ahoCorasick) findAll(content string) []acMatch {
+	var matches []acMatch
+	node := 0
+	for i := 0; i < len(content); i++ {
+		b := content[i]
+		for node != 0 {
+			if _, ok := ac.nodes[node].next[b]; ok {
+				break
+			}
+			node = ac.nodes[node].fail
+		}
+		if next, ok := ac.nodes[node].next[b]; ok {
+			node = next
+		}
+		for _, pattern := range ac.nodes[node].outputs {
+			matches = append(matches, acMatch{pattern: pattern, start: i + 1 - ac.lengths[pattern], end: i + 1})
+		}
+	}
+	return matches
+}
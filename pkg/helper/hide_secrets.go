@@ -59,8 +59,9 @@ func HideSecretsInArrayOfStrings(array []string, secrets map[string]string) []st
 	if len(secrets) == 0 {
 		return array
 	}
+	masker := NewMasker(secrets)
 	for i, line := range array {
-		array[i] = HideSecretsInString(line, secrets)
+		array[i] = masker.Mask(line)
 	}
 	return array
 }
@@ -69,8 +70,5 @@ func HideSecretsInString(content string, secrets map[string]string) string {
 	if len(secrets) == 0 {
 		return content
 	}
-	for secretName, secretValue := range secrets {
-		content = strings.ReplaceAll(content, secretValue, fmt.Sprintf("***%s***", secretName))
-	}
-	return content
+	return NewMasker(secrets).Mask(content)
 }
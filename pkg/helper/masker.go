@@ -0,0 +1,226 @@
+package helper
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"sort"
+	"strings"
+)
+
+const (
+	// MASK_MIN_LENGTH is the minimum length of secrets which are masked, shorter ones would mask random text
+	MASK_MIN_LENGTH = 3
+	// MASK_MIN_ENCODED_LENGTH is the minimum length of encoded variants of secrets
+	MASK_MIN_ENCODED_LENGTH = 8
+)
+
+// Masker replaces secrets and their common encodings with ***<name>***
+type Masker struct {
+	matcher *ahoCorasick
+	names   []string
+	// following lines of a multiline secret whose first line is the pattern, nil for other patterns
+	lines   [][]string
+	skipped []string
+}
+
+// NewMasker precomputes the variants of all secrets, it can be used for many strings
+func NewMasker(secrets map[string]string) *Masker {
+	names := make([]string, 0, len(secrets))
+	for name := range secrets {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	m := &Masker{}
+	seen := make(map[string]bool)
+	var patterns []string
+	for _, name := range names {
+		value := secrets[name]
+		if len(strings.TrimSpace(value)) < MASK_MIN_LENGTH {
+			m.skipped = append(m.skipped, name)
+			continue
+		}
+		for _, variant := range secretVariants(value) {
+			if seen[variant] {
+				continue
+			}
+			seen[variant] = true
+			patterns = append(patterns, variant)
+			m.names = append(m.names, name)
+			m.lines = append(m.lines, nil)
+		}
+		// the lines of a multiline secret are only masked together, e.g. if the secret is printed indented,
+		// a single line like "new line" is too common to be masked on its own
+		if lines := secretLines(value); len(lines) > 1 && !seen["lines:"+strings.Join(lines, "\n")] {
+			seen["lines:"+strings.Join(lines, "\n")] = true
+			patterns = append(patterns, lines[0])
+			m.names = append(m.names, name)
+			m.lines = append(m.lines, lines[1:])
+		}
+	}
+	if len(patterns) > 0 {
+		m.matcher = newAhoCorasick(patterns)
+	}
+	return m
+}
+
+// Skipped returns the names of the secrets which are too short to be masked
+func (m *Masker) Skipped() []string {
+	if m == nil {
+		return nil
+	}
+	return m.skipped
+}
+
+// SecretMatch is a part of a string which contains a secret or one of its variants
+type SecretMatch struct {
+	// Name of the secret
+	Name  string
+	Start int
+	End   int
+}
+
+// Find returns the parts of the content which contain secrets, overlapping matches are merged
+func (m *Masker) Find(content string) []SecretMatch {
+	if m == nil || m.matcher == nil || content == "" {
+		return nil
+	}
+	matches := m.matchFollowingLines(content, m.matcher.findAll(content))
+	sort.Slice(matches, func(i, j int) bool {
+		if matches[i].start != matches[j].start {
+			return matches[i].start < matches[j].start
+		}
+		return matches[i].end > matches[j].end
+	})
+
+	var found []SecretMatch
+	for i := 0; i < len(matches); {
+		match := SecretMatch{Name: m.names[matches[i].pattern], Start: matches[i].start, End: matches[i].end}
+		i++
+		for i < len(matches) && matches[i].start < match.End {
+			if matches[i].end > match.End {
+				match.End = matches[i].end
+			}
+			i++
+		}
+		found = append(found, match)
+	}
+	return found
+}
+
+// Mask replaces all secrets in the content, overlapping matches are masked together
+func (m *Masker) Mask(content string) string {
+	matches := m.Find(content)
+	if len(matches) == 0 {
+		return content
+	}
+	var masked strings.Builder
+	masked.Grow(len(content))
+	position := 0
+	for _, match := range matches {
+		masked.WriteString(content[position:match.Start])
+		masked.WriteString(fmt.Sprintf("***%s***", match.Name))
+		position = match.End
+	}
+	masked.WriteString(content[position:])
+	return masked.String()
+}
+
+// matchFollowingLines extends the matches of the first line of multiline secrets by their following lines,
+// matches which are not followed by the lines of the secret are dropped
+func (m *Masker) matchFollowingLines(content string, matches []acMatch) []acMatch {
+	complete := matches[:0]
+	for _, match := range matches {
+		if lines := m.lines[match.pattern]; lines != nil {
+			end, ok := followingLines(content, match.end, lines)
+			if !ok {
+				continue
+			}
+			match.end = end
+		}
+		complete = append(complete, match)
+	}
+	return complete
+}
+
+// followingLines returns the end of the lines in the content after the position, the lines may be indented,
+// but every line has to start on a new line
+func followingLines(content string, position int, lines []string) (int, bool) {
+	for _, line := range lines {
+		newLine := false
+		for position < len(content) && strings.IndexByte(" \t\r\n", content[position]) >= 0 {
+			newLine = newLine || content[position] == '\n'
+			position++
+		}
+		if !newLine || !strings.HasPrefix(content[position:], line) {
+			return 0, false
+		}
+		position += len(line)
+	}
+	return position, true
+}
+
+// secretLines returns the trimmed non-empty lines of the secret
+func secretLines(value string) []string {
+	var lines []string
+	for _, line := range strings.Split(value, "\n") {
+		if line = strings.TrimSpace(line); line != "" {
+			lines = append(lines, line)
+		}
+	}
+	return lines
+}
+
+// secretVariants returns the value and the forms in which it is commonly printed
+func secretVariants(value string) []string {
+	variants := []string{value}
+	if trimmed := strings.TrimSpace(value); trimmed != value {
+		variants = append(variants, trimmed)
+	}
+	value = strings.TrimSpace(value)
+
+	var encoded []string
+	encoded = append(encoded, jsonEscaped(value, true), jsonEscaped(value, false))
+	encoded = append(encoded, url.QueryEscape(value), url.PathEscape(value))
+	for _, encoding := range []*base64.Encoding{base64.StdEncoding, base64.URLEncoding} {
+		encoded = append(encoded, encoding.EncodeToString([]byte(value)))
+		encoded = append(encoded, embeddedBase64(value, encoding)...)
+	}
+	if strings.ContainsAny(value, "\r\n") {
+		encoded = append(encoded, strings.ReplaceAll(value, "\n", "\r\n"))
+	}
+	for _, variant := range encoded {
+		if len(variant) >= MASK_MIN_ENCODED_LENGTH && variant != value {
+			variants = append(variants, variant)
+		}
+	}
+	return variants
+}
+
+func jsonEscaped(value string, escapeHTML bool) string {
+	var buffer bytes.Buffer
+	encoder := json.NewEncoder(&buffer)
+	encoder.SetEscapeHTML(escapeHTML)
+	_ = encoder.Encode(value)
+	escaped := strings.TrimSuffix(buffer.String(), "\n")
+	return strings.TrimSuffix(strings.TrimPrefix(escaped, `"`), `"`)
+}
+
+// embeddedBase64 returns the parts of the base64 encoding which only depend on the value, for the three possible
+// alignments of the value in encoded content, e.g. the password in the Authorization header base64(user:password)
+func embeddedBase64(value string, encoding *base64.Encoding) []string {
+	variants := make([]string, 0, 3)
+	for offset := 0; offset < 3; offset++ {
+		encoded := encoding.EncodeToString(append(make([]byte, offset), value...))
+		// every character encodes 6 bits, skip characters which contain bits of the prefix or of the padding
+		start := (offset*8 + 5) / 6
+		end := (offset + len(value)) * 8 / 6
+		if end > start {
+			variants = append(variants, encoded[start:end])
+		}
+	}
+	return variants
+}
@@ -0,0 +1,159 @@
+package helper
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"math/rand"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMasker(t *testing.T) {
+	secrets := map[string]string{
+		"PASSWORD": "p@ss/w0rd&more",
+		"USER":     "onyx-user",
+		"KEY":      "-----BEGIN KEY-----\nMIIEvQIBADANBgkqhkiG9w0BAQEF\nAASCBKcwggSjAgEAAoIBAQC7\n-----END KEY-----",
+	}
+	masker := NewMasker(secrets)
+
+	testCases := map[string]struct {
+		content string
+		want    string
+	}{
+		"plain": {
+			content: "login with p@ss/w0rd&more",
+			want:    "login with ***PASSWORD***",
+		},
+		"base64": {
+			content: "encoded " + base64.StdEncoding.EncodeToString([]byte("p@ss/w0rd&more")),
+			want:    "encoded ***PASSWORD***",
+		},
+		"url encoded": {
+			content: "https://example.com?password=" + url.QueryEscape("p@ss/w0rd&more"),
+			want:    "https://example.com?password=***PASSWORD***",
+		},
+		"json escaped": {
+			content: `{"key":` + mustMarshal(t, secrets["KEY"]) + `}`,
+			want:    `{"key":"***KEY***"}`,
+		},
+		"indented multiline secret": {
+			content: "key:\n  -----BEGIN KEY-----\n  MIIEvQIBADANBgkqhkiG9w0BAQEF\r\n  AASCBKcwggSjAgEAAoIBAQC7\n  -----END KEY-----\nnext: value",
+			want:    "key:\n  ***KEY***\nnext: value",
+		},
+		"single line of a multiline secret": {
+			content: "line AASCBKcwggSjAgEAAoIBAQC7 leaked",
+			want:    "line AASCBKcwggSjAgEAAoIBAQC7 leaked",
+		},
+		"incomplete multiline secret": {
+			content: "-----BEGIN KEY-----\nMIIEvQIBADANBgkqhkiG9w0BAQEF\n-----END KEY-----",
+			want:    "-----BEGIN KEY-----\nMIIEvQIBADANBgkqhkiG9w0BAQEF\n-----END KEY-----",
+		},
+	}
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			assert.Equal(t, tc.want, masker.Mask(tc.content))
+		})
+	}
+
+	t.Run("basic auth header", func(t *testing.T) {
+		header := "Authorization: Basic " + base64.StdEncoding.EncodeToString([]byte("someone:p@ss/w0rd&more"))
+
+		masked := masker.Mask(header)
+
+		assert.Contains(t, masked, "***PASSWORD***")
+		decoded, _ := base64.StdEncoding.DecodeString(strings.TrimPrefix(header, "Authorization: Basic "))
+		assert.NotContains(t, masked, string(decoded))
+		assert.NotContains(t, masked, base64.StdEncoding.EncodeToString([]byte("p@ss/w0rd&more"))[2:])
+	})
+
+	t.Run("basic auth header with both secrets", func(t *testing.T) {
+		header := "Basic " + base64.StdEncoding.EncodeToString([]byte("onyx-user:p@ss/w0rd&more"))
+
+		masked := masker.Mask(header)
+
+		assert.True(t, strings.HasPrefix(masked, "Basic ***USER***"))
+		assert.True(t, strings.HasSuffix(masked, "***PASSWORD***"))
+		assert.Less(t, len(masked), len("Basic ***USER******PASSWORD***")+4)
+	})
+}
+
+func TestMaskerMinimumLength(t *testing.T) {
+	masker := NewMasker(map[string]string{"EMPTY": "", "SHORT": "ab", "SPACES": "   ", "OK": "abc"})
+
+	assert.Equal(t, []string{"EMPTY", "SHORT", "SPACES"}, masker.Skipped())
+	assert.Equal(t, "xab ***OK*** ", masker.Mask("xab abc "))
+}
+
+func TestMaskerMultilineSecretWithCommonLine(t *testing.T) {
+	masker := NewMasker(map[string]string{"NOTE": "first secret line\nnew line\n"})
+
+	assert.Equal(t, "a new line is printed", masker.Mask("a new line is printed"))
+	assert.Equal(t, "first secret line", masker.Mask("first secret line"))
+	assert.Equal(t, "log: ***NOTE*** done", masker.Mask("log: first secret line\n      new line done"))
+}
+
+func TestMaskerOverlappingSecrets(t *testing.T) {
+	masker := NewMasker(map[string]string{"A": "secret-value", "B": "value-and-more"})
+
+	assert.Equal(t, "x ***A*** y", masker.Mask("x secret-value-and-more y"))
+	assert.Equal(t, "***B***", masker.Mask("value-and-more"))
+}
+
+func TestAhoCorasick(t *testing.T) {
+	random := rand.New(rand.NewSource(1))
+	alphabet := "abc"
+	randomString := func(length int) string {
+		var b strings.Builder
+		for i := 0; i < length; i++ {
+			b.WriteByte(alphabet[random.Intn(len(alphabet))])
+		}
+		return b.String()
+	}
+	for run := 0; run < 50; run++ {
+		patterns := make([]string, 10)
+		for i := range patterns {
+			patterns[i] = randomString(1 + random.Intn(5))
+		}
+		content := randomString(200)
+
+		got := map[string]bool{}
+		for _, match := range newAhoCorasick(patterns).findAll(content) {
+			assert.Equal(t, patterns[match.pattern], content[match.start:match.end])
+			got[fmt.Sprintf("%d-%d", match.pattern, match.start)] = true
+		}
+
+		want := map[string]bool{}
+		for index, pattern := range patterns {
+			for start := 0; start+len(pattern) <= len(content); start++ {
+				if content[start:start+len(pattern)] == pattern {
+					want[fmt.Sprintf("%d-%d", index, start)] = true
+				}
+			}
+		}
+		assert.Equal(t, want, got)
+	}
+}
+
+func BenchmarkMasker(b *testing.B) {
+	secrets := make(map[string]string, 500)
+	for i := 0; i < 500; i++ {
+		secrets[fmt.Sprintf("SECRET_%d", i)] = fmt.Sprintf("secret-value-%d-%x", i, i*7919)
+	}
+	masker := NewMasker(secrets)
+	content := strings.Repeat("some autopilot output without any secret in it\n", 50000) + secrets["SECRET_42"]
+	b.SetBytes(int64(len(content)))
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		masker.Mask(content)
+	}
+}
+
+func mustMarshal(t *testing.T, value string) string {
+	content, err := json.Marshal(value)
+	assert.NoError(t, err)
+	return string(content)
+}
@@ -40,7 +40,7 @@ func NewAutopilot(s ...Settings) *Autopilot {
 	return &Autopilot{
 		Log{
 			logger,
-			settings,
+			settings.withMasker(),
 		},
 		hrBuffer,
 		mrBuffer,
@@ -3,6 +3,7 @@ package logger
 import (
 	"os"
 
+	"github.com/B-S-F/onyx/pkg/helper"
 	"github.com/spf13/viper"
 	"go.uber.org/zap"
 	"go.uber.org/zap/zapcore"
@@ -61,10 +62,14 @@ func NewCommon(s ...Settings) *Common {
 	} else {
 		core = zapcore.NewCore(consoleEncoder, consoleLogging, level)
 	}
-	return &Common{
+	common := &Common{
 		Log{
 			zap.New(core),
-			settings,
+			settings.withMasker(),
 		},
 	}
+	for _, name := range common.masker.Skipped() {
+		common.Warnf("secret '%s' is shorter than %d characters and is not masked in logs and results", name, helper.MASK_MIN_LENGTH)
+	}
+	return common
 }
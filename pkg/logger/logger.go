@@ -1,6 +1,8 @@
 package logger
 
 import (
+	"fmt"
+
 	"github.com/B-S-F/onyx/pkg/helper"
 	"go.uber.org/zap"
 	"go.uber.org/zap/zapcore"
@@ -18,6 +20,8 @@ type Settings struct {
 	Level   string
 	File    string
 	Secrets map[string]string
+	// masker is precomputed for the secrets by the constructors
+	masker *helper.Masker
 }
 
 type Log struct {
@@ -37,35 +41,66 @@ type Logger interface {
 }
 
 func (l *Log) Debug(msg string, fields ...zap.Field) {
-	l.Logger.Debug(helper.HideSecretsInString(msg, l.Secrets), fields...)
+	l.Logger.Debug(l.mask(msg), fields...)
 }
 
 func (l *Log) Debugf(msg string, args ...interface{}) {
-	l.Logger.Sugar().Debugf(helper.HideSecretsInString(msg, l.Secrets), args...)
+	if l.Logger.Core().Enabled(zap.DebugLevel) {
+		l.Logger.Debug(l.mask(format(msg, args)))
+	}
 }
 
 func (l *Log) Info(msg string, fields ...zap.Field) {
-	l.Logger.Info(helper.HideSecretsInString(msg, l.Secrets), fields...)
+	l.Logger.Info(l.mask(msg), fields...)
 }
 
 func (l *Log) Infof(msg string, args ...interface{}) {
-	l.Logger.Sugar().Infof(helper.HideSecretsInString(msg, l.Secrets), args...)
+	if l.Logger.Core().Enabled(zap.InfoLevel) {
+		l.Logger.Info(l.mask(format(msg, args)))
+	}
 }
 
 func (l *Log) Warn(msg string, fields ...zap.Field) {
-	l.Logger.Warn(helper.HideSecretsInString(msg, l.Secrets), fields...)
+	l.Logger.Warn(l.mask(msg), fields...)
 }
 
 func (l *Log) Warnf(msg string, args ...interface{}) {
-	l.Logger.Sugar().Warnf(helper.HideSecretsInString(msg, l.Secrets), args...)
+	if l.Logger.Core().Enabled(zap.WarnLevel) {
+		l.Logger.Warn(l.mask(format(msg, args)))
+	}
 }
 
 func (l *Log) Error(msg string, fields ...zap.Field) {
-	l.Logger.Error(helper.HideSecretsInString(msg, l.Secrets), fields...)
+	l.Logger.Error(l.mask(msg), fields...)
 }
 
 func (l *Log) Errorf(msg string, args ...interface{}) {
-	l.Logger.Sugar().Errorf(helper.HideSecretsInString(msg, l.Secrets), args...)
+	if l.Logger.Core().Enabled(zap.ErrorLevel) {
+		l.Logger.Error(l.mask(format(msg, args)))
+	}
+}
+
+// mask hides the secrets, the arguments of formatted messages are masked as well
+func (l *Log) mask(msg string) string {
+	if l.masker != nil {
+		return l.masker.Mask(msg)
+	}
+	return helper.HideSecretsInString(msg, l.Secrets)
+}
+
+func format(msg string, args []interface{}) string {
+	if len(args) == 0 {
+		return msg
+	}
+	return fmt.Sprintf(msg, args...)
+}
+
+// withMasker precomputes the masker of the secrets
+func (s Settings) withMasker() Settings {
+	if len(s.Secrets) > 0 {
+		s.masker = helper.NewMasker(s.Secrets)
+	}
+	return s
 }
 
 func Get() Logger {
@@ -61,4 +61,19 @@ func TestLogger(t *testing.T) {
 		assert.NotContains(t, got[2].Message, "test-secret")
 		assert.NotContains(t, got[3].Message, "test-secret")
 	})
+	t.Run("should hide secrets in the arguments of formatted messages", func(t *testing.T) {
+		// arrange
+		core, logs := observer.New(zap.DebugLevel)
+		log := NewCommon(Settings{Secrets: map[string]string{"TEST_SECRET": "test-secret"}})
+		log.Logger = zap.New(core)
+
+		// act
+		log.Infof("token %s", "test-secret")
+		log.Errorf("encoded %s", "dGVzdC1zZWNyZXQ=")
+
+		// assert
+		got := logs.All()
+		assert.Equal(t, "token ***TEST_SECRET***", got[0].Message)
+		assert.Equal(t, "encoded ***TEST_SECRET***", got[1].Message)
+	})
 }
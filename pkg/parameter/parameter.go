@@ -3,6 +3,10 @@ package parameter
 import (
 	"strings"
 	"time"
+
+	"github.com/B-S-F/onyx/pkg/evidence"
+	"github.com/B-S-F/onyx/pkg/secrets"
+	"github.com/B-S-F/onyx/pkg/zip"
 )
 
 type ExecutionParameter struct {
@@ -14,6 +18,24 @@ type ExecutionParameter struct {
 	VarsName        string
 	SecretsName     string
 	CheckIdentifier string
+	// ParallelInstalls is the maximum number of apps installed at the same time
+	ParallelInstalls int
+	// SecretProviders replace the secrets file if set
+	SecretProviders []secrets.ProviderConfig
+	// EvidenceScanPolicy decides what happens with secrets found in the evidence files, defaults to redact
+	EvidenceScanPolicy string
+	// EvidenceScanRules find credentials in addition to the known secret values
+	EvidenceScanRules []evidence.Rule
+	// EvidenceSigningKey is the name of the secret with the private key which signs the evidence attestation
+	EvidenceSigningKey string
+	// EvidenceArchive are the format and filters of the evidence archive
+	EvidenceArchive zip.Options
+	// ResultFormat is the format of the result file, yaml (default), json or both
+	ResultFormat string
+	// StrictResultValidation makes result files which do not match their schema an error instead of a warning
+	StrictResultValidation bool
+	// ResultExports are the formats the result is exported to next to the result file, e.g. junit or sarif
+	ResultExports []string
 }
 
 type CheckIdentifier struct {
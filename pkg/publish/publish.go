@@ -0,0 +1,189 @@
+package publish
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/B-S-F/onyx/pkg/helper"
+	"github.com/B-S-F/onyx/pkg/logger"
+)
+
+const DEFAULT_RETRIES = 3
+const DEFAULT_BACKOFF = time.Second
+
+// Target uploads the result file and the evidence archive to a location outside of the run
+type Target interface {
+	// Location returns where a file with the name is uploaded to, it is known before the upload
+	Location(name string) string
+	// Upload uploads the file, the digest of the file is sent along, so the target can verify the content
+	Upload(file File) error
+	Name() string
+}
+
+// File is a local file which is uploaded
+type File struct {
+	// Name of the file at the target
+	Name string
+	// Path of the local file
+	Path string
+	Size int64
+	// SHA256 is the hex encoded digest of the content
+	SHA256 string
+}
+
+// NewFile calculates the size and the digest of the file which is uploaded with the name of the file
+func NewFile(path string) (File, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return File{}, err
+	}
+	defer file.Close()
+	hash := sha256.New()
+	size, err := io.Copy(hash, file)
+	if err != nil {
+		return File{}, fmt.Errorf("error reading '%s': %w", path, err)
+	}
+	return File{Name: filepath.Base(path), Path: path, Size: size, SHA256: hex.EncodeToString(hash.Sum(nil))}, nil
+}
+
+var contentTypes = map[string]string{
+	".yaml":  "application/yaml",
+	".json":  "application/json",
+	".xml":   "application/xml",
+	".sarif": "application/sarif+json",
+	".zip":   "application/zip",
+	".gz":    "application/gzip",
+	".zst":   "application/zstd",
+}
+
+// ContentType returns the media type of the file based on its extension
+func (f File) ContentType() string {
+	if contentType, ok := contentTypes[strings.ToLower(filepath.Ext(f.Name))]; ok {
+		return contentType
+	}
+	return "application/octet-stream"
+}
+
+// Upload of a file to a target
+type Upload struct {
+	Target Target
+	// File is the name of the file at the target
+	File     string
+	Location string
+}
+
+// Plan returns the uploads of the files to every target, the files are uploaded to the targets in the given order
+func Plan(targets []Target, files []string) []Upload {
+	uploads := make([]Upload, 0, len(targets)*len(files))
+	for _, target := range targets {
+		for _, file := range files {
+			uploads = append(uploads, Upload{Target: target, File: file, Location: target.Location(file)})
+		}
+	}
+	return uploads
+}
+
+// permanentError marks errors which are not solved by retrying
+type permanentError struct {
+	error
+}
+
+func (e permanentError) Unwrap() error {
+	return e.error
+}
+
+// Permanent marks an error of an upload which is not retried, e.g. a rejected authorization
+func Permanent(err error) error {
+	return permanentError{err}
+}
+
+// Publisher uploads files and retries failed uploads with exponential backoff
+type Publisher struct {
+	// Number of retries after the first attempt
+	Retries int
+	// Wait time before the first retry, it is doubled for every further retry
+	Backoff time.Duration
+	logger  logger.Logger
+}
+
+func NewPublisher(retries int) *Publisher {
+	return &Publisher{
+		Retries: retries,
+		Backoff: DEFAULT_BACKOFF,
+		logger:  logger.Get(),
+	}
+}
+
+// Publish uploads the files of the directory, a failed upload does not stop the remaining uploads
+func (p *Publisher) Publish(uploads []Upload, dir string) error {
+	files := make(map[string]File)
+	var errs []error
+	for _, upload := range uploads {
+		file, ok := files[upload.File]
+		if !ok {
+			var err error
+			file, err = NewFile(filepath.Join(dir, upload.File))
+			if err != nil {
+				errs = append(errs, fmt.Errorf("error uploading '%s' to '%s': %w", upload.File, upload.Target.Name(), err))
+				continue
+			}
+			files[upload.File] = file
+		}
+		err := p.upload(upload.Target, file)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("error uploading '%s' to '%s': %w", upload.File, upload.Target.Name(), err))
+			continue
+		}
+		p.logger.Infof("uploaded '%s' to %s", upload.File, upload.Location)
+	}
+	return helper.Join(errs...)
+}
+
+func (p *Publisher) upload(target Target, file File) error {
+	for attempt := 0; ; attempt++ {
+		start := time.Now()
+		err := target.Upload(file)
+		if err == nil {
+			p.logger.Debugf("uploaded '%s' (%d bytes, sha256 %s) to '%s' in %s", file.Name, file.Size, file.SHA256, target.Name(), time.Since(start).Round(time.Millisecond))
+			return nil
+		}
+		var permanent permanentError
+		if errors.As(err, &permanent) || attempt >= p.Retries {
+			return err
+		}
+		wait := p.Backoff << attempt
+		p.logger.Warnf("attempt %d to upload '%s' to '%s' failed: %v, retrying in %s", attempt+1, file.Name, target.Name(), err, wait)
+		time.Sleep(wait)
+	}
+}
+
+type TargetFactory struct {
+	toTarget map[string]func(name string, config map[string]interface{}) (Target, error)
+}
+
+func (f *TargetFactory) New(name string, typeName string, config map[string]interface{}) (Target, error) {
+	if toTarget, ok := f.toTarget[typeName]; ok {
+		if config == nil {
+			config = map[string]interface{}{}
+		}
+		return toTarget(name, config)
+	}
+	return nil, fmt.Errorf("unsupported publish target type: %s", typeName)
+}
+
+func (f *TargetFactory) Register(typeName string, conversion func(name string, config map[string]interface{}) (Target, error)) {
+	f.toTarget[typeName] = conversion
+}
+
+func NewTargetFactory() *TargetFactory {
+	return &TargetFactory{
+		toTarget: make(map[string]func(name string, config map[string]interface{}) (Target, error)),
+	}
+}
@@ -0,0 +1,151 @@
+package publish
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeTarget fails the first uploads with the given errors
+type fakeTarget struct {
+	name     string
+	errs     []error
+	attempts int
+	uploaded []File
+}
+
+func (f *fakeTarget) Location(name string) string {
+	return "fake://" + f.name + "/" + name
+}
+
+func (f *fakeTarget) Upload(file File) error {
+	f.attempts++
+	if len(f.errs) > 0 {
+		err := f.errs[0]
+		f.errs = f.errs[1:]
+		return err
+	}
+	f.uploaded = append(f.uploaded, file)
+	return nil
+}
+
+func (f *fakeTarget) Name() string {
+	return f.name
+}
+
+func writeFiles(t *testing.T) string {
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "qg-result.yaml"), []byte("overallStatus: GREEN"), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "evidence.zip"), []byte("evidence"), 0644))
+	return dir
+}
+
+func TestPlan(t *testing.T) {
+	first := &fakeTarget{name: "first"}
+	second := &fakeTarget{name: "second"}
+
+	uploads := Plan([]Target{first, second}, []string{"evidence.zip", "qg-result.yaml"})
+
+	assert.Equal(t, []Upload{
+		{Target: first, File: "evidence.zip", Location: "fake://first/evidence.zip"},
+		{Target: first, File: "qg-result.yaml", Location: "fake://first/qg-result.yaml"},
+		{Target: second, File: "evidence.zip", Location: "fake://second/evidence.zip"},
+		{Target: second, File: "qg-result.yaml", Location: "fake://second/qg-result.yaml"},
+	}, uploads)
+}
+
+func TestPublish(t *testing.T) {
+	newPublisher := func() *Publisher {
+		publisher := NewPublisher(2)
+		publisher.Backoff = time.Millisecond
+		return publisher
+	}
+
+	t.Run("should upload the files with their digests", func(t *testing.T) {
+		dir := writeFiles(t)
+		target := &fakeTarget{name: "target"}
+
+		err := newPublisher().Publish(Plan([]Target{target}, []string{"evidence.zip", "qg-result.yaml"}), dir)
+
+		require.NoError(t, err)
+		assert.Equal(t, []File{
+			{Name: "evidence.zip", Path: filepath.Join(dir, "evidence.zip"), Size: 8, SHA256: "ee8250fb76e094b34b471f13a73dbbe51d1ae142e9df59d7c0d31ec20f0a0a8e"},
+			{Name: "qg-result.yaml", Path: filepath.Join(dir, "qg-result.yaml"), Size: 20, SHA256: "18602fdb30015a4b7865f59b5f2964b9d9ec738a8cb8571ae9bed023e0c8a278"},
+		}, target.uploaded)
+	})
+
+	t.Run("should retry failed uploads", func(t *testing.T) {
+		dir := writeFiles(t)
+		target := &fakeTarget{name: "target", errs: []error{fmt.Errorf("connection reset"), fmt.Errorf("connection reset")}}
+
+		err := newPublisher().Publish(Plan([]Target{target}, []string{"qg-result.yaml"}), dir)
+
+		require.NoError(t, err)
+		assert.Equal(t, 3, target.attempts)
+		assert.Len(t, target.uploaded, 1)
+	})
+
+	t.Run("should not retry permanent errors", func(t *testing.T) {
+		dir := writeFiles(t)
+		target := &fakeTarget{name: "target", errs: []error{Permanent(fmt.Errorf("403 Forbidden"))}}
+
+		err := newPublisher().Publish(Plan([]Target{target}, []string{"qg-result.yaml"}), dir)
+
+		assert.EqualError(t, err, "error uploading 'qg-result.yaml' to 'target': 403 Forbidden")
+		assert.Equal(t, 1, target.attempts)
+	})
+
+	t.Run("should continue with the remaining uploads after a failed upload", func(t *testing.T) {
+		dir := writeFiles(t)
+		failing := &fakeTarget{name: "failing", errs: []error{fmt.Errorf("1"), fmt.Errorf("2"), fmt.Errorf("3")}}
+		working := &fakeTarget{name: "working"}
+
+		err := newPublisher().Publish(Plan([]Target{failing, working}, []string{"missing.zip", "qg-result.yaml"}), dir)
+
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "error uploading 'missing.zip' to 'failing'")
+		assert.Contains(t, err.Error(), "error uploading 'qg-result.yaml' to 'failing': 3")
+		assert.Contains(t, err.Error(), "error uploading 'missing.zip' to 'working'")
+		assert.Equal(t, 3, failing.attempts)
+		require.Len(t, working.uploaded, 1)
+		assert.Equal(t, "qg-result.yaml", working.uploaded[0].Name)
+	})
+}
+
+func TestContentType(t *testing.T) {
+	testCases := map[string]string{
+		"qg-result.yaml":      "application/yaml",
+		"evidence.zip":        "application/zip",
+		"evidence.tar.gz":     "application/gzip",
+		"evidence.tar.zst":    "application/zstd",
+		"evidence.unknown":    "application/octet-stream",
+		"QG-RESULT.JSON":      "application/json",
+		"qg-result.junit.xml": "application/xml",
+		"qg-result.sarif":     "application/sarif+json",
+	}
+	for name, contentType := range testCases {
+		t.Run(name, func(t *testing.T) {
+			assert.Equal(t, contentType, File{Name: name}.ContentType())
+		})
+	}
+}
+
+func TestTargetFactory(t *testing.T) {
+	factory := NewTargetFactory()
+	factory.Register("fake", func(name string, config map[string]interface{}) (Target, error) {
+		assert.NotNil(t, config)
+		return &fakeTarget{name: name}, nil
+	})
+
+	target, err := factory.New("my-target", "fake", nil)
+	require.NoError(t, err)
+	assert.Equal(t, "my-target", target.Name())
+
+	_, err = factory.New("my-target", "unknown", nil)
+	assert.EqualError(t, err, "unsupported publish target type: unknown")
+}
@@ -0,0 +1,58 @@
+package azblob
+
+import (
+	"fmt"
+
+	"github.com/B-S-F/onyx/pkg/repository/types/azblob"
+)
+
+type Config struct {
+	StorageAccountName      string
+	StorageAccountContainer string
+	// Path in the container the files are uploaded to, defaults to the root of the container
+	// Example "qg-results/my-project"
+	StorageAccountPath string
+	// Auth configuration, the same as for azure-blob-storage repositories
+	Auth *azblob.Auth
+}
+
+func (c *Config) Type() string {
+	return "azure-blob-storage"
+}
+
+func newConfig(config map[string]interface{}) (*Config, error) {
+	parsed := &Config{}
+	for key, value := range map[string]*string{
+		azblob.StorageAccountNameKey:      &parsed.StorageAccountName,
+		azblob.StorageAccountContainerKey: &parsed.StorageAccountContainer,
+		azblob.StorageAccountPathKey:      &parsed.StorageAccountPath,
+	} {
+		if config[key] == nil {
+			continue
+		}
+		var ok bool
+		*value, ok = config[key].(string)
+		if !ok {
+			return nil, fmt.Errorf("%s must be a string", key)
+		}
+	}
+	if parsed.StorageAccountName == "" {
+		return nil, fmt.Errorf("missing '%s' in config", azblob.StorageAccountNameKey)
+	}
+	if parsed.StorageAccountContainer == "" {
+		return nil, fmt.Errorf("missing '%s' in config", azblob.StorageAccountContainerKey)
+	}
+	if config["auth"] == nil {
+		return nil, fmt.Errorf("missing 'auth' in config")
+	}
+	authConfig, ok := config["auth"].(map[string]interface{})
+	if !ok {
+		return nil, fmt.Errorf("auth must be a map")
+	}
+	auth, err := azblob.NewAuth(authConfig)
+	if err != nil {
+		return nil, fmt.Errorf("error creating auth: %w", err)
+	}
+	parsed.Auth = auth
+	return parsed, nil
+}
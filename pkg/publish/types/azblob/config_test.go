@@ -0,0 +1,57 @@
+package azblob
+
+import (
+	"testing"
+
+	"github.com/B-S-F/onyx/pkg/repository/types/azblob"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewConfig(t *testing.T) {
+	auth := map[string]interface{}{"type": "storage_account_signature", "signature": "sv=2022-11-02&sig=abc"}
+
+	t.Run("should parse the config", func(t *testing.T) {
+		config, err := newConfig(map[string]interface{}{
+			"storage_account_name":      "myaccount",
+			"storage_account_container": "qg-results",
+			"storage_account_path":      "my-project",
+			"auth":                      auth,
+		})
+
+		require.NoError(t, err)
+		assert.Equal(t, "myaccount", config.StorageAccountName)
+		assert.Equal(t, "qg-results", config.StorageAccountContainer)
+		assert.Equal(t, "my-project", config.StorageAccountPath)
+		assert.Equal(t, azblob.SharedAccessSignatureAuthType, config.Auth.Type)
+	})
+
+	testCases := map[string]struct {
+		config map[string]interface{}
+		err    string
+	}{
+		"missing name":      {config: map[string]interface{}{"storage_account_container": "qg-results", "auth": auth}, err: "missing 'storage_account_name' in config"},
+		"missing container": {config: map[string]interface{}{"storage_account_name": "myaccount", "auth": auth}, err: "missing 'storage_account_container' in config"},
+		"missing auth":      {config: map[string]interface{}{"storage_account_name": "myaccount", "storage_account_container": "qg-results"}, err: "missing 'auth' in config"},
+		"invalid path":      {config: map[string]interface{}{"storage_account_name": "myaccount", "storage_account_container": "qg-results", "storage_account_path": 1, "auth": auth}, err: "storage_account_path must be a string"},
+		"invalid auth":      {config: map[string]interface{}{"storage_account_name": "myaccount", "storage_account_container": "qg-results", "auth": map[string]interface{}{"type": "unknown"}}, err: "error creating auth"},
+	}
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			_, err := newConfig(tc.config)
+			assert.ErrorContains(t, err, tc.err)
+		})
+	}
+}
+
+func TestLocation(t *testing.T) {
+	target, err := NewTarget("blob", map[string]interface{}{
+		"storage_account_name":      "myaccount",
+		"storage_account_container": "qg-results",
+		"storage_account_path":      "/my-project/",
+		"auth":                      map[string]interface{}{"type": "storage_account_signature", "signature": "sv=2022-11-02&sig=abc"},
+	})
+	require.NoError(t, err)
+
+	assert.Equal(t, "https://myaccount.blob.core.windows.net/qg-results/my-project/evidence.zip", target.Location("evidence.zip"))
+}
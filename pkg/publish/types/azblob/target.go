@@ -0,0 +1,69 @@
+package azblob
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path"
+	"strings"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/storage/azblob"
+	"github.com/Azure/azure-sdk-for-go/sdk/storage/azblob/blob"
+	"github.com/B-S-F/onyx/pkg/publish"
+	repository "github.com/B-S-F/onyx/pkg/repository/types/azblob"
+)
+
+// SHA256_METADATA is the metadata of the blobs which contains the hex encoded digest of the content
+const SHA256_METADATA = "sha256"
+
+type Target struct {
+	Config     Config
+	TargetName string
+	client     *azblob.Client
+}
+
+func NewTarget(name string, config map[string]interface{}) (publish.Target, error) {
+	parsed, err := newConfig(config)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create config: %w", err)
+	}
+	return &Target{Config: *parsed, TargetName: name}, nil
+}
+
+func (t *Target) blobName(name string) string {
+	return strings.TrimPrefix(path.Join(t.Config.StorageAccountPath, name), "/")
+}
+
+func (t *Target) Location(name string) string {
+	return repository.ServiceURL(t.Config.StorageAccountName) + "/" + t.Config.StorageAccountContainer + "/" + t.blobName(name)
+}
+
+// Upload uploads the file as block blob, the digest is stored in the metadata of the blob
+func (t *Target) Upload(file publish.File) error {
+	if t.client == nil {
+		client, err := repository.NewClient(t.Config.StorageAccountName, t.Config.Auth)
+		if err != nil {
+			return publish.Permanent(fmt.Errorf("failed to create blob client: %w", err))
+		}
+		t.client = client
+	}
+	source, err := os.Open(file.Path)
+	if err != nil {
+		return publish.Permanent(err)
+	}
+	defer source.Close()
+	digest := file.SHA256
+	contentType := file.ContentType()
+	_, err = t.client.UploadFile(context.Background(), t.Config.StorageAccountContainer, t.blobName(file.Name), source, &azblob.UploadFileOptions{
+		Metadata:    map[string]*string{SHA256_METADATA: &digest},
+		HTTPHeaders: &blob.HTTPHeaders{BlobContentType: &contentType},
+	})
+	if err != nil {
+		return fmt.Errorf("failed to upload blob: %w", err)
+	}
+	return nil
+}
+
+func (t *Target) Name() string {
+	return t.TargetName
+}
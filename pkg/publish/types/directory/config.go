@@ -0,0 +1,36 @@
+package directory
+
+import (
+	"fmt"
+	"path/filepath"
+)
+
+const PathKey = "path"
+
+type Config struct {
+	// Directory the files are copied to, it is created if it does not exist
+	// Example "/mnt/share/qg-results/my-project"
+	Path string
+}
+
+func (c *Config) Type() string {
+	return "directory"
+}
+
+func newConfig(config map[string]interface{}) (*Config, error) {
+	if config[PathKey] == nil {
+		return nil, fmt.Errorf("missing '%s' in config", PathKey)
+	}
+	path, ok := config[PathKey].(string)
+	if !ok {
+		return nil, fmt.Errorf("%s must be a string", PathKey)
+	}
+	if path == "" {
+		return nil, fmt.Errorf("missing '%s' in config", PathKey)
+	}
+	path, err := filepath.Abs(path)
+	if err != nil {
+		return nil, fmt.Errorf("invalid path '%s': %w", path, err)
+	}
+	return &Config{Path: path}, nil
+}
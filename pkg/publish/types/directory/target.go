@@ -0,0 +1,67 @@
+package directory
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+
+	"github.com/B-S-F/onyx/pkg/publish"
+)
+
+type Target struct {
+	Config     Config
+	TargetName string
+}
+
+func NewTarget(name string, config map[string]interface{}) (publish.Target, error) {
+	parsed, err := newConfig(config)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create config: %w", err)
+	}
+	return &Target{Config: *parsed, TargetName: name}, nil
+}
+
+func (t *Target) Location(name string) string {
+	return filepath.Join(t.Config.Path, name)
+}
+
+// Upload copies the file to a temporary file next to the destination, which replaces the destination if the digest matches
+func (t *Target) Upload(file publish.File) error {
+	err := os.MkdirAll(t.Config.Path, 0755)
+	if err != nil {
+		return fmt.Errorf("error creating directory: %w", err)
+	}
+	source, err := os.Open(file.Path)
+	if err != nil {
+		return publish.Permanent(err)
+	}
+	defer source.Close()
+	temp, err := os.CreateTemp(t.Config.Path, "."+file.Name+"-*")
+	if err != nil {
+		return fmt.Errorf("error creating file: %w", err)
+	}
+	defer os.Remove(temp.Name())
+	hash := sha256.New()
+	_, err = io.Copy(io.MultiWriter(temp, hash), source)
+	if cerr := temp.Close(); err == nil {
+		err = cerr
+	}
+	if err != nil {
+		return fmt.Errorf("error copying file: %w", err)
+	}
+	if digest := hex.EncodeToString(hash.Sum(nil)); digest != file.SHA256 {
+		return publish.Permanent(fmt.Errorf("file changed while it was uploaded, expected sha256 %s but got %s", file.SHA256, digest))
+	}
+	err = os.Chmod(temp.Name(), 0644)
+	if err != nil {
+		return fmt.Errorf("error changing file permissions: %w", err)
+	}
+	return os.Rename(temp.Name(), t.Location(file.Name))
+}
+
+func (t *Target) Name() string {
+	return t.TargetName
+}
@@ -0,0 +1,79 @@
+package directory
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/B-S-F/onyx/pkg/publish"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUpload(t *testing.T) {
+	source := filepath.Join(t.TempDir(), "qg-result.yaml")
+	require.NoError(t, os.WriteFile(source, []byte("overallStatus: GREEN"), 0600))
+	file, err := publish.NewFile(source)
+	require.NoError(t, err)
+
+	t.Run("should copy the file into the directory", func(t *testing.T) {
+		dir := filepath.Join(t.TempDir(), "results", "my-project")
+		target, err := NewTarget("share", map[string]interface{}{"path": dir})
+		require.NoError(t, err)
+
+		err = target.Upload(file)
+
+		require.NoError(t, err)
+		assert.Equal(t, filepath.Join(dir, "qg-result.yaml"), target.Location("qg-result.yaml"))
+		content, err := os.ReadFile(filepath.Join(dir, "qg-result.yaml"))
+		require.NoError(t, err)
+		assert.Equal(t, "overallStatus: GREEN", string(content))
+		entries, err := os.ReadDir(dir)
+		require.NoError(t, err)
+		assert.Len(t, entries, 1, "temporary file should be removed")
+	})
+
+	t.Run("should replace an existing file", func(t *testing.T) {
+		dir := t.TempDir()
+		require.NoError(t, os.WriteFile(filepath.Join(dir, "qg-result.yaml"), []byte("overallStatus: RED"), 0644))
+		target, err := NewTarget("share", map[string]interface{}{"path": dir})
+		require.NoError(t, err)
+
+		require.NoError(t, target.Upload(file))
+
+		content, err := os.ReadFile(filepath.Join(dir, "qg-result.yaml"))
+		require.NoError(t, err)
+		assert.Equal(t, "overallStatus: GREEN", string(content))
+	})
+
+	t.Run("should fail if the file does not match its digest", func(t *testing.T) {
+		dir := t.TempDir()
+		target, err := NewTarget("share", map[string]interface{}{"path": dir})
+		require.NoError(t, err)
+		changed := file
+		changed.SHA256 = "0000"
+
+		err = target.Upload(changed)
+
+		assert.ErrorContains(t, err, "file changed while it was uploaded")
+		_, err = os.Stat(filepath.Join(dir, "qg-result.yaml"))
+		assert.True(t, os.IsNotExist(err))
+	})
+}
+
+func TestNewConfig(t *testing.T) {
+	testCases := map[string]struct {
+		config map[string]interface{}
+		err    string
+	}{
+		"missing path": {config: map[string]interface{}{}, err: "missing 'path' in config"},
+		"empty path":   {config: map[string]interface{}{"path": ""}, err: "missing 'path' in config"},
+		"invalid path": {config: map[string]interface{}{"path": 1}, err: "path must be a string"},
+	}
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			_, err := NewTarget("share", tc.config)
+			assert.ErrorContains(t, err, tc.err)
+		})
+	}
+}
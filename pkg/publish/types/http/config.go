@@ -0,0 +1,112 @@
+package http
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/B-S-F/onyx/pkg/repository/types/curl"
+)
+
+// FILE_PLACEHOLDER is replaced with the name of the uploaded file in the url
+const FILE_PLACEHOLDER = "{file}"
+
+const UPLOAD_TIMEOUT = 5 * time.Minute
+
+type Config struct {
+	// URL the files are uploaded to, it must contain the {file} placeholder
+	// Example "https://my-file-server.com/qg-results/my-project/{file}"
+	URL string
+	// Method of the upload requests, PUT or POST, defaults to PUT
+	Method string
+	// Auth configuration, the same as for curl repositories
+	Auth *curl.Auth
+	// Additional headers sent with every upload
+	Headers map[string]string
+	// Timeout of a single upload attempt including the token request
+	Timeout time.Duration
+	// Proxy used for all requests, defaults to HTTP_PROXY/HTTPS_PROXY/NO_PROXY of the environment
+	Proxy string
+	// CA bundle (path or PEM content) which is trusted in addition to the system certificates
+	CABundle string
+	// Client certificate and key (path or PEM content) for mutual TLS
+	ClientCert string
+	ClientKey  string
+}
+
+func (c *Config) Type() string {
+	return "http"
+}
+
+func newConfig(config map[string]interface{}) (*Config, error) {
+	parsed := &Config{Method: http.MethodPut, Timeout: UPLOAD_TIMEOUT}
+	for key, value := range map[string]*string{
+		"url":         &parsed.URL,
+		"method":      &parsed.Method,
+		"proxy":       &parsed.Proxy,
+		"ca_bundle":   &parsed.CABundle,
+		"client_cert": &parsed.ClientCert,
+		"client_key":  &parsed.ClientKey,
+	} {
+		if config[key] == nil {
+			continue
+		}
+		var ok bool
+		*value, ok = config[key].(string)
+		if !ok {
+			return nil, fmt.Errorf("%s must be a string", key)
+		}
+	}
+	if parsed.URL == "" {
+		return nil, fmt.Errorf("missing 'url' in config")
+	}
+	if !strings.Contains(parsed.URL, FILE_PLACEHOLDER) {
+		return nil, fmt.Errorf("url does not contain %s placeholder", FILE_PLACEHOLDER)
+	}
+	if _, err := url.ParseRequestURI(strings.ReplaceAll(parsed.URL, FILE_PLACEHOLDER, "file")); err != nil {
+		return nil, fmt.Errorf("url must be a valid url: %w", err)
+	}
+	parsed.Method = strings.ToUpper(parsed.Method)
+	if parsed.Method != http.MethodPut && parsed.Method != http.MethodPost {
+		return nil, fmt.Errorf("unsupported method '%s', must be PUT or POST", parsed.Method)
+	}
+	if (parsed.ClientCert == "") != (parsed.ClientKey == "") {
+		return nil, fmt.Errorf("'client_cert' and 'client_key' must be set together")
+	}
+	if config["headers"] != nil {
+		headers, ok := config["headers"].(map[string]interface{})
+		if !ok {
+			return nil, fmt.Errorf("headers must be a map")
+		}
+		parsed.Headers = make(map[string]string, len(headers))
+		for key, value := range headers {
+			valueString, ok := value.(string)
+			if !ok {
+				return nil, fmt.Errorf("value of header '%s' must be a string", key)
+			}
+			parsed.Headers[key] = valueString
+		}
+	}
+	if config["timeout"] != nil {
+		var err error
+		parsed.Timeout, err = curl.ParseTimeout(config["timeout"])
+		if err != nil {
+			return nil, err
+		}
+	}
+	if config["auth"] == nil {
+		return parsed, nil
+	}
+	authConfig, ok := config["auth"].(map[string]interface{})
+	if !ok {
+		return nil, fmt.Errorf("auth must be a map")
+	}
+	auth, err := curl.NewAuth(authConfig)
+	if err != nil {
+		return nil, fmt.Errorf("error creating auth: %w", err)
+	}
+	parsed.Auth = auth
+	return parsed, nil
+}
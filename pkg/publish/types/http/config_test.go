@@ -0,0 +1,56 @@
+package http
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewConfig(t *testing.T) {
+	t.Run("should apply the defaults", func(t *testing.T) {
+		config, err := newConfig(map[string]interface{}{"url": "https://example.com/{file}"})
+
+		require.NoError(t, err)
+		assert.Equal(t, http.MethodPut, config.Method)
+		assert.Equal(t, UPLOAD_TIMEOUT, config.Timeout)
+		assert.Nil(t, config.Auth)
+	})
+
+	t.Run("should parse the config", func(t *testing.T) {
+		config, err := newConfig(map[string]interface{}{
+			"url":     "https://example.com/{file}",
+			"method":  "post",
+			"timeout": "10m",
+			"headers": map[string]interface{}{"X-Project": "my-project"},
+			"auth":    map[string]interface{}{"type": "basic", "username": "user", "password": "password"},
+		})
+
+		require.NoError(t, err)
+		assert.Equal(t, http.MethodPost, config.Method)
+		assert.Equal(t, 10*time.Minute, config.Timeout)
+		assert.Equal(t, map[string]string{"X-Project": "my-project"}, config.Headers)
+		assert.Equal(t, "basic", string(config.Auth.Type))
+	})
+
+	testCases := map[string]struct {
+		config map[string]interface{}
+		err    string
+	}{
+		"missing url":        {config: map[string]interface{}{}, err: "missing 'url' in config"},
+		"missing file":       {config: map[string]interface{}{"url": "https://example.com/qg-result.yaml"}, err: "url does not contain {file} placeholder"},
+		"invalid url":        {config: map[string]interface{}{"url": "example/{file}"}, err: "url must be a valid url"},
+		"unsupported method": {config: map[string]interface{}{"url": "https://example.com/{file}", "method": "PATCH"}, err: "unsupported method 'PATCH'"},
+		"invalid header":     {config: map[string]interface{}{"url": "https://example.com/{file}", "headers": map[string]interface{}{"X-Count": 1}}, err: "value of header 'X-Count' must be a string"},
+		"client cert only":   {config: map[string]interface{}{"url": "https://example.com/{file}", "client_cert": "cert.pem"}, err: "'client_cert' and 'client_key' must be set together"},
+		"invalid auth":       {config: map[string]interface{}{"url": "https://example.com/{file}", "auth": map[string]interface{}{"type": "unknown"}}, err: "error creating auth"},
+	}
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			_, err := newConfig(tc.config)
+			assert.ErrorContains(t, err, tc.err)
+		})
+	}
+}
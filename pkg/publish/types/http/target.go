@@ -0,0 +1,113 @@
+package http
+
+import (
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+
+	"github.com/B-S-F/onyx/pkg/publish"
+	"github.com/B-S-F/onyx/pkg/repository/download"
+	"github.com/B-S-F/onyx/pkg/repository/types/curl"
+)
+
+// CHECKSUM_HEADER contains the hex encoded digest of the content, it is supported by Artifactory and Nexus.
+// The digest is sent in the Content-Digest header of RFC 9530 as well.
+const CHECKSUM_HEADER = "X-Checksum-Sha256"
+
+type Target struct {
+	Config     Config
+	TargetName string
+	client     *http.Client
+}
+
+// invalidator is implemented by auths which cache credentials that can be revoked by the server
+type invalidator interface {
+	Invalidate()
+}
+
+func NewTarget(name string, config map[string]interface{}) (publish.Target, error) {
+	parsed, err := newConfig(config)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create config: %w", err)
+	}
+	client, err := curl.NewClient(curl.Config{
+		Proxy:      parsed.Proxy,
+		CABundle:   parsed.CABundle,
+		ClientCert: parsed.ClientCert,
+		ClientKey:  parsed.ClientKey,
+		Timeout:    parsed.Timeout,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error creating http client: %w", err)
+	}
+	return &Target{Config: *parsed, TargetName: name, client: client}, nil
+}
+
+func (t *Target) url(name string) string {
+	return strings.ReplaceAll(t.Config.URL, FILE_PLACEHOLDER, url.PathEscape(name))
+}
+
+func (t *Target) Location(name string) string {
+	return download.RedactURL(t.url(name))
+}
+
+// Upload sends the file as body of a single request, a rejected cached token is requested again with the next attempt
+func (t *Target) Upload(file publish.File) error {
+	source, err := os.Open(file.Path)
+	if err != nil {
+		return publish.Permanent(err)
+	}
+	defer source.Close()
+	request, err := http.NewRequest(t.Config.Method, t.url(file.Name), source)
+	if err != nil {
+		return publish.Permanent(fmt.Errorf("error creating request: %w", err))
+	}
+	request.ContentLength = file.Size
+	digest, err := hex.DecodeString(file.SHA256)
+	if err != nil {
+		return publish.Permanent(fmt.Errorf("invalid digest: %w", err))
+	}
+	request.Header.Set("Content-Type", file.ContentType())
+	request.Header.Set("Content-Digest", "sha-256=:"+base64.StdEncoding.EncodeToString(digest)+":")
+	request.Header.Set(CHECKSUM_HEADER, file.SHA256)
+	for key, value := range t.Config.Headers {
+		request.Header.Set(key, value)
+	}
+	if t.Config.Auth != nil {
+		header, err := t.Config.Auth.Config.Header(t.client)
+		if err != nil {
+			return fmt.Errorf("error getting auth header: %w", err)
+		}
+		request.Header.Set("Authorization", header)
+	}
+
+	response, err := t.client.Do(request)
+	if err != nil {
+		return err
+	}
+	defer response.Body.Close()
+	_, _ = io.Copy(io.Discard, io.LimitReader(response.Body, 1<<20))
+	if response.StatusCode >= 200 && response.StatusCode < 300 {
+		return nil
+	}
+	statusError := &download.StatusError{StatusCode: response.StatusCode, Status: response.Status}
+	switch {
+	case response.StatusCode == http.StatusUnauthorized && t.Config.Auth != nil:
+		if auth, ok := t.Config.Auth.Config.(invalidator); ok {
+			auth.Invalidate()
+			return statusError
+		}
+	case response.StatusCode == http.StatusTooManyRequests || response.StatusCode >= 500:
+		return statusError
+	}
+	return publish.Permanent(statusError)
+}
+
+func (t *Target) Name() string {
+	return t.TargetName
+}
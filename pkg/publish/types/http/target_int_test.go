@@ -0,0 +1,144 @@
+//go:build integration
+// +build integration
+
+package http
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/B-S-F/onyx/pkg/publish"
+	"github.com/B-S-F/onyx/pkg/repository/download"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type request struct {
+	method  string
+	path    string
+	header  http.Header
+	content string
+}
+
+// newServer records the requests and answers with the given status codes, the last one is repeated
+func newServer(t *testing.T, statusCodes ...int) (*httptest.Server, *[]request) {
+	var requests []request
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		content, err := io.ReadAll(r.Body)
+		require.NoError(t, err)
+		requests = append(requests, request{method: r.Method, path: r.URL.Path, header: r.Header, content: string(content)})
+		statusCode := statusCodes[0]
+		if len(statusCodes) > 1 {
+			statusCodes = statusCodes[1:]
+		}
+		w.WriteHeader(statusCode)
+	}))
+	t.Cleanup(server.Close)
+	return server, &requests
+}
+
+func newFile(t *testing.T) publish.File {
+	path := filepath.Join(t.TempDir(), "qg-result.yaml")
+	require.NoError(t, os.WriteFile(path, []byte("overallStatus: GREEN"), 0644))
+	file, err := publish.NewFile(path)
+	require.NoError(t, err)
+	return file
+}
+
+func TestUpload(t *testing.T) {
+	file := newFile(t)
+
+	t.Run("should put the file with its digest", func(t *testing.T) {
+		server, requests := newServer(t, http.StatusCreated)
+		target, err := NewTarget("results", map[string]interface{}{
+			"url":     server.URL + "/results/{file}",
+			"headers": map[string]interface{}{"X-Project": "my-project"},
+			"auth":    map[string]interface{}{"type": "token", "token": "my-token"},
+		})
+		require.NoError(t, err)
+
+		err = target.Upload(file)
+
+		require.NoError(t, err)
+		require.Len(t, *requests, 1)
+		received := (*requests)[0]
+		assert.Equal(t, http.MethodPut, received.method)
+		assert.Equal(t, "/results/qg-result.yaml", received.path)
+		assert.Equal(t, "overallStatus: GREEN", received.content)
+		assert.Equal(t, "application/yaml", received.header.Get("Content-Type"))
+		assert.Equal(t, file.SHA256, received.header.Get(CHECKSUM_HEADER))
+		assert.Equal(t, "sha-256=:GGAv2zABWkt4ZfWbXylkudnsc4qMuFca6b7QI+DIong=:", received.header.Get("Content-Digest"))
+		assert.Equal(t, "Bearer my-token", received.header.Get("Authorization"))
+		assert.Equal(t, "my-project", received.header.Get("X-Project"))
+		assert.Equal(t, server.URL+"/results/qg-result.yaml", target.Location("qg-result.yaml"))
+	})
+
+	t.Run("should post the file", func(t *testing.T) {
+		server, requests := newServer(t, http.StatusOK)
+		target, err := NewTarget("results", map[string]interface{}{"url": server.URL + "/upload?name={file}", "method": "post"})
+		require.NoError(t, err)
+
+		require.NoError(t, target.Upload(file))
+
+		require.Len(t, *requests, 1)
+		assert.Equal(t, http.MethodPost, (*requests)[0].method)
+	})
+
+	t.Run("should retry server errors", func(t *testing.T) {
+		server, requests := newServer(t, http.StatusBadGateway, http.StatusCreated)
+		target, err := NewTarget("results", map[string]interface{}{"url": server.URL + "/{file}"})
+		require.NoError(t, err)
+		publisher := publish.NewPublisher(1)
+		publisher.Backoff = 0
+
+		err = publisher.Publish(publish.Plan([]publish.Target{target}, []string{file.Name}), filepath.Dir(file.Path))
+
+		require.NoError(t, err)
+		assert.Len(t, *requests, 2)
+		assert.Equal(t, (*requests)[0].content, (*requests)[1].content, "every attempt should send the whole file")
+	})
+
+	t.Run("should not retry rejected uploads", func(t *testing.T) {
+		server, requests := newServer(t, http.StatusForbidden)
+		target, err := NewTarget("results", map[string]interface{}{"url": server.URL + "/{file}"})
+		require.NoError(t, err)
+		publisher := publish.NewPublisher(2)
+		publisher.Backoff = 0
+
+		err = publisher.Publish(publish.Plan([]publish.Target{target}, []string{file.Name}), filepath.Dir(file.Path))
+
+		var statusError *download.StatusError
+		require.True(t, errors.As(err, &statusError))
+		assert.Equal(t, http.StatusForbidden, statusError.StatusCode)
+		assert.Len(t, *requests, 1)
+	})
+
+	t.Run("should request a new oauth2 token after it was rejected", func(t *testing.T) {
+		var tokens int
+		tokenServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			tokens++
+			w.Header().Set("Content-Type", "application/json")
+			_, _ = w.Write([]byte(`{"access_token":"token","token_type":"Bearer","expires_in":3600}`))
+		}))
+		t.Cleanup(tokenServer.Close)
+		server, requests := newServer(t, http.StatusUnauthorized, http.StatusCreated)
+		target, err := NewTarget("results", map[string]interface{}{
+			"url":  server.URL + "/{file}",
+			"auth": map[string]interface{}{"type": "oauth2", "token_url": tokenServer.URL, "client_id": "id", "client_secret": "secret"},
+		})
+		require.NoError(t, err)
+		publisher := publish.NewPublisher(1)
+		publisher.Backoff = 0
+
+		err = publisher.Publish(publish.Plan([]publish.Target{target}, []string{file.Name}), filepath.Dir(file.Path))
+
+		require.NoError(t, err)
+		assert.Len(t, *requests, 2)
+		assert.Equal(t, 2, tokens)
+	})
+}
@@ -0,0 +1,77 @@
+package s3
+
+import (
+	"fmt"
+
+	"github.com/B-S-F/onyx/pkg/repository/types/s3"
+)
+
+const defaultEndpoint = "https://s3.amazonaws.com"
+
+type Config struct {
+	// Name of the bucket the files are uploaded to
+	Bucket string
+	// Prefix of the object keys, defaults to the root of the bucket
+	// Example "qg-results/my-project"
+	Prefix string
+	// Region of the bucket
+	// Example "eu-central-1"
+	Region string
+	// Endpoint of the S3 compatible storage, defaults to AWS S3
+	// Example "http://localhost:9000"
+	Endpoint string
+	// Address the bucket as part of the path instead of the host name (required by most MinIO setups)
+	PathStyle bool
+	// Auth configuration, the same as for s3 repositories, defaults to the environment credential chain
+	Auth *s3.Auth
+}
+
+func (c *Config) Type() string {
+	return "s3"
+}
+
+func newConfig(config map[string]interface{}) (*Config, error) {
+	parsed := &Config{}
+	for key, value := range map[string]*string{
+		s3.BucketKey:   &parsed.Bucket,
+		s3.PrefixKey:   &parsed.Prefix,
+		s3.RegionKey:   &parsed.Region,
+		s3.EndpointKey: &parsed.Endpoint,
+	} {
+		if config[key] == nil {
+			continue
+		}
+		var ok bool
+		*value, ok = config[key].(string)
+		if !ok {
+			return nil, fmt.Errorf("%s must be a string", key)
+		}
+	}
+	if parsed.Bucket == "" {
+		return nil, fmt.Errorf("missing '%s' in config", s3.BucketKey)
+	}
+	if parsed.Endpoint == "" {
+		parsed.Endpoint = defaultEndpoint
+	}
+	if config[s3.PathStyleKey] != nil {
+		var ok bool
+		parsed.PathStyle, ok = config[s3.PathStyleKey].(bool)
+		if !ok {
+			return nil, fmt.Errorf("%s must be a boolean", s3.PathStyleKey)
+		}
+	}
+	authConfig := map[string]interface{}{"type": string(s3.EnvironmentAuthType)}
+	if config["auth"] != nil {
+		var ok bool
+		authConfig, ok = config["auth"].(map[string]interface{})
+		if !ok {
+			return nil, fmt.Errorf("auth must be a map")
+		}
+	}
+	auth, err := s3.NewAuth(authConfig)
+	if err != nil {
+		return nil, fmt.Errorf("error creating auth: %w", err)
+	}
+	parsed.Auth = auth
+	return parsed, nil
+}
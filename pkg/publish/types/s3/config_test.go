@@ -0,0 +1,43 @@
+package s3
+
+import (
+	"testing"
+
+	"github.com/B-S-F/onyx/pkg/repository/types/s3"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewConfig(t *testing.T) {
+	t.Run("should default to AWS S3 with the environment credentials", func(t *testing.T) {
+		config, err := newConfig(map[string]interface{}{"bucket": "results"})
+
+		require.NoError(t, err)
+		assert.Equal(t, defaultEndpoint, config.Endpoint)
+		assert.Equal(t, s3.EnvironmentAuthType, config.Auth.Type)
+		assert.Empty(t, config.Prefix)
+	})
+
+	testCases := map[string]struct {
+		config map[string]interface{}
+		err    string
+	}{
+		"missing bucket":     {config: map[string]interface{}{}, err: "missing 'bucket' in config"},
+		"invalid prefix":     {config: map[string]interface{}{"bucket": "results", "prefix": 1}, err: "prefix must be a string"},
+		"invalid path style": {config: map[string]interface{}{"bucket": "results", "path_style": "yes"}, err: "path_style must be a boolean"},
+		"invalid auth":       {config: map[string]interface{}{"bucket": "results", "auth": "key"}, err: "auth must be a map"},
+	}
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			_, err := newConfig(tc.config)
+			assert.ErrorContains(t, err, tc.err)
+		})
+	}
+}
+
+func TestLocation(t *testing.T) {
+	target, err := NewTarget("bucket", map[string]interface{}{"bucket": "results", "prefix": "/my-project", "endpoint": "minio.example.com:9000"})
+	require.NoError(t, err)
+
+	assert.Equal(t, "https://minio.example.com:9000/results/my-project/qg-result.yaml", target.Location("qg-result.yaml"))
+}
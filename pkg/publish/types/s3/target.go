@@ -0,0 +1,80 @@
+package s3
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"os"
+	"path"
+	"strings"
+
+	"github.com/B-S-F/onyx/pkg/publish"
+	"github.com/B-S-F/onyx/pkg/repository/download"
+	"github.com/B-S-F/onyx/pkg/repository/types/s3"
+	"github.com/minio/minio-go/v7"
+)
+
+// SHA256_METADATA is the user metadata of the objects which contains the hex encoded digest of the content
+const SHA256_METADATA = "sha256"
+
+type Target struct {
+	Config     Config
+	TargetName string
+	client     *minio.Client
+}
+
+func NewTarget(name string, config map[string]interface{}) (publish.Target, error) {
+	parsed, err := newConfig(config)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create config: %w", err)
+	}
+	client, err := s3.NewClient(&s3.Config{
+		Bucket:    parsed.Bucket,
+		Region:    parsed.Region,
+		Endpoint:  parsed.Endpoint,
+		PathStyle: parsed.PathStyle,
+		Auth:      parsed.Auth,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error creating s3 client: %w", err)
+	}
+	return &Target{Config: *parsed, TargetName: name, client: client}, nil
+}
+
+func (t *Target) objectKey(name string) string {
+	return strings.TrimPrefix(path.Join(t.Config.Prefix, name), "/")
+}
+
+func (t *Target) Location(name string) string {
+	endpoint := strings.TrimSuffix(t.Config.Endpoint, "/")
+	if !strings.Contains(endpoint, "://") {
+		endpoint = "https://" + endpoint
+	}
+	return download.RedactURL(endpoint + "/" + t.Config.Bucket + "/" + t.objectKey(name))
+}
+
+// Upload puts the object with an MD5 checksum of the transfer, the digest is stored in the user metadata of the object
+func (t *Target) Upload(file publish.File) error {
+	source, err := os.Open(file.Path)
+	if err != nil {
+		return publish.Permanent(err)
+	}
+	defer source.Close()
+	_, err = t.client.PutObject(context.Background(), t.Config.Bucket, t.objectKey(file.Name), source, file.Size, minio.PutObjectOptions{
+		ContentType:    file.ContentType(),
+		UserMetadata:   map[string]string{SHA256_METADATA: file.SHA256},
+		SendContentMd5: true,
+	})
+	if err != nil {
+		switch minio.ToErrorResponse(err).StatusCode {
+		case http.StatusBadRequest, http.StatusForbidden, http.StatusNotFound:
+			return publish.Permanent(fmt.Errorf("failed to upload object: %w", err))
+		}
+		return fmt.Errorf("failed to upload object: %w", err)
+	}
+	return nil
+}
+
+func (t *Target) Name() string {
+	return t.TargetName
+}
@@ -0,0 +1,122 @@
+//go:build integration
+// +build integration
+
+package s3
+
+import (
+	"crypto/md5"
+	"encoding/base64"
+	"encoding/hex"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/B-S-F/onyx/pkg/publish"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newFakeS3 stores path-style put object requests which are signed with the access key
+func newFakeS3(t *testing.T, accessKeyID string, objects map[string]*http.Request, contents map[string][]byte) *httptest.Server {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !strings.Contains(r.Header.Get("Authorization"), "Credential="+accessKeyID+"/") {
+			w.WriteHeader(http.StatusForbidden)
+			_, _ = w.Write([]byte(`<?xml version="1.0" encoding="UTF-8"?><Error><Code>AccessDenied</Code><Message>Access Denied</Message></Error>`))
+			return
+		}
+		if r.Method != http.MethodPut {
+			w.WriteHeader(http.StatusMethodNotAllowed)
+			return
+		}
+		content, err := io.ReadAll(r.Body)
+		require.NoError(t, err)
+		if strings.HasPrefix(r.Header.Get("X-Amz-Content-Sha256"), "STREAMING-") {
+			content = decodeChunks(t, content)
+		}
+		digest := md5.Sum(content)
+		if r.Header.Get("Content-Md5") != base64.StdEncoding.EncodeToString(digest[:]) {
+			w.WriteHeader(http.StatusBadRequest)
+			_, _ = w.Write([]byte(`<?xml version="1.0" encoding="UTF-8"?><Error><Code>BadDigest</Code><Message>The Content-MD5 you specified did not match what we received.</Message></Error>`))
+			return
+		}
+		objects[r.URL.Path] = r
+		contents[r.URL.Path] = content
+		w.Header().Set("ETag", `"`+hex.EncodeToString(digest[:])+`"`)
+		w.WriteHeader(http.StatusOK)
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+// decodeChunks returns the data of a body with signed chunks, which minio-go sends to plain http endpoints
+func decodeChunks(t *testing.T, body []byte) []byte {
+	var content []byte
+	for {
+		header, rest, ok := strings.Cut(string(body), "\r\n")
+		require.True(t, ok, "invalid chunk")
+		sizeHex, _, _ := strings.Cut(header, ";")
+		size, err := strconv.ParseInt(sizeHex, 16, 64)
+		require.NoError(t, err)
+		if size == 0 {
+			return content
+		}
+		content = append(content, rest[:size]...)
+		body = []byte(strings.TrimPrefix(rest[size:], "\r\n"))
+	}
+}
+
+func TestUpload(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "evidence.zip")
+	require.NoError(t, os.WriteFile(path, []byte("evidence"), 0644))
+	file, err := publish.NewFile(path)
+	require.NoError(t, err)
+	auth := map[string]interface{}{"type": "access_key", "access_key_id": "testKeyID", "secret_access_key": "testSecret"}
+
+	t.Run("should put the object with its digest", func(t *testing.T) {
+		objects := map[string]*http.Request{}
+		contents := map[string][]byte{}
+		server := newFakeS3(t, "testKeyID", objects, contents)
+		target, err := NewTarget("bucket", map[string]interface{}{
+			"bucket":     "results",
+			"prefix":     "my-project/",
+			"region":     "us-east-1",
+			"endpoint":   server.URL,
+			"path_style": true,
+			"auth":       auth,
+		})
+		require.NoError(t, err)
+
+		err = target.Upload(file)
+
+		require.NoError(t, err)
+		require.Contains(t, objects, "/results/my-project/evidence.zip")
+		object := objects["/results/my-project/evidence.zip"]
+		assert.Equal(t, "evidence", string(contents["/results/my-project/evidence.zip"]))
+		assert.Equal(t, file.SHA256, object.Header.Get("X-Amz-Meta-Sha256"))
+		assert.Equal(t, "application/zip", object.Header.Get("Content-Type"))
+		assert.Equal(t, server.URL+"/results/my-project/evidence.zip", target.Location("evidence.zip"))
+	})
+
+	t.Run("should not retry denied uploads", func(t *testing.T) {
+		server := newFakeS3(t, "otherKeyID", map[string]*http.Request{}, map[string][]byte{})
+		target, err := NewTarget("bucket", map[string]interface{}{
+			"bucket":     "results",
+			"region":     "us-east-1",
+			"endpoint":   server.URL,
+			"path_style": true,
+			"auth":       auth,
+		})
+		require.NoError(t, err)
+		publisher := publish.NewPublisher(2)
+		publisher.Backoff = 0
+
+		err = publisher.Publish(publish.Plan([]publish.Target{target}, []string{file.Name}), filepath.Dir(file.Path))
+
+		assert.ErrorContains(t, err, "Access Denied")
+	})
+}
@@ -5,7 +5,9 @@ import (
 	"os"
 	"path/filepath"
 
+	"filippo.io/age"
 	"github.com/B-S-F/onyx/pkg/logger"
+	"github.com/B-S-F/onyx/pkg/secrets"
 	"github.com/pkg/errors"
 )
 
@@ -16,15 +18,19 @@ type FileReader interface {
 
 type readFile func(name string) ([]byte, error)
 
+type readIdentities func(identityFile string) ([]age.Identity, error)
+
 type fileReader struct {
-	logger logger.Logger
-	reader readFile
+	logger     logger.Logger
+	reader     readFile
+	identities readIdentities
 }
 
 func New() FileReader {
 	return &fileReader{
-		logger: logger.Get(),
-		reader: os.ReadFile,
+		logger:     logger.Get(),
+		reader:     os.ReadFile,
+		identities: secrets.Identities,
 	}
 }
 
@@ -42,6 +48,12 @@ func (h *fileReader) ReadJsonMap(name string) (map[string]string, error) {
 	if err != nil {
 		return nil, err
 	}
+	if secrets.IsEncrypted(content) {
+		content, err = h.decrypt(name, content)
+		if err != nil {
+			return nil, err
+		}
+	}
 	m := make(map[string]string)
 	if len(content) > 0 {
 		if err := json.Unmarshal(content, &m); err != nil {
@@ -50,3 +62,17 @@ func (h *fileReader) ReadJsonMap(name string) (map[string]string, error) {
 	}
 	return m, nil
 }
+
+// decrypt decrypts age encrypted files in memory, the identities are read from the environment
+func (h *fileReader) decrypt(name string, content []byte) ([]byte, error) {
+	h.logger.Infof("decrypting file '%s'", filepath.Base(name))
+	identities, err := h.identities("")
+	if err != nil {
+		return nil, errors.Wrapf(err, "error decrypting file '%s'", name)
+	}
+	plain, err := secrets.Decrypt(content, identities)
+	if err != nil {
+		return nil, errors.Wrapf(err, "error decrypting file '%s'", name)
+	}
+	return plain, nil
+}
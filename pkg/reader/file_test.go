@@ -7,9 +7,12 @@ import (
 	"fmt"
 	"testing"
 
+	"filippo.io/age"
 	"github.com/B-S-F/onyx/pkg/logger"
+	"github.com/B-S-F/onyx/pkg/secrets"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
 	"go.uber.org/zap"
 )
 
@@ -176,3 +179,49 @@ func TestParseJsonMap(t *testing.T) {
 		}
 	})
 }
+
+func TestReadEncryptedJsonMap(t *testing.T) {
+	identity, err := age.GenerateX25519Identity()
+	require.NoError(t, err)
+	encrypted, err := secrets.Encrypt([]byte(`{"SECRET": "secret"}`), []age.Recipient{identity.Recipient()}, true)
+	require.NoError(t, err)
+
+	t.Run("should decrypt an age encrypted file", func(t *testing.T) {
+		// arrange
+		mock := &mockFileRead{}
+		r := &fileReader{
+			logger: nopLogger,
+			reader: mock.readFile,
+			identities: func(string) ([]age.Identity, error) {
+				return []age.Identity{identity}, nil
+			},
+		}
+		mock.On("readFile", ".secrets.age").Return(encrypted, nil).Once()
+
+		// act
+		secrets, err := r.ReadJsonMap(".secrets.age")
+
+		// assert
+		assert.NoError(t, err)
+		assert.Equal(t, map[string]string{"SECRET": "secret"}, secrets)
+	})
+
+	t.Run("should return an error if no identity is available", func(t *testing.T) {
+		// arrange
+		mock := &mockFileRead{}
+		r := &fileReader{
+			logger: nopLogger,
+			reader: mock.readFile,
+			identities: func(string) ([]age.Identity, error) {
+				return nil, assert.AnError
+			},
+		}
+		mock.On("readFile", ".secrets.age").Return(encrypted, nil).Once()
+
+		// act
+		_, err := r.ReadJsonMap(".secrets.age")
+
+		// assert
+		assert.ErrorContains(t, err, "error decrypting file '.secrets.age'")
+	})
+}
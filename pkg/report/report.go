@@ -0,0 +1,232 @@
+package report
+
+import (
+	"bytes"
+	"embed"
+	"fmt"
+	htmlTemplate "html/template"
+	"os"
+	"path/filepath"
+	"strings"
+	textTemplate "text/template"
+
+	"github.com/B-S-F/onyx/pkg/helper"
+	"github.com/B-S-F/onyx/pkg/v2/result"
+)
+
+const (
+	FORMAT_HTML     = "html"
+	FORMAT_MARKDOWN = "markdown"
+)
+
+// Formats lists the formats a report can be rendered in
+var Formats = []string{FORMAT_HTML, FORMAT_MARKDOWN}
+
+//go:embed templates
+var templates embed.FS
+
+var defaultTemplates = map[string]string{
+	FORMAT_HTML:     "templates/report.html.tmpl",
+	FORMAT_MARKDOWN: "templates/report.md.tmpl",
+}
+
+// Report is the data the templates are executed with
+type Report struct {
+	Header        result.Header
+	OverallStatus string
+	Statistics    result.Statistics
+	Chapters      []Chapter
+	// Failed lists the checks which are RED, YELLOW or ERROR in the order of the chapters
+	Failed      []Check
+	ToolVersion string
+}
+
+type Chapter struct {
+	ID           string
+	Title        string
+	Text         string
+	Status       string
+	Requirements []Requirement
+}
+
+type Requirement struct {
+	ID     string
+	Title  string
+	Text   string
+	Status string
+	Checks []Check
+}
+
+type Check struct {
+	// ID of the check as <chapter>_<requirement>_<check>
+	ID     string
+	Title  string
+	Type   string
+	Status string
+	Reason string
+	// Criteria which are not fulfilled
+	Criteria []result.EvaluationResult
+	Warnings []string
+	Messages []string
+	Logs     []string
+}
+
+// New creates the report data of a result, the chapters, requirements and checks are ordered by their ids,
+// chapters and requirements without checks are part of the report as well
+func New(res *result.Result) *Report {
+	report := &Report{
+		Header:        res.Header,
+		OverallStatus: res.OverallStatus,
+		Statistics:    res.Statistics,
+		ToolVersion:   helper.ToolVersion,
+	}
+	for _, chapterId := range sortedIds(res.Chapters) {
+		chapter := res.Chapters[chapterId]
+		if chapter == nil {
+			continue
+		}
+		c := Chapter{ID: chapterId, Title: chapter.Title, Text: chapter.Text, Status: chapter.Status}
+		for _, requirementId := range sortedIds(chapter.Requirements) {
+			requirement := chapter.Requirements[requirementId]
+			if requirement == nil {
+				continue
+			}
+			r := Requirement{ID: requirementId, Title: requirement.Title, Text: requirement.Text, Status: requirement.Status}
+			for _, checkId := range sortedIds(requirement.Checks) {
+				check := requirement.Checks[checkId]
+				if check == nil {
+					continue
+				}
+				item := newCheck(result.CheckRef{Chapter: chapterId, Requirement: requirementId, Check: checkId}, check)
+				r.Checks = append(r.Checks, item)
+				switch item.Status {
+				case "RED", "YELLOW", "ERROR":
+					report.Failed = append(report.Failed, item)
+				}
+			}
+			c.Requirements = append(c.Requirements, r)
+		}
+		report.Chapters = append(report.Chapters, c)
+	}
+	return report
+}
+
+func newCheck(ref result.CheckRef, check *result.Check) Check {
+	item := Check{
+		ID:       ref.String(),
+		Title:    check.Title,
+		Type:     check.Type,
+		Status:   check.Evaluation.Status,
+		Reason:   strings.TrimSpace(check.Evaluation.Reason),
+		Warnings: check.Evaluation.Warnings,
+		Messages: check.Evaluation.Messages,
+		Logs:     check.Evaluation.Logs,
+	}
+	for _, criterion := range check.Evaluation.Results {
+		if !criterion.Fulfilled {
+			item.Criteria = append(item.Criteria, criterion)
+		}
+	}
+	return item
+}
+
+// sortedIds returns the ids of the map ordered like result.Walk
+func sortedIds[T any](m map[string]T) []string {
+	ids := make([]string, 0, len(m))
+	for id := range m {
+		ids = append(ids, id)
+	}
+	result.SortIDs(ids)
+	return ids
+}
+
+var funcs = map[string]interface{}{
+	"lower": strings.ToLower,
+	"trim":  func(s interface{}) string { return strings.TrimSpace(fmt.Sprint(s)) },
+	"emoji": emoji,
+	"color": color,
+	"cell":  cell,
+	"percent": func(value float64) string {
+		return fmt.Sprintf("%.0f%%", value)
+	},
+}
+
+// Render renders the report of the result in the format with the default template or the template file
+// HTML templates are html/template files, Markdown templates text/template files, both have access to the Report
+func Render(res *result.Result, format string, templateFile string) ([]byte, error) {
+	name, ok := defaultTemplates[format]
+	if !ok {
+		return nil, fmt.Errorf("unsupported report format '%s', must be one of: %s", format, strings.Join(Formats, ", "))
+	}
+	var content []byte
+	var err error
+	if templateFile != "" {
+		name = templateFile
+		content, err = os.ReadFile(templateFile)
+	} else {
+		content, err = templates.ReadFile(name)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error reading report template '%s': %w", name, err)
+	}
+
+	var buffer bytes.Buffer
+	report := New(res)
+	if format == FORMAT_HTML {
+		tmpl, err := htmlTemplate.New(filepath.Base(name)).Funcs(funcs).Parse(string(content))
+		if err != nil {
+			return nil, fmt.Errorf("error parsing report template '%s': %w", name, err)
+		}
+		err = tmpl.Execute(&buffer, report)
+		if err != nil {
+			return nil, fmt.Errorf("error rendering report template '%s': %w", name, err)
+		}
+		return buffer.Bytes(), nil
+	}
+	tmpl, err := textTemplate.New(filepath.Base(name)).Funcs(funcs).Parse(string(content))
+	if err != nil {
+		return nil, fmt.Errorf("error parsing report template '%s': %w", name, err)
+	}
+	err = tmpl.Execute(&buffer, report)
+	if err != nil {
+		return nil, fmt.Errorf("error rendering report template '%s': %w", name, err)
+	}
+	return buffer.Bytes(), nil
+}
+
+func emoji(status string) string {
+	switch status {
+	case "GREEN":
+		return "🟢"
+	case "YELLOW":
+		return "🟡"
+	case "RED":
+		return "🔴"
+	case "ERROR":
+		return "❌"
+	default:
+		return "⚪"
+	}
+}
+
+func color(status string) string {
+	switch status {
+	case "GREEN":
+		return "#2e7d32"
+	case "YELLOW":
+		return "#f9a825"
+	case "RED":
+		return "#c62828"
+	case "ERROR":
+		return "#6a1b9a"
+	default:
+		return "#757575"
+	}
+}
+
+// cell escapes a value for a Markdown table cell, line breaks become spaces
+func cell(value interface{}) string {
+	s := strings.TrimSpace(fmt.Sprint(value))
+	s = strings.ReplaceAll(s, "|", "\\|")
+	return strings.Join(strings.Fields(s), " ")
+}
@@ -0,0 +1,121 @@
+package report
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/B-S-F/onyx/pkg/result/common"
+	"github.com/B-S-F/onyx/pkg/v2/result"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func testResult() *result.Result {
+	return &result.Result{
+		Header:        result.Header{Name: "My Project", Version: "1.0", Date: "2024-01-01T12:00:00Z"},
+		OverallStatus: "RED",
+		Statistics:    result.Statistics{CountChecks: 3, CountAutomatedChecks: 2, CountManualChecks: 1, PercentageAutomated: 66.67, PercentageDone: 100},
+		Chapters: map[string]*result.Chapter{
+			"10": {Title: "Later", Status: "GREEN", Requirements: map[string]*result.Requirement{
+				"1": {Title: "Manual", Status: "GREEN", Checks: map[string]*result.Check{
+					"1": {Title: "manual check", Type: "manual", Evaluation: result.Evaluation{Status: "GREEN", Reason: "answered"}},
+				}},
+			}},
+			"2": {Title: "Security", Status: "RED", Requirements: map[string]*result.Requirement{
+				"1": {Title: "Tickets | Bugs", Text: "No open tickets", Status: "RED", Checks: map[string]*result.Check{
+					"1": {Title: "open tickets", Type: "automation", Evaluation: result.Evaluation{
+						Status: "RED",
+						Reason: "<b>found</b> tickets",
+						Logs:   []string{`{"source":"stdout","text":"<script>"}`},
+						Results: []result.EvaluationResult{
+							{Criterion: "ticket 42", Justification: "is open\nsince a week", Metadata: common.StringMap{"id": "42"}},
+							{Criterion: "ticket 43", Fulfilled: true},
+						},
+					}},
+					"2": {Title: "closed tickets", Type: "automation", Evaluation: result.Evaluation{Status: "GREEN", Reason: "fine"}},
+				}},
+			}},
+		},
+	}
+}
+
+func TestNew(t *testing.T) {
+	report := New(testResult())
+
+	require.Len(t, report.Chapters, 2)
+	assert.Equal(t, "2", report.Chapters[0].ID)
+	assert.Equal(t, "10", report.Chapters[1].ID)
+	checks := report.Chapters[0].Requirements[0].Checks
+	require.Len(t, checks, 2)
+	assert.Equal(t, "2_1_1", checks[0].ID)
+	require.Len(t, checks[0].Criteria, 1)
+	assert.Equal(t, common.MultilineString("ticket 42"), checks[0].Criteria[0].Criterion)
+	require.Len(t, report.Failed, 1)
+	assert.Equal(t, "2_1_1", report.Failed[0].ID)
+}
+
+func TestNewWithoutChecks(t *testing.T) {
+	res := testResult()
+	res.Chapters["10"].Requirements["2"] = &result.Requirement{Title: "Not yet planned", Status: "NA"}
+	res.Chapters["3"] = &result.Chapter{Title: "Empty"}
+
+	report := New(res)
+
+	require.Len(t, report.Chapters, 3)
+	assert.Equal(t, "3", report.Chapters[1].ID)
+	assert.Empty(t, report.Chapters[1].Requirements)
+	requirements := report.Chapters[2].Requirements
+	require.Len(t, requirements, 2)
+	assert.Equal(t, Requirement{ID: "2", Title: "Not yet planned", Status: "NA"}, requirements[1])
+
+	content, err := Render(res, FORMAT_HTML, "")
+	require.NoError(t, err)
+	assert.Contains(t, string(content), "Not yet planned")
+}
+
+func TestRender(t *testing.T) {
+	t.Run("should render a self-contained html report", func(t *testing.T) {
+		content, err := Render(testResult(), FORMAT_HTML, "")
+
+		require.NoError(t, err)
+		html := string(content)
+		assert.True(t, strings.HasPrefix(html, "<!DOCTYPE html>"))
+		assert.Contains(t, html, `<section class="chapter" id="chapter-2">`)
+		assert.Contains(t, html, "&lt;b&gt;found&lt;/b&gt; tickets")
+		assert.Contains(t, html, "<summary>Logs (1 lines)</summary>")
+		assert.NotContains(t, html, "<script>")
+		assert.Contains(t, html, "id=42")
+		assert.NotContains(t, html, "<link")
+	})
+
+	t.Run("should render a markdown summary", func(t *testing.T) {
+		content, err := Render(testResult(), FORMAT_MARKDOWN, "")
+
+		require.NoError(t, err)
+		markdown := string(content)
+		assert.Contains(t, markdown, "## 🔴 My Project 1.0: RED")
+		assert.Contains(t, markdown, "| 3 | 2 | 1 | 0 | 0 | 67% | 100% |")
+		assert.Contains(t, markdown, "| 2 Security | 🔴 RED |")
+		assert.Contains(t, markdown, "<summary>1 checks need attention</summary>")
+		assert.Contains(t, markdown, "| 2_1_1 open tickets | 🔴 RED | <b>found</b> tickets<br>• ticket 42: is open since a week |")
+	})
+
+	t.Run("should render a custom template", func(t *testing.T) {
+		content, err := Render(testResult(), FORMAT_MARKDOWN, "testdata/custom.md.tmpl")
+
+		require.NoError(t, err)
+		assert.Equal(t, "My Project: RED\n- 2_1_1 RED\n", string(content))
+	})
+
+	t.Run("should fail for unknown formats and templates", func(t *testing.T) {
+		_, err := Render(testResult(), "pdf", "")
+		assert.EqualError(t, err, "unsupported report format 'pdf', must be one of: html, markdown")
+
+		_, err = Render(testResult(), FORMAT_HTML, "testdata/missing.html.tmpl")
+		assert.ErrorContains(t, err, "error reading report template 'testdata/missing.html.tmpl'")
+	})
+}
+
+func TestCell(t *testing.T) {
+	assert.Equal(t, `a \| b c`, cell(" a | b\n c "))
+}
@@ -17,6 +17,14 @@ type App interface {
 	ExecutablePath() string
 	// PossibleReferences returns a list of possible references for the app.
 	PossibleReferences() []string
+	// Executables returns all executables of the app together with the references they are exposed as.
+	Executables() []Executable
+}
+
+// Executable is a single executable file of an app which is exposed under the given references.
+type Executable struct {
+	Path       string
+	References []string
 }
 
 type Reference struct {
@@ -0,0 +1,219 @@
+package app
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// ArchiveKey is the key in a repository config which marks the apps of the repository as archives
+const ArchiveKey = "archive"
+
+// ArchiveManifestFile can be shipped at the root of an archive to declare its entrypoints
+const ArchiveManifestFile = "onyx-app.yaml"
+
+type ArchiveConfig struct {
+	// Entrypoints maps the names which are exposed on PATH to the paths of the executables inside the archive.
+	// Names and paths can contain the placeholders {name} and {version}.
+	// Example {"{name}": "bin/{name}", "helper": "bin/helper"}
+	Entrypoints map[string]string
+}
+
+type archiveManifest struct {
+	Entrypoints map[string]string `yaml:"entrypoints"`
+}
+
+// NewArchiveConfig parses the archive section of a repository config.
+// It returns nil if the repository does not contain archives.
+func NewArchiveConfig(config map[string]interface{}) (*ArchiveConfig, error) {
+	if config[ArchiveKey] == nil {
+		return nil, nil
+	}
+	archiveConfig, ok := config[ArchiveKey].(map[string]interface{})
+	if !ok {
+		return nil, fmt.Errorf("%s must be a map", ArchiveKey)
+	}
+	parsed := &ArchiveConfig{Entrypoints: map[string]string{}}
+	if archiveConfig["entrypoints"] == nil {
+		return parsed, nil
+	}
+	entrypoints, ok := archiveConfig["entrypoints"].(map[string]interface{})
+	if !ok {
+		return nil, fmt.Errorf("entrypoints must be a map")
+	}
+	for name, path := range entrypoints {
+		pathString, ok := path.(string)
+		if !ok || pathString == "" {
+			return nil, fmt.Errorf("path of entrypoint '%s' must be a non-empty string", name)
+		}
+		parsed.Entrypoints[name] = pathString
+	}
+	return parsed, nil
+}
+
+type ArchiveApp struct {
+	// Name of the repository
+	repository string
+	// Name of the app
+	name string
+	// Version of the app
+	version string
+	// Checksum of the archive
+	checksum string
+	// Executables of the app sorted by path
+	executables []Executable
+}
+
+// NewArchiveApp extracts the archive next to itself and exposes the declared entrypoints.
+// Entrypoints of the repository config take precedence over the manifest inside of the archive,
+// if neither declares any, an executable with the name of the app at the archive root is expected.
+func NewArchiveApp(repository string, reference *Reference, checksum, archivePath string, config *ArchiveConfig) (App, error) {
+	directory := archivePath + "-extracted"
+	err := os.RemoveAll(directory)
+	if err != nil {
+		return nil, fmt.Errorf("error cleaning up extraction directory: %w", err)
+	}
+	err = Extract(archivePath, directory)
+	if err != nil {
+		return nil, fmt.Errorf("error extracting archive: %w", err)
+	}
+
+	entrypoints := config.Entrypoints
+	if len(entrypoints) == 0 {
+		entrypoints, err = readArchiveManifest(directory)
+		if err != nil {
+			return nil, err
+		}
+	}
+	if len(entrypoints) == 0 {
+		entrypoints = map[string]string{"{name}": "{name}"}
+	}
+
+	replacer := strings.NewReplacer("{name}", reference.Name, "{version}", reference.Version)
+	var executables []Executable
+	for name, path := range entrypoints {
+		name = replacer.Replace(name)
+		if name == "" || strings.ContainsAny(name, `/\`) {
+			return nil, fmt.Errorf("invalid entrypoint name '%s'", name)
+		}
+		executablePath, err := resolveEntrypoint(directory, replacer.Replace(path))
+		if err != nil {
+			return nil, err
+		}
+		executables = append(executables, Executable{
+			Path:       executablePath,
+			References: []string{name + "@" + reference.Version, name},
+		})
+	}
+	sort.Slice(executables, func(i, j int) bool {
+		return executables[i].References[1] < executables[j].References[1]
+	})
+
+	return &ArchiveApp{
+		repository:  repository,
+		name:        reference.Name,
+		version:     reference.Version,
+		checksum:    checksum,
+		executables: executables,
+	}, nil
+}
+
+func readArchiveManifest(directory string) (map[string]string, error) {
+	content, err := os.ReadFile(filepath.Join(directory, ArchiveManifestFile))
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error reading %s: %w", ArchiveManifestFile, err)
+	}
+	var manifest archiveManifest
+	err = yaml.Unmarshal(content, &manifest)
+	if err != nil {
+		return nil, fmt.Errorf("error parsing %s: %w", ArchiveManifestFile, err)
+	}
+	return manifest.Entrypoints, nil
+}
+
+// resolveEntrypoint returns the absolute path of the entrypoint and makes it executable.
+// The entrypoint has to be a regular file inside of the extracted archive.
+func resolveEntrypoint(directory, path string) (string, error) {
+	if !isInside(directory, filepath.Join(directory, path)) {
+		return "", fmt.Errorf("entrypoint '%s' is not a file inside the archive", path)
+	}
+	resolvedDirectory, err := filepath.EvalSymlinks(directory)
+	if err != nil {
+		return "", fmt.Errorf("error resolving extraction directory: %w", err)
+	}
+	resolved, err := filepath.EvalSymlinks(filepath.Join(directory, path))
+	if err != nil {
+		return "", fmt.Errorf("entrypoint '%s' not found in archive: %w", path, err)
+	}
+	if !isInside(resolvedDirectory, resolved) {
+		return "", fmt.Errorf("entrypoint '%s' is not a file inside the archive", path)
+	}
+	info, err := os.Stat(resolved)
+	if err != nil {
+		return "", fmt.Errorf("entrypoint '%s' not found in archive: %w", path, err)
+	}
+	if !info.Mode().IsRegular() {
+		return "", fmt.Errorf("entrypoint '%s' is not a regular file", path)
+	}
+	err = os.Chmod(resolved, 0755)
+	if err != nil {
+		return "", fmt.Errorf("error changing file permissions of entrypoint '%s': %w", path, err)
+	}
+	return filepath.Join(directory, path), nil
+}
+
+func (a *ArchiveApp) Reference() *Reference {
+	if a.repository == "" {
+		panic("Repository is not set")
+	}
+	if a.name == "" {
+		panic("Name is not set")
+	}
+	if a.version == "" {
+		panic("Version is not set")
+	}
+	return &Reference{
+		Repository: a.repository,
+		Name:       a.name,
+		Version:    a.version,
+	}
+}
+
+func (a *ArchiveApp) Checksum() string {
+	if a.checksum == "" {
+		panic("Checksum is not set")
+	}
+	return a.checksum
+}
+
+// ExecutablePath returns the entrypoint named like the app or the first entrypoint otherwise
+func (a *ArchiveApp) ExecutablePath() string {
+	if len(a.executables) == 0 {
+		panic("Executables are not set")
+	}
+	for _, executable := range a.executables {
+		if executable.References[1] == a.name {
+			return executable.Path
+		}
+	}
+	return a.executables[0].Path
+}
+
+func (a *ArchiveApp) PossibleReferences() []string {
+	var references []string
+	for _, executable := range a.executables {
+		references = append(references, executable.References...)
+	}
+	return references
+}
+
+func (a *ArchiveApp) Executables() []Executable {
+	return a.executables
+}
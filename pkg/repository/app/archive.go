@@ -0,0 +1,227 @@
+package app
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// Extract unpacks a tar, tar.gz or zip archive into the given directory.
+// The format is detected from the content, so the archive can be stored without file extension.
+// Entries which would end up outside of the directory (zip-slip) are rejected.
+func Extract(archivePath, directory string) error {
+	file, err := os.Open(archivePath)
+	if err != nil {
+		return fmt.Errorf("error opening archive: %w", err)
+	}
+	defer file.Close()
+
+	header := make([]byte, 512)
+	n, err := io.ReadFull(file, header)
+	if err != nil && err != io.ErrUnexpectedEOF {
+		return fmt.Errorf("error reading archive: %w", err)
+	}
+	header = header[:n]
+	_, err = file.Seek(0, io.SeekStart)
+	if err != nil {
+		return fmt.Errorf("error reading archive: %w", err)
+	}
+
+	err = os.MkdirAll(directory, 0755)
+	if err != nil {
+		return fmt.Errorf("error creating directory: %w", err)
+	}
+	switch {
+	case bytes.HasPrefix(header, []byte{0x1f, 0x8b}):
+		gzipReader, err := gzip.NewReader(bufio.NewReader(file))
+		if err != nil {
+			return fmt.Errorf("error reading gzip archive: %w", err)
+		}
+		defer gzipReader.Close()
+		return extractTar(gzipReader, directory)
+	case bytes.HasPrefix(header, []byte("PK\x03\x04")), bytes.HasPrefix(header, []byte("PK\x05\x06")):
+		info, err := file.Stat()
+		if err != nil {
+			return fmt.Errorf("error reading archive: %w", err)
+		}
+		return extractZip(file, info.Size(), directory)
+	case len(header) > 262 && bytes.Equal(header[257:262], []byte("ustar")):
+		return extractTar(file, directory)
+	default:
+		return fmt.Errorf("unsupported archive format, supported formats are tar, tar.gz and zip")
+	}
+}
+
+func extractTar(reader io.Reader, directory string) error {
+	tarReader := tar.NewReader(reader)
+	for {
+		header, err := tarReader.Next()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("error reading tar archive: %w", err)
+		}
+		target, err := prepareTarget(directory, header.Name)
+		if err != nil {
+			return err
+		}
+		switch header.Typeflag {
+		case tar.TypeDir:
+			err = os.MkdirAll(target, 0755)
+		case tar.TypeReg:
+			err = writeFile(target, tarReader, header.FileInfo().Mode())
+		case tar.TypeSymlink:
+			err = writeSymlink(directory, target, header.Linkname)
+		case tar.TypeLink:
+			var source string
+			source, err = prepareTarget(directory, header.Linkname)
+			if err == nil {
+				err = os.Link(source, target)
+			}
+		default:
+			// devices, fifos and extended headers are not needed to run apps
+			continue
+		}
+		if err != nil {
+			return fmt.Errorf("error extracting '%s': %w", header.Name, err)
+		}
+	}
+}
+
+func extractZip(reader io.ReaderAt, size int64, directory string) error {
+	zipReader, err := zip.NewReader(reader, size)
+	if err != nil {
+		return fmt.Errorf("error reading zip archive: %w", err)
+	}
+	for _, entry := range zipReader.File {
+		target, err := prepareTarget(directory, entry.Name)
+		if err != nil {
+			return err
+		}
+		mode := entry.Mode()
+		switch {
+		case mode.IsDir():
+			err = os.MkdirAll(target, 0755)
+		case mode&os.ModeSymlink != 0:
+			err = extractZipSymlink(entry, directory, target)
+		case mode.IsRegular():
+			err = extractZipFile(entry, target)
+		}
+		if err != nil {
+			return fmt.Errorf("error extracting '%s': %w", entry.Name, err)
+		}
+	}
+	return nil
+}
+
+func extractZipFile(entry *zip.File, target string) error {
+	content, err := entry.Open()
+	if err != nil {
+		return err
+	}
+	defer content.Close()
+	return writeFile(target, content, entry.Mode())
+}
+
+func extractZipSymlink(entry *zip.File, directory, target string) error {
+	content, err := entry.Open()
+	if err != nil {
+		return err
+	}
+	defer content.Close()
+	linkname, err := io.ReadAll(io.LimitReader(content, 4096))
+	if err != nil {
+		return err
+	}
+	return writeSymlink(directory, target, string(linkname))
+}
+
+// prepareTarget returns the path of the entry inside of the directory and creates its parents.
+// It fails if the entry escapes the directory either directly or through a previously extracted symlink.
+func prepareTarget(directory, name string) (string, error) {
+	slashName := strings.ReplaceAll(name, `\`, "/")
+	target := filepath.Join(directory, filepath.FromSlash(slashName))
+	if strings.HasPrefix(slashName, "/") || filepath.IsAbs(filepath.FromSlash(slashName)) || !isInside(directory, target) {
+		return "", fmt.Errorf("illegal path '%s' in archive", name)
+	}
+	relative, _ := filepath.Rel(directory, filepath.Dir(target))
+	current := filepath.Clean(directory)
+	for _, part := range strings.Split(relative, string(os.PathSeparator)) {
+		if part == "." {
+			continue
+		}
+		current = filepath.Join(current, part)
+		info, err := os.Lstat(current)
+		if os.IsNotExist(err) {
+			break
+		}
+		if err != nil {
+			return "", err
+		}
+		if info.Mode()&os.ModeSymlink != 0 {
+			return "", fmt.Errorf("illegal path '%s' in archive: parent directory is a symlink", name)
+		}
+	}
+	err := os.MkdirAll(filepath.Dir(target), 0755)
+	if err != nil {
+		return "", err
+	}
+	return target, nil
+}
+
+func writeFile(target string, content io.Reader, mode os.FileMode) error {
+	err := removeExisting(target)
+	if err != nil {
+		return err
+	}
+	file, err := os.OpenFile(target, os.O_CREATE|os.O_EXCL|os.O_WRONLY, mode.Perm()|0600)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+	_, err = io.Copy(file, content)
+	return err
+}
+
+// writeSymlink creates a symlink which must point to a path inside of the directory
+func writeSymlink(directory, target, linkname string) error {
+	if filepath.IsAbs(linkname) || !isInside(directory, filepath.Join(filepath.Dir(target), linkname)) {
+		return fmt.Errorf("symlink to '%s' points outside of the archive", linkname)
+	}
+	err := removeExisting(target)
+	if err != nil {
+		return err
+	}
+	return os.Symlink(linkname, target)
+}
+
+func removeExisting(target string) error {
+	info, err := os.Lstat(target)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if info.IsDir() {
+		return fmt.Errorf("'%s' is a directory", target)
+	}
+	return os.Remove(target)
+}
+
+// isInside checks if path is the directory itself or located inside of it
+func isInside(directory, path string) bool {
+	relative, err := filepath.Rel(directory, path)
+	if err != nil {
+		return false
+	}
+	return relative != ".." && !strings.HasPrefix(relative, ".."+string(os.PathSeparator))
+}
@@ -0,0 +1,122 @@
+package app
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewArchiveConfig(t *testing.T) {
+	t.Run("should return nil without archive", func(t *testing.T) {
+		config, err := NewArchiveConfig(map[string]interface{}{"url": "http://example.com"})
+		assert.NoError(t, err)
+		assert.Nil(t, config)
+	})
+
+	t.Run("should parse entrypoints", func(t *testing.T) {
+		config, err := NewArchiveConfig(map[string]interface{}{
+			"archive": map[string]interface{}{
+				"entrypoints": map[string]interface{}{"{name}": "bin/{name}"},
+			},
+		})
+		assert.NoError(t, err)
+		assert.Equal(t, map[string]string{"{name}": "bin/{name}"}, config.Entrypoints)
+	})
+
+	t.Run("should fail for invalid entrypoints", func(t *testing.T) {
+		_, err := NewArchiveConfig(map[string]interface{}{
+			"archive": map[string]interface{}{
+				"entrypoints": map[string]interface{}{"tool": 42},
+			},
+		})
+		assert.EqualError(t, err, "path of entrypoint 'tool' must be a non-empty string")
+	})
+
+	t.Run("should fail for invalid archive", func(t *testing.T) {
+		_, err := NewArchiveConfig(map[string]interface{}{"archive": true})
+		assert.EqualError(t, err, "archive must be a map")
+	})
+}
+
+func TestNewArchiveApp(t *testing.T) {
+	reference := &Reference{Repository: "repo", Name: "tool", Version: "1.0.0"}
+
+	t.Run("should expose entrypoints of config", func(t *testing.T) {
+		archivePath := writeTarGz(t, []archiveEntry{
+			{name: "bin/tool", content: "tool"},
+			{name: "bin/helper", content: "helper"},
+		})
+
+		installed, err := NewArchiveApp("repo", reference, "abc123", archivePath, &ArchiveConfig{
+			Entrypoints: map[string]string{"{name}": "bin/{name}", "tool-helper": "bin/helper"},
+		})
+		require.NoError(t, err)
+		directory := archivePath + "-extracted"
+		assert.Equal(t, "abc123", installed.Checksum())
+		assert.Equal(t, filepath.Join(directory, "bin", "tool"), installed.ExecutablePath())
+		assert.Equal(t, []string{"tool@1.0.0", "tool", "tool-helper@1.0.0", "tool-helper"}, installed.PossibleReferences())
+		assert.Equal(t, []Executable{
+			{Path: filepath.Join(directory, "bin", "tool"), References: []string{"tool@1.0.0", "tool"}},
+			{Path: filepath.Join(directory, "bin", "helper"), References: []string{"tool-helper@1.0.0", "tool-helper"}},
+		}, installed.Executables())
+		info, err := os.Stat(filepath.Join(directory, "bin", "helper"))
+		require.NoError(t, err)
+		assert.Equal(t, os.FileMode(0755), info.Mode().Perm())
+	})
+
+	t.Run("should expose entrypoints of manifest", func(t *testing.T) {
+		archivePath := writeZip(t, []archiveEntry{
+			{name: ArchiveManifestFile, content: "entrypoints:\n  other: cli/other\n"},
+			{name: "cli/other", content: "other"},
+		})
+
+		installed, err := NewArchiveApp("repo", reference, "abc123", archivePath, &ArchiveConfig{})
+		require.NoError(t, err)
+		assert.Equal(t, filepath.Join(archivePath+"-extracted", "cli", "other"), installed.ExecutablePath())
+		assert.Equal(t, []string{"other@1.0.0", "other"}, installed.PossibleReferences())
+	})
+
+	t.Run("should default to executable named like the app", func(t *testing.T) {
+		archivePath := writeTarGz(t, []archiveEntry{{name: "tool", content: "tool"}})
+
+		installed, err := NewArchiveApp("repo", reference, "abc123", archivePath, &ArchiveConfig{})
+		require.NoError(t, err)
+		assert.Equal(t, []string{"tool@1.0.0", "tool"}, installed.PossibleReferences())
+	})
+
+	testCases := map[string]struct {
+		entries     []archiveEntry
+		entrypoints map[string]string
+		wantErr     string
+	}{
+		"should fail for missing entrypoint": {
+			entries:     []archiveEntry{{name: "bin/tool", content: "tool"}},
+			entrypoints: map[string]string{"tool": "bin/other"},
+			wantErr:     "entrypoint 'bin/other' not found in archive",
+		},
+		"should fail for entrypoint outside of archive": {
+			entries:     []archiveEntry{{name: "bin/tool", content: "tool"}},
+			entrypoints: map[string]string{"tool": "../tool"},
+			wantErr:     "entrypoint '../tool' is not a file inside the archive",
+		},
+		"should fail for directory entrypoint": {
+			entries:     []archiveEntry{{name: "bin/tool", content: "tool"}},
+			entrypoints: map[string]string{"tool": "bin"},
+			wantErr:     "entrypoint 'bin' is not a regular file",
+		},
+		"should fail for invalid entrypoint name": {
+			entries:     []archiveEntry{{name: "bin/tool", content: "tool"}},
+			entrypoints: map[string]string{"bin/tool": "bin/tool"},
+			wantErr:     "invalid entrypoint name 'bin/tool'",
+		},
+	}
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			_, err := NewArchiveApp("repo", reference, "abc123", writeTarGz(t, tc.entries), &ArchiveConfig{Entrypoints: tc.entrypoints})
+			assert.ErrorContains(t, err, tc.wantErr)
+		})
+	}
+}
@@ -0,0 +1,134 @@
+package app
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bytes"
+	"compress/gzip"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type archiveEntry struct {
+	name     string
+	content  string
+	linkname string
+	mode     os.FileMode
+}
+
+func writeTarGz(t *testing.T, entries []archiveEntry) string {
+	var buffer bytes.Buffer
+	gzipWriter := gzip.NewWriter(&buffer)
+	tarWriter := tar.NewWriter(gzipWriter)
+	for _, entry := range entries {
+		header := &tar.Header{Name: entry.name, Mode: int64(entry.mode.Perm()), Size: int64(len(entry.content)), Typeflag: tar.TypeReg}
+		if entry.mode == 0 {
+			header.Mode = 0644
+		}
+		if entry.linkname != "" {
+			header.Typeflag = tar.TypeSymlink
+			header.Linkname = entry.linkname
+			header.Size = 0
+		}
+		require.NoError(t, tarWriter.WriteHeader(header))
+		_, err := tarWriter.Write([]byte(entry.content))
+		require.NoError(t, err)
+	}
+	require.NoError(t, tarWriter.Close())
+	require.NoError(t, gzipWriter.Close())
+	path := filepath.Join(t.TempDir(), "archive")
+	require.NoError(t, os.WriteFile(path, buffer.Bytes(), 0644))
+	return path
+}
+
+func writeZip(t *testing.T, entries []archiveEntry) string {
+	var buffer bytes.Buffer
+	zipWriter := zip.NewWriter(&buffer)
+	for _, entry := range entries {
+		header := &zip.FileHeader{Name: entry.name, Method: zip.Deflate}
+		header.SetMode(0644)
+		content := entry.content
+		if entry.linkname != "" {
+			header.SetMode(os.ModeSymlink | 0777)
+			content = entry.linkname
+		}
+		writer, err := zipWriter.CreateHeader(header)
+		require.NoError(t, err)
+		_, err = writer.Write([]byte(content))
+		require.NoError(t, err)
+	}
+	require.NoError(t, zipWriter.Close())
+	path := filepath.Join(t.TempDir(), "archive")
+	require.NoError(t, os.WriteFile(path, buffer.Bytes(), 0644))
+	return path
+}
+
+func TestExtract(t *testing.T) {
+	writers := map[string]func(*testing.T, []archiveEntry) string{
+		"tar.gz": writeTarGz,
+		"zip":    writeZip,
+	}
+	for format, write := range writers {
+		t.Run(format, func(t *testing.T) {
+			t.Run("should extract files and symlinks", func(t *testing.T) {
+				archivePath := write(t, []archiveEntry{
+					{name: "bin/tool", content: "tool"},
+					{name: "lib/libtool.so", content: "lib"},
+					{name: "bin/tool-link", linkname: "tool"},
+				})
+				directory := filepath.Join(t.TempDir(), "extracted")
+
+				err := Extract(archivePath, directory)
+				require.NoError(t, err)
+				content, err := os.ReadFile(filepath.Join(directory, "bin", "tool-link"))
+				require.NoError(t, err)
+				assert.Equal(t, "tool", string(content))
+				content, err = os.ReadFile(filepath.Join(directory, "lib", "libtool.so"))
+				require.NoError(t, err)
+				assert.Equal(t, "lib", string(content))
+			})
+
+			testCases := map[string][]archiveEntry{
+				"should reject relative paths escaping the directory": {
+					{name: "../evil", content: "evil"},
+				},
+				"should reject absolute paths": {
+					{name: "/tmp/evil", content: "evil"},
+				},
+				"should reject symlinks pointing outside": {
+					{name: "link", linkname: "../../etc/passwd"},
+				},
+				"should reject absolute symlinks": {
+					{name: "link", linkname: "/etc/passwd"},
+				},
+				"should reject writing through symlinks": {
+					{name: "dir", linkname: "."},
+					{name: "dir/evil", linkname: "../outside"},
+				},
+			}
+			for name, entries := range testCases {
+				t.Run(name, func(t *testing.T) {
+					root := t.TempDir()
+					directory := filepath.Join(root, "extracted")
+
+					err := Extract(write(t, entries), directory)
+					assert.Error(t, err)
+					_, err = os.Lstat(filepath.Join(root, "evil"))
+					assert.True(t, os.IsNotExist(err))
+				})
+			}
+		})
+	}
+
+	t.Run("should reject unknown formats", func(t *testing.T) {
+		archivePath := filepath.Join(t.TempDir(), "archive")
+		require.NoError(t, os.WriteFile(archivePath, []byte("#!/bin/bash"), 0644))
+
+		err := Extract(archivePath, t.TempDir())
+		assert.EqualError(t, err, "unsupported archive format, supported formats are tar, tar.gz and zip")
+	})
+}
@@ -65,6 +65,13 @@ func (a *BinaryApp) PossibleReferences() []string {
 	return a.Reference().PossibleReferences()
 }
 
+func (a *BinaryApp) Executables() []Executable {
+	return []Executable{{
+		Path:       a.ExecutablePath(),
+		References: a.PossibleReferences(),
+	}}
+}
+
 func CalculateFileChecksum(filePath string) (string, error) {
 	file, err := os.Open(filePath)
 	if err != nil {
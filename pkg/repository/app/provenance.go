@@ -0,0 +1,50 @@
+package app
+
+const (
+	SignatureVerified = "verified"
+	SignatureFailed   = "failed"
+)
+
+// Signature describes the signature verification of an app
+type Signature struct {
+	// Type of the signature
+	// Example "cosign", "minisign" or "gpg"
+	Type string
+	// Status of the verification, failed verifications are only reported if the policy allows them
+	// Example "verified" or "failed"
+	Status string
+	// KeyID of the key which verified the signature
+	KeyID string
+	// Signer is the identity bound to the key, e.g. the user id of a gpg key
+	Signer string
+}
+
+// Provenance describes where an installed app came from
+type Provenance struct {
+	// Source the app was downloaded from, without credentials
+	// Example "https://my-file-server.com/my-app/1.0.0"
+	Source string
+	// Signature of the app, nil if signatures are not verified by the repository
+	Signature *Signature
+}
+
+type provenanceApp struct {
+	App
+	provenance Provenance
+}
+
+// WithProvenance attaches the provenance to the installed app
+func WithProvenance(app App, provenance Provenance) App {
+	if provenance == (Provenance{}) {
+		return app
+	}
+	return &provenanceApp{App: app, provenance: provenance}
+}
+
+// ProvenanceOf returns the provenance of the app, it is empty if the repository did not provide one
+func ProvenanceOf(app App) Provenance {
+	if annotated, ok := app.(*provenanceApp); ok {
+		return annotated.provenance
+	}
+	return Provenance{}
+}
@@ -0,0 +1,27 @@
+package app
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWithProvenance(t *testing.T) {
+	installed := NewBinaryApp("repo", "app", "1.0.0", "checksum", "/apps/app")
+
+	t.Run("empty provenance keeps the app", func(t *testing.T) {
+		assert.Same(t, installed, WithProvenance(installed, Provenance{}))
+		assert.Equal(t, Provenance{}, ProvenanceOf(installed))
+	})
+
+	t.Run("provenance is attached", func(t *testing.T) {
+		provenance := Provenance{
+			Source:    "https://example.com/app/1.0.0",
+			Signature: &Signature{Type: "minisign", Status: SignatureVerified, KeyID: "1122334455667788"},
+		}
+		annotated := WithProvenance(installed, provenance)
+		assert.Equal(t, provenance, ProvenanceOf(annotated))
+		assert.Equal(t, installed.Checksum(), annotated.Checksum())
+		assert.Equal(t, installed.Executables(), annotated.Executables())
+	})
+}
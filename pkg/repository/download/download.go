@@ -0,0 +1,164 @@
+package download
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/B-S-F/onyx/pkg/logger"
+)
+
+const DEFAULT_RETRIES = 3
+const DEFAULT_BACKOFF = time.Second
+
+// Downloader fetches files over http, retries failed attempts with exponential backoff
+// and resumes interrupted downloads with range requests if the server supports them.
+type Downloader struct {
+	// Number of retries after the first attempt
+	Retries int
+	// Wait time before the first retry, it is doubled for every further retry
+	Backoff time.Duration
+	// Do sends the request, it can add authorization to the request
+	Do     func(request *http.Request) (*http.Response, error)
+	logger logger.Logger
+}
+
+func New(retries int, do func(request *http.Request) (*http.Response, error)) *Downloader {
+	return &Downloader{
+		Retries: retries,
+		Backoff: DEFAULT_BACKOFF,
+		Do:      do,
+		logger:  logger.Get(),
+	}
+}
+
+// StatusError is returned if the server answered with an unexpected status code
+type StatusError struct {
+	StatusCode int
+	Status     string
+}
+
+func (e *StatusError) Error() string {
+	return e.Status
+}
+
+// permanentError marks errors which are not solved by retrying
+type permanentError struct {
+	error
+}
+
+func (e permanentError) Unwrap() error {
+	return e.error
+}
+
+// retryable returns true for errors which could be gone with the next attempt
+func retryable(err error) bool {
+	var permanent permanentError
+	var certificateError *tls.CertificateVerificationError
+	var unknownAuthorityError x509.UnknownAuthorityError
+	if errors.As(err, &permanent) || errors.As(err, &certificateError) || errors.As(err, &unknownAuthorityError) {
+		return false
+	}
+	var statusError *StatusError
+	if errors.As(err, &statusError) {
+		return statusError.StatusCode == http.StatusTooManyRequests || statusError.StatusCode >= 500
+	}
+	return true
+}
+
+// Download writes the content of the url to outputPath
+func (d *Downloader) Download(url string, outputPath string) error {
+	file, err := os.OpenFile(outputPath, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, 0644)
+	if err != nil {
+		return fmt.Errorf("error opening file: %w", err)
+	}
+	defer file.Close()
+
+	var offset int64
+	for attempt := 0; ; attempt++ {
+		start := time.Now()
+		offset, err = d.attempt(url, file, offset)
+		if err == nil {
+			d.logger.Debugf("downloaded %s (%d bytes) in %s", url, offset, time.Since(start).Round(time.Millisecond))
+			return nil
+		}
+		if !retryable(err) || attempt >= d.Retries {
+			return err
+		}
+		wait := d.Backoff << attempt
+		d.logger.Warnf("attempt %d to download %s failed after %s: %v, retrying in %s", attempt+1, url, time.Since(start).Round(time.Millisecond), err, wait)
+		time.Sleep(wait)
+	}
+}
+
+// attempt downloads the content starting at offset and returns the number of bytes in the file afterwards.
+// If the server ignores the range, the file is truncated and the download starts from the beginning.
+func (d *Downloader) attempt(url string, file *os.File, offset int64) (int64, error) {
+	request, err := http.NewRequest(http.MethodGet, url, nil)
+	if err != nil {
+		return offset, permanentError{fmt.Errorf("error creating request: %w", err)}
+	}
+	if offset > 0 {
+		request.Header.Set("Range", fmt.Sprintf("bytes=%d-", offset))
+	}
+	response, err := d.Do(request)
+	if err != nil {
+		return offset, err
+	}
+	defer response.Body.Close()
+
+	switch {
+	case response.StatusCode == http.StatusOK:
+		if offset > 0 {
+			d.logger.Debugf("server does not support range requests for %s, restarting download", url)
+			offset = 0
+			err = restart(file)
+			if err != nil {
+				return 0, permanentError{fmt.Errorf("error truncating file: %w", err)}
+			}
+		}
+	case response.StatusCode == http.StatusPartialContent && offset > 0:
+		if rangeStart(response.Header.Get("Content-Range")) != offset {
+			err = restart(file)
+			if err != nil {
+				return 0, permanentError{fmt.Errorf("error truncating file: %w", err)}
+			}
+			return 0, fmt.Errorf("unexpected content range '%s'", response.Header.Get("Content-Range"))
+		}
+		d.logger.Debugf("resuming download of %s at byte %d", url, offset)
+	default:
+		return offset, &StatusError{StatusCode: response.StatusCode, Status: response.Status}
+	}
+	written, err := io.Copy(file, response.Body)
+	return offset + written, err
+}
+
+func restart(file *os.File) error {
+	err := file.Truncate(0)
+	if err != nil {
+		return err
+	}
+	_, err = file.Seek(0, io.SeekStart)
+	return err
+}
+
+// rangeStart returns the first byte of a content range like "bytes 100-199/200"
+func rangeStart(contentRange string) int64 {
+	value, ok := strings.CutPrefix(contentRange, "bytes ")
+	if !ok {
+		return -1
+	}
+	start, _, _ := strings.Cut(value, "-")
+	parsed, err := strconv.ParseInt(start, 10, 64)
+	if err != nil {
+		return -1
+	}
+	return parsed
+}
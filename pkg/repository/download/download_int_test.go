@@ -0,0 +1,130 @@
+//go:build integration
+// +build integration
+
+package download
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var content = []byte(strings.Repeat("0123456789", 100))
+
+func newDownloader(retries int) *Downloader {
+	downloader := New(retries, http.DefaultClient.Do)
+	downloader.Backoff = time.Millisecond
+	return downloader
+}
+
+// interruptedServer sends only half of the content on the first request
+func interruptedServer(t *testing.T, supportsRange bool) (*httptest.Server, *[]string) {
+	var requests int32
+	var ranges []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ranges = append(ranges, r.Header.Get("Range"))
+		if atomic.AddInt32(&requests, 1) == 1 {
+			w.Header().Set("Content-Length", strconv.Itoa(len(content)))
+			w.WriteHeader(http.StatusOK)
+			w.Write(content[:len(content)/2])
+			return
+		}
+		offset := 0
+		if supportsRange && r.Header.Get("Range") != "" {
+			_, err := fmt.Sscanf(r.Header.Get("Range"), "bytes=%d-", &offset)
+			require.NoError(t, err)
+			w.Header().Set("Content-Range", fmt.Sprintf("bytes %d-%d/%d", offset, len(content)-1, len(content)))
+			w.WriteHeader(http.StatusPartialContent)
+		}
+		w.Write(content[offset:])
+	}))
+	t.Cleanup(server.Close)
+	return server, &ranges
+}
+
+func TestDownloadResumesInterruptedDownload(t *testing.T) {
+	server, ranges := interruptedServer(t, true)
+	output := filepath.Join(t.TempDir(), "app")
+
+	err := newDownloader(1).Download(server.URL, output)
+	require.NoError(t, err)
+
+	downloaded, err := os.ReadFile(output)
+	require.NoError(t, err)
+	assert.Equal(t, content, downloaded)
+	assert.Equal(t, []string{"", fmt.Sprintf("bytes=%d-", len(content)/2)}, *ranges)
+}
+
+func TestDownloadRestartsIfRangeIsNotSupported(t *testing.T) {
+	server, _ := interruptedServer(t, false)
+	output := filepath.Join(t.TempDir(), "app")
+
+	err := newDownloader(1).Download(server.URL, output)
+	require.NoError(t, err)
+
+	downloaded, err := os.ReadFile(output)
+	require.NoError(t, err)
+	assert.Equal(t, content, downloaded)
+}
+
+func TestDownloadRetries(t *testing.T) {
+	testCases := map[string]struct {
+		status   int
+		retries  int
+		requests int32
+	}{
+		"retries server errors":             {status: http.StatusServiceUnavailable, retries: 2, requests: 3},
+		"retries too many requests":         {status: http.StatusTooManyRequests, retries: 1, requests: 2},
+		"does not retry client errors":      {status: http.StatusNotFound, retries: 2, requests: 1},
+		"does not retry without retries":    {status: http.StatusBadGateway, retries: 0, requests: 1},
+		"does not retry forbidden requests": {status: http.StatusForbidden, retries: 3, requests: 1},
+	}
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			var requests int32
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				atomic.AddInt32(&requests, 1)
+				w.WriteHeader(tc.status)
+			}))
+			defer server.Close()
+
+			err := newDownloader(tc.retries).Download(server.URL, filepath.Join(t.TempDir(), "app"))
+
+			var statusError *StatusError
+			require.ErrorAs(t, err, &statusError)
+			assert.Equal(t, tc.status, statusError.StatusCode)
+			assert.Equal(t, tc.requests, atomic.LoadInt32(&requests))
+		})
+	}
+}
+
+func TestDownloadSucceedsAfterTransientError(t *testing.T) {
+	var requests int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&requests, 1) == 1 {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		w.Write(content)
+	}))
+	defer server.Close()
+	output := filepath.Join(t.TempDir(), "app")
+
+	err := newDownloader(3).Download(server.URL, output)
+	require.NoError(t, err)
+
+	downloaded, err := os.ReadFile(output)
+	require.NoError(t, err)
+	assert.Equal(t, content, downloaded)
+	assert.Equal(t, int32(2), atomic.LoadInt32(&requests))
+}
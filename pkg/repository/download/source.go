@@ -0,0 +1,30 @@
+package download
+
+import (
+	"net/url"
+	"strings"
+)
+
+// sensitiveParameters are parts of query parameter names which indicate credentials, e.g. SAS or pre-signed URLs
+var sensitiveParameters = []string{"sig", "token", "key", "credential", "password", "secret", "auth"}
+
+// RedactURL removes user info and credential query parameters from the URL so it can be reported as source of an app
+func RedactURL(raw string) string {
+	parsed, err := url.Parse(raw)
+	if err != nil {
+		return ""
+	}
+	parsed.User = nil
+	query := parsed.Query()
+	for name := range query {
+		lower := strings.ToLower(name)
+		for _, sensitive := range sensitiveParameters {
+			if strings.Contains(lower, sensitive) {
+				query.Del(name)
+				break
+			}
+		}
+	}
+	parsed.RawQuery = query.Encode()
+	return parsed.String()
+}
@@ -2,32 +2,126 @@ package registry
 
 import (
 	"fmt"
+	"sync"
+	"time"
 
 	"github.com/B-S-F/onyx/pkg/logger"
 	"github.com/B-S-F/onyx/pkg/repository"
 	"github.com/B-S-F/onyx/pkg/repository/app"
 )
 
+// DEFAULT_PARALLEL_INSTALLS is the default number of apps which are installed at the same time
+const DEFAULT_PARALLEL_INSTALLS = 4
+
+// Resolution defines which repository provides an app without a repository prefix
+type Resolution string
+
+const (
+	// ResolutionFirst installs the app from the first repository which provides it
+	ResolutionFirst Resolution = "first"
+	// ResolutionStrict fails if more than one repository provides the app
+	ResolutionStrict Resolution = "strict"
+)
+
+// ParseResolution returns the resolution for the given value, an empty value defaults to ResolutionFirst
+func ParseResolution(value string) (Resolution, error) {
+	switch Resolution(value) {
+	case "", ResolutionFirst:
+		return ResolutionFirst, nil
+	case ResolutionStrict:
+		return ResolutionStrict, nil
+	default:
+		return "", fmt.Errorf("unknown resolution '%s', must be one of '%s' or '%s'", value, ResolutionFirst, ResolutionStrict)
+	}
+}
+
 type Registry struct {
 	logger         logger.Logger
 	repositoryApps map[string]app.App
 	repositories   map[string]repository.Repository
+	// order in which the repositories are asked for apps without a repository prefix
+	order      []repository.Repository
+	resolution Resolution
+	// installations of the apps by repository, name and version, which determine the installation path
+	installations map[string]*installation
+	mutex         sync.RWMutex
+}
+
+// installation of an app from a repository, it is shared by all references which resolve to the same installation path,
+// e.g. app@1.0.0 and repository::app@1.0.0, so they are not installed into the same path at the same time
+type installation struct {
+	mutex sync.Mutex
+	app   app.App
 }
 
-func NewRegistry(repositories []repository.Repository) *Registry {
+// NewRegistry creates a registry for the repositories, they are asked for apps in the given order
+func NewRegistry(repositories []repository.Repository, resolution Resolution) *Registry {
 	registryRepositories := make(map[string]repository.Repository)
+	var order []repository.Repository
 	for _, repository := range repositories {
+		if _, ok := registryRepositories[repository.Name()]; !ok {
+			order = append(order, repository)
+		}
 		registryRepositories[repository.Name()] = repository
 	}
 	return &Registry{
 		logger:         logger.Get(),
 		repositoryApps: make(map[string]app.App),
 		repositories:   registryRepositories,
+		order:          order,
+		resolution:     resolution,
+		installations:  make(map[string]*installation),
+	}
+}
+
+// InstallAll installs the given apps with at most parallelism installations at the same time.
+// Duplicate references are installed once. All failures are returned in the order of the references.
+func (r *Registry) InstallAll(references []*app.Reference, parallelism int) error {
+	if parallelism < 1 {
+		parallelism = 1
+	}
+	var unique []*app.Reference
+	seen := make(map[string]bool)
+	for _, reference := range references {
+		if seen[r.hashKey(reference)] {
+			continue
+		}
+		seen[r.hashKey(reference)] = true
+		unique = append(unique, reference)
+	}
+
+	start := time.Now()
+	errs := make([]error, len(unique))
+	semaphore := make(chan struct{}, parallelism)
+	var wg sync.WaitGroup
+	for i, reference := range unique {
+		wg.Add(1)
+		semaphore <- struct{}{}
+		go func(i int, reference *app.Reference) {
+			defer wg.Done()
+			defer func() { <-semaphore }()
+			errs[i] = r.Install(reference)
+		}(i, reference)
+	}
+	wg.Wait()
+	r.logger.Infof("installed %d apps in %s", len(unique), time.Since(start).Round(time.Millisecond))
+
+	var err error
+	for i, installError := range errs {
+		if installError == nil {
+			continue
+		}
+		if err == nil {
+			err = fmt.Errorf("error installing app %s: %w", unique[i], installError)
+			continue
+		}
+		err = fmt.Errorf("%w\nerror installing app %s: %v", err, unique[i], installError)
 	}
+	return err
 }
 
 func (r *Registry) Install(reference *app.Reference) error {
-	if ok := r.repositoryApps[r.hashKey(reference)]; ok != nil {
+	if _, err := r.Get(reference); err == nil {
 		r.logger.Infof("app.App %s already installed", reference)
 		return nil
 	}
@@ -40,36 +134,71 @@ func (r *Registry) Install(reference *app.Reference) error {
 	return r.installFromRepository(reference)
 }
 
-// tryInstallFromAllRepositories tries to install the app from all repositories.
-// If the app is found in multiple repositories, an error is returned.
+// tryInstallFromAllRepositories installs the app from the repositories in their order.
+// Repositories which support probes are only asked if they contain the app, others have to download it.
+// With ResolutionFirst the first repository providing the app wins,
+// with ResolutionStrict an error is returned if the app is found in multiple repositories.
 func (r *Registry) tryInstallFromAllRepositories(appReferences *app.Reference) error {
-	r.logger.Debugf("Installing app %s from all repositories", appReferences)
+	r.logger.Debugf("Installing app %s from all repositories with resolution %s", appReferences, r.resolution)
 	var installationErrors []error
-	var foundApps []app.App = make([]app.App, 0)
-	for _, repository := range r.repositories {
-		app, err := repository.InstallApp(appReferences)
+	var candidates []repository.Repository
+	installedApps := make(map[string]app.App)
+	for _, repo := range r.order {
+		if r.resolution != ResolutionStrict && len(candidates) > 0 {
+			break
+		}
+		start := time.Now()
+		if prober, ok := repo.(repository.Prober); ok {
+			found, err := prober.HasApp(appReferences)
+			r.logger.Debugf("probed app %s in repository %s in %s: found=%t err=%v", appReferences, repo.Name(), time.Since(start).Round(time.Millisecond), found, err)
+			if err != nil {
+				installationErrors = append(installationErrors, fmt.Errorf("repository %s: %v", repo.Name(), err))
+				continue
+			}
+			if !found {
+				installationErrors = append(installationErrors, fmt.Errorf("repository %s: app not found", repo.Name()))
+				continue
+			}
+			if r.resolution == ResolutionStrict {
+				candidates = append(candidates, repo)
+				continue
+			}
+		}
+		app, err := r.timedInstall(repo, appReferences)
 		if err != nil {
-			r.logger.Debugf("Failed to install app %s from repository %s: %v", appReferences, repository.Name(), err)
-			installationErrors = append(installationErrors, fmt.Errorf("repository %s: %v", repository.Name(), err))
+			installationErrors = append(installationErrors, fmt.Errorf("repository %s: %v", repo.Name(), err))
 			continue
 		}
-		foundApps = append(foundApps, app)
+		candidates = append(candidates, repo)
+		installedApps[repo.Name()] = app
 	}
-	if len(foundApps) == 0 {
+	if len(candidates) == 0 {
 		err := fmt.Errorf("app %s could not be downloaded from any repository:", appReferences)
 		for _, installationError := range installationErrors {
 			err = fmt.Errorf("%w\n\t%v", err, installationError)
 		}
 		return err
 	}
-	if len(foundApps) > 1 {
-		repositoryNames := make([]string, 0, len(foundApps))
-		for _, app := range foundApps {
-			repositoryNames = append(repositoryNames, app.Reference().Repository)
+	if len(candidates) > 1 {
+		repositoryNames := make([]string, 0, len(candidates))
+		for _, candidate := range candidates {
+			repositoryNames = append(repositoryNames, candidate.Name())
 		}
 		return fmt.Errorf("app %s found in multiple repositories %v", appReferences, repositoryNames)
 	}
-	r.repositoryApps[appReferences.String()] = foundApps[0]
+
+	installed, ok := installedApps[candidates[0].Name()]
+	if !ok {
+		var err error
+		installed, err = r.timedInstall(candidates[0], appReferences)
+		if err != nil {
+			return err
+		}
+	}
+	r.logger.Infof("resolved app %s to repository %s", appReferences, candidates[0].Name())
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	r.repositoryApps[appReferences.String()] = installed
 	return nil
 }
 
@@ -80,20 +209,60 @@ func (r *Registry) installFromRepository(appReference *app.Reference) error {
 		return fmt.Errorf("repository %s not found", appReference.Repository)
 	}
 
-	app, err := repository.InstallApp(appReference)
+	app, err := r.timedInstall(repository, appReference)
 	if err != nil {
 		return err
 	}
 
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
 	r.repositoryApps[appReference.String()] = app
 	return nil
 }
 
+// timedInstall installs the app and logs how long the installation took.
+// An app which is already installed from the repository is reused, a running installation of it is awaited.
+func (r *Registry) timedInstall(repository repository.Repository, appReference *app.Reference) (app.App, error) {
+	installation := r.installation(repository, appReference)
+	installation.mutex.Lock()
+	defer installation.mutex.Unlock()
+	if installation.app != nil {
+		r.logger.Debugf("app %s is already installed from repository %s", appReference, repository.Name())
+		return installation.app, nil
+	}
+
+	start := time.Now()
+	app, err := repository.InstallApp(appReference)
+	if err != nil {
+		r.logger.Debugf("failed to install app %s from repository %s after %s: %v", appReference, repository.Name(), time.Since(start).Round(time.Millisecond), err)
+		return nil, err
+	}
+	r.logger.Infof("installed app %s from repository %s in %s", appReference, repository.Name(), time.Since(start).Round(time.Millisecond))
+	installation.app = app
+	return app, nil
+}
+
+// installation returns the installation of the app from the repository, the key does not depend on the repository prefix of the reference
+func (r *Registry) installation(repository repository.Repository, appReference *app.Reference) *installation {
+	key := (&app.Reference{Repository: repository.Name(), Name: appReference.Name, Version: appReference.Version}).String()
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	if r.installations == nil {
+		r.installations = make(map[string]*installation)
+	}
+	if _, ok := r.installations[key]; !ok {
+		r.installations[key] = &installation{}
+	}
+	return r.installations[key]
+}
+
 func (r *Registry) hashKey(reference *app.Reference) string {
 	return reference.String()
 }
 
 func (r *Registry) Get(reference *app.Reference) (app.App, error) {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
 	app, ok := r.repositoryApps[r.hashKey(reference)]
 	if !ok {
 		return nil, fmt.Errorf("app %s not found", reference)
@@ -102,5 +271,7 @@ func (r *Registry) Get(reference *app.Reference) (app.App, error) {
 }
 
 func (r *Registry) Stats() string {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
 	return fmt.Sprintf("Number of apps: %d", len(r.repositoryApps))
 }
@@ -43,7 +43,7 @@ func TestInstall(t *testing.T) {
 		},
 	}
 
-	registry := NewRegistry([]repository.Repository{&repo1, &repo2})
+	registry := NewRegistry([]repository.Repository{&repo1, &repo2}, ResolutionStrict)
 
 	t.Run("test install with defined repository", func(t *testing.T) {
 		appRef := &app.Reference{
@@ -101,4 +101,19 @@ func TestInstall(t *testing.T) {
 		assert.Contains(t, err.Error(), "repo2")
 		assert.Contains(t, err.Error(), "app@1.0.0")
 	})
+
+	t.Run("test install from first repository in order", func(t *testing.T) {
+		registry := NewRegistry([]repository.Repository{&repo2, &repo1}, ResolutionFirst)
+		appRef := &app.Reference{
+			Name:    "app",
+			Version: "1.0.0",
+		}
+
+		err := registry.Install(appRef)
+		assert.NoError(t, err)
+
+		app, err := registry.Get(appRef)
+		assert.NoError(t, err)
+		assert.Equal(t, "repo2", app.Reference().Repository)
+	})
 }
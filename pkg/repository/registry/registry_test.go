@@ -2,7 +2,9 @@ package registry
 
 import (
 	"errors"
+	"sync"
 	"testing"
+	"time"
 
 	"github.com/B-S-F/onyx/pkg/repository"
 	"github.com/B-S-F/onyx/pkg/repository/app"
@@ -27,6 +29,10 @@ func (m *MockApp) PossibleReferences() []string {
 	return []string{"mockapp@1.0.0", "mockapp"}
 }
 
+func (m *MockApp) Executables() []app.Executable {
+	return []app.Executable{{Path: m.ExecutablePath(), References: m.PossibleReferences()}}
+}
+
 type MockRepository struct {
 	RepositoryName string
 }
@@ -54,7 +60,7 @@ func TestNewRegistry(t *testing.T) {
 	}
 
 	// Create a new registry with the mock repositories
-	registry := NewRegistry(repositories)
+	registry := NewRegistry(repositories, ResolutionFirst)
 
 	// Assert that the registry is not nil
 	assert.NotNil(t, registry)
@@ -122,3 +128,146 @@ func TestStats(t *testing.T) {
 	assert.NotEmpty(t, stats)
 	assert.Equal(t, stats, "Number of apps: 2")
 }
+
+type MockProbeRepository struct {
+	RepositoryName string
+	Apps           map[string]bool
+	mutex          sync.Mutex
+	installs       int
+	running        int
+	maxRunning     int
+}
+
+func (m *MockProbeRepository) InstallApp(appReference *app.Reference) (app.App, error) {
+	m.mutex.Lock()
+	m.installs++
+	m.running++
+	if m.running > m.maxRunning {
+		m.maxRunning = m.running
+	}
+	m.mutex.Unlock()
+	time.Sleep(10 * time.Millisecond)
+	m.mutex.Lock()
+	m.running--
+	m.mutex.Unlock()
+	if !m.Apps[appReference.Name] {
+		return nil, errors.New("app not found")
+	}
+	return &MockApp{}, nil
+}
+
+func (m *MockProbeRepository) HasApp(appReference *app.Reference) (bool, error) {
+	return m.Apps[appReference.Name], nil
+}
+
+func (m *MockProbeRepository) Name() string {
+	return m.RepositoryName
+}
+
+func TestInstallWithStrictResolution(t *testing.T) {
+	repo1 := &MockProbeRepository{RepositoryName: "repo1", Apps: map[string]bool{"app1": true, "shared": true}}
+	repo2 := &MockProbeRepository{RepositoryName: "repo2", Apps: map[string]bool{"app2": true, "shared": true}}
+	registry := NewRegistry([]repository.Repository{repo1, repo2}, ResolutionStrict)
+
+	t.Run("installs only from the repository containing the app", func(t *testing.T) {
+		err := registry.Install(&app.Reference{Name: "app2", Version: "1.0.0"})
+		assert.NoError(t, err)
+		assert.Equal(t, 0, repo1.installs)
+		assert.Equal(t, 1, repo2.installs)
+	})
+
+	t.Run("fails if the app is found in multiple repositories", func(t *testing.T) {
+		err := registry.Install(&app.Reference{Name: "shared", Version: "1.0.0"})
+		assert.ErrorContains(t, err, "found in multiple repositories [repo1 repo2]")
+		assert.Equal(t, 0, repo1.installs)
+	})
+
+	t.Run("fails if the app is not found", func(t *testing.T) {
+		err := registry.Install(&app.Reference{Name: "missing", Version: "1.0.0"})
+		assert.ErrorContains(t, err, "could not be downloaded from any repository")
+		assert.ErrorContains(t, err, "repository repo1: app not found")
+	})
+}
+
+func TestInstallAll(t *testing.T) {
+	repo := &MockProbeRepository{RepositoryName: "repo", Apps: map[string]bool{"app1": true, "app2": true, "app3": true, "app4": true}}
+	registry := NewRegistry([]repository.Repository{repo}, ResolutionFirst)
+	var references []*app.Reference
+	for _, name := range []string{"app1", "app2", "app3", "app4", "app1"} {
+		references = append(references, &app.Reference{Repository: "repo", Name: name, Version: "1.0.0"})
+	}
+
+	err := registry.InstallAll(references, 2)
+	assert.NoError(t, err)
+	assert.Equal(t, 4, repo.installs)
+	assert.Equal(t, 2, repo.maxRunning)
+	assert.Equal(t, "Number of apps: 4", registry.Stats())
+
+	err = registry.InstallAll([]*app.Reference{
+		{Repository: "repo", Name: "unknown", Version: "1.0.0"},
+		{Repository: "other", Name: "app1", Version: "1.0.0"},
+	}, 2)
+	assert.ErrorContains(t, err, "error installing app repo::unknown@1.0.0: app not found")
+	assert.ErrorContains(t, err, "repository other not found")
+}
+
+func TestInstallAllSharesInstallationPath(t *testing.T) {
+	repo := &MockProbeRepository{RepositoryName: "repo", Apps: map[string]bool{"app": true}}
+	registry := NewRegistry([]repository.Repository{repo}, ResolutionFirst)
+	unqualified := &app.Reference{Name: "app", Version: "1.0.0"}
+	qualified := &app.Reference{Repository: "repo", Name: "app", Version: "1.0.0"}
+
+	err := registry.InstallAll([]*app.Reference{unqualified, qualified}, 2)
+
+	assert.NoError(t, err)
+	assert.Equal(t, 1, repo.installs)
+	assert.Equal(t, 1, repo.maxRunning)
+	_, err = registry.Get(unqualified)
+	assert.NoError(t, err)
+	_, err = registry.Get(qualified)
+	assert.NoError(t, err)
+}
+
+func TestInstallWithFirstResolution(t *testing.T) {
+	repo1 := &MockProbeRepository{RepositoryName: "repo1", Apps: map[string]bool{"app1": true, "shared": true}}
+	repo2 := &MockRepository{RepositoryName: "repo2"}
+	repo3 := &MockProbeRepository{RepositoryName: "repo3", Apps: map[string]bool{"app3": true, "shared": true}}
+	registry := NewRegistry([]repository.Repository{repo3, repo2, repo1}, ResolutionFirst)
+
+	err := registry.Install(&app.Reference{Name: "shared", Version: "1.0.0"})
+	assert.NoError(t, err)
+	assert.Equal(t, 0, repo1.installs)
+	assert.Equal(t, 1, repo3.installs)
+
+	err = registry.Install(&app.Reference{Name: "app1", Version: "1.0.0"})
+	assert.NoError(t, err)
+	assert.Equal(t, 1, repo1.installs)
+	assert.Equal(t, 1, repo3.installs)
+
+	err = registry.Install(&app.Reference{Name: "missing", Version: "1.0.0"})
+	assert.ErrorContains(t, err, "repository repo2: not implemented")
+}
+
+func TestParseResolution(t *testing.T) {
+	testCases := map[string]struct {
+		value    string
+		expected Resolution
+		err      string
+	}{
+		"default": {value: "", expected: ResolutionFirst},
+		"first":   {value: "first", expected: ResolutionFirst},
+		"strict":  {value: "strict", expected: ResolutionStrict},
+		"unknown": {value: "last", err: "unknown resolution 'last'"},
+	}
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			resolution, err := ParseResolution(tc.value)
+			if tc.err != "" {
+				assert.ErrorContains(t, err, tc.err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expected, resolution)
+		})
+	}
+}
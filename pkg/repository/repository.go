@@ -18,6 +18,11 @@ type Repository interface {
 	Name() string
 }
 
+// Prober is implemented by repositories which can check if an app exists without downloading it
+type Prober interface {
+	HasApp(*app.Reference) (bool, error)
+}
+
 type RepositoryFactory struct {
 	toRepository map[string]func(name string, installationPath string, config map[string]interface{}) (Repository, error)
 }
@@ -33,6 +38,12 @@ func (r *RepositoryFactory) Register(typeName string, conversion func(name strin
 	r.toRepository[typeName] = conversion
 }
 
+// Supports checks if a repository type is registered
+func (r *RepositoryFactory) Supports(typeName string) bool {
+	_, ok := r.toRepository[typeName]
+	return ok
+}
+
 func NewRepositoryFactory() *RepositoryFactory {
 	return &RepositoryFactory{
 		toRepository: make(map[string]func(name string, installationPath string, config map[string]interface{}) (Repository, error)),
@@ -0,0 +1,103 @@
+package signature
+
+import (
+	"fmt"
+	"os"
+	"strings"
+)
+
+// SignatureKey is the key in a repository config which enables the signature verification of the apps
+const SignatureKey = "signature"
+
+const (
+	TypeCosign   = "cosign"
+	TypeMinisign = "minisign"
+	TypeGPG      = "gpg"
+)
+
+const (
+	// PolicyEnforce fails the installation if the signature is missing or invalid
+	PolicyEnforce = "enforce"
+	// PolicyWarn logs a warning and installs the app without verified signature
+	PolicyWarn = "warn"
+)
+
+// defaultSuffixes of the signature files which are downloaded next to the apps
+var defaultSuffixes = map[string]string{
+	TypeCosign:   ".bundle",
+	TypeMinisign: ".minisig",
+	TypeGPG:      ".asc",
+}
+
+type Config struct {
+	// Type of the signature, one of cosign, minisign or gpg
+	Type string
+	// PublicKey (path or content) which has to verify the signature
+	// cosign: PEM encoded public key, minisign: public key file or base64 key, gpg: armored or binary key ring
+	PublicKey string
+	// Signer is reported for keys which do not carry an identity, e.g. cosign keys
+	Signer string
+	// Suffix which is appended to the location of the app to get the signature
+	// Example ".sig"
+	Suffix string
+	// Policy defines what happens if the verification fails, either enforce or warn
+	Policy string
+}
+
+// NewConfig parses the signature section of a repository config.
+// It returns nil if the apps of the repository are not signed.
+func NewConfig(config map[string]interface{}) (*Config, error) {
+	if config[SignatureKey] == nil {
+		return nil, nil
+	}
+	signatureConfig, ok := config[SignatureKey].(map[string]interface{})
+	if !ok {
+		return nil, fmt.Errorf("%s must be a map", SignatureKey)
+	}
+	parsed := &Config{Policy: PolicyEnforce}
+	for key, value := range map[string]*string{
+		"type":       &parsed.Type,
+		"public_key": &parsed.PublicKey,
+		"signer":     &parsed.Signer,
+		"suffix":     &parsed.Suffix,
+		"policy":     &parsed.Policy,
+	} {
+		if signatureConfig[key] == nil {
+			continue
+		}
+		*value, ok = signatureConfig[key].(string)
+		if !ok {
+			return nil, fmt.Errorf("%s must be a string", key)
+		}
+	}
+	defaultSuffix, ok := defaultSuffixes[parsed.Type]
+	if !ok {
+		return nil, fmt.Errorf("unknown signature type '%s', must be one of '%s', '%s' or '%s'", parsed.Type, TypeCosign, TypeMinisign, TypeGPG)
+	}
+	if parsed.Suffix == "" {
+		parsed.Suffix = defaultSuffix
+	}
+	if parsed.PublicKey == "" {
+		return nil, fmt.Errorf("missing 'public_key' in signature config")
+	}
+	if parsed.Policy != PolicyEnforce && parsed.Policy != PolicyWarn {
+		return nil, fmt.Errorf("unknown signature policy '%s', must be '%s' or '%s'", parsed.Policy, PolicyEnforce, PolicyWarn)
+	}
+	return parsed, nil
+}
+
+// readKey reads the key from the file if the value is an existing path, otherwise the value is the key itself.
+// This allows to pass keys directly from secrets.
+func readKey(value string) ([]byte, error) {
+	if strings.Contains(value, "\n") {
+		return []byte(value), nil
+	}
+	content, err := os.ReadFile(value)
+	if os.IsPermission(err) {
+		return nil, err
+	}
+	if err != nil {
+		return []byte(value), nil
+	}
+	return content, nil
+}
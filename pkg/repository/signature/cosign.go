@@ -0,0 +1,119 @@
+package signature
+
+import (
+	"bytes"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/B-S-F/onyx/pkg/repository/app"
+)
+
+// cosignBundle covers the bundle of 'cosign sign-blob --bundle' as well as the sigstore bundle format.
+// The transparency log entries are not checked, the signature is verified offline against the public key.
+type cosignBundle struct {
+	// cosign bundle
+	Base64Signature string `json:"base64Signature"`
+	// sigstore bundle
+	MessageSignature *struct {
+		MessageDigest struct {
+			Algorithm string `json:"algorithm"`
+			Digest    string `json:"digest"`
+		} `json:"messageDigest"`
+		Signature string `json:"signature"`
+	} `json:"messageSignature"`
+}
+
+// verifyCosign verifies a cosign or sigstore bundle or a plain base64 encoded signature of 'cosign sign-blob'
+func verifyCosign(filePath string, signature []byte, publicKey []byte) (*app.Signature, error) {
+	key, keyID, err := parseCosignKey(publicKey)
+	if err != nil {
+		return nil, err
+	}
+	digest, err := sha256File(filePath)
+	if err != nil {
+		return nil, err
+	}
+
+	encodedSignature := string(bytes.TrimSpace(signature))
+	if bytes.HasPrefix(bytes.TrimSpace(signature), []byte("{")) {
+		var bundle cosignBundle
+		err = json.Unmarshal(signature, &bundle)
+		if err != nil {
+			return nil, fmt.Errorf("error parsing cosign bundle: %w", err)
+		}
+		encodedSignature = bundle.Base64Signature
+		if bundle.MessageSignature != nil {
+			if bundle.MessageSignature.MessageDigest.Algorithm != "SHA2_256" {
+				return nil, fmt.Errorf("unsupported digest algorithm '%s' in sigstore bundle", bundle.MessageSignature.MessageDigest.Algorithm)
+			}
+			if bundle.MessageSignature.MessageDigest.Digest != base64.StdEncoding.EncodeToString(digest) {
+				return nil, fmt.Errorf("digest of sigstore bundle does not match the app")
+			}
+			encodedSignature = bundle.MessageSignature.Signature
+		}
+	}
+	rawSignature, err := base64.StdEncoding.DecodeString(encodedSignature)
+	if err != nil || len(rawSignature) == 0 {
+		return nil, fmt.Errorf("signature is not base64 encoded")
+	}
+
+	switch key := key.(type) {
+	case *ecdsa.PublicKey:
+		if !ecdsa.VerifyASN1(key, digest, rawSignature) {
+			return nil, fmt.Errorf("invalid signature")
+		}
+	case *rsa.PublicKey:
+		if rsa.VerifyPKCS1v15(key, crypto.SHA256, digest, rawSignature) != nil && rsa.VerifyPSS(key, crypto.SHA256, digest, rawSignature, nil) != nil {
+			return nil, fmt.Errorf("invalid signature")
+		}
+	case ed25519.PublicKey:
+		content, err := os.ReadFile(filePath)
+		if err != nil {
+			return nil, fmt.Errorf("error reading file: %w", err)
+		}
+		if !ed25519.Verify(key, content, rawSignature) {
+			return nil, fmt.Errorf("invalid signature")
+		}
+	default:
+		return nil, fmt.Errorf("unsupported public key type %T", key)
+	}
+	return &app.Signature{KeyID: keyID}, nil
+}
+
+// parseCosignKey parses a PEM encoded public key, the key id is the sha256 of the DER encoded key
+func parseCosignKey(publicKey []byte) (crypto.PublicKey, string, error) {
+	block, _ := pem.Decode(publicKey)
+	if block == nil {
+		return nil, "", fmt.Errorf("public key is not PEM encoded")
+	}
+	key, err := x509.ParsePKIXPublicKey(block.Bytes)
+	if err != nil {
+		return nil, "", fmt.Errorf("error parsing public key: %w", err)
+	}
+	keyID := sha256.Sum256(block.Bytes)
+	return key, "sha256:" + hex.EncodeToString(keyID[:]), nil
+}
+
+func sha256File(filePath string) ([]byte, error) {
+	file, err := os.Open(filePath)
+	if err != nil {
+		return nil, fmt.Errorf("error opening file: %w", err)
+	}
+	defer file.Close()
+	hash := sha256.New()
+	if _, err := io.Copy(hash, file); err != nil {
+		return nil, fmt.Errorf("error hashing file: %w", err)
+	}
+	return hash.Sum(nil), nil
+}
@@ -0,0 +1,51 @@
+package signature
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+
+	"github.com/B-S-F/onyx/pkg/repository/app"
+	"github.com/ProtonMail/go-crypto/openpgp"
+)
+
+// verifyGPG verifies an armored or binary detached signature against a key ring.
+// The fingerprint of the primary key and its primary user id are reported.
+func verifyGPG(filePath string, signature []byte, publicKey []byte) (*app.Signature, error) {
+	keyRing, err := readKeyRing(publicKey)
+	if err != nil {
+		return nil, fmt.Errorf("error parsing public key: %w", err)
+	}
+	file, err := os.Open(filePath)
+	if err != nil {
+		return nil, fmt.Errorf("error opening file: %w", err)
+	}
+	defer file.Close()
+
+	var signer *openpgp.Entity
+	if isArmored(signature) {
+		signer, err = openpgp.CheckArmoredDetachedSignature(keyRing, file, bytes.NewReader(signature), nil)
+	} else {
+		signer, err = openpgp.CheckDetachedSignature(keyRing, file, bytes.NewReader(signature), nil)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("invalid signature: %w", err)
+	}
+
+	verified := &app.Signature{KeyID: fmt.Sprintf("%X", signer.PrimaryKey.Fingerprint)}
+	if identity := signer.PrimaryIdentity(); identity != nil {
+		verified.Signer = identity.Name
+	}
+	return verified, nil
+}
+
+func readKeyRing(publicKey []byte) (openpgp.EntityList, error) {
+	if isArmored(publicKey) {
+		return openpgp.ReadArmoredKeyRing(bytes.NewReader(publicKey))
+	}
+	return openpgp.ReadKeyRing(bytes.NewReader(publicKey))
+}
+
+func isArmored(content []byte) bool {
+	return bytes.HasPrefix(bytes.TrimSpace(content), []byte("-----BEGIN PGP"))
+}
@@ -0,0 +1,99 @@
+package signature
+
+import (
+	"bytes"
+	"crypto/ed25519"
+	"encoding/base64"
+	"encoding/binary"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/B-S-F/onyx/pkg/repository/app"
+	"golang.org/x/crypto/blake2b"
+)
+
+const trustedCommentPrefix = "trusted comment: "
+
+// verifyMinisign verifies a minisign signature, both legacy and prehashed signatures are supported.
+// The trusted comment of the signature is reported as signer.
+func verifyMinisign(filePath string, signature []byte, publicKey []byte) (*app.Signature, error) {
+	keyID, key, err := decodeMinisign(lastNonCommentLine(publicKey), "Ed", ed25519.PublicKeySize)
+	if err != nil {
+		return nil, fmt.Errorf("error parsing public key: %w", err)
+	}
+
+	lines := strings.Split(strings.TrimSpace(string(signature)), "\n")
+	if len(lines) != 4 || !strings.HasPrefix(lines[2], trustedCommentPrefix) {
+		return nil, fmt.Errorf("invalid minisign signature file")
+	}
+	algorithm, signatureKeyID, rawSignature, err := decodeMinisignSignature(lines[1])
+	if err != nil {
+		return nil, err
+	}
+	if signatureKeyID != keyID {
+		return nil, fmt.Errorf("signature was created by key %s instead of %s", formatMinisignKeyID(signatureKeyID), formatMinisignKeyID(keyID))
+	}
+
+	message, err := os.ReadFile(filePath)
+	if err != nil {
+		return nil, fmt.Errorf("error reading file: %w", err)
+	}
+	if algorithm == "ED" {
+		hash := blake2b.Sum512(message)
+		message = hash[:]
+	}
+	if !ed25519.Verify(key, message, rawSignature) {
+		return nil, fmt.Errorf("invalid signature")
+	}
+
+	trustedComment := strings.TrimPrefix(strings.TrimRight(lines[2], "\r"), trustedCommentPrefix)
+	globalSignature, err := base64.StdEncoding.DecodeString(strings.TrimSpace(lines[3]))
+	if err != nil || !ed25519.Verify(key, append(rawSignature, []byte(trustedComment)...), globalSignature) {
+		return nil, fmt.Errorf("invalid signature of trusted comment")
+	}
+	return &app.Signature{KeyID: formatMinisignKeyID(keyID), Signer: trustedComment}, nil
+}
+
+func decodeMinisignSignature(line string) (string, [8]byte, []byte, error) {
+	decoded, err := base64.StdEncoding.DecodeString(strings.TrimSpace(line))
+	if err != nil || len(decoded) != 2+8+ed25519.SignatureSize {
+		return "", [8]byte{}, nil, fmt.Errorf("invalid minisign signature")
+	}
+	algorithm := string(decoded[:2])
+	if algorithm != "Ed" && algorithm != "ED" {
+		return "", [8]byte{}, nil, fmt.Errorf("unsupported minisign signature algorithm '%s'", algorithm)
+	}
+	var keyID [8]byte
+	copy(keyID[:], decoded[2:10])
+	return algorithm, keyID, decoded[10:], nil
+}
+
+// decodeMinisign decodes the base64 encoded algorithm, key id and payload of a minisign key
+func decodeMinisign(line string, algorithm string, size int) ([8]byte, []byte, error) {
+	var keyID [8]byte
+	decoded, err := base64.StdEncoding.DecodeString(line)
+	if err != nil || len(decoded) != 2+8+size || string(decoded[:2]) != algorithm {
+		return keyID, nil, fmt.Errorf("invalid minisign key")
+	}
+	copy(keyID[:], decoded[2:10])
+	return keyID, decoded[10:], nil
+}
+
+// lastNonCommentLine returns the key of a minisign public key file which can contain an untrusted comment
+func lastNonCommentLine(content []byte) string {
+	var last string
+	for _, line := range bytes.Split(content, []byte("\n")) {
+		line = bytes.TrimSpace(line)
+		if len(line) == 0 || bytes.HasPrefix(line, []byte("untrusted comment:")) {
+			continue
+		}
+		last = string(line)
+	}
+	return last
+}
+
+// formatMinisignKeyID formats the key id like the minisign tool
+func formatMinisignKeyID(keyID [8]byte) string {
+	return fmt.Sprintf("%016X", binary.LittleEndian.Uint64(keyID[:]))
+}
@@ -0,0 +1,60 @@
+package signature
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/B-S-F/onyx/pkg/logger"
+	"github.com/B-S-F/onyx/pkg/repository/app"
+)
+
+// verifier checks the detached signature of a file with the public key
+type verifier func(filePath string, signature []byte, publicKey []byte) (*app.Signature, error)
+
+var verifiers = map[string]verifier{
+	TypeCosign:   verifyCosign,
+	TypeMinisign: verifyMinisign,
+	TypeGPG:      verifyGPG,
+}
+
+// Verify fetches the signature of the downloaded file next to it and verifies it.
+// fetch downloads the signature, which is located at the location of the app with the configured suffix, to the given path.
+// With PolicyWarn failed verifications are logged and reported with status failed instead of an error.
+func (c *Config) Verify(filePath string, fetch func(signaturePath string) error) (*app.Signature, error) {
+	signature, err := c.verify(filePath, fetch)
+	if err == nil {
+		logger.Get().Infof("verified %s signature of %s with key %s", c.Type, filePath, signature.KeyID)
+		return signature, nil
+	}
+	if c.Policy == PolicyWarn {
+		logger.Get().Warnf("signature verification of %s failed: %v", filePath, err)
+		return &app.Signature{Type: c.Type, Status: app.SignatureFailed}, nil
+	}
+	return nil, fmt.Errorf("signature verification failed: %w", err)
+}
+
+func (c *Config) verify(filePath string, fetch func(signaturePath string) error) (*app.Signature, error) {
+	signaturePath := filePath + c.Suffix
+	err := fetch(signaturePath)
+	if err != nil {
+		return nil, fmt.Errorf("error fetching signature: %w", err)
+	}
+	signature, err := os.ReadFile(signaturePath)
+	if err != nil {
+		return nil, fmt.Errorf("error reading signature: %w", err)
+	}
+	publicKey, err := readKey(c.PublicKey)
+	if err != nil {
+		return nil, fmt.Errorf("error reading public key: %w", err)
+	}
+	verified, err := verifiers[c.Type](filePath, signature, publicKey)
+	if err != nil {
+		return nil, err
+	}
+	verified.Type = c.Type
+	verified.Status = app.SignatureVerified
+	if verified.Signer == "" {
+		verified.Signer = c.Signer
+	}
+	return verified, nil
+}
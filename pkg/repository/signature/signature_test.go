@@ -0,0 +1,284 @@
+package signature
+
+import (
+	"bytes"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/B-S-F/onyx/pkg/repository/app"
+	"github.com/ProtonMail/go-crypto/openpgp"
+	"github.com/ProtonMail/go-crypto/openpgp/armor"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/blake2b"
+)
+
+var content = []byte("#!/bin/sh\necho hello\n")
+
+func writeApp(t *testing.T) string {
+	path := filepath.Join(t.TempDir(), "app")
+	require.NoError(t, os.WriteFile(path, content, 0755))
+	return path
+}
+
+// fetchSignature imitates a repository which provides the signature next to the app
+func fetchSignature(signature []byte) func(string) error {
+	return func(signaturePath string) error {
+		if signature == nil {
+			return fmt.Errorf("404 Not Found")
+		}
+		return os.WriteFile(signaturePath, signature, 0644)
+	}
+}
+
+func ecdsaKey(t *testing.T) (*ecdsa.PrivateKey, string) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	der, err := x509.MarshalPKIXPublicKey(&key.PublicKey)
+	require.NoError(t, err)
+	return key, string(pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der}))
+}
+
+func TestNewConfig(t *testing.T) {
+	testCases := map[string]struct {
+		config   map[string]interface{}
+		expected *Config
+		err      string
+	}{
+		"no signature": {
+			config: map[string]interface{}{"url": "https://example.com"},
+		},
+		"defaults": {
+			config:   map[string]interface{}{"signature": map[string]interface{}{"type": "minisign", "public_key": "key"}},
+			expected: &Config{Type: "minisign", PublicKey: "key", Suffix: ".minisig", Policy: "enforce"},
+		},
+		"all values": {
+			config: map[string]interface{}{"signature": map[string]interface{}{
+				"type": "cosign", "public_key": "key", "signer": "release-team", "suffix": ".sig", "policy": "warn",
+			}},
+			expected: &Config{Type: "cosign", PublicKey: "key", Signer: "release-team", Suffix: ".sig", Policy: "warn"},
+		},
+		"no map":         {config: map[string]interface{}{"signature": "cosign"}, err: "signature must be a map"},
+		"unknown type":   {config: map[string]interface{}{"signature": map[string]interface{}{"type": "x509", "public_key": "key"}}, err: "unknown signature type 'x509'"},
+		"missing key":    {config: map[string]interface{}{"signature": map[string]interface{}{"type": "gpg"}}, err: "missing 'public_key'"},
+		"unknown policy": {config: map[string]interface{}{"signature": map[string]interface{}{"type": "gpg", "public_key": "key", "policy": "ignore"}}, err: "unknown signature policy 'ignore'"},
+		"invalid value":  {config: map[string]interface{}{"signature": map[string]interface{}{"type": "gpg", "public_key": 1}}, err: "public_key must be a string"},
+	}
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			config, err := NewConfig(tc.config)
+			if tc.err != "" {
+				assert.ErrorContains(t, err, tc.err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expected, config)
+		})
+	}
+}
+
+func TestVerifyCosign(t *testing.T) {
+	key, publicKey := ecdsaKey(t)
+	_, otherPublicKey := ecdsaKey(t)
+	digest := sha256.Sum256(content)
+	rawSignature, err := ecdsa.SignASN1(rand.Reader, key, digest[:])
+	require.NoError(t, err)
+	encodedSignature := base64.StdEncoding.EncodeToString(rawSignature)
+
+	cosignBundle, err := json.Marshal(map[string]interface{}{
+		"base64Signature": encodedSignature,
+		"rekorBundle":     map[string]interface{}{"Payload": map[string]interface{}{"logIndex": 1}},
+	})
+	require.NoError(t, err)
+	sigstoreBundle, err := json.Marshal(map[string]interface{}{
+		"mediaType": "application/vnd.dev.sigstore.bundle+json;version=0.2",
+		"messageSignature": map[string]interface{}{
+			"messageDigest": map[string]interface{}{"algorithm": "SHA2_256", "digest": base64.StdEncoding.EncodeToString(digest[:])},
+			"signature":     encodedSignature,
+		},
+	})
+	require.NoError(t, err)
+	otherDigest := sha256.Sum256([]byte("other"))
+	mismatchingBundle := bytes.Replace(sigstoreBundle, []byte(base64.StdEncoding.EncodeToString(digest[:])), []byte(base64.StdEncoding.EncodeToString(otherDigest[:])), 1)
+
+	der, _ := pem.Decode([]byte(publicKey))
+	keyID := sha256.Sum256(der.Bytes)
+
+	testCases := map[string]struct {
+		signature []byte
+		publicKey string
+		err       string
+	}{
+		"cosign bundle":          {signature: cosignBundle, publicKey: publicKey},
+		"sigstore bundle":        {signature: sigstoreBundle, publicKey: publicKey},
+		"plain signature":        {signature: []byte(encodedSignature + "\n"), publicKey: publicKey},
+		"wrong key":              {signature: cosignBundle, publicKey: otherPublicKey, err: "invalid signature"},
+		"digest mismatch":        {signature: mismatchingBundle, publicKey: publicKey, err: "digest of sigstore bundle does not match"},
+		"missing signature":      {publicKey: publicKey, err: "error fetching signature: 404 Not Found"},
+		"invalid public key":     {signature: cosignBundle, publicKey: "not a key\n", err: "public key is not PEM encoded"},
+		"not a base64 signature": {signature: []byte("###"), publicKey: publicKey, err: "signature is not base64 encoded"},
+	}
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			config := &Config{Type: TypeCosign, PublicKey: tc.publicKey, Signer: "release-team", Suffix: ".bundle", Policy: PolicyEnforce}
+			signature, err := config.Verify(writeApp(t), fetchSignature(tc.signature))
+			if tc.err != "" {
+				assert.ErrorContains(t, err, tc.err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, "cosign", signature.Type)
+			assert.Equal(t, app.SignatureVerified, signature.Status)
+			assert.Equal(t, fmt.Sprintf("sha256:%x", keyID), signature.KeyID)
+			assert.Equal(t, "release-team", signature.Signer)
+		})
+	}
+
+	t.Run("ed25519 key from file", func(t *testing.T) {
+		public, private, err := ed25519.GenerateKey(rand.Reader)
+		require.NoError(t, err)
+		der, err := x509.MarshalPKIXPublicKey(public)
+		require.NoError(t, err)
+		keyFile := filepath.Join(t.TempDir(), "cosign.pub")
+		require.NoError(t, os.WriteFile(keyFile, pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der}), 0644))
+		rawSignature, err := private.Sign(rand.Reader, content, crypto.Hash(0))
+		require.NoError(t, err)
+
+		config := &Config{Type: TypeCosign, PublicKey: keyFile, Suffix: ".sig", Policy: PolicyEnforce}
+		signature, err := config.Verify(writeApp(t), fetchSignature([]byte(base64.StdEncoding.EncodeToString(rawSignature))))
+		require.NoError(t, err)
+		assert.True(t, strings.HasPrefix(signature.KeyID, "sha256:"))
+	})
+}
+
+// minisignKey creates a key pair and returns a function to sign files in the minisign format
+func minisignKey(t *testing.T, id uint64) (string, func(message []byte, prehashed bool, trustedComment string) []byte) {
+	public, private, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+	keyID := make([]byte, 8)
+	binary.LittleEndian.PutUint64(keyID, id)
+	publicKey := "untrusted comment: minisign public key\n" + base64.StdEncoding.EncodeToString(append(append([]byte("Ed"), keyID...), public...)) + "\n"
+
+	sign := func(message []byte, prehashed bool, trustedComment string) []byte {
+		algorithm := "Ed"
+		if prehashed {
+			algorithm = "ED"
+			hash := blake2b.Sum512(message)
+			message = hash[:]
+		}
+		signature := ed25519.Sign(private, message)
+		globalSignature := ed25519.Sign(private, append(append([]byte{}, signature...), []byte(trustedComment)...))
+		return []byte(strings.Join([]string{
+			"untrusted comment: signature from minisign secret key",
+			base64.StdEncoding.EncodeToString(append(append([]byte(algorithm), keyID...), signature...)),
+			trustedCommentPrefix + trustedComment,
+			base64.StdEncoding.EncodeToString(globalSignature),
+		}, "\n") + "\n")
+	}
+	return publicKey, sign
+}
+
+func TestVerifyMinisign(t *testing.T) {
+	publicKey, sign := minisignKey(t, 0x1122334455667788)
+	otherPublicKey, _ := minisignKey(t, 0x99)
+	valid := sign(content, true, "timestamp:1700000000\tfile:app")
+	tamperedComment := bytes.Replace(valid, []byte("file:app"), []byte("file:evil"), 1)
+
+	testCases := map[string]struct {
+		signature []byte
+		publicKey string
+		err       string
+	}{
+		"prehashed signature": {signature: valid, publicKey: publicKey},
+		"legacy signature":    {signature: sign(content, false, "timestamp:1700000000\tfile:app"), publicKey: publicKey},
+		"wrong content":       {signature: sign([]byte("other"), true, "timestamp:1700000000\tfile:app"), publicKey: publicKey, err: "invalid signature"},
+		"tampered comment":    {signature: tamperedComment, publicKey: publicKey, err: "invalid signature of trusted comment"},
+		"other key":           {signature: valid, publicKey: otherPublicKey, err: "signature was created by key 1122334455667788 instead of 0000000000000099"},
+		"invalid file":        {signature: []byte("signature"), publicKey: publicKey, err: "invalid minisign signature file"},
+	}
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			config := &Config{Type: TypeMinisign, PublicKey: tc.publicKey, Suffix: ".minisig", Policy: PolicyEnforce}
+			signature, err := config.Verify(writeApp(t), fetchSignature(tc.signature))
+			if tc.err != "" {
+				assert.ErrorContains(t, err, tc.err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, "minisign", signature.Type)
+			assert.Equal(t, "1122334455667788", signature.KeyID)
+			assert.Equal(t, "timestamp:1700000000\tfile:app", signature.Signer)
+		})
+	}
+}
+
+func TestVerifyGPG(t *testing.T) {
+	entity, err := openpgp.NewEntity("Release Bot", "", "release@example.com", nil)
+	require.NoError(t, err)
+	other, err := openpgp.NewEntity("Someone Else", "", "other@example.com", nil)
+	require.NoError(t, err)
+	armoredKey := func(entity *openpgp.Entity) string {
+		var buffer bytes.Buffer
+		writer, err := armor.Encode(&buffer, "PGP PUBLIC KEY BLOCK", nil)
+		require.NoError(t, err)
+		require.NoError(t, entity.Serialize(writer))
+		require.NoError(t, writer.Close())
+		return buffer.String()
+	}
+	var binaryKey bytes.Buffer
+	require.NoError(t, entity.Serialize(&binaryKey))
+	binaryKeyFile := filepath.Join(t.TempDir(), "key.gpg")
+	require.NoError(t, os.WriteFile(binaryKeyFile, binaryKey.Bytes(), 0644))
+
+	var armoredSignature, binarySignature bytes.Buffer
+	require.NoError(t, openpgp.ArmoredDetachSign(&armoredSignature, entity, bytes.NewReader(content), nil))
+	require.NoError(t, openpgp.DetachSign(&binarySignature, entity, bytes.NewReader(content), nil))
+
+	testCases := map[string]struct {
+		signature []byte
+		publicKey string
+		err       string
+	}{
+		"armored signature":      {signature: armoredSignature.Bytes(), publicKey: armoredKey(entity)},
+		"binary signature":       {signature: binarySignature.Bytes(), publicKey: binaryKeyFile},
+		"signature of other key": {signature: armoredSignature.Bytes(), publicKey: armoredKey(other), err: "invalid signature"},
+	}
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			config := &Config{Type: TypeGPG, PublicKey: tc.publicKey, Suffix: ".asc", Policy: PolicyEnforce}
+			signature, err := config.Verify(writeApp(t), fetchSignature(tc.signature))
+			if tc.err != "" {
+				assert.ErrorContains(t, err, tc.err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, "gpg", signature.Type)
+			assert.Equal(t, fmt.Sprintf("%X", entity.PrimaryKey.Fingerprint), signature.KeyID)
+			assert.Equal(t, "Release Bot <release@example.com>", signature.Signer)
+		})
+	}
+}
+
+func TestVerifyWithWarnPolicy(t *testing.T) {
+	_, publicKey := ecdsaKey(t)
+	config := &Config{Type: TypeCosign, PublicKey: publicKey, Suffix: ".bundle", Policy: PolicyWarn}
+
+	signature, err := config.Verify(writeApp(t), fetchSignature(nil))
+
+	assert.NoError(t, err)
+	assert.Equal(t, &app.Signature{Type: TypeCosign, Status: app.SignatureFailed}, signature)
+}
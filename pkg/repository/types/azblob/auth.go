@@ -5,6 +5,8 @@ import (
 	"fmt"
 
 	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/cloud"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/policy"
 	"github.com/Azure/azure-sdk-for-go/sdk/azidentity"
 )
 
@@ -14,6 +16,9 @@ const (
 	ClientSecretAuthType          AuthType = "client_secret"
 	OnBehalfOfAuthType            AuthType = "on_behalf_of"
 	SharedAccessSignatureAuthType AuthType = "storage_account_signature"
+	ManagedIdentityAuthType       AuthType = "managed_identity"
+	WorkloadIdentityAuthType      AuthType = "workload_identity"
+	DefaultAzureCredentialType    AuthType = "default"
 )
 
 type Auth struct {
@@ -30,12 +35,16 @@ type AuthConfig interface {
 	StorageAccountSignature() (string, error)
 }
 
-var supportedAuthTypes = []AuthType{ClientSecretAuthType, OnBehalfOfAuthType, SharedAccessSignatureAuthType}
+var supportedAuthTypes = []AuthType{ClientSecretAuthType, OnBehalfOfAuthType, SharedAccessSignatureAuthType, ManagedIdentityAuthType, WorkloadIdentityAuthType, DefaultAzureCredentialType}
 
 const clientIDKey = "client_id"
 const clientSecretKey = "client_secret"
 const tenantIDKey = "tenant_id"
 const sasKey = "signature"
+const userAssertionKey = "user_assertion"
+const resourceIDKey = "resource_id"
+const tokenFileKey = "token_file"
+const authorityHostKey = "authority_host"
 
 type AuthFactory struct {
 	toAuthConfig map[AuthType]func(map[string]interface{}) (AuthConfig, error)
@@ -44,12 +53,21 @@ type AuthFactory struct {
 func newAuthFactory() *AuthFactory {
 	toAuthConfig := make(map[AuthType]func(map[string]interface{}) (AuthConfig, error))
 	toAuthConfig[ClientSecretAuthType] = newClientSecretAuth
+	toAuthConfig[OnBehalfOfAuthType] = newOnBehalfOfAuth
 	toAuthConfig[SharedAccessSignatureAuthType] = newSharedAccessSignatureAuth
+	toAuthConfig[ManagedIdentityAuthType] = newManagedIdentityAuth
+	toAuthConfig[WorkloadIdentityAuthType] = newWorkloadIdentityAuth
+	toAuthConfig[DefaultAzureCredentialType] = newDefaultAzureCredentialAuth
 	return &AuthFactory{
 		toAuthConfig: toAuthConfig,
 	}
 }
 
+// NewAuth creates the auth of the config, it allows other packages to reuse the auth types of the repository
+func NewAuth(config map[string]interface{}) (*Auth, error) {
+	return newAuthFactory().newAuth(config)
+}
+
 // newAuth creates a new Auth object based on the given config
 func (f *AuthFactory) newAuth(config map[string]interface{}) (*Auth, error) {
 	authType, ok := config["type"].(string)
@@ -152,3 +170,211 @@ func (c SharedAccessSignatureAuth) Token(ctx context.Context) (azcore.TokenCrede
 func (c SharedAccessSignatureAuth) StorageAccountSignature() (string, error) {
 	return c.storageAccountSignature, nil
 }
+
+// OnBehalfOfAuth exchanges the user assertion (an access token issued for the client) for a storage token
+type OnBehalfOfAuth struct {
+	ClientID      string
+	ClientSecret  string
+	TenantID      string
+	UserAssertion string
+	AuthorityHost string
+	transport     policy.Transporter
+}
+
+func newOnBehalfOfAuth(config map[string]interface{}) (AuthConfig, error) {
+	clientID, err := requiredString(config, clientIDKey, "on behalf of")
+	if err != nil {
+		return OnBehalfOfAuth{}, err
+	}
+	clientSecret, err := requiredString(config, clientSecretKey, "on behalf of")
+	if err != nil {
+		return OnBehalfOfAuth{}, err
+	}
+	tenantID, err := requiredString(config, tenantIDKey, "on behalf of")
+	if err != nil {
+		return OnBehalfOfAuth{}, err
+	}
+	userAssertion, err := requiredString(config, userAssertionKey, "on behalf of")
+	if err != nil {
+		return OnBehalfOfAuth{}, err
+	}
+	authorityHost, err := optionalString(config, authorityHostKey)
+	if err != nil {
+		return OnBehalfOfAuth{}, err
+	}
+	return OnBehalfOfAuth{
+		ClientID:      clientID,
+		ClientSecret:  clientSecret,
+		TenantID:      tenantID,
+		UserAssertion: userAssertion,
+		AuthorityHost: authorityHost,
+	}, nil
+}
+
+func (c OnBehalfOfAuth) Token(ctx context.Context) (azcore.TokenCredential, error) {
+	return azidentity.NewOnBehalfOfCredentialWithSecret(c.TenantID, c.ClientID, c.UserAssertion, c.ClientSecret, &azidentity.OnBehalfOfCredentialOptions{
+		ClientOptions:            clientOptions(c.AuthorityHost, c.transport),
+		DisableInstanceDiscovery: c.AuthorityHost != "",
+	})
+}
+
+func (c OnBehalfOfAuth) StorageAccountSignature() (string, error) {
+	return "", fmt.Errorf("StorageAccountSignature is not supported for on behalf of auth")
+}
+
+// ManagedIdentityAuth uses the managed identity of the host (VM, App Service, Arc, ...).
+// Without client_id or resource_id the system assigned identity is used.
+type ManagedIdentityAuth struct {
+	ClientID   string
+	ResourceID string
+}
+
+func newManagedIdentityAuth(config map[string]interface{}) (AuthConfig, error) {
+	clientID, err := optionalString(config, clientIDKey)
+	if err != nil {
+		return ManagedIdentityAuth{}, err
+	}
+	resourceID, err := optionalString(config, resourceIDKey)
+	if err != nil {
+		return ManagedIdentityAuth{}, err
+	}
+	if clientID != "" && resourceID != "" {
+		return ManagedIdentityAuth{}, fmt.Errorf("only one of '%s' and '%s' can be set in managed identity auth config", clientIDKey, resourceIDKey)
+	}
+	return ManagedIdentityAuth{
+		ClientID:   clientID,
+		ResourceID: resourceID,
+	}, nil
+}
+
+func (c ManagedIdentityAuth) Token(ctx context.Context) (azcore.TokenCredential, error) {
+	options := &azidentity.ManagedIdentityCredentialOptions{}
+	if c.ClientID != "" {
+		options.ID = azidentity.ClientID(c.ClientID)
+	}
+	if c.ResourceID != "" {
+		options.ID = azidentity.ResourceID(c.ResourceID)
+	}
+	return azidentity.NewManagedIdentityCredential(options)
+}
+
+func (c ManagedIdentityAuth) StorageAccountSignature() (string, error) {
+	return "", fmt.Errorf("StorageAccountSignature is not supported for managed identity auth")
+}
+
+// WorkloadIdentityAuth exchanges a federated service account token for a storage token.
+// Unset values default to AZURE_TENANT_ID, AZURE_CLIENT_ID and AZURE_FEDERATED_TOKEN_FILE
+// which are injected by the AKS workload identity webhook.
+type WorkloadIdentityAuth struct {
+	ClientID      string
+	TenantID      string
+	TokenFile     string
+	AuthorityHost string
+	transport     policy.Transporter
+}
+
+func newWorkloadIdentityAuth(config map[string]interface{}) (AuthConfig, error) {
+	clientID, err := optionalString(config, clientIDKey)
+	if err != nil {
+		return WorkloadIdentityAuth{}, err
+	}
+	tenantID, err := optionalString(config, tenantIDKey)
+	if err != nil {
+		return WorkloadIdentityAuth{}, err
+	}
+	tokenFile, err := optionalString(config, tokenFileKey)
+	if err != nil {
+		return WorkloadIdentityAuth{}, err
+	}
+	authorityHost, err := optionalString(config, authorityHostKey)
+	if err != nil {
+		return WorkloadIdentityAuth{}, err
+	}
+	return WorkloadIdentityAuth{
+		ClientID:      clientID,
+		TenantID:      tenantID,
+		TokenFile:     tokenFile,
+		AuthorityHost: authorityHost,
+	}, nil
+}
+
+func (c WorkloadIdentityAuth) Token(ctx context.Context) (azcore.TokenCredential, error) {
+	return azidentity.NewWorkloadIdentityCredential(&azidentity.WorkloadIdentityCredentialOptions{
+		ClientOptions:            clientOptions(c.AuthorityHost, c.transport),
+		ClientID:                 c.ClientID,
+		TenantID:                 c.TenantID,
+		TokenFilePath:            c.TokenFile,
+		DisableInstanceDiscovery: c.AuthorityHost != "",
+	})
+}
+
+func (c WorkloadIdentityAuth) StorageAccountSignature() (string, error) {
+	return "", fmt.Errorf("StorageAccountSignature is not supported for workload identity auth")
+}
+
+// DefaultAzureCredentialAuth tries environment, workload identity, managed identity and the
+// Azure CLI credentials in this order
+type DefaultAzureCredentialAuth struct {
+	TenantID      string
+	AuthorityHost string
+	transport     policy.Transporter
+}
+
+func newDefaultAzureCredentialAuth(config map[string]interface{}) (AuthConfig, error) {
+	tenantID, err := optionalString(config, tenantIDKey)
+	if err != nil {
+		return DefaultAzureCredentialAuth{}, err
+	}
+	authorityHost, err := optionalString(config, authorityHostKey)
+	if err != nil {
+		return DefaultAzureCredentialAuth{}, err
+	}
+	return DefaultAzureCredentialAuth{
+		TenantID:      tenantID,
+		AuthorityHost: authorityHost,
+	}, nil
+}
+
+func (c DefaultAzureCredentialAuth) Token(ctx context.Context) (azcore.TokenCredential, error) {
+	return azidentity.NewDefaultAzureCredential(&azidentity.DefaultAzureCredentialOptions{
+		ClientOptions:            clientOptions(c.AuthorityHost, c.transport),
+		TenantID:                 c.TenantID,
+		DisableInstanceDiscovery: c.AuthorityHost != "",
+	})
+}
+
+func (c DefaultAzureCredentialAuth) StorageAccountSignature() (string, error) {
+	return "", fmt.Errorf("StorageAccountSignature is not supported for default auth")
+}
+
+// clientOptions points the credential to another Microsoft Entra authority (e.g. a sovereign or private cloud) if configured.
+// Instance discovery is disabled for configured authorities, so they are trusted as is.
+func clientOptions(authorityHost string, transport policy.Transporter) azcore.ClientOptions {
+	options := azcore.ClientOptions{Transport: transport}
+	if authorityHost != "" {
+		options.Cloud = cloud.Configuration{ActiveDirectoryAuthorityHost: authorityHost}
+	}
+	return options
+}
+
+func requiredString(config map[string]interface{}, key, authName string) (string, error) {
+	value, err := optionalString(config, key)
+	if err != nil {
+		return "", err
+	}
+	if value == "" {
+		return "", fmt.Errorf("missing '%s' in %s auth config", key, authName)
+	}
+	return value, nil
+}
+
+func optionalString(config map[string]interface{}, key string) (string, error) {
+	if config[key] == nil {
+		return "", nil
+	}
+	value, ok := config[key].(string)
+	if !ok {
+		return "", fmt.Errorf("%s must be a string", key)
+	}
+	return value, nil
+}
@@ -0,0 +1,173 @@
+//go:build integration
+// +build integration
+
+package azblob
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/policy"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var storageScope = policy.TokenRequestOptions{Scopes: []string{"https://storage.azure.com/.default"}}
+
+// fakeTokenServer imitates the Microsoft Entra endpoints and records the token requests
+type fakeTokenServer struct {
+	*httptest.Server
+	mutex    sync.Mutex
+	requests []map[string]string
+}
+
+func newFakeTokenServer(t *testing.T) *fakeTokenServer {
+	fake := &fakeTokenServer{}
+	fake.Server = httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		tenant := strings.Split(strings.TrimPrefix(r.URL.Path, "/"), "/")[0]
+		switch {
+		case strings.HasSuffix(r.URL.Path, "/.well-known/openid-configuration"):
+			json.NewEncoder(w).Encode(map[string]interface{}{
+				"token_endpoint":         fake.URL + "/" + tenant + "/oauth2/v2.0/token",
+				"authorization_endpoint": fake.URL + "/" + tenant + "/oauth2/v2.0/authorize",
+				"issuer":                 fake.URL + "/" + tenant + "/v2.0",
+			})
+		case strings.HasSuffix(r.URL.Path, "/oauth2/v2.0/token"):
+			require.NoError(t, r.ParseForm())
+			request := map[string]string{"tenant": tenant}
+			for key := range r.PostForm {
+				request[key] = r.PostForm.Get(key)
+			}
+			fake.mutex.Lock()
+			fake.requests = append(fake.requests, request)
+			fake.mutex.Unlock()
+			json.NewEncoder(w).Encode(map[string]interface{}{
+				"access_token": "fakeToken",
+				"token_type":   "Bearer",
+				"expires_in":   3600,
+			})
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	t.Cleanup(fake.Close)
+	return fake
+}
+
+func (f *fakeTokenServer) lastRequest() map[string]string {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	if len(f.requests) == 0 {
+		return nil
+	}
+	return f.requests[len(f.requests)-1]
+}
+
+func TestOnBehalfOfAuthToken(t *testing.T) {
+	server := newFakeTokenServer(t)
+	auth := OnBehalfOfAuth{
+		ClientID:      "testClientID",
+		ClientSecret:  "testClientSecret",
+		TenantID:      "testTenantID",
+		UserAssertion: "testAssertion",
+		AuthorityHost: server.URL,
+		transport:     server.Client(),
+	}
+
+	credential, err := auth.Token(context.Background())
+	require.NoError(t, err)
+	token, err := credential.GetToken(context.Background(), storageScope)
+	require.NoError(t, err)
+
+	assert.Equal(t, "fakeToken", token.Token)
+	request := server.lastRequest()
+	assert.Equal(t, "testtenantid", request["tenant"])
+	assert.Equal(t, "urn:ietf:params:oauth:grant-type:jwt-bearer", request["grant_type"])
+	assert.Equal(t, "testAssertion", request["assertion"])
+	assert.Equal(t, "testClientID", request["client_id"])
+	assert.Equal(t, "testClientSecret", request["client_secret"])
+}
+
+func TestWorkloadIdentityAuthToken(t *testing.T) {
+	server := newFakeTokenServer(t)
+	tokenFile := filepath.Join(t.TempDir(), "token")
+	require.NoError(t, os.WriteFile(tokenFile, []byte("federatedToken"), 0600))
+	auth := WorkloadIdentityAuth{
+		ClientID:      "testClientID",
+		TenantID:      "testTenantID",
+		TokenFile:     tokenFile,
+		AuthorityHost: server.URL,
+		transport:     server.Client(),
+	}
+
+	credential, err := auth.Token(context.Background())
+	require.NoError(t, err)
+	token, err := credential.GetToken(context.Background(), storageScope)
+	require.NoError(t, err)
+
+	assert.Equal(t, "fakeToken", token.Token)
+	request := server.lastRequest()
+	assert.Equal(t, "client_credentials", request["grant_type"])
+	assert.Equal(t, "federatedToken", request["client_assertion"])
+	assert.Equal(t, "testClientID", request["client_id"])
+}
+
+func TestDefaultAzureCredentialAuthToken(t *testing.T) {
+	server := newFakeTokenServer(t)
+	t.Setenv("AZURE_TENANT_ID", "testTenantID")
+	t.Setenv("AZURE_CLIENT_ID", "testClientID")
+	t.Setenv("AZURE_CLIENT_SECRET", "testClientSecret")
+	auth := DefaultAzureCredentialAuth{
+		AuthorityHost: server.URL,
+		transport:     server.Client(),
+	}
+
+	credential, err := auth.Token(context.Background())
+	require.NoError(t, err)
+	token, err := credential.GetToken(context.Background(), storageScope)
+	require.NoError(t, err)
+
+	assert.Equal(t, "fakeToken", token.Token)
+	request := server.lastRequest()
+	assert.Equal(t, "client_credentials", request["grant_type"])
+	assert.Equal(t, "testClientSecret", request["client_secret"])
+}
+
+func TestManagedIdentityAuthToken(t *testing.T) {
+	var query map[string]string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "testIdentityHeader", r.Header.Get("X-IDENTITY-HEADER"))
+		query = map[string]string{}
+		for key := range r.URL.Query() {
+			query[key] = r.URL.Query().Get(key)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"access_token": "fakeManagedToken",
+			"token_type":   "Bearer",
+			"expires_on":   "4102444800",
+			"resource":     "https://storage.azure.com",
+		})
+	}))
+	defer server.Close()
+	// imitate the managed identity endpoint of Azure App Service
+	t.Setenv("IDENTITY_ENDPOINT", server.URL)
+	t.Setenv("IDENTITY_HEADER", "testIdentityHeader")
+
+	credential, err := ManagedIdentityAuth{ClientID: "testClientID"}.Token(context.Background())
+	require.NoError(t, err)
+	token, err := credential.GetToken(context.Background(), storageScope)
+	require.NoError(t, err)
+
+	assert.Equal(t, "fakeManagedToken", token.Token)
+	assert.Equal(t, "https://storage.azure.com", query["resource"])
+	assert.Equal(t, "testClientID", query["client_id"])
+}
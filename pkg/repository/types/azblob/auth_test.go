@@ -153,6 +153,119 @@ func TestAuth(t *testing.T) {
 		})
 	})
 
+	t.Run("On Behalf Of", func(t *testing.T) {
+		t.Run("Valid", func(t *testing.T) {
+			config := map[string]interface{}{
+				"type":           "on_behalf_of",
+				"client_id":      "testClientID",
+				"client_secret":  "testClientSecret",
+				"tenant_id":      "testTenantID",
+				"user_assertion": "testAssertion",
+			}
+			auth, err := authFactory.newAuth(config)
+
+			assert.NoError(t, err)
+			assert.Equal(t, OnBehalfOfAuthType, auth.Type)
+			assert.Equal(t, "testAssertion", auth.Config.(OnBehalfOfAuth).UserAssertion)
+
+			token, err := auth.Config.Token(context.Background())
+			assert.NoError(t, err)
+			assert.NotNil(t, token)
+
+			_, err = auth.Config.StorageAccountSignature()
+			assert.Error(t, err)
+		})
+
+		t.Run("Missing user_assertion", func(t *testing.T) {
+			config := map[string]interface{}{
+				"type":          "on_behalf_of",
+				"client_id":     "testClientID",
+				"client_secret": "testClientSecret",
+				"tenant_id":     "testTenantID",
+			}
+			_, err := authFactory.newAuth(config)
+
+			assert.EqualError(t, err, "error creating auth: missing 'user_assertion' in on behalf of auth config")
+		})
+
+		t.Run("Invalid client_id", func(t *testing.T) {
+			config := map[string]interface{}{
+				"type":      "on_behalf_of",
+				"client_id": 42,
+			}
+			_, err := authFactory.newAuth(config)
+
+			assert.EqualError(t, err, "error creating auth: client_id must be a string")
+		})
+	})
+
+	t.Run("Managed Identity", func(t *testing.T) {
+		t.Run("System assigned", func(t *testing.T) {
+			auth, err := authFactory.newAuth(map[string]interface{}{"type": "managed_identity"})
+
+			assert.NoError(t, err)
+			assert.Equal(t, ManagedIdentityAuthType, auth.Type)
+			token, err := auth.Config.Token(context.Background())
+			assert.NoError(t, err)
+			assert.NotNil(t, token)
+		})
+
+		t.Run("User assigned", func(t *testing.T) {
+			auth, err := authFactory.newAuth(map[string]interface{}{"type": "managed_identity", "client_id": "testClientID"})
+
+			assert.NoError(t, err)
+			assert.Equal(t, "testClientID", auth.Config.(ManagedIdentityAuth).ClientID)
+		})
+
+		t.Run("Client and resource id", func(t *testing.T) {
+			_, err := authFactory.newAuth(map[string]interface{}{
+				"type":        "managed_identity",
+				"client_id":   "testClientID",
+				"resource_id": "testResourceID",
+			})
+
+			assert.EqualError(t, err, "error creating auth: only one of 'client_id' and 'resource_id' can be set in managed identity auth config")
+		})
+	})
+
+	t.Run("Workload Identity", func(t *testing.T) {
+		t.Run("Valid", func(t *testing.T) {
+			auth, err := authFactory.newAuth(map[string]interface{}{
+				"type":       "workload_identity",
+				"client_id":  "testClientID",
+				"tenant_id":  "testTenantID",
+				"token_file": "/var/run/secrets/azure/tokens/azure-identity-token",
+			})
+
+			assert.NoError(t, err)
+			assert.Equal(t, WorkloadIdentityAuthType, auth.Type)
+			assert.Equal(t, WorkloadIdentityAuth{
+				ClientID:  "testClientID",
+				TenantID:  "testTenantID",
+				TokenFile: "/var/run/secrets/azure/tokens/azure-identity-token",
+			}, auth.Config)
+		})
+
+		t.Run("Missing environment", func(t *testing.T) {
+			t.Setenv("AZURE_CLIENT_ID", "")
+			t.Setenv("AZURE_TENANT_ID", "")
+			t.Setenv("AZURE_FEDERATED_TOKEN_FILE", "")
+			auth, err := authFactory.newAuth(map[string]interface{}{"type": "workload_identity"})
+			assert.NoError(t, err)
+
+			_, err = auth.Config.Token(context.Background())
+			assert.Error(t, err)
+		})
+	})
+
+	t.Run("Default Azure Credential", func(t *testing.T) {
+		auth, err := authFactory.newAuth(map[string]interface{}{"type": "default", "tenant_id": "testTenantID"})
+
+		assert.NoError(t, err)
+		assert.Equal(t, DefaultAzureCredentialType, auth.Type)
+		assert.Equal(t, "testTenantID", auth.Config.(DefaultAzureCredentialAuth).TenantID)
+	})
+
 	t.Run("Unknown type", func(t *testing.T) {
 		config := map[string]interface{}{
 			"type": "unknown",
@@ -160,6 +273,6 @@ func TestAuth(t *testing.T) {
 		_, err := authFactory.newAuth(config)
 
 		assert.Error(t, err)
-		assert.Equal(t, "auth type unknown is not supported, supported auth types are [client_secret on_behalf_of storage_account_signature]", err.Error())
+		assert.Equal(t, "auth type unknown is not supported, supported auth types are [client_secret on_behalf_of storage_account_signature managed_identity workload_identity default]", err.Error())
 	})
 }
@@ -4,6 +4,8 @@ import (
 	"fmt"
 
 	"github.com/B-S-F/onyx/pkg/repository"
+	"github.com/B-S-F/onyx/pkg/repository/app"
+	"github.com/B-S-F/onyx/pkg/repository/signature"
 )
 
 const StorageAccountNameKey = "storage_account_name"
@@ -15,6 +17,10 @@ type Config struct {
 	StorageAccountContainer string
 	StorageAccountPath      string
 	Auth                    *Auth
+	// Archive configuration, if set the apps are archives which are extracted after download
+	Archive *app.ArchiveConfig
+	// Signature configuration, if set the signatures of the apps are verified after download
+	Signature *signature.Config
 }
 
 func (c *Config) Type() string {
@@ -35,6 +41,14 @@ func newConfig(config map[string]interface{}) (repository.Config, error) {
 	if config["auth"] == nil {
 		return nil, fmt.Errorf("missing 'auth' in config")
 	}
+	archive, err := app.NewArchiveConfig(config)
+	if err != nil {
+		return nil, fmt.Errorf("error creating archive config: %w", err)
+	}
+	signatureConfig, err := signature.NewConfig(config)
+	if err != nil {
+		return nil, fmt.Errorf("error creating signature config: %w", err)
+	}
 	auth, err := authFactory.newAuth(config["auth"].(map[string]interface{}))
 	if err != nil {
 		return nil, fmt.Errorf("error creating auth: %w", err)
@@ -44,5 +58,7 @@ func newConfig(config map[string]interface{}) (repository.Config, error) {
 		StorageAccountContainer: config[StorageAccountContainerKey].(string),
 		StorageAccountPath:      config[StorageAccountPathKey].(string),
 		Auth:                    auth,
+		Archive:                 archive,
+		Signature:               signatureConfig,
 	}, nil
 }
@@ -9,6 +9,7 @@ import (
 
 	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
 	"github.com/Azure/azure-sdk-for-go/sdk/storage/azblob"
+	"github.com/Azure/azure-sdk-for-go/sdk/storage/azblob/bloberror"
 	"github.com/B-S-F/onyx/pkg/repository"
 	"github.com/B-S-F/onyx/pkg/repository/app"
 )
@@ -73,12 +74,29 @@ func (r *Repository) InstallApp(appReference *app.Reference) (app.App, error) {
 		return nil, fmt.Errorf("failed to install app %s: %w", appReference, err)
 	}
 
+	provenance := app.Provenance{Source: r.serviceUrl() + "/" + r.Config.StorageAccountContainer + "/" + strings.TrimPrefix(appPath, "/")}
+	if r.Config.Signature != nil {
+		provenance.Signature, err = r.Config.Signature.Verify(ouputPath, func(signaturePath string) error {
+			return r.downloadFile(appPath+r.Config.Signature.Suffix, signaturePath)
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to install app %s: %w", appReference, err)
+		}
+	}
+
 	checksum, err := app.CalculateFileChecksum(ouputPath)
 	if err != nil {
 		return nil, fmt.Errorf("failed to install app %s: %w", appReference, err)
 	}
 
-	return app.NewBinaryApp(r.RepoName, appReference.Name, appReference.Version, checksum, ouputPath), nil
+	if r.Config.Archive != nil {
+		installed, err := app.NewArchiveApp(r.RepoName, appReference, checksum, ouputPath, r.Config.Archive)
+		if err != nil {
+			return nil, fmt.Errorf("failed to install app %s: %w", appReference, err)
+		}
+		return app.WithProvenance(installed, provenance), nil
+	}
+	return app.WithProvenance(app.NewBinaryApp(r.RepoName, appReference.Name, appReference.Version, checksum, ouputPath), provenance), nil
 }
 
 // Download the file from the azure blob storage, save it in the outputPath and make it executable
@@ -109,14 +127,33 @@ func (r *Repository) downloadFile(appPath, outputPath string) error {
 
 // Initialize the azure blob storage client
 func (r *Repository) initClient() (*azblob.Client, error) {
-	serviceUrl := r.serviceUrl()
-	if r.Token == nil && r.StorageAccountSignature == "" {
+	return newClient(r.serviceUrl(), r.Token, r.StorageAccountSignature)
+}
+
+// NewClient creates a client of the storage account which authenticates with the token or the signature of the auth
+func NewClient(storageAccountName string, auth *Auth) (*azblob.Client, error) {
+	if auth.Type == SharedAccessSignatureAuthType {
+		storageAccountSignature, err := auth.Config.StorageAccountSignature()
+		if err != nil {
+			return nil, fmt.Errorf("failed to get storage account signature: %w", err)
+		}
+		return newClient(ServiceURL(storageAccountName), nil, storageAccountSignature)
+	}
+	token, err := auth.Config.Token(context.Background())
+	if err != nil {
+		return nil, fmt.Errorf("failed to get token: %w", err)
+	}
+	return newClient(ServiceURL(storageAccountName), token, "")
+}
+
+func newClient(serviceUrl string, token azcore.TokenCredential, storageAccountSignature string) (*azblob.Client, error) {
+	if token == nil && storageAccountSignature == "" {
 		return nil, fmt.Errorf("no authorization options provided")
 	}
-	if r.Token != nil {
-		return azblob.NewClient(serviceUrl, r.Token, nil)
+	if token != nil {
+		return azblob.NewClient(serviceUrl, token, nil)
 	}
-	connectionString := fmt.Sprintf("BlobEndpoint=%s;SharedAccessSignature=%s", serviceUrl, r.StorageAccountSignature)
+	connectionString := fmt.Sprintf("BlobEndpoint=%s;SharedAccessSignature=%s", serviceUrl, storageAccountSignature)
 	return azblob.NewClientFromConnectionString(connectionString, nil)
 }
 
@@ -138,7 +175,33 @@ func (r *Repository) getAppPath(appName, appVersion string) (string, error) {
 }
 
 func (r *Repository) serviceUrl() string {
-	return fmt.Sprintf("https://%s.blob.core.windows.net", r.Config.StorageAccountName)
+	return ServiceURL(r.Config.StorageAccountName)
+}
+
+// ServiceURL returns the blob endpoint of the storage account
+func ServiceURL(storageAccountName string) string {
+	return fmt.Sprintf("https://%s.blob.core.windows.net", storageAccountName)
+}
+
+// HasApp checks if the blob of the app exists without downloading it
+func (r *Repository) HasApp(appReference *app.Reference) (bool, error) {
+	appPath, err := r.getAppPath(appReference.Name, appReference.Version)
+	if err != nil {
+		return false, err
+	}
+	client, err := r.initClient()
+	if err != nil {
+		return false, fmt.Errorf("failed to create blob client: %w", err)
+	}
+	blobClient := client.ServiceClient().NewContainerClient(r.Config.StorageAccountContainer).NewBlobClient(appPath)
+	_, err = blobClient.GetProperties(context.Background(), nil)
+	if bloberror.HasCode(err, bloberror.BlobNotFound, bloberror.ContainerNotFound) {
+		return false, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("failed to check blob: %w", err)
+	}
+	return true, nil
 }
 
 func (r *Repository) Name() string {
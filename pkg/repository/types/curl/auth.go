@@ -2,14 +2,22 @@ package curl
 
 import (
 	"encoding/base64"
+	"encoding/json"
 	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
 )
 
 type AuthType string
 
 const (
-	BasicAuthType AuthType = "basic"
-	TokenAuthType AuthType = "token"
+	BasicAuthType  AuthType = "basic"
+	TokenAuthType  AuthType = "token"
+	OAuth2AuthType AuthType = "oauth2"
 )
 
 type Auth struct {
@@ -20,11 +28,17 @@ type Auth struct {
 }
 
 type AuthConfig interface {
-	// Get the authentication header
-	Header() string
+	// Get the authentication header, the client is used if a token has to be requested first
+	Header(client *http.Client) (string, error)
 }
 
-var supportedAuthTypes = []AuthType{BasicAuthType, TokenAuthType}
+// cachedAuth is implemented by auth configs which cache credentials that can be revoked by the server
+type cachedAuth interface {
+	// Invalidate drops the cached credentials, so they are requested again on the next call to Header
+	Invalidate()
+}
+
+var supportedAuthTypes = []AuthType{BasicAuthType, TokenAuthType, OAuth2AuthType}
 
 type AuthFactory struct {
 	toAuthConfig map[AuthType]func(map[string]interface{}) (AuthConfig, error)
@@ -34,11 +48,17 @@ func newAuthFactory() *AuthFactory {
 	toAuthConfig := make(map[AuthType]func(map[string]interface{}) (AuthConfig, error))
 	toAuthConfig[BasicAuthType] = newBasicAuth
 	toAuthConfig[TokenAuthType] = newTokenAuth
+	toAuthConfig[OAuth2AuthType] = newOAuth2Auth
 	return &AuthFactory{
 		toAuthConfig: toAuthConfig,
 	}
 }
 
+// NewAuth creates the auth of the config, it allows other packages to reuse the auth types of the repository
+func NewAuth(config map[string]interface{}) (*Auth, error) {
+	return newAuthFactory().newAuth(config)
+}
+
 // newAuth creates a new Auth object based on the given config
 func (f *AuthFactory) newAuth(config map[string]interface{}) (*Auth, error) {
 	authType, ok := config["type"].(string)
@@ -85,9 +105,9 @@ func newBasicAuth(config map[string]interface{}) (AuthConfig, error) {
 	}, nil
 }
 
-func (b BasicAuth) Header() string {
+func (b BasicAuth) Header(client *http.Client) (string, error) {
 	base64Encoded := base64.StdEncoding.EncodeToString([]byte(b.Username + ":" + b.Password))
-	return "Basic " + base64Encoded
+	return "Basic " + base64Encoded, nil
 }
 
 type TokenAuth struct {
@@ -107,6 +127,146 @@ func newTokenAuth(config map[string]interface{}) (AuthConfig, error) {
 	}, nil
 }
 
-func (t TokenAuth) Header() string {
-	return "Bearer " + t.Token
+func (t TokenAuth) Header(client *http.Client) (string, error) {
+	return "Bearer " + t.Token, nil
+}
+
+// tokenExpiryMargin is subtracted from the lifetime of a token to refresh it before it expires during a download
+const tokenExpiryMargin = 30 * time.Second
+
+// OAuth2Auth requests a token with the client credentials grant and caches it until shortly before it expires
+type OAuth2Auth struct {
+	TokenURL     string
+	ClientID     string
+	ClientSecret string
+	Scopes       []string
+	Audience     string
+
+	mutex  sync.Mutex
+	token  string
+	expiry time.Time
+}
+
+type tokenResponse struct {
+	AccessToken string `json:"access_token"`
+	TokenType   string `json:"token_type"`
+	ExpiresIn   int64  `json:"expires_in"`
+}
+
+func newOAuth2Auth(config map[string]interface{}) (AuthConfig, error) {
+	for _, key := range []string{"token_url", "client_id", "client_secret"} {
+		if config[key] == nil {
+			return nil, fmt.Errorf("missing '%s' in oauth2 auth config", key)
+		}
+	}
+	tokenURL, ok := config["token_url"].(string)
+	if !ok {
+		return nil, fmt.Errorf("token_url must be a string")
+	}
+	if _, err := url.ParseRequestURI(tokenURL); err != nil {
+		return nil, fmt.Errorf("token_url must be a valid url: %w", err)
+	}
+	clientID, ok := config["client_id"].(string)
+	if !ok {
+		return nil, fmt.Errorf("client_id must be a string")
+	}
+	clientSecret, ok := config["client_secret"].(string)
+	if !ok {
+		return nil, fmt.Errorf("client_secret must be a string")
+	}
+	var scopes []string
+	switch value := config["scopes"].(type) {
+	case nil:
+	case string:
+		scopes = strings.Fields(value)
+	case []interface{}:
+		for _, scope := range value {
+			scopeString, ok := scope.(string)
+			if !ok {
+				return nil, fmt.Errorf("scopes must be a list of strings")
+			}
+			scopes = append(scopes, scopeString)
+		}
+	default:
+		return nil, fmt.Errorf("scopes must be a list of strings")
+	}
+	var audience string
+	if config["audience"] != nil {
+		audience, ok = config["audience"].(string)
+		if !ok {
+			return nil, fmt.Errorf("audience must be a string")
+		}
+	}
+	return &OAuth2Auth{
+		TokenURL:     tokenURL,
+		ClientID:     clientID,
+		ClientSecret: clientSecret,
+		Scopes:       scopes,
+		Audience:     audience,
+	}, nil
+}
+
+func (o *OAuth2Auth) Header(client *http.Client) (string, error) {
+	o.mutex.Lock()
+	defer o.mutex.Unlock()
+	if o.token == "" || (!o.expiry.IsZero() && time.Now().After(o.expiry)) {
+		err := o.requestToken(client)
+		if err != nil {
+			return "", err
+		}
+	}
+	return "Bearer " + o.token, nil
+}
+
+func (o *OAuth2Auth) Invalidate() {
+	o.mutex.Lock()
+	defer o.mutex.Unlock()
+	o.token = ""
+	o.expiry = time.Time{}
+}
+
+// requestToken fetches a new token, the client credentials are sent using http basic authentication
+func (o *OAuth2Auth) requestToken(client *http.Client) error {
+	form := url.Values{}
+	form.Set("grant_type", "client_credentials")
+	if len(o.Scopes) > 0 {
+		form.Set("scope", strings.Join(o.Scopes, " "))
+	}
+	if o.Audience != "" {
+		form.Set("audience", o.Audience)
+	}
+	request, err := http.NewRequest(http.MethodPost, o.TokenURL, strings.NewReader(form.Encode()))
+	if err != nil {
+		return fmt.Errorf("error creating token request: %w", err)
+	}
+	request.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	request.Header.Set("Accept", "application/json")
+	request.SetBasicAuth(url.QueryEscape(o.ClientID), url.QueryEscape(o.ClientSecret))
+
+	response, err := client.Do(request)
+	if err != nil {
+		return fmt.Errorf("error requesting token: %w", err)
+	}
+	defer response.Body.Close()
+	body, err := io.ReadAll(io.LimitReader(response.Body, 1<<20))
+	if err != nil {
+		return fmt.Errorf("error reading token response: %w", err)
+	}
+	if response.StatusCode != http.StatusOK {
+		return fmt.Errorf("error requesting token: %s", response.Status)
+	}
+	var token tokenResponse
+	err = json.Unmarshal(body, &token)
+	if err != nil {
+		return fmt.Errorf("error parsing token response: %w", err)
+	}
+	if token.AccessToken == "" {
+		return fmt.Errorf("token response does not contain an access_token")
+	}
+	o.token = token.AccessToken
+	o.expiry = time.Time{}
+	if token.ExpiresIn > 0 {
+		o.expiry = time.Now().Add(time.Duration(token.ExpiresIn)*time.Second - tokenExpiryMargin)
+	}
+	return nil
 }
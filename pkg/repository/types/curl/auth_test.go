@@ -19,7 +19,9 @@ func TestAuth(t *testing.T) {
 
 		assert.NoError(t, err)
 		assert.Equal(t, BasicAuthType, auth.Type)
-		assert.Equal(t, "Basic dGVzdFVzZXI6dGVzdFBhc3M=", auth.Config.Header())
+		header, err := auth.Config.Header(nil)
+		assert.NoError(t, err)
+		assert.Equal(t, "Basic dGVzdFVzZXI6dGVzdFBhc3M=", header)
 		assert.NotNil(t, auth.Config)
 	})
 
@@ -32,10 +34,55 @@ func TestAuth(t *testing.T) {
 
 		assert.NoError(t, err)
 		assert.Equal(t, TokenAuthType, auth.Type)
-		assert.Equal(t, "Bearer testToken", auth.Config.Header())
+		header, err := auth.Config.Header(nil)
+		assert.NoError(t, err)
+		assert.Equal(t, "Bearer testToken", header)
 		assert.NotNil(t, auth.Config)
 	})
 
+	t.Run("oauth2", func(t *testing.T) {
+		config := map[string]interface{}{
+			"type":          "oauth2",
+			"token_url":     "https://auth.example.com/token",
+			"client_id":     "testClient",
+			"client_secret": "testSecret",
+			"scopes":        "read:apps write:apps",
+			"audience":      "artifacts",
+		}
+		auth, err := authFactory.newAuth(config)
+
+		assert.NoError(t, err)
+		assert.Equal(t, OAuth2AuthType, auth.Type)
+		oauth2Auth := auth.Config.(*OAuth2Auth)
+		assert.Equal(t, "https://auth.example.com/token", oauth2Auth.TokenURL)
+		assert.Equal(t, []string{"read:apps", "write:apps"}, oauth2Auth.Scopes)
+		assert.Equal(t, "artifacts", oauth2Auth.Audience)
+	})
+
+	t.Run("oauth2 without client_secret", func(t *testing.T) {
+		config := map[string]interface{}{
+			"type":      "oauth2",
+			"token_url": "https://auth.example.com/token",
+			"client_id": "testClient",
+		}
+		_, err := authFactory.newAuth(config)
+
+		assert.EqualError(t, err, "error creating auth: missing 'client_secret' in oauth2 auth config")
+	})
+
+	t.Run("oauth2 with invalid scopes", func(t *testing.T) {
+		config := map[string]interface{}{
+			"type":          "oauth2",
+			"token_url":     "https://auth.example.com/token",
+			"client_id":     "testClient",
+			"client_secret": "testSecret",
+			"scopes":        []interface{}{"read", 42},
+		}
+		_, err := authFactory.newAuth(config)
+
+		assert.EqualError(t, err, "error creating auth: scopes must be a list of strings")
+	})
+
 	t.Run("unknown type", func(t *testing.T) {
 		config := map[string]interface{}{
 			"type": "unknown",
@@ -43,7 +90,7 @@ func TestAuth(t *testing.T) {
 		_, err := authFactory.newAuth(config)
 
 		assert.Error(t, err)
-		assert.Equal(t, "auth type unknown is not supported, supported auth types are [basic token]", err.Error())
+		assert.Equal(t, "auth type unknown is not supported, supported auth types are [basic token oauth2]", err.Error())
 	})
 
 }
@@ -0,0 +1,73 @@
+package curl
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+)
+
+// NewClient creates the http client of the repository with the configured proxy, CA bundle and client certificate
+func NewClient(config Config) (*http.Client, error) {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	if config.Proxy != "" {
+		proxyURL, err := url.Parse(config.Proxy)
+		if err != nil || proxyURL.Host == "" {
+			return nil, fmt.Errorf("invalid proxy url '%s'", config.Proxy)
+		}
+		transport.Proxy = http.ProxyURL(proxyURL)
+	}
+
+	tlsConfig := &tls.Config{MinVersion: tls.VersionTLS12}
+	if config.CABundle != "" {
+		caBundle, err := readPEM(config.CABundle)
+		if err != nil {
+			return nil, fmt.Errorf("error reading ca_bundle: %w", err)
+		}
+		rootCAs, err := x509.SystemCertPool()
+		if err != nil || rootCAs == nil {
+			rootCAs = x509.NewCertPool()
+		}
+		if !rootCAs.AppendCertsFromPEM(caBundle) {
+			return nil, fmt.Errorf("ca_bundle does not contain any certificate")
+		}
+		tlsConfig.RootCAs = rootCAs
+	}
+	if config.ClientCert != "" {
+		cert, err := readPEM(config.ClientCert)
+		if err != nil {
+			return nil, fmt.Errorf("error reading client_cert: %w", err)
+		}
+		key, err := readPEM(config.ClientKey)
+		if err != nil {
+			return nil, fmt.Errorf("error reading client_key: %w", err)
+		}
+		certificate, err := tls.X509KeyPair(cert, key)
+		if err != nil {
+			return nil, fmt.Errorf("error loading client certificate: %w", err)
+		}
+		tlsConfig.Certificates = []tls.Certificate{certificate}
+	}
+	transport.TLSClientConfig = tlsConfig
+
+	timeout := config.Timeout
+	if timeout == 0 {
+		timeout = DOWNLOAD_TIMEOUT
+	}
+	return &http.Client{
+		Transport: transport,
+		Timeout:   timeout,
+	}, nil
+}
+
+// readPEM returns the value itself if it contains PEM data, otherwise it is read as file path.
+// This allows to pass certificates directly from secrets.
+func readPEM(value string) ([]byte, error) {
+	if strings.Contains(value, "-----BEGIN") {
+		return []byte(value), nil
+	}
+	return os.ReadFile(value)
+}
@@ -0,0 +1,278 @@
+//go:build integration
+// +build integration
+
+package curl
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/json"
+	"encoding/pem"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/B-S-F/onyx/pkg/repository/app"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var testApp = &app.Reference{Name: "testApp", Version: "1.0.0"}
+
+func certificatePEM(server *httptest.Server) string {
+	return string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: server.Certificate().Raw}))
+}
+
+// newClientCertificate creates a CA and a client certificate signed by it and returns the CA pool and the PEM encoded certificate and key
+func newClientCertificate(t *testing.T) (*x509.CertPool, string, string) {
+	caKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	caTemplate := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "test ca"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		IsCA:                  true,
+		BasicConstraintsValid: true,
+		KeyUsage:              x509.KeyUsageCertSign,
+	}
+	caDER, err := x509.CreateCertificate(rand.Reader, caTemplate, caTemplate, &caKey.PublicKey, caKey)
+	require.NoError(t, err)
+	ca, err := x509.ParseCertificate(caDER)
+	require.NoError(t, err)
+
+	clientKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	clientTemplate := &x509.Certificate{
+		SerialNumber: big.NewInt(2),
+		Subject:      pkix.Name{CommonName: "test client"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+	}
+	clientDER, err := x509.CreateCertificate(rand.Reader, clientTemplate, ca, &clientKey.PublicKey, caKey)
+	require.NoError(t, err)
+	keyDER, err := x509.MarshalECPrivateKey(clientKey)
+	require.NoError(t, err)
+
+	pool := x509.NewCertPool()
+	pool.AddCert(ca)
+	return pool,
+		string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: clientDER})),
+		string(pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER}))
+}
+
+func TestInstallAppWithCABundle(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("app"))
+	}))
+	defer server.Close()
+
+	t.Run("should fail without ca bundle", func(t *testing.T) {
+		repo, err := NewRepository("testRepo", t.TempDir(), map[string]interface{}{
+			"url": server.URL + "/{name}/{version}",
+		})
+		require.NoError(t, err)
+
+		_, err = repo.InstallApp(testApp)
+		assert.ErrorContains(t, err, "certificate")
+	})
+
+	t.Run("should trust ca bundle file", func(t *testing.T) {
+		caBundle := filepath.Join(t.TempDir(), "ca.pem")
+		require.NoError(t, os.WriteFile(caBundle, []byte(certificatePEM(server)), 0600))
+		repo, err := NewRepository("testRepo", t.TempDir(), map[string]interface{}{
+			"url":       server.URL + "/{name}/{version}",
+			"ca_bundle": caBundle,
+		})
+		require.NoError(t, err)
+
+		_, err = repo.InstallApp(testApp)
+		assert.NoError(t, err)
+	})
+}
+
+func TestInstallAppWithClientCertificate(t *testing.T) {
+	clientCAs, clientCert, clientKey := newClientCertificate(t)
+	server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "test client", r.TLS.PeerCertificates[0].Subject.CommonName)
+		w.Write([]byte("app"))
+	}))
+	server.TLS = &tls.Config{ClientAuth: tls.RequireAndVerifyClientCert, ClientCAs: clientCAs}
+	server.StartTLS()
+	defer server.Close()
+
+	t.Run("should fail without client certificate", func(t *testing.T) {
+		repo, err := NewRepository("testRepo", t.TempDir(), map[string]interface{}{
+			"url":       server.URL + "/{name}/{version}",
+			"ca_bundle": certificatePEM(server),
+			"retries":   0,
+		})
+		require.NoError(t, err)
+
+		_, err = repo.InstallApp(testApp)
+		assert.Error(t, err)
+	})
+
+	t.Run("should authenticate with client certificate", func(t *testing.T) {
+		repo, err := NewRepository("testRepo", t.TempDir(), map[string]interface{}{
+			"url":         server.URL + "/{name}/{version}",
+			"ca_bundle":   certificatePEM(server),
+			"client_cert": clientCert,
+			"client_key":  clientKey,
+		})
+		require.NoError(t, err)
+
+		_, err = repo.InstallApp(testApp)
+		assert.NoError(t, err)
+	})
+}
+
+func TestInstallAppWithOAuth2(t *testing.T) {
+	var tokenRequests, revoked atomic.Int32
+	var expiresIn atomic.Int64
+	expiresIn.Store(3600)
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/token":
+			clientID, clientSecret, ok := r.BasicAuth()
+			assert.True(t, ok)
+			assert.Equal(t, "testClient", clientID)
+			assert.Equal(t, "testSecret", clientSecret)
+			assert.Equal(t, "client_credentials", r.FormValue("grant_type"))
+			assert.Equal(t, "read:apps write:none", r.FormValue("scope"))
+			count := tokenRequests.Add(1)
+			w.Header().Set("Content-Type", "application/json")
+			json.NewEncoder(w).Encode(map[string]interface{}{
+				"access_token": "token" + string(rune('0'+count)),
+				"token_type":   "Bearer",
+				"expires_in":   expiresIn.Load(),
+			})
+		default:
+			if revoked.Load() > 0 && r.Header.Get("Authorization") == "Bearer token1" {
+				w.WriteHeader(http.StatusUnauthorized)
+				return
+			}
+			assert.Contains(t, r.Header.Get("Authorization"), "Bearer token")
+			w.Write([]byte("app"))
+		}
+	}))
+	defer server.Close()
+
+	newRepository := func(t *testing.T) *Repository {
+		repo, err := NewRepository("testRepo", t.TempDir(), map[string]interface{}{
+			"url":       server.URL + "/{name}/{version}",
+			"ca_bundle": certificatePEM(server),
+			"auth": map[string]interface{}{
+				"type":          "oauth2",
+				"token_url":     server.URL + "/token",
+				"client_id":     "testClient",
+				"client_secret": "testSecret",
+				"scopes":        []interface{}{"read:apps", "write:none"},
+			},
+		})
+		require.NoError(t, err)
+		return repo.(*Repository)
+	}
+
+	t.Run("should cache token", func(t *testing.T) {
+		tokenRequests.Store(0)
+		repo := newRepository(t)
+		for i := 0; i < 3; i++ {
+			_, err := repo.InstallApp(testApp)
+			require.NoError(t, err)
+		}
+		assert.Equal(t, int32(1), tokenRequests.Load())
+	})
+
+	t.Run("should request new token after expiry", func(t *testing.T) {
+		tokenRequests.Store(0)
+		expiresIn.Store(1)
+		defer expiresIn.Store(3600)
+		repo := newRepository(t)
+		for i := 0; i < 2; i++ {
+			_, err := repo.InstallApp(testApp)
+			require.NoError(t, err)
+		}
+		assert.Equal(t, int32(2), tokenRequests.Load())
+	})
+
+	t.Run("should request new token if cached token is rejected", func(t *testing.T) {
+		tokenRequests.Store(0)
+		repo := newRepository(t)
+		_, err := repo.InstallApp(testApp)
+		require.NoError(t, err)
+		revoked.Store(1)
+		defer revoked.Store(0)
+
+		_, err = repo.InstallApp(testApp)
+		require.NoError(t, err)
+		assert.Equal(t, int32(2), tokenRequests.Load())
+	})
+}
+
+func TestInstallAppWithHeadersProxyAndTimeout(t *testing.T) {
+	t.Run("should send headers", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			assert.Equal(t, "team-a", r.Header.Get("X-Tenant"))
+			assert.Equal(t, "Bearer testToken", r.Header.Get("Authorization"))
+			w.Write([]byte("app"))
+		}))
+		defer server.Close()
+		repo, err := NewRepository("testRepo", t.TempDir(), map[string]interface{}{
+			"url":     server.URL + "/{name}/{version}",
+			"headers": map[string]interface{}{"X-Tenant": "team-a"},
+			"auth":    map[string]interface{}{"type": "token", "token": "testToken"},
+		})
+		require.NoError(t, err)
+
+		_, err = repo.InstallApp(testApp)
+		assert.NoError(t, err)
+	})
+
+	t.Run("should use proxy", func(t *testing.T) {
+		var proxied atomic.Bool
+		proxy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			assert.Equal(t, "http://apps.example.invalid/testApp/1.0.0", r.URL.String())
+			proxied.Store(true)
+			w.Write([]byte("app"))
+		}))
+		defer proxy.Close()
+		repo, err := NewRepository("testRepo", t.TempDir(), map[string]interface{}{
+			"url":   "http://apps.example.invalid/{name}/{version}",
+			"proxy": proxy.URL,
+		})
+		require.NoError(t, err)
+
+		_, err = repo.InstallApp(testApp)
+		assert.NoError(t, err)
+		assert.True(t, proxied.Load())
+	})
+
+	t.Run("should time out", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			time.Sleep(500 * time.Millisecond)
+			w.Write([]byte("app"))
+		}))
+		defer server.Close()
+		repo, err := NewRepository("testRepo", t.TempDir(), map[string]interface{}{
+			"url":     server.URL + "/{name}/{version}",
+			"timeout": "100ms",
+			"retries": 0,
+		})
+		require.NoError(t, err)
+
+		_, err = repo.InstallApp(testApp)
+		assert.ErrorContains(t, err, "Client.Timeout")
+	})
+}
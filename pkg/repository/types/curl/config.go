@@ -2,16 +2,40 @@ package curl
 
 import (
 	"fmt"
+	"time"
 
 	"github.com/B-S-F/onyx/pkg/repository"
+	"github.com/B-S-F/onyx/pkg/repository/app"
+	"github.com/B-S-F/onyx/pkg/repository/download"
+	"github.com/B-S-F/onyx/pkg/repository/signature"
 )
 
+const DOWNLOAD_TIMEOUT = 30 * time.Second
+
 type Config struct {
 	// URL of the file to download
 	// Example "https://my-file-server.com/{app-name}-{app-version}.tar.gz"
 	URL string
 	// Auth configuration
 	Auth *Auth
+	// Archive configuration, if set the apps are archives which are extracted after download
+	Archive *app.ArchiveConfig
+	// Signature configuration, if set the signatures of the apps are verified after download
+	Signature *signature.Config
+	// Additional headers sent with every download
+	Headers map[string]string
+	// Proxy used for all requests, defaults to HTTP_PROXY/HTTPS_PROXY/NO_PROXY of the environment
+	// Example "http://proxy.example.com:3128"
+	Proxy string
+	// Timeout of a single download attempt including the token request
+	Timeout time.Duration
+	// Retries of failed downloads, interrupted downloads are resumed if the server supports range requests
+	Retries int
+	// CA bundle (path or PEM content) which is trusted in addition to the system certificates
+	CABundle string
+	// Client certificate and key (path or PEM content) for mutual TLS
+	ClientCert string
+	ClientKey  string
 }
 
 func (c Config) Type() string {
@@ -23,17 +47,105 @@ func newConfig(config map[string]interface{}) (repository.Config, error) {
 	if config["url"] == nil {
 		return nil, fmt.Errorf("missing 'url' in config")
 	}
+	url, ok := config["url"].(string)
+	if !ok {
+		return nil, fmt.Errorf("url must be a string")
+	}
+	archive, err := app.NewArchiveConfig(config)
+	if err != nil {
+		return nil, fmt.Errorf("error creating archive config: %w", err)
+	}
+	signatureConfig, err := signature.NewConfig(config)
+	if err != nil {
+		return nil, fmt.Errorf("error creating signature config: %w", err)
+	}
+	parsed := Config{
+		URL:       url,
+		Archive:   archive,
+		Signature: signatureConfig,
+		Timeout:   DOWNLOAD_TIMEOUT,
+		Retries:   download.DEFAULT_RETRIES,
+	}
+
+	if config["headers"] != nil {
+		headers, ok := config["headers"].(map[string]interface{})
+		if !ok {
+			return nil, fmt.Errorf("headers must be a map")
+		}
+		parsed.Headers = make(map[string]string, len(headers))
+		for key, value := range headers {
+			valueString, ok := value.(string)
+			if !ok {
+				return nil, fmt.Errorf("value of header '%s' must be a string", key)
+			}
+			parsed.Headers[key] = valueString
+		}
+	}
+	if config["timeout"] != nil {
+		parsed.Timeout, err = ParseTimeout(config["timeout"])
+		if err != nil {
+			return nil, err
+		}
+	}
+	if config["retries"] != nil {
+		retries, ok := config["retries"].(int)
+		if !ok || retries < 0 {
+			return nil, fmt.Errorf("retries must be a non-negative integer")
+		}
+		parsed.Retries = retries
+	}
+	for key, value := range map[string]*string{
+		"proxy":       &parsed.Proxy,
+		"ca_bundle":   &parsed.CABundle,
+		"client_cert": &parsed.ClientCert,
+		"client_key":  &parsed.ClientKey,
+	} {
+		if config[key] == nil {
+			continue
+		}
+		*value, ok = config[key].(string)
+		if !ok {
+			return nil, fmt.Errorf("%s must be a string", key)
+		}
+	}
+	if (parsed.ClientCert == "") != (parsed.ClientKey == "") {
+		return nil, fmt.Errorf("'client_cert' and 'client_key' must be set together")
+	}
+
 	if config["auth"] == nil {
-		return Config{
-			URL: config["url"].(string),
-		}, nil
+		return parsed, nil
+	}
+	authConfig, ok := config["auth"].(map[string]interface{})
+	if !ok {
+		return nil, fmt.Errorf("auth must be a map")
 	}
-	auth, err := authFactory.newAuth(config["auth"].(map[string]interface{}))
+	auth, err := authFactory.newAuth(authConfig)
 	if err != nil {
 		return nil, fmt.Errorf("error creating auth: %w", err)
 	}
-	return Config{
-		URL:  config["url"].(string),
-		Auth: auth,
-	}, nil
+	parsed.Auth = auth
+	return parsed, nil
+}
+
+// ParseTimeout accepts durations like "90s" or "2m" as well as plain numbers of seconds
+func ParseTimeout(value interface{}) (time.Duration, error) {
+	var timeout time.Duration
+	switch v := value.(type) {
+	case string:
+		parsed, err := time.ParseDuration(v)
+		if err != nil {
+			return 0, fmt.Errorf("timeout must be a duration: %w", err)
+		}
+		timeout = parsed
+	case int:
+		timeout = time.Duration(v) * time.Second
+	case float64:
+		timeout = time.Duration(v * float64(time.Second))
+	default:
+		return 0, fmt.Errorf("timeout must be a duration or a number of seconds")
+	}
+	if timeout <= 0 {
+		return 0, fmt.Errorf("timeout must be positive")
+	}
+	return timeout, nil
 }
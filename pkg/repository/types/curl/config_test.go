@@ -2,6 +2,7 @@ package curl
 
 import (
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 )
@@ -63,6 +64,72 @@ func TestNewConfig(t *testing.T) {
 		}
 		_, err := newConfig(configMap)
 		assert.Error(t, err)
-		assert.Equal(t, "error creating auth: auth type unknown is not supported, supported auth types are [basic token]", err.Error())
+		assert.Equal(t, "error creating auth: auth type unknown is not supported, supported auth types are [basic token oauth2]", err.Error())
 	})
+
+	t.Run("with transport options", func(t *testing.T) {
+		configMap := map[string]interface{}{
+			"url":         "https://example.com/{name}/{version}",
+			"headers":     map[string]interface{}{"X-Tenant": "team-a"},
+			"proxy":       "http://proxy.example.com:3128",
+			"timeout":     "2m",
+			"ca_bundle":   "/etc/ssl/corporate.pem",
+			"client_cert": "/etc/ssl/client.pem",
+			"client_key":  "/etc/ssl/client-key.pem",
+		}
+		config, err := newConfig(configMap)
+		assert.NoError(t, err)
+
+		concreteConfig := config.(Config)
+		assert.Equal(t, map[string]string{"X-Tenant": "team-a"}, concreteConfig.Headers)
+		assert.Equal(t, "http://proxy.example.com:3128", concreteConfig.Proxy)
+		assert.Equal(t, 2*time.Minute, concreteConfig.Timeout)
+		assert.Equal(t, "/etc/ssl/corporate.pem", concreteConfig.CABundle)
+		assert.Equal(t, "/etc/ssl/client.pem", concreteConfig.ClientCert)
+		assert.Equal(t, "/etc/ssl/client-key.pem", concreteConfig.ClientKey)
+	})
+
+	t.Run("with default timeout", func(t *testing.T) {
+		config, err := newConfig(map[string]interface{}{"url": "https://example.com/{name}/{version}"})
+		assert.NoError(t, err)
+		assert.Equal(t, DOWNLOAD_TIMEOUT, config.(Config).Timeout)
+	})
+
+	t.Run("with timeout in seconds", func(t *testing.T) {
+		config, err := newConfig(map[string]interface{}{"url": "https://example.com/{name}/{version}", "timeout": 90})
+		assert.NoError(t, err)
+		assert.Equal(t, 90*time.Second, config.(Config).Timeout)
+	})
+
+	invalidConfigs := map[string]struct {
+		config  map[string]interface{}
+		wantErr string
+	}{
+		"with invalid url": {
+			config:  map[string]interface{}{"url": 42},
+			wantErr: "url must be a string",
+		},
+		"with invalid headers": {
+			config:  map[string]interface{}{"url": "https://example.com", "headers": map[string]interface{}{"X-Count": 1}},
+			wantErr: "value of header 'X-Count' must be a string",
+		},
+		"with invalid timeout": {
+			config:  map[string]interface{}{"url": "https://example.com", "timeout": "soon"},
+			wantErr: "timeout must be a duration: time: invalid duration \"soon\"",
+		},
+		"with negative timeout": {
+			config:  map[string]interface{}{"url": "https://example.com", "timeout": "-1s"},
+			wantErr: "timeout must be positive",
+		},
+		"with client certificate without key": {
+			config:  map[string]interface{}{"url": "https://example.com", "client_cert": "/etc/ssl/client.pem"},
+			wantErr: "'client_cert' and 'client_key' must be set together",
+		},
+	}
+	for name, tc := range invalidConfigs {
+		t.Run(name, func(t *testing.T) {
+			_, err := newConfig(tc.config)
+			assert.EqualError(t, err, tc.wantErr)
+		})
+	}
 }
@@ -7,18 +7,19 @@ import (
 	"os"
 	"path/filepath"
 	"strings"
-	"time"
+	"sync"
 
 	"github.com/B-S-F/onyx/pkg/repository"
 	"github.com/B-S-F/onyx/pkg/repository/app"
+	"github.com/B-S-F/onyx/pkg/repository/download"
 )
 
-const DOWNLOAD_TIMEOUT = 30 * time.Second
-
 type Repository struct {
 	Config           Config
 	RepoName         string
 	InstallationPath string
+	client           *http.Client
+	mutex            sync.Mutex
 }
 
 func NewRepository(name string, installationPath string, config map[string]interface{}) (repository.Repository, error) {
@@ -29,10 +30,15 @@ func NewRepository(name string, installationPath string, config map[string]inter
 	if err != nil {
 		return nil, fmt.Errorf("failed to create config: %w", err)
 	}
+	client, err := NewClient(parsed.(Config))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create http client: %w", err)
+	}
 	return &Repository{
 		Config:           parsed.(Config),
 		RepoName:         name,
 		InstallationPath: installationPath,
+		client:           client,
 	}, nil
 }
 
@@ -57,12 +63,31 @@ func (r *Repository) InstallApp(appReference *app.Reference) (app.App, error) {
 		return nil, fmt.Errorf("failed to install app %s: %w", appReference, err)
 	}
 
+	provenance := app.Provenance{Source: download.RedactURL(url.String())}
+	if r.Config.Signature != nil {
+		provenance.Signature, err = r.Config.Signature.Verify(outputPath, func(signaturePath string) error {
+			signatureURL := *url
+			signatureURL.Path += r.Config.Signature.Suffix
+			return r.downloadFile(&signatureURL, signaturePath)
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to install app %s: %w", appReference, err)
+		}
+	}
+
 	checksum, err := app.CalculateFileChecksum(outputPath)
 	if err != nil {
 		return nil, fmt.Errorf("failed to install app %s: %w", appReference, err)
 	}
 
-	return app.NewBinaryApp(r.RepoName, appReference.Name, appReference.Version, checksum, outputPath), nil
+	if r.Config.Archive != nil {
+		installed, err := app.NewArchiveApp(r.RepoName, appReference, checksum, outputPath, r.Config.Archive)
+		if err != nil {
+			return nil, fmt.Errorf("failed to install app %s: %w", appReference, err)
+		}
+		return app.WithProvenance(installed, provenance), nil
+	}
+	return app.WithProvenance(app.NewBinaryApp(r.RepoName, appReference.Name, appReference.Version, checksum, outputPath), provenance), nil
 }
 
 // Replace {name} and {version} in the URL with the actual app name and version
@@ -88,47 +113,103 @@ func (r *Repository) getAppURL(appName, appVersion string) (*url.URL, error) {
 // TODO: Files are not verified after download they could be anything
 // -> We should restrict our pods to not be able to access anything relevant
 func (r *Repository) downloadFile(url *url.URL, outputPath string) error {
-
-	header := http.Header{}
-	if r.Config.Auth != nil {
-		header.Add("Authorization", r.Config.Auth.Config.Header())
-	}
-
-	request := http.Request{
-		Method: "GET",
-		URL:    url,
-		Header: header,
-	}
-
-	client := http.Client{
-		Timeout: DOWNLOAD_TIMEOUT,
-	}
-	response, err := client.Do(&request)
+	err := download.New(r.Config.Retries, r.do).Download(url.String(), outputPath)
 	if err != nil {
 		return fmt.Errorf("error downloading file: %w", err)
 	}
-
-	if response.StatusCode != http.StatusOK {
-		return fmt.Errorf("error downloading file: %s", response.Status)
+	err = os.Chmod(outputPath, 0755)
+	if err != nil {
+		return fmt.Errorf("error changing file permissions: %w", err)
 	}
+	return nil
+}
 
-	defer response.Body.Close()
-	file, err := os.Create(outputPath)
+// HasApp checks with a HEAD request if the app exists without downloading it.
+// Servers which do not support HEAD are asked for the first byte instead.
+func (r *Repository) HasApp(appReference *app.Reference) (bool, error) {
+	url, err := r.getAppURL(appReference.Name, appReference.Version)
 	if err != nil {
-		return fmt.Errorf("error opening file: %w.", err)
-	}
-	defer file.Close()
+		return false, err
+	}
+	for _, method := range []string{http.MethodHead, http.MethodGet} {
+		request, err := http.NewRequest(method, url.String(), nil)
+		if err != nil {
+			return false, err
+		}
+		if method == http.MethodGet {
+			request.Header.Set("Range", "bytes=0-0")
+		}
+		response, err := r.do(request)
+		if err != nil {
+			return false, err
+		}
+		response.Body.Close()
+		switch response.StatusCode {
+		case http.StatusOK, http.StatusPartialContent:
+			return true, nil
+		case http.StatusNotFound, http.StatusGone:
+			return false, nil
+		case http.StatusMethodNotAllowed, http.StatusNotImplemented:
+			continue
+		default:
+			return false, fmt.Errorf("error checking app: %s", response.Status)
+		}
+	}
+	return false, fmt.Errorf("error checking app: server supports neither HEAD nor GET")
+}
 
-	_, err = file.ReadFrom(response.Body)
+// do sends a request with the configured headers and authorization.
+// Cached tokens could have been revoked, so a new one is requested once if the server rejects it.
+func (r *Repository) do(request *http.Request) (*http.Response, error) {
+	response, err := r.send(request)
 	if err != nil {
-		return fmt.Errorf("error writing file: %w", err)
+		return nil, err
 	}
+	if cached, ok := r.authConfig().(cachedAuth); ok && response.StatusCode == http.StatusUnauthorized {
+		response.Body.Close()
+		cached.Invalidate()
+		return r.send(request.Clone(request.Context()))
+	}
+	return response, nil
+}
 
-	err = file.Chmod(0755)
+func (r *Repository) send(request *http.Request) (*http.Response, error) {
+	for key, value := range r.Config.Headers {
+		request.Header.Set(key, value)
+	}
+	client, err := r.httpClient()
 	if err != nil {
-		return fmt.Errorf("error changing file permissions: %w", err)
+		return nil, fmt.Errorf("failed to create http client: %w", err)
 	}
-	return nil
+	if authConfig := r.authConfig(); authConfig != nil {
+		header, err := authConfig.Header(client)
+		if err != nil {
+			return nil, fmt.Errorf("error authenticating: %w", err)
+		}
+		request.Header.Set("Authorization", header)
+	}
+	return client.Do(request)
+}
+
+// httpClient returns the client of the repository, it is created on first use for repositories which are not created by NewRepository
+func (r *Repository) httpClient() (*http.Client, error) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	if r.client == nil {
+		client, err := NewClient(r.Config)
+		if err != nil {
+			return nil, err
+		}
+		r.client = client
+	}
+	return r.client, nil
+}
+
+func (r *Repository) authConfig() AuthConfig {
+	if r.Config.Auth == nil {
+		return nil
+	}
+	return r.Config.Auth.Config
 }
 
 func (r *Repository) Name() string {
@@ -4,14 +4,25 @@
 package curl
 
 import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
 	"io/ioutil"
 	"net/http"
 	"net/http/httptest"
 	"net/url"
 	"os"
+	"path/filepath"
 	"testing"
 
 	"github.com/B-S-F/onyx/pkg/repository/app"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestInstallApp(t *testing.T) {
@@ -51,6 +62,48 @@ func TestInstallApp(t *testing.T) {
 	}
 }
 
+func TestInstallArchiveApp(t *testing.T) {
+	var archive bytes.Buffer
+	gzipWriter := gzip.NewWriter(&archive)
+	tarWriter := tar.NewWriter(gzipWriter)
+	for name, content := range map[string]string{"bin/testApp": "#!/bin/sh\necho app\n", "bin/testHelper": "#!/bin/sh\necho helper\n"} {
+		require.NoError(t, tarWriter.WriteHeader(&tar.Header{Name: name, Mode: 0644, Size: int64(len(content))}))
+		_, err := tarWriter.Write([]byte(content))
+		require.NoError(t, err)
+	}
+	require.NoError(t, tarWriter.Close())
+	require.NoError(t, gzipWriter.Close())
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+		w.Write(archive.Bytes())
+	}))
+	defer server.Close()
+
+	installationPath := t.TempDir()
+	repo, err := NewRepository("testRepo", installationPath, map[string]interface{}{
+		"url": server.URL + "/{name}/{version}.tar.gz",
+		"archive": map[string]interface{}{
+			"entrypoints": map[string]interface{}{
+				"{name}": "bin/{name}",
+				"helper": "bin/testHelper",
+			},
+		},
+	})
+	require.NoError(t, err)
+
+	installed, err := repo.InstallApp(&app.Reference{Name: "testApp", Version: "1.0.0"})
+	require.NoError(t, err)
+	executables := installed.Executables()
+	require.Len(t, executables, 2)
+	assert.Equal(t, []string{"helper@1.0.0", "helper"}, executables[0].References)
+	assert.Equal(t, "testHelper", filepath.Base(executables[0].Path))
+	assert.Equal(t, []string{"testApp@1.0.0", "testApp"}, executables[1].References)
+	checksum, err := app.CalculateFileChecksum(app.InstallationPath(installationPath, "testRepo", "testApp", "1.0.0"))
+	require.NoError(t, err)
+	assert.Equal(t, checksum, installed.Checksum())
+}
+
 func TestDownloadFile(t *testing.T) {
 	// This test requires a mock HTTP server to simulate downloading a file.
 	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
@@ -95,3 +148,127 @@ func TestDownloadFile(t *testing.T) {
 		t.Errorf("File was not downloaded")
 	}
 }
+
+func TestHasApp(t *testing.T) {
+	var methods []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		methods = append(methods, r.Method)
+		switch {
+		case r.URL.Path == "/head-not-allowed/1.0.0" && r.Method == http.MethodHead:
+			w.WriteHeader(http.StatusMethodNotAllowed)
+		case r.URL.Path == "/head-not-allowed/1.0.0":
+			assert.Equal(t, "bytes=0-0", r.Header.Get("Range"))
+			w.WriteHeader(http.StatusPartialContent)
+		case r.URL.Path == "/existing/1.0.0":
+			w.WriteHeader(http.StatusOK)
+		case r.URL.Path == "/broken/1.0.0":
+			w.WriteHeader(http.StatusInternalServerError)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	repo, err := NewRepository("testRepo", t.TempDir(), map[string]interface{}{
+		"url": server.URL + "/{name}/{version}",
+	})
+	require.NoError(t, err)
+	curlRepo := repo.(*Repository)
+
+	testCases := map[string]struct {
+		name    string
+		found   bool
+		methods []string
+		err     string
+	}{
+		"existing app":              {name: "existing", found: true, methods: []string{http.MethodHead}},
+		"missing app":               {name: "missing", found: false, methods: []string{http.MethodHead}},
+		"fallback to range request": {name: "head-not-allowed", found: true, methods: []string{http.MethodHead, http.MethodGet}},
+		"server error":              {name: "broken", err: "500 Internal Server Error", methods: []string{http.MethodHead}},
+	}
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			methods = nil
+			found, err := curlRepo.HasApp(&app.Reference{Name: tc.name, Version: "1.0.0"})
+			if tc.err != "" {
+				assert.ErrorContains(t, err, tc.err)
+			} else {
+				assert.NoError(t, err)
+			}
+			assert.Equal(t, tc.found, found)
+			assert.Equal(t, tc.methods, methods)
+		})
+	}
+}
+
+func TestInstallAppWithSignature(t *testing.T) {
+	publicKey, privateKey, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+	der, err := x509.MarshalPKIXPublicKey(publicKey)
+	require.NoError(t, err)
+	publicKeyPEM := string(pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der}))
+	content := []byte("#!/bin/sh\necho app\n")
+	signature := base64.StdEncoding.EncodeToString(ed25519.Sign(privateKey, content))
+
+	newServer := func(withSignature bool) *httptest.Server {
+		return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			switch r.URL.Path {
+			case "/testApp/1.0.0":
+				w.Write(content)
+			case "/testApp/1.0.0.bundle":
+				if withSignature {
+					w.Write([]byte(signature))
+					return
+				}
+				w.WriteHeader(http.StatusNotFound)
+			default:
+				w.WriteHeader(http.StatusNotFound)
+			}
+		}))
+	}
+	newRepository := func(t *testing.T, serverURL string, policy string) *Repository {
+		repo, err := NewRepository("testRepo", t.TempDir(), map[string]interface{}{
+			"url": serverURL + "/{name}/{version}",
+			"signature": map[string]interface{}{
+				"type":       "cosign",
+				"public_key": publicKeyPEM,
+				"signer":     "release-pipeline",
+				"policy":     policy,
+			},
+		})
+		require.NoError(t, err)
+		return repo.(*Repository)
+	}
+
+	t.Run("verified signature is reported", func(t *testing.T) {
+		server := newServer(true)
+		defer server.Close()
+
+		installed, err := newRepository(t, server.URL, "enforce").InstallApp(&app.Reference{Name: "testApp", Version: "1.0.0"})
+		require.NoError(t, err)
+		provenance := app.ProvenanceOf(installed)
+		assert.Equal(t, server.URL+"/testApp/1.0.0", provenance.Source)
+		require.NotNil(t, provenance.Signature)
+		assert.Equal(t, "cosign", provenance.Signature.Type)
+		assert.Equal(t, app.SignatureVerified, provenance.Signature.Status)
+		assert.Contains(t, provenance.Signature.KeyID, "sha256:")
+		assert.Equal(t, "release-pipeline", provenance.Signature.Signer)
+	})
+
+	t.Run("missing signature fails with enforce policy", func(t *testing.T) {
+		server := newServer(false)
+		defer server.Close()
+
+		_, err := newRepository(t, server.URL, "enforce").InstallApp(&app.Reference{Name: "testApp", Version: "1.0.0"})
+		assert.ErrorContains(t, err, "signature verification failed")
+	})
+
+	t.Run("missing signature is reported as failed with warn policy", func(t *testing.T) {
+		server := newServer(false)
+		defer server.Close()
+
+		installed, err := newRepository(t, server.URL, "warn").InstallApp(&app.Reference{Name: "testApp", Version: "1.0.0"})
+		require.NoError(t, err)
+		assert.Equal(t, &app.Signature{Type: "cosign", Status: app.SignatureFailed}, app.ProvenanceOf(installed).Signature)
+	})
+}
@@ -0,0 +1,83 @@
+package git
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/B-S-F/onyx/pkg/repository"
+	"github.com/B-S-F/onyx/pkg/repository/signature"
+)
+
+const URLKey = "url"
+const TagKey = "tag"
+const EntrypointKey = "entrypoint"
+const BuildKey = "build"
+
+type Config struct {
+	// URL or local path of the git repository, {name} is replaced with the app name
+	// Example "https://github.com/my-org/{name}.git"
+	URL string
+	// Tag to check out, {version} is replaced with the app version
+	// Example "v{version}"
+	Tag string
+	// Path of the executable inside the repository, {name} is replaced with the app name
+	// Example "bin/{name}.sh"
+	Entrypoint string
+	// Command that builds the app into the file given by $APP_OUTPUT_PATH (executed inside the checkout)
+	// Example "go build -o $APP_OUTPUT_PATH ./cmd/{name}"
+	Build string
+	// Auth configuration
+	Auth *Auth
+}
+
+func (c Config) Type() string {
+	return "git"
+}
+
+func newConfig(config map[string]interface{}) (repository.Config, error) {
+	authFactory := newAuthFactory()
+	if config[URLKey] == nil {
+		return nil, fmt.Errorf("missing '%s' in config", URLKey)
+	}
+	url, ok := config[URLKey].(string)
+	if !ok || url == "" {
+		return nil, fmt.Errorf("%s must be a non-empty string", URLKey)
+	}
+	if config[signature.SignatureKey] != nil {
+		return nil, fmt.Errorf("signature verification is not supported by git repositories")
+	}
+	parsed := Config{
+		URL:        url,
+		Tag:        "{version}",
+		Entrypoint: "{name}",
+	}
+	for key, target := range map[string]*string{TagKey: &parsed.Tag, EntrypointKey: &parsed.Entrypoint, BuildKey: &parsed.Build} {
+		if config[key] == nil {
+			continue
+		}
+		value, ok := config[key].(string)
+		if !ok {
+			return nil, fmt.Errorf("%s must be a string", key)
+		}
+		*target = value
+	}
+	if !strings.Contains(parsed.Tag, "{version}") {
+		return nil, fmt.Errorf("%s does not contain {version} placeholder", TagKey)
+	}
+	if config[EntrypointKey] != nil && parsed.Build != "" {
+		return nil, fmt.Errorf("only one of '%s' and '%s' can be set", EntrypointKey, BuildKey)
+	}
+	if config["auth"] == nil {
+		return parsed, nil
+	}
+	authConfig, ok := config["auth"].(map[string]interface{})
+	if !ok {
+		return nil, fmt.Errorf("auth must be a map")
+	}
+	auth, err := authFactory.newAuth(authConfig)
+	if err != nil {
+		return nil, fmt.Errorf("error creating auth: %w", err)
+	}
+	parsed.Auth = auth
+	return parsed, nil
+}
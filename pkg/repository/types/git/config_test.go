@@ -0,0 +1,79 @@
+package git
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewConfig(t *testing.T) {
+	t.Run("defaults", func(t *testing.T) {
+		config, err := newConfig(map[string]interface{}{
+			"url": "https://example.com/{name}.git",
+		})
+		assert.NoError(t, err)
+		assert.Equal(t, "git", config.Type())
+		concreteConfig, ok := config.(Config)
+		assert.True(t, ok)
+		assert.Equal(t, "https://example.com/{name}.git", concreteConfig.URL)
+		assert.Equal(t, "{version}", concreteConfig.Tag)
+		assert.Equal(t, "{name}", concreteConfig.Entrypoint)
+		assert.Equal(t, "", concreteConfig.Build)
+		assert.Nil(t, concreteConfig.Auth)
+	})
+
+	t.Run("with build and auth", func(t *testing.T) {
+		config, err := newConfig(map[string]interface{}{
+			"url":   "https://example.com/tools.git",
+			"tag":   "v{version}",
+			"build": "make build",
+			"auth": map[string]interface{}{
+				"type":  "token",
+				"token": "testToken",
+			},
+		})
+		assert.NoError(t, err)
+		concreteConfig := config.(Config)
+		assert.Equal(t, "v{version}", concreteConfig.Tag)
+		assert.Equal(t, "make build", concreteConfig.Build)
+		assert.Equal(t, TokenAuthType, concreteConfig.Auth.Type)
+	})
+
+	t.Run("with missing url", func(t *testing.T) {
+		_, err := newConfig(map[string]interface{}{})
+		assert.EqualError(t, err, "missing 'url' in config")
+	})
+
+	t.Run("with tag without version", func(t *testing.T) {
+		_, err := newConfig(map[string]interface{}{
+			"url": "https://example.com/tools.git",
+			"tag": "main",
+		})
+		assert.EqualError(t, err, "tag does not contain {version} placeholder")
+	})
+
+	t.Run("with entrypoint and build", func(t *testing.T) {
+		_, err := newConfig(map[string]interface{}{
+			"url":        "https://example.com/tools.git",
+			"entrypoint": "bin/tool",
+			"build":      "make build",
+		})
+		assert.EqualError(t, err, "only one of 'entrypoint' and 'build' can be set")
+	})
+
+	t.Run("with invalid entrypoint", func(t *testing.T) {
+		_, err := newConfig(map[string]interface{}{
+			"url":        "https://example.com/tools.git",
+			"entrypoint": 42,
+		})
+		assert.EqualError(t, err, "entrypoint must be a string")
+	})
+
+	t.Run("with signature", func(t *testing.T) {
+		_, err := newConfig(map[string]interface{}{
+			"url":       "https://example.com/tools.git",
+			"signature": map[string]interface{}{"type": "gpg", "public_key": "key.asc"},
+		})
+		assert.EqualError(t, err, "signature verification is not supported by git repositories")
+	})
+}
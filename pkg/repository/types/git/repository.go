@@ -0,0 +1,221 @@
+package git
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/B-S-F/onyx/pkg/repository"
+	"github.com/B-S-F/onyx/pkg/repository/app"
+	"github.com/B-S-F/onyx/pkg/repository/download"
+)
+
+const GIT_TIMEOUT = 5 * time.Minute
+const BUILD_TIMEOUT = 10 * time.Minute
+
+type Repository struct {
+	Config           Config
+	RepoName         string
+	InstallationPath string
+}
+
+func NewRepository(name string, installationPath string, config map[string]interface{}) (repository.Repository, error) {
+	if config == nil {
+		return nil, fmt.Errorf("config is nil")
+	}
+	parsed, err := newConfig(config)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create config: %w", err)
+	}
+	return &Repository{
+		Config:           parsed.(Config),
+		RepoName:         name,
+		InstallationPath: installationPath,
+	}, nil
+}
+
+// InstallApp clones the repository at the tag of the app version and exposes the entrypoint
+// (or the result of the build command) as executable. The commit SHA is used as checksum.
+func (r *Repository) InstallApp(appReference *app.Reference) (app.App, error) {
+	if r.InstallationPath == "" {
+		return nil, fmt.Errorf("installation path is not set")
+	}
+	if err := checkReference(appReference); err != nil {
+		return nil, fmt.Errorf("failed to install app %s: %w", appReference, err)
+	}
+	url := strings.ReplaceAll(r.Config.URL, "{name}", appReference.Name)
+	tag := strings.ReplaceAll(r.Config.Tag, "{version}", appReference.Version)
+
+	outputPath := app.InstallationPath(r.InstallationPath, r.RepoName, appReference.Name, appReference.Version)
+	checkoutPath := outputPath + "-src"
+	err := os.MkdirAll(filepath.Dir(outputPath), os.ModePerm)
+	if err != nil {
+		return nil, fmt.Errorf("failed to install app %s: %w", appReference, err)
+	}
+	err = os.RemoveAll(checkoutPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to install app %s: %w", appReference, err)
+	}
+
+	commit, err := r.checkout(url, tag, checkoutPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to install app %s: %w", appReference, err)
+	}
+
+	var executablePath string
+	if r.Config.Build != "" {
+		executablePath = outputPath
+		err = r.build(appReference, checkoutPath, outputPath)
+	} else {
+		executablePath, err = r.entrypoint(appReference, checkoutPath)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to install app %s: %w", appReference, err)
+	}
+	err = os.Chmod(executablePath, 0755)
+	if err != nil {
+		return nil, fmt.Errorf("failed to install app %s: error changing file permissions: %w", appReference, err)
+	}
+
+	installed := app.NewBinaryApp(r.RepoName, appReference.Name, appReference.Version, commit, executablePath)
+	return app.WithProvenance(installed, app.Provenance{Source: source(url, tag)}), nil
+}
+
+// checkReference rejects names and versions which git could interpret as options
+func checkReference(appReference *app.Reference) error {
+	if strings.HasPrefix(appReference.Name, "-") {
+		return fmt.Errorf("app name '%s' must not start with '-'", appReference.Name)
+	}
+	if strings.HasPrefix(appReference.Version, "-") {
+		return fmt.Errorf("app version '%s' must not start with '-'", appReference.Version)
+	}
+	return nil
+}
+
+// source is the url of the repository without credentials and the checked out tag, e.g. https://github.com/my-org/my-app.git#v1.0.0
+func source(url, tag string) string {
+	redacted := download.RedactURL(url)
+	if redacted == "" {
+		// scp-like urls as git@github.com:my-org/my-app.git can't be parsed but don't contain passwords
+		redacted = url
+	}
+	return redacted + "#" + tag
+}
+
+// checkout clones the given tag into checkoutPath and returns the checked out commit SHA
+func (r *Repository) checkout(url, tag, checkoutPath string) (string, error) {
+	_, err := r.git("", "ls-remote", "--exit-code", "--tags", "--", url, "refs/tags/"+tag)
+	if err != nil {
+		return "", fmt.Errorf("tag '%s' not found in '%s': %w", tag, url, err)
+	}
+	_, err = r.git("", "clone", "--quiet", "--depth", "1", "--branch", tag, "--", url, checkoutPath)
+	if err != nil {
+		return "", fmt.Errorf("error cloning '%s' at tag '%s': %w", url, tag, err)
+	}
+	commit, err := r.git(checkoutPath, "rev-parse", "HEAD^{commit}")
+	if err != nil {
+		return "", fmt.Errorf("error reading commit of tag '%s': %w", tag, err)
+	}
+	return commit, nil
+}
+
+// entrypoint returns the path of the declared entrypoint which has to stay inside the checkout
+func (r *Repository) entrypoint(appReference *app.Reference, checkoutPath string) (string, error) {
+	entrypoint := strings.ReplaceAll(r.Config.Entrypoint, "{name}", appReference.Name)
+	executablePath := filepath.Join(checkoutPath, entrypoint)
+	relative, err := filepath.Rel(checkoutPath, executablePath)
+	if err != nil || relative == "." || strings.HasPrefix(relative, "..") {
+		return "", fmt.Errorf("entrypoint '%s' is not a file inside the repository", entrypoint)
+	}
+	info, err := os.Lstat(executablePath)
+	if err != nil {
+		return "", fmt.Errorf("entrypoint '%s' not found in repository: %w", entrypoint, err)
+	}
+	if !info.Mode().IsRegular() {
+		return "", fmt.Errorf("entrypoint '%s' is not a regular file", entrypoint)
+	}
+	return executablePath, nil
+}
+
+// build executes the build command inside the checkout, it has to create the file $APP_OUTPUT_PATH
+func (r *Repository) build(appReference *app.Reference, checkoutPath, outputPath string) error {
+	err := os.RemoveAll(outputPath)
+	if err != nil {
+		return err
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), BUILD_TIMEOUT)
+	defer cancel()
+	buildCommand := strings.ReplaceAll(r.Config.Build, "{name}", appReference.Name)
+	cmd := exec.CommandContext(ctx, "/bin/bash", "-c", "set -e\n"+buildCommand)
+	cmd.Dir = checkoutPath
+	cmd.Env = append(os.Environ(),
+		"APP_NAME="+appReference.Name,
+		"APP_VERSION="+appReference.Version,
+		"APP_OUTPUT_PATH="+outputPath,
+	)
+	output, err := cmd.CombinedOutput()
+	if err != nil {
+		return fmt.Errorf("error running build command: %w: %s", err, strings.TrimSpace(string(output)))
+	}
+	info, err := os.Stat(outputPath)
+	if err != nil {
+		return fmt.Errorf("build command did not create $APP_OUTPUT_PATH: %w", err)
+	}
+	if !info.Mode().IsRegular() {
+		return fmt.Errorf("build command did not create a regular file at $APP_OUTPUT_PATH")
+	}
+	return nil
+}
+
+// git runs a git command and returns its trimmed stdout.
+// Credentials are passed through the environment to keep them out of the process list.
+func (r *Repository) git(dir string, args ...string) (string, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), GIT_TIMEOUT)
+	defer cancel()
+	cmd := exec.CommandContext(ctx, "git", args...)
+	cmd.Dir = dir
+	cmd.Env = append(os.Environ(), "GIT_TERMINAL_PROMPT=0")
+	if r.Config.Auth != nil {
+		cmd.Env = append(cmd.Env,
+			"GIT_CONFIG_COUNT=1",
+			"GIT_CONFIG_KEY_0=http.extraHeader",
+			"GIT_CONFIG_VALUE_0=Authorization: "+r.Config.Auth.Config.Header(),
+		)
+	}
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	err := cmd.Run()
+	if err != nil {
+		return "", fmt.Errorf("git %s failed: %w: %s", args[0], err, strings.TrimSpace(stderr.String()))
+	}
+	return strings.TrimSpace(stdout.String()), nil
+}
+
+// HasApp checks if the tag of the app version exists without cloning the repository
+func (r *Repository) HasApp(appReference *app.Reference) (bool, error) {
+	if err := checkReference(appReference); err != nil {
+		return false, err
+	}
+	url := strings.ReplaceAll(r.Config.URL, "{name}", appReference.Name)
+	tag := strings.ReplaceAll(r.Config.Tag, "{version}", appReference.Version)
+	_, err := r.git("", "ls-remote", "--exit-code", "--tags", "--", url, "refs/tags/"+tag)
+	var exitError *exec.ExitError
+	if errors.As(err, &exitError) && exitError.ExitCode() == 2 {
+		return false, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("error checking tag '%s' in '%s': %w", tag, url, err)
+	}
+	return true, nil
+}
+
+func (r *Repository) Name() string {
+	return r.RepoName
+}
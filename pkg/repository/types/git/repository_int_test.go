@@ -0,0 +1,107 @@
+//go:build integration
+// +build integration
+
+package git
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/B-S-F/onyx/pkg/repository/app"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newGitRepository creates a local repository with one commit per given tag and returns its path and the commit SHAs
+func newGitRepository(t *testing.T, files map[string]map[string]string, tags []string) (string, map[string]string) {
+	repoPath := t.TempDir()
+	run := func(args ...string) string {
+		cmd := exec.Command("git", args...)
+		cmd.Dir = repoPath
+		cmd.Env = append(os.Environ(), "GIT_AUTHOR_NAME=test", "GIT_AUTHOR_EMAIL=test@example.com", "GIT_COMMITTER_NAME=test", "GIT_COMMITTER_EMAIL=test@example.com")
+		output, err := cmd.CombinedOutput()
+		require.NoError(t, err, string(output))
+		return strings.TrimSpace(string(output))
+	}
+	run("init", "--quiet")
+	commits := make(map[string]string)
+	for _, tag := range tags {
+		for name, content := range files[tag] {
+			require.NoError(t, os.MkdirAll(filepath.Dir(filepath.Join(repoPath, name)), 0755))
+			require.NoError(t, os.WriteFile(filepath.Join(repoPath, name), []byte(content), 0644))
+		}
+		run("add", "-A")
+		run("commit", "--quiet", "-m", "release "+tag)
+		run("tag", "-a", tag, "-m", tag)
+		commits[tag] = run("rev-parse", "HEAD")
+	}
+	return repoPath, commits
+}
+
+func TestInstallApp(t *testing.T) {
+	repoPath, commits := newGitRepository(t, map[string]map[string]string{
+		"v1.0.0": {"bin/hello.sh": "#!/bin/bash\necho v1\n", "build.sh": "cp bin/hello.sh \"$APP_OUTPUT_PATH\"\n"},
+		"v2.0.0": {"bin/hello.sh": "#!/bin/bash\necho v2\n"},
+	}, []string{"v1.0.0", "v2.0.0"})
+
+	t.Run("should expose entrypoint of tag", func(t *testing.T) {
+		repo, err := NewRepository("testRepo", t.TempDir(), map[string]interface{}{
+			"url":        repoPath,
+			"tag":        "v{version}",
+			"entrypoint": "bin/{name}.sh",
+		})
+		require.NoError(t, err)
+
+		installed, err := repo.InstallApp(&app.Reference{Name: "hello", Version: "1.0.0"})
+		require.NoError(t, err)
+		assert.Equal(t, commits["v1.0.0"], installed.Checksum())
+		output, err := exec.Command(installed.ExecutablePath()).Output()
+		require.NoError(t, err)
+		assert.Equal(t, "v1\n", string(output))
+	})
+
+	t.Run("should run build command", func(t *testing.T) {
+		installationPath := t.TempDir()
+		repo, err := NewRepository("testRepo", installationPath, map[string]interface{}{
+			"url":   repoPath,
+			"tag":   "v{version}",
+			"build": "bash build.sh",
+		})
+		require.NoError(t, err)
+
+		installed, err := repo.InstallApp(&app.Reference{Name: "hello", Version: "1.0.0"})
+		require.NoError(t, err)
+		assert.Equal(t, app.InstallationPath(installationPath, "testRepo", "hello", "1.0.0"), installed.ExecutablePath())
+		assert.Equal(t, commits["v1.0.0"], installed.Checksum())
+		output, err := exec.Command(installed.ExecutablePath()).Output()
+		require.NoError(t, err)
+		assert.Equal(t, "v1\n", string(output))
+	})
+
+	t.Run("should fail if build does not create output", func(t *testing.T) {
+		repo, err := NewRepository("testRepo", t.TempDir(), map[string]interface{}{
+			"url":   repoPath,
+			"tag":   "v{version}",
+			"build": "true",
+		})
+		require.NoError(t, err)
+
+		_, err = repo.InstallApp(&app.Reference{Name: "hello", Version: "2.0.0"})
+		assert.ErrorContains(t, err, "build command did not create $APP_OUTPUT_PATH")
+	})
+
+	t.Run("should fail for unknown tag", func(t *testing.T) {
+		repo, err := NewRepository("testRepo", t.TempDir(), map[string]interface{}{
+			"url":        repoPath,
+			"tag":        "v{version}",
+			"entrypoint": "bin/{name}.sh",
+		})
+		require.NoError(t, err)
+
+		_, err = repo.InstallApp(&app.Reference{Name: "hello", Version: "3.0.0"})
+		assert.ErrorContains(t, err, "tag 'v3.0.0' not found")
+	})
+}
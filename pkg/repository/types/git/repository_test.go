@@ -0,0 +1,82 @@
+package git
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/B-S-F/onyx/pkg/repository/app"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewRepository(t *testing.T) {
+	repo, err := NewRepository("testRepo", "/path/to/install", map[string]interface{}{
+		"url": "https://example.com/{name}.git",
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, "testRepo", repo.Name())
+
+	_, err = NewRepository("testRepo", "/path/to/install", nil)
+	assert.Error(t, err)
+}
+
+func TestEntrypoint(t *testing.T) {
+	checkoutPath := t.TempDir()
+	assert.NoError(t, os.MkdirAll(filepath.Join(checkoutPath, "bin"), 0755))
+	assert.NoError(t, os.WriteFile(filepath.Join(checkoutPath, "bin", "app.sh"), []byte("echo hello"), 0644))
+
+	testCases := map[string]struct {
+		entrypoint string
+		want       string
+		wantErr    bool
+	}{
+		"should resolve entrypoint with name": {
+			entrypoint: "bin/{name}.sh",
+			want:       filepath.Join(checkoutPath, "bin", "app.sh"),
+		},
+		"should fail for missing entrypoint": {
+			entrypoint: "bin/other.sh",
+			wantErr:    true,
+		},
+		"should fail for directories": {
+			entrypoint: "bin",
+			wantErr:    true,
+		},
+		"should fail for paths outside of the repository": {
+			entrypoint: "../{name}.sh",
+			wantErr:    true,
+		},
+	}
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			repo := &Repository{Config: Config{Entrypoint: tc.entrypoint}}
+			got, err := repo.entrypoint(&app.Reference{Name: "app", Version: "1.0.0"}, checkoutPath)
+			if tc.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tc.want, got)
+		})
+	}
+}
+
+func TestRejectOptionLikeReferences(t *testing.T) {
+	repo := &Repository{
+		RepoName:         "testRepo",
+		InstallationPath: t.TempDir(),
+		Config:           Config{URL: "https://example.com/{name}.git", Tag: "{version}"},
+	}
+	testCases := map[string]*app.Reference{
+		"name":    {Name: "--upload-pack=touch /tmp/pwned", Version: "1.0.0"},
+		"version": {Name: "app", Version: "-v1.0.0"},
+	}
+	for name, reference := range testCases {
+		t.Run(name, func(t *testing.T) {
+			_, err := repo.InstallApp(reference)
+			assert.ErrorContains(t, err, "must not start with '-'")
+			_, err = repo.HasApp(reference)
+			assert.ErrorContains(t, err, "must not start with '-'")
+		})
+	}
+}
@@ -0,0 +1,95 @@
+package oci
+
+import (
+	"fmt"
+
+	"github.com/B-S-F/onyx/pkg/repository"
+	"github.com/B-S-F/onyx/pkg/repository/app"
+	"github.com/B-S-F/onyx/pkg/repository/download"
+	"github.com/B-S-F/onyx/pkg/repository/signature"
+)
+
+const RegistryKey = "registry"
+const NamespaceKey = "namespace"
+const InsecureKey = "insecure"
+const RetriesKey = "retries"
+
+type Config struct {
+	// Host of the OCI registry
+	// Example "ghcr.io"
+	Registry string
+	// Namespace of the apps inside the registry
+	// Example "my-org/autopilots"
+	Namespace string
+	// Use plain http instead of https to talk to the registry
+	Insecure bool
+	// Retries of failed blob downloads, interrupted downloads are resumed
+	Retries int
+	// Auth configuration
+	Auth *Auth
+	// Archive configuration, if set the apps are archives which are extracted after download
+	Archive *app.ArchiveConfig
+}
+
+func (c Config) Type() string {
+	return "oci"
+}
+
+func newConfig(config map[string]interface{}) (repository.Config, error) {
+	authFactory := newAuthFactory()
+	if config[RegistryKey] == nil {
+		return nil, fmt.Errorf("missing '%s' in config", RegistryKey)
+	}
+	registry, ok := config[RegistryKey].(string)
+	if !ok || registry == "" {
+		return nil, fmt.Errorf("%s must be a non-empty string", RegistryKey)
+	}
+	var namespace string
+	if config[NamespaceKey] != nil {
+		namespace, ok = config[NamespaceKey].(string)
+		if !ok {
+			return nil, fmt.Errorf("%s must be a string", NamespaceKey)
+		}
+	}
+	var insecure bool
+	if config[InsecureKey] != nil {
+		insecure, ok = config[InsecureKey].(bool)
+		if !ok {
+			return nil, fmt.Errorf("%s must be a boolean", InsecureKey)
+		}
+	}
+	retries := download.DEFAULT_RETRIES
+	if config[RetriesKey] != nil {
+		retries, ok = config[RetriesKey].(int)
+		if !ok || retries < 0 {
+			return nil, fmt.Errorf("%s must be a non-negative integer", RetriesKey)
+		}
+	}
+	if config[signature.SignatureKey] != nil {
+		return nil, fmt.Errorf("signature verification is not supported by oci repositories, the digest of the manifest is verified instead")
+	}
+	archive, err := app.NewArchiveConfig(config)
+	if err != nil {
+		return nil, fmt.Errorf("error creating archive config: %w", err)
+	}
+	parsed := Config{
+		Registry:  registry,
+		Namespace: namespace,
+		Insecure:  insecure,
+		Retries:   retries,
+		Archive:   archive,
+	}
+	if config["auth"] == nil {
+		return parsed, nil
+	}
+	authConfig, ok := config["auth"].(map[string]interface{})
+	if !ok {
+		return nil, fmt.Errorf("auth must be a map")
+	}
+	auth, err := authFactory.newAuth(authConfig)
+	if err != nil {
+		return nil, fmt.Errorf("error creating auth: %w", err)
+	}
+	parsed.Auth = auth
+	return parsed, nil
+}
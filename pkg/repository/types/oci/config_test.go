@@ -0,0 +1,75 @@
+package oci
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewConfig(t *testing.T) {
+	t.Run("basic", func(t *testing.T) {
+		config, err := newConfig(map[string]interface{}{
+			"registry":  "ghcr.io",
+			"namespace": "my-org/autopilots",
+		})
+		assert.NoError(t, err)
+		assert.Equal(t, "oci", config.Type())
+		concreteConfig, ok := config.(Config)
+		assert.True(t, ok)
+		assert.Equal(t, "ghcr.io", concreteConfig.Registry)
+		assert.Equal(t, "my-org/autopilots", concreteConfig.Namespace)
+		assert.False(t, concreteConfig.Insecure)
+		assert.Nil(t, concreteConfig.Auth)
+	})
+
+	t.Run("with auth and insecure", func(t *testing.T) {
+		config, err := newConfig(map[string]interface{}{
+			"registry": "localhost:5000",
+			"insecure": true,
+			"auth": map[string]interface{}{
+				"type":  "token",
+				"token": "testToken",
+			},
+		})
+		assert.NoError(t, err)
+		concreteConfig := config.(Config)
+		assert.True(t, concreteConfig.Insecure)
+		assert.Equal(t, TokenAuthType, concreteConfig.Auth.Type)
+	})
+
+	t.Run("with missing registry", func(t *testing.T) {
+		_, err := newConfig(map[string]interface{}{
+			"namespace": "my-org",
+		})
+		assert.Error(t, err)
+		assert.Equal(t, "missing 'registry' in config", err.Error())
+	})
+
+	t.Run("with invalid insecure flag", func(t *testing.T) {
+		_, err := newConfig(map[string]interface{}{
+			"registry": "ghcr.io",
+			"insecure": "yes",
+		})
+		assert.Error(t, err)
+		assert.Equal(t, "insecure must be a boolean", err.Error())
+	})
+
+	t.Run("with invalid auth type", func(t *testing.T) {
+		_, err := newConfig(map[string]interface{}{
+			"registry": "ghcr.io",
+			"auth": map[string]interface{}{
+				"type": "unknown",
+			},
+		})
+		assert.Error(t, err)
+		assert.Equal(t, "error creating auth: auth type unknown is not supported, supported auth types are [basic token]", err.Error())
+	})
+
+	t.Run("with signature", func(t *testing.T) {
+		_, err := newConfig(map[string]interface{}{
+			"registry":  "ghcr.io",
+			"signature": map[string]interface{}{"type": "cosign", "public_key": "cosign.pub"},
+		})
+		assert.ErrorContains(t, err, "signature verification is not supported by oci repositories")
+	})
+}
@@ -0,0 +1,394 @@
+package oci
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/B-S-F/onyx/pkg/repository"
+	"github.com/B-S-F/onyx/pkg/repository/app"
+	"github.com/B-S-F/onyx/pkg/repository/download"
+)
+
+const DOWNLOAD_TIMEOUT = 30 * time.Second
+
+// maximum size of a manifest that is accepted from a registry
+const maxManifestSize = 4 * 1024 * 1024
+
+const (
+	ociManifestMediaType    = "application/vnd.oci.image.manifest.v1+json"
+	dockerManifestMediaType = "application/vnd.docker.distribution.manifest.v2+json"
+	ociIndexMediaType       = "application/vnd.oci.image.index.v1+json"
+	dockerListMediaType     = "application/vnd.docker.distribution.manifest.list.v2+json"
+	titleAnnotation         = "org.opencontainers.image.title"
+	digestPrefix            = "sha256:"
+)
+
+type Repository struct {
+	Config           Config
+	RepoName         string
+	InstallationPath string
+	client           *http.Client
+	// authorization headers per repository path, obtained by answering the registry challenges
+	authorizations map[string]string
+	mutex          sync.Mutex
+}
+
+type manifest struct {
+	MediaType string       `json:"mediaType"`
+	Layers    []descriptor `json:"layers"`
+}
+
+type descriptor struct {
+	MediaType   string            `json:"mediaType"`
+	Digest      string            `json:"digest"`
+	Size        int64             `json:"size"`
+	Annotations map[string]string `json:"annotations,omitempty"`
+}
+
+func NewRepository(name string, installationPath string, config map[string]interface{}) (repository.Repository, error) {
+	if config == nil {
+		return nil, fmt.Errorf("config is nil")
+	}
+	parsed, err := newConfig(config)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create config: %w", err)
+	}
+	return &Repository{
+		Config:           parsed.(Config),
+		RepoName:         name,
+		InstallationPath: installationPath,
+		client: &http.Client{
+			Timeout: DOWNLOAD_TIMEOUT,
+		},
+		authorizations: make(map[string]string),
+	}, nil
+}
+
+// InstallApp pulls the artifact layer of <registry>/<namespace>/<name>:<version> (or @<digest>),
+// verifies it against the manifest and uses the manifest digest as the app checksum
+func (r *Repository) InstallApp(appReference *app.Reference) (app.App, error) {
+	if r.InstallationPath == "" {
+		return nil, fmt.Errorf("installation path is not set")
+	}
+	manifestDigest, manifest, err := r.fetchManifest(appReference.Name, appReference.Version)
+	if err != nil {
+		return nil, fmt.Errorf("failed to install app %s: %w", appReference, err)
+	}
+	layer, err := selectLayer(manifest, appReference.Name)
+	if err != nil {
+		return nil, fmt.Errorf("failed to install app %s from %s: %w", appReference, r.reference(appReference.Name, appReference.Version), err)
+	}
+	outputPath := app.InstallationPath(r.InstallationPath, r.RepoName, appReference.Name, appReference.Version)
+	outputDir := filepath.Dir(outputPath)
+	err = os.MkdirAll(outputDir, os.ModePerm)
+	if err != nil {
+		return nil, fmt.Errorf("failed to install app %s: %w", appReference, err)
+	}
+	err = r.downloadBlob(appReference.Name, layer, outputPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to install app %s: %w", appReference, err)
+	}
+	provenance := app.Provenance{Source: r.reference(appReference.Name, appReference.Version)}
+	if r.Config.Archive != nil {
+		installed, err := app.NewArchiveApp(r.RepoName, appReference, manifestDigest, outputPath, r.Config.Archive)
+		if err != nil {
+			return nil, fmt.Errorf("failed to install app %s: %w", appReference, err)
+		}
+		return app.WithProvenance(installed, provenance), nil
+	}
+	return app.WithProvenance(app.NewBinaryApp(r.RepoName, appReference.Name, appReference.Version, manifestDigest, outputPath), provenance), nil
+}
+
+// reference returns the full OCI reference of an app, e.g. ghcr.io/my-org/my-app:1.0.0
+func (r *Repository) reference(appName, appVersion string) string {
+	separator := ":"
+	if isDigest(appVersion) {
+		separator = "@"
+	}
+	return r.Config.Registry + "/" + r.repositoryPath(appName) + separator + appVersion
+}
+
+func (r *Repository) repositoryPath(appName string) string {
+	return path.Join(r.Config.Namespace, appName)
+}
+
+func (r *Repository) baseURL() string {
+	scheme := "https"
+	if r.Config.Insecure {
+		scheme = "http"
+	}
+	return scheme + "://" + r.Config.Registry
+}
+
+// fetchManifest downloads the manifest of the given app and verifies its digest
+func (r *Repository) fetchManifest(appName, appVersion string) (string, *manifest, error) {
+	repositoryPath := r.repositoryPath(appName)
+	request, err := http.NewRequest(http.MethodGet, fmt.Sprintf("%s/v2/%s/manifests/%s", r.baseURL(), repositoryPath, appVersion), nil)
+	if err != nil {
+		return "", nil, fmt.Errorf("error creating manifest request: %w", err)
+	}
+	request.Header.Set("Accept", strings.Join([]string{ociManifestMediaType, dockerManifestMediaType}, ", "))
+	response, err := r.do(request, repositoryPath)
+	if err != nil {
+		return "", nil, fmt.Errorf("error fetching manifest of %s: %w", r.reference(appName, appVersion), err)
+	}
+	defer response.Body.Close()
+	if response.StatusCode != http.StatusOK {
+		return "", nil, fmt.Errorf("error fetching manifest of %s: %s", r.reference(appName, appVersion), response.Status)
+	}
+	content, err := io.ReadAll(io.LimitReader(response.Body, maxManifestSize+1))
+	if err != nil {
+		return "", nil, fmt.Errorf("error reading manifest of %s: %w", r.reference(appName, appVersion), err)
+	}
+	if len(content) > maxManifestSize {
+		return "", nil, fmt.Errorf("manifest of %s exceeds %d bytes", r.reference(appName, appVersion), maxManifestSize)
+	}
+	digest := calculateDigest(content)
+	if contentDigest := response.Header.Get("Docker-Content-Digest"); contentDigest != "" && contentDigest != digest {
+		return "", nil, fmt.Errorf("manifest digest mismatch for %s: registry announced %s but content has %s", r.reference(appName, appVersion), contentDigest, digest)
+	}
+	if isDigest(appVersion) && appVersion != digest {
+		return "", nil, fmt.Errorf("manifest digest mismatch for %s: content has %s", r.reference(appName, appVersion), digest)
+	}
+	var parsed manifest
+	err = json.Unmarshal(content, &parsed)
+	if err != nil {
+		return "", nil, fmt.Errorf("error parsing manifest of %s: %w", r.reference(appName, appVersion), err)
+	}
+	if parsed.MediaType == ociIndexMediaType || parsed.MediaType == dockerListMediaType {
+		return "", nil, fmt.Errorf("%s references an image index which is not supported, please reference a single artifact", r.reference(appName, appVersion))
+	}
+	return digest, &parsed, nil
+}
+
+// selectLayer returns the layer containing the app executable.
+// If the artifact contains more than one layer, the layer annotated with the app name as title is used.
+func selectLayer(m *manifest, appName string) (*descriptor, error) {
+	if len(m.Layers) == 0 {
+		return nil, fmt.Errorf("manifest does not contain any layers")
+	}
+	if len(m.Layers) == 1 {
+		return &m.Layers[0], nil
+	}
+	for index := range m.Layers {
+		if m.Layers[index].Annotations[titleAnnotation] == appName {
+			return &m.Layers[index], nil
+		}
+	}
+	return nil, fmt.Errorf("manifest contains %d layers and none is annotated with '%s: %s'", len(m.Layers), titleAnnotation, appName)
+}
+
+// downloadBlob downloads the layer into outputPath, verifies its digest and makes it executable
+func (r *Repository) downloadBlob(appName string, layer *descriptor, outputPath string) error {
+	if !isDigest(layer.Digest) {
+		return fmt.Errorf("unsupported layer digest '%s'", layer.Digest)
+	}
+	repositoryPath := r.repositoryPath(appName)
+	downloader := download.New(r.Config.Retries, func(request *http.Request) (*http.Response, error) {
+		return r.do(request, repositoryPath)
+	})
+	err := downloader.Download(fmt.Sprintf("%s/v2/%s/blobs/%s", r.baseURL(), repositoryPath, layer.Digest), outputPath)
+	if err != nil {
+		return fmt.Errorf("error downloading layer %s: %w", layer.Digest, err)
+	}
+	// the digest is verified after the download, since interrupted downloads are resumed
+	checksum, err := app.CalculateFileChecksum(outputPath)
+	if err != nil {
+		return err
+	}
+	if digest := digestPrefix + checksum; digest != layer.Digest {
+		_ = os.Remove(outputPath)
+		return fmt.Errorf("layer digest mismatch: expected %s but downloaded content has %s", layer.Digest, digest)
+	}
+	err = os.Chmod(outputPath, 0755)
+	if err != nil {
+		return fmt.Errorf("error changing file permissions: %w", err)
+	}
+	return nil
+}
+
+// HasApp checks if the manifest of the app exists without downloading it
+func (r *Repository) HasApp(appReference *app.Reference) (bool, error) {
+	repositoryPath := r.repositoryPath(appReference.Name)
+	request, err := http.NewRequest(http.MethodHead, fmt.Sprintf("%s/v2/%s/manifests/%s", r.baseURL(), repositoryPath, appReference.Version), nil)
+	if err != nil {
+		return false, fmt.Errorf("error creating manifest request: %w", err)
+	}
+	request.Header.Set("Accept", strings.Join([]string{ociManifestMediaType, dockerManifestMediaType}, ", "))
+	response, err := r.do(request, repositoryPath)
+	if err != nil {
+		return false, fmt.Errorf("error checking manifest of %s: %w", r.reference(appReference.Name, appReference.Version), err)
+	}
+	response.Body.Close()
+	switch response.StatusCode {
+	case http.StatusOK:
+		return true, nil
+	case http.StatusNotFound:
+		return false, nil
+	default:
+		return false, fmt.Errorf("error checking manifest of %s: %s", r.reference(appReference.Name, appReference.Version), response.Status)
+	}
+}
+
+func (r *Repository) do(request *http.Request, repositoryPath string) (*http.Response, error) {
+	r.authorize(request, repositoryPath)
+	response, err := r.client.Do(request)
+	if err != nil {
+		return nil, err
+	}
+	if response.StatusCode != http.StatusUnauthorized {
+		return response, nil
+	}
+	challenge := response.Header.Get("WWW-Authenticate")
+	response.Body.Close()
+	err = r.answerChallenge(challenge, repositoryPath)
+	if err != nil {
+		return nil, err
+	}
+	retry := request.Clone(request.Context())
+	r.authorize(retry, repositoryPath)
+	return r.client.Do(retry)
+}
+
+func (r *Repository) authorize(request *http.Request, repositoryPath string) {
+	r.mutex.Lock()
+	authorization, ok := r.authorizations[repositoryPath]
+	r.mutex.Unlock()
+	if ok {
+		request.Header.Set("Authorization", authorization)
+		return
+	}
+	if r.Config.Auth != nil && r.Config.Auth.Type == TokenAuthType {
+		request.Header.Set("Authorization", r.Config.Auth.Config.Header())
+	}
+}
+
+// answerChallenge obtains the authorization requested in a WWW-Authenticate header
+func (r *Repository) answerChallenge(challenge string, repositoryPath string) error {
+	scheme, params := parseChallenge(challenge)
+	var authorization string
+	switch strings.ToLower(scheme) {
+	case "basic":
+		if r.Config.Auth == nil || r.Config.Auth.Type != BasicAuthType {
+			return fmt.Errorf("registry requires basic authentication but no basic auth is configured")
+		}
+		authorization = r.Config.Auth.Config.Header()
+	case "bearer":
+		token, err := r.fetchToken(params, repositoryPath)
+		if err != nil {
+			return err
+		}
+		authorization = "Bearer " + token
+	default:
+		return fmt.Errorf("registry responded with unsupported authentication challenge '%s'", challenge)
+	}
+	r.mutex.Lock()
+	r.authorizations[repositoryPath] = authorization
+	r.mutex.Unlock()
+	return nil
+}
+
+// fetchToken requests a pull token from the token service announced by the registry
+func (r *Repository) fetchToken(params map[string]string, repositoryPath string) (string, error) {
+	realm := params["realm"]
+	if realm == "" {
+		return "", fmt.Errorf("registry bearer challenge does not contain a realm")
+	}
+	tokenURL, err := url.Parse(realm)
+	if err != nil {
+		return "", fmt.Errorf("error parsing token realm: %w", err)
+	}
+	query := tokenURL.Query()
+	if params["service"] != "" {
+		query.Set("service", params["service"])
+	}
+	scope := params["scope"]
+	if scope == "" {
+		scope = fmt.Sprintf("repository:%s:pull", repositoryPath)
+	}
+	query.Set("scope", scope)
+	tokenURL.RawQuery = query.Encode()
+	request, err := http.NewRequest(http.MethodGet, tokenURL.String(), nil)
+	if err != nil {
+		return "", fmt.Errorf("error creating token request: %w", err)
+	}
+	if r.Config.Auth != nil && r.Config.Auth.Type == BasicAuthType {
+		request.Header.Set("Authorization", r.Config.Auth.Config.Header())
+	}
+	response, err := r.client.Do(request)
+	if err != nil {
+		return "", fmt.Errorf("error requesting token: %w", err)
+	}
+	defer response.Body.Close()
+	if response.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("error requesting token: %s", response.Status)
+	}
+	var tokenResponse struct {
+		Token       string `json:"token"`
+		AccessToken string `json:"access_token"`
+	}
+	err = json.NewDecoder(response.Body).Decode(&tokenResponse)
+	if err != nil {
+		return "", fmt.Errorf("error parsing token response: %w", err)
+	}
+	if tokenResponse.Token != "" {
+		return tokenResponse.Token, nil
+	}
+	if tokenResponse.AccessToken != "" {
+		return tokenResponse.AccessToken, nil
+	}
+	return "", fmt.Errorf("token response does not contain a token")
+}
+
+// parseChallenge splits a WWW-Authenticate header like
+// `Bearer realm="https://auth.example.com/token",service="registry",scope="repository:app:pull"`
+// into its scheme and parameters
+func parseChallenge(challenge string) (string, map[string]string) {
+	params := make(map[string]string)
+	challenge = strings.TrimSpace(challenge)
+	scheme, rest, _ := strings.Cut(challenge, " ")
+	for rest = strings.TrimSpace(rest); rest != ""; rest = strings.TrimSpace(rest) {
+		key, value, found := strings.Cut(rest, "=")
+		if !found {
+			break
+		}
+		key = strings.ToLower(strings.TrimSpace(key))
+		if strings.HasPrefix(value, `"`) {
+			end := strings.Index(value[1:], `"`)
+			if end < 0 {
+				params[key] = value[1:]
+				break
+			}
+			params[key] = value[1 : end+1]
+			rest = strings.TrimPrefix(strings.TrimSpace(value[end+2:]), ",")
+			continue
+		}
+		value, rest, _ = strings.Cut(value, ",")
+		params[key] = strings.TrimSpace(value)
+	}
+	return scheme, params
+}
+
+func calculateDigest(content []byte) string {
+	hash := sha256.Sum256(content)
+	return digestPrefix + hex.EncodeToString(hash[:])
+}
+
+func isDigest(value string) bool {
+	return strings.HasPrefix(value, digestPrefix)
+}
+
+func (r *Repository) Name() string {
+	return r.RepoName
+}
@@ -0,0 +1,175 @@
+//go:build integration
+// +build integration
+
+package oci
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/B-S-F/onyx/pkg/repository/app"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeRegistry is a minimal in-process implementation of the OCI distribution API
+// which protects all endpoints with a token challenge
+type fakeRegistry struct {
+	server    *httptest.Server
+	manifests map[string][]byte
+	blobs     map[string][]byte
+	username  string
+	password  string
+}
+
+func newFakeRegistry(t *testing.T) *fakeRegistry {
+	registry := &fakeRegistry{
+		manifests: make(map[string][]byte),
+		blobs:     make(map[string][]byte),
+		username:  "user",
+		password:  "pass",
+	}
+	mux := http.NewServeMux()
+	mux.HandleFunc("/token", func(w http.ResponseWriter, r *http.Request) {
+		username, password, ok := r.BasicAuth()
+		if !ok || username != registry.username || password != registry.password {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		_ = json.NewEncoder(w).Encode(map[string]string{"token": "token-for-" + r.URL.Query().Get("scope")})
+	})
+	mux.HandleFunc("/v2/", func(w http.ResponseWriter, r *http.Request) {
+		path := strings.TrimPrefix(r.URL.Path, "/v2/")
+		var repositoryPath, kind, reference string
+		if index := strings.LastIndex(path, "/manifests/"); index >= 0 {
+			repositoryPath, kind, reference = path[:index], "manifests", path[index+len("/manifests/"):]
+		} else if index := strings.LastIndex(path, "/blobs/"); index >= 0 {
+			repositoryPath, kind, reference = path[:index], "blobs", path[index+len("/blobs/"):]
+		}
+		scope := fmt.Sprintf("repository:%s:pull", repositoryPath)
+		if r.Header.Get("Authorization") != "Bearer token-for-"+scope {
+			w.Header().Set("WWW-Authenticate", fmt.Sprintf(`Bearer realm="%s/token",service="fake",scope="%s"`, registry.server.URL, scope))
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		switch kind {
+		case "manifests":
+			content, ok := registry.manifests[repositoryPath+":"+reference]
+			if !ok {
+				w.WriteHeader(http.StatusNotFound)
+				return
+			}
+			w.Header().Set("Content-Type", ociManifestMediaType)
+			w.Header().Set("Docker-Content-Digest", calculateDigest(content))
+			_, _ = w.Write(content)
+		case "blobs":
+			content, ok := registry.blobs[reference]
+			if !ok {
+				w.WriteHeader(http.StatusNotFound)
+				return
+			}
+			_, _ = w.Write(content)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	})
+	registry.server = httptest.NewServer(mux)
+	t.Cleanup(registry.server.Close)
+	return registry
+}
+
+// push stores an artifact with a single layer and returns the manifest digest
+func (f *fakeRegistry) push(t *testing.T, repositoryPath, tag string, content []byte) string {
+	layerDigest := calculateDigest(content)
+	f.blobs[layerDigest] = content
+	manifestContent, err := json.Marshal(manifest{
+		MediaType: ociManifestMediaType,
+		Layers: []descriptor{
+			{MediaType: "application/octet-stream", Digest: layerDigest, Size: int64(len(content))},
+		},
+	})
+	require.NoError(t, err)
+	manifestDigest := calculateDigest(manifestContent)
+	f.manifests[repositoryPath+":"+tag] = manifestContent
+	f.manifests[repositoryPath+":"+manifestDigest] = manifestContent
+	return manifestDigest
+}
+
+func (f *fakeRegistry) host() string {
+	return strings.TrimPrefix(f.server.URL, "http://")
+}
+
+func TestInstallApp(t *testing.T) {
+	registry := newFakeRegistry(t)
+	content := []byte("#!/bin/bash\necho hello\n")
+	manifestDigest := registry.push(t, "autopilots/testApp", "1.0.0", content)
+
+	newRepo := func(t *testing.T, auth map[string]interface{}) *Repository {
+		config := map[string]interface{}{
+			"registry":  registry.host(),
+			"namespace": "autopilots",
+			"insecure":  true,
+		}
+		if auth != nil {
+			config["auth"] = auth
+		}
+		repo, err := NewRepository("testRepo", t.TempDir(), config)
+		require.NoError(t, err)
+		return repo.(*Repository)
+	}
+	basicAuth := map[string]interface{}{"type": "basic", "username": "user", "password": "pass"}
+
+	t.Run("should install app by tag", func(t *testing.T) {
+		repo := newRepo(t, basicAuth)
+		installed, err := repo.InstallApp(&app.Reference{Name: "testApp", Version: "1.0.0"})
+		require.NoError(t, err)
+		assert.Equal(t, manifestDigest, installed.Checksum())
+		downloaded, err := os.ReadFile(installed.ExecutablePath())
+		require.NoError(t, err)
+		assert.Equal(t, content, downloaded)
+		info, err := os.Stat(installed.ExecutablePath())
+		require.NoError(t, err)
+		assert.Equal(t, os.FileMode(0755), info.Mode().Perm())
+	})
+
+	t.Run("should install app by digest", func(t *testing.T) {
+		repo := newRepo(t, basicAuth)
+		installed, err := repo.InstallApp(&app.Reference{Name: "testApp", Version: manifestDigest})
+		require.NoError(t, err)
+		assert.Equal(t, manifestDigest, installed.Checksum())
+	})
+
+	t.Run("should fail with wrong credentials", func(t *testing.T) {
+		repo := newRepo(t, map[string]interface{}{"type": "basic", "username": "user", "password": "wrong"})
+		_, err := repo.InstallApp(&app.Reference{Name: "testApp", Version: "1.0.0"})
+		assert.ErrorContains(t, err, "error requesting token: 401 Unauthorized")
+	})
+
+	t.Run("should fail for unknown tag", func(t *testing.T) {
+		repo := newRepo(t, basicAuth)
+		_, err := repo.InstallApp(&app.Reference{Name: "testApp", Version: "2.0.0"})
+		assert.ErrorContains(t, err, "404 Not Found")
+	})
+
+	t.Run("should fail if the manifest does not match the requested digest", func(t *testing.T) {
+		otherDigest := "sha256:0123456789abcdef0123456789abcdef0123456789abcdef0123456789abcdef"
+		registry.manifests["autopilots/testApp:"+otherDigest] = registry.manifests["autopilots/testApp:1.0.0"]
+		repo := newRepo(t, basicAuth)
+		_, err := repo.InstallApp(&app.Reference{Name: "testApp", Version: otherDigest})
+		assert.ErrorContains(t, err, "manifest digest mismatch")
+	})
+
+	t.Run("should fail if the layer content does not match its digest", func(t *testing.T) {
+		tamperedDigest := registry.push(t, "autopilots/tampered", "1.0.0", []byte("original"))
+		assert.NotEmpty(t, tamperedDigest)
+		registry.blobs[calculateDigest([]byte("original"))] = []byte("tampered")
+		repo := newRepo(t, basicAuth)
+		_, err := repo.InstallApp(&app.Reference{Name: "tampered", Version: "1.0.0"})
+		assert.ErrorContains(t, err, "layer digest mismatch")
+	})
+}
@@ -0,0 +1,74 @@
+package oci
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const testDigest = "sha256:0123456789abcdef0123456789abcdef0123456789abcdef0123456789abcdef"
+
+func TestNewRepository(t *testing.T) {
+	repo, err := NewRepository("testRepo", "/path/to/install", map[string]interface{}{
+		"registry": "ghcr.io",
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, "testRepo", repo.Name())
+
+	_, err = NewRepository("testRepo", "/path/to/install", nil)
+	assert.Error(t, err)
+}
+
+func TestReference(t *testing.T) {
+	repo, err := NewRepository("testRepo", t.TempDir(), map[string]interface{}{
+		"registry":  "ghcr.io",
+		"namespace": "my-org/autopilots",
+	})
+	assert.NoError(t, err)
+	concreteRepo := repo.(*Repository)
+
+	assert.Equal(t, "ghcr.io/my-org/autopilots/app:1.0.0", concreteRepo.reference("app", "1.0.0"))
+	assert.Equal(t, "ghcr.io/my-org/autopilots/app@"+testDigest, concreteRepo.reference("app", testDigest))
+	assert.Equal(t, "https://ghcr.io", concreteRepo.baseURL())
+}
+
+func TestSelectLayer(t *testing.T) {
+	t.Run("single layer", func(t *testing.T) {
+		layer, err := selectLayer(&manifest{Layers: []descriptor{{Digest: testDigest}}}, "app")
+		assert.NoError(t, err)
+		assert.Equal(t, testDigest, layer.Digest)
+	})
+
+	t.Run("layer annotated with app name", func(t *testing.T) {
+		layer, err := selectLayer(&manifest{Layers: []descriptor{
+			{Digest: "sha256:other", Annotations: map[string]string{titleAnnotation: "README.md"}},
+			{Digest: testDigest, Annotations: map[string]string{titleAnnotation: "app"}},
+		}}, "app")
+		assert.NoError(t, err)
+		assert.Equal(t, testDigest, layer.Digest)
+	})
+
+	t.Run("ambiguous layers", func(t *testing.T) {
+		_, err := selectLayer(&manifest{Layers: []descriptor{{Digest: "sha256:a"}, {Digest: "sha256:b"}}}, "app")
+		assert.Error(t, err)
+	})
+
+	t.Run("no layers", func(t *testing.T) {
+		_, err := selectLayer(&manifest{}, "app")
+		assert.Error(t, err)
+	})
+}
+
+func TestParseChallenge(t *testing.T) {
+	scheme, params := parseChallenge(`Bearer realm="https://auth.example.com/token",service="registry.example.com",scope="repository:org/app:pull,push"`)
+	assert.Equal(t, "Bearer", scheme)
+	assert.Equal(t, map[string]string{
+		"realm":   "https://auth.example.com/token",
+		"service": "registry.example.com",
+		"scope":   "repository:org/app:pull,push",
+	}, params)
+
+	scheme, params = parseChallenge(`Basic realm=registry`)
+	assert.Equal(t, "Basic", scheme)
+	assert.Equal(t, map[string]string{"realm": "registry"}, params)
+}
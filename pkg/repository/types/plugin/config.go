@@ -0,0 +1,60 @@
+package plugin
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/B-S-F/onyx/pkg/repository"
+	"github.com/B-S-F/onyx/pkg/repository/app"
+)
+
+const DEFAULT_TIMEOUT = 10 * time.Minute
+
+type Config struct {
+	// Repository type which is served by the plugin
+	RepositoryType string
+	// Path of the plugin executable
+	Executable string
+	// Settings are passed unchanged to the plugin, it is responsible for validating them
+	Settings map[string]interface{}
+	// Archive configuration, if set the apps returned by the plugin are archives which are extracted by onyx
+	Archive *app.ArchiveConfig
+	// Timeout of a single plugin call
+	Timeout time.Duration
+}
+
+func (c Config) Type() string {
+	return c.RepositoryType
+}
+
+func newConfig(repositoryType string, executable string, config map[string]interface{}) (repository.Config, error) {
+	archive, err := app.NewArchiveConfig(config)
+	if err != nil {
+		return nil, fmt.Errorf("error creating archive config: %w", err)
+	}
+	timeout := DEFAULT_TIMEOUT
+	if config["timeout"] != nil {
+		value, ok := config["timeout"].(string)
+		if !ok {
+			return nil, fmt.Errorf("timeout must be a string")
+		}
+		timeout, err = time.ParseDuration(value)
+		if err != nil || timeout <= 0 {
+			return nil, fmt.Errorf("timeout must be a positive duration like '30s'")
+		}
+	}
+	settings := make(map[string]interface{}, len(config))
+	for key, value := range config {
+		if key == app.ArchiveKey || key == "timeout" {
+			continue
+		}
+		settings[key] = value
+	}
+	return Config{
+		RepositoryType: repositoryType,
+		Executable:     executable,
+		Settings:       settings,
+		Archive:        archive,
+		Timeout:        timeout,
+	}, nil
+}
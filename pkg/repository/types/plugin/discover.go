@@ -0,0 +1,41 @@
+package plugin
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// EXECUTABLE_PREFIX of plugin executables, the remainder of the file name is the repository type
+const EXECUTABLE_PREFIX = "onyx-repository-"
+
+// Discover searches the directories of the PATH like list for plugin executables.
+// The result maps repository types to executables, like the shell the first match in the PATH wins.
+func Discover(path string) map[string]string {
+	plugins := map[string]string{}
+	for _, directory := range filepath.SplitList(path) {
+		if directory == "" {
+			continue
+		}
+		entries, err := os.ReadDir(directory)
+		if err != nil {
+			continue
+		}
+		for _, entry := range entries {
+			repositoryType := strings.TrimPrefix(entry.Name(), EXECUTABLE_PREFIX)
+			if repositoryType == entry.Name() || repositoryType == "" {
+				continue
+			}
+			if _, ok := plugins[repositoryType]; ok {
+				continue
+			}
+			executable := filepath.Join(directory, entry.Name())
+			info, err := os.Stat(executable)
+			if err != nil || info.IsDir() || info.Mode().Perm()&0111 == 0 {
+				continue
+			}
+			plugins[repositoryType] = executable
+		}
+	}
+	return plugins
+}
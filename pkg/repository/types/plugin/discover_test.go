@@ -0,0 +1,52 @@
+package plugin
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDiscover(t *testing.T) {
+	first := t.TempDir()
+	second := t.TempDir()
+	write := func(directory string, name string, mode os.FileMode) string {
+		path := filepath.Join(directory, name)
+		require.NoError(t, os.WriteFile(path, []byte("#!/bin/sh\n"), mode))
+		return path
+	}
+	artifactory := write(first, "onyx-repository-artifactory", 0755)
+	write(first, "onyx-repository-disabled", 0644)
+	write(first, "other-tool", 0755)
+	write(second, "onyx-repository-artifactory", 0755)
+	nexus := write(second, "onyx-repository-nexus", 0755)
+	require.NoError(t, os.Mkdir(filepath.Join(second, "onyx-repository-directory"), 0755))
+
+	plugins := Discover(strings.Join([]string{first, "", filepath.Join(first, "missing"), second}, string(os.PathListSeparator)))
+
+	assert.Equal(t, map[string]string{"artifactory": artifactory, "nexus": nexus}, plugins)
+}
+
+func TestNewConfig(t *testing.T) {
+	t.Run("settings are passed without the onyx settings", func(t *testing.T) {
+		config, err := newConfig("nexus", "/bin/onyx-repository-nexus", map[string]interface{}{
+			"url":     "https://nexus.example.com",
+			"timeout": "30s",
+			"archive": map[string]interface{}{},
+		})
+		require.NoError(t, err)
+		parsed := config.(Config)
+		assert.Equal(t, "nexus", parsed.Type())
+		assert.Equal(t, map[string]interface{}{"url": "https://nexus.example.com"}, parsed.Settings)
+		assert.NotNil(t, parsed.Archive)
+		assert.Equal(t, "30s", parsed.Timeout.String())
+	})
+
+	t.Run("invalid timeout", func(t *testing.T) {
+		_, err := newConfig("nexus", "/bin/onyx-repository-nexus", map[string]interface{}{"timeout": "soon"})
+		assert.EqualError(t, err, "timeout must be a positive duration like '30s'")
+	})
+}
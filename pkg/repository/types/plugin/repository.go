@@ -0,0 +1,179 @@
+package plugin
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/B-S-F/onyx/pkg/logger"
+	"github.com/B-S-F/onyx/pkg/repository"
+	"github.com/B-S-F/onyx/pkg/repository/app"
+	"github.com/B-S-F/onyx/pkg/repository/download"
+)
+
+// API_VERSION of the protocol between onyx and the plugins
+const API_VERSION = "v1"
+
+// Request is written as JSON to stdin of the plugin
+type Request struct {
+	APIVersion string `json:"apiVersion"`
+	// Name of the repository in the execution plan
+	Repository string `json:"repository"`
+	// Type of the repository, the suffix of the plugin executable
+	Type string `json:"type"`
+	// Config of the repository without the settings handled by onyx
+	Config map[string]interface{} `json:"config"`
+	App    RequestApp             `json:"app"`
+	// InstallationPath is the suggested file path for the app, its directory exists already
+	InstallationPath string `json:"installationPath"`
+}
+
+type RequestApp struct {
+	Name    string `json:"name"`
+	Version string `json:"version"`
+}
+
+// Response is read as JSON from stdout of the plugin
+type Response struct {
+	// Path of the installed app, it must be located in the directory of the installation path
+	Path string `json:"path"`
+	// Checksum is the hex encoded sha256 of the installed app
+	Checksum string `json:"checksum"`
+	// Source the app was downloaded from, credentials are removed before it is reported
+	Source string `json:"source,omitempty"`
+}
+
+type Repository struct {
+	Config           Config
+	RepoName         string
+	InstallationPath string
+}
+
+// NewFactory returns the constructor of repositories which are served by the plugin executable
+func NewFactory(repositoryType string, executable string) func(name string, installationPath string, config map[string]interface{}) (repository.Repository, error) {
+	return func(name string, installationPath string, config map[string]interface{}) (repository.Repository, error) {
+		if config == nil {
+			config = map[string]interface{}{}
+		}
+		parsed, err := newConfig(repositoryType, executable, config)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create config: %w", err)
+		}
+		return &Repository{
+			Config:           parsed.(Config),
+			RepoName:         name,
+			InstallationPath: installationPath,
+		}, nil
+	}
+}
+
+func (r *Repository) InstallApp(appReference *app.Reference) (app.App, error) {
+	if r.InstallationPath == "" {
+		return nil, fmt.Errorf("installation path is not set")
+	}
+	outputPath := app.InstallationPath(r.InstallationPath, r.RepoName, appReference.Name, appReference.Version)
+	err := os.MkdirAll(filepath.Dir(outputPath), os.ModePerm)
+	if err != nil {
+		return nil, fmt.Errorf("failed to install app %s: %w", appReference, err)
+	}
+
+	response, err := r.call(Request{
+		APIVersion:       API_VERSION,
+		Repository:       r.RepoName,
+		Type:             r.Config.RepositoryType,
+		Config:           r.Config.Settings,
+		App:              RequestApp{Name: appReference.Name, Version: appReference.Version},
+		InstallationPath: outputPath,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to install app %s: %w", appReference, err)
+	}
+	installedPath, err := r.validatePath(response.Path, filepath.Dir(outputPath))
+	if err != nil {
+		return nil, fmt.Errorf("failed to install app %s: %w", appReference, err)
+	}
+	checksum, err := app.CalculateFileChecksum(installedPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to install app %s: %w", appReference, err)
+	}
+	if !strings.EqualFold(checksum, response.Checksum) {
+		return nil, fmt.Errorf("failed to install app %s: checksum '%s' of plugin response does not match checksum '%s' of the installed app", appReference, response.Checksum, checksum)
+	}
+
+	provenance := app.Provenance{Source: download.RedactURL(response.Source)}
+	if r.Config.Archive != nil {
+		installed, err := app.NewArchiveApp(r.RepoName, appReference, checksum, installedPath, r.Config.Archive)
+		if err != nil {
+			return nil, fmt.Errorf("failed to install app %s: %w", appReference, err)
+		}
+		return app.WithProvenance(installed, provenance), nil
+	}
+	err = os.Chmod(installedPath, 0755)
+	if err != nil {
+		return nil, fmt.Errorf("failed to install app %s: error changing file permissions: %w", appReference, err)
+	}
+	return app.WithProvenance(app.NewBinaryApp(r.RepoName, appReference.Name, appReference.Version, checksum, installedPath), provenance), nil
+}
+
+// call runs the plugin with the request on stdin, the output on stderr is logged and added to errors
+func (r *Repository) call(request Request) (*Response, error) {
+	input, err := json.Marshal(request)
+	if err != nil {
+		return nil, fmt.Errorf("error encoding plugin request: %w", err)
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), r.Config.Timeout)
+	defer cancel()
+
+	var stdout, stderr bytes.Buffer
+	cmd := exec.CommandContext(ctx, r.Config.Executable)
+	cmd.Stdin = bytes.NewReader(input)
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	err = cmd.Run()
+	if stderr.Len() > 0 {
+		logger.Get().Debugf("plugin %s: %s", filepath.Base(r.Config.Executable), strings.TrimSpace(stderr.String()))
+	}
+	if ctx.Err() == context.DeadlineExceeded {
+		return nil, fmt.Errorf("plugin %s timed out after %s", r.Config.Executable, r.Config.Timeout)
+	}
+	if err != nil {
+		if message := strings.TrimSpace(stderr.String()); message != "" {
+			return nil, fmt.Errorf("plugin %s failed: %w: %s", r.Config.Executable, err, message)
+		}
+		return nil, fmt.Errorf("plugin %s failed: %w", r.Config.Executable, err)
+	}
+
+	var response Response
+	err = json.Unmarshal(stdout.Bytes(), &response)
+	if err != nil {
+		return nil, fmt.Errorf("error decoding response of plugin %s: %w", r.Config.Executable, err)
+	}
+	if response.Path == "" {
+		return nil, fmt.Errorf("missing 'path' in response of plugin %s", r.Config.Executable)
+	}
+	if response.Checksum == "" {
+		return nil, fmt.Errorf("missing 'checksum' in response of plugin %s", r.Config.Executable)
+	}
+	return &response, nil
+}
+
+// validatePath ensures that plugins only return apps in the directory which was reserved for the app
+func (r *Repository) validatePath(path string, directory string) (string, error) {
+	if !filepath.IsAbs(path) {
+		path = filepath.Join(directory, path)
+	}
+	relative, err := filepath.Rel(directory, filepath.Clean(path))
+	if err != nil || relative == ".." || strings.HasPrefix(relative, ".."+string(filepath.Separator)) {
+		return "", fmt.Errorf("plugin returned path %s outside of %s", path, directory)
+	}
+	return filepath.Clean(path), nil
+}
+
+func (r *Repository) Name() string {
+	return r.RepoName
+}
@@ -0,0 +1,80 @@
+//go:build integration
+// +build integration
+
+package plugin
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/B-S-F/onyx/pkg/repository/app"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// writePlugin creates a plugin which runs the script with the request in the variable REQUEST
+func writePlugin(t *testing.T, script string) string {
+	path := filepath.Join(t.TempDir(), EXECUTABLE_PREFIX+"test")
+	require.NoError(t, os.WriteFile(path, []byte("#!/bin/sh\nREQUEST=$(cat)\n"+script), 0755))
+	return path
+}
+
+func TestInstallApp(t *testing.T) {
+	t.Run("app is installed by the plugin", func(t *testing.T) {
+		executable := writePlugin(t, `
+TARGET=$(echo "$REQUEST" | sed 's/.*"installationPath":"\([^"]*\)".*/\1/')
+echo "$REQUEST" > "$TARGET"
+echo "{\"path\":\"$TARGET\",\"checksum\":\"$(sha256sum "$TARGET" | cut -d' ' -f1)\"}"
+`)
+		installationPath := t.TempDir()
+		repo, err := NewFactory("test", executable)("testRepo", installationPath, map[string]interface{}{"url": "https://store.example.com"})
+		require.NoError(t, err)
+
+		installed, err := repo.InstallApp(&app.Reference{Name: "testApp", Version: "1.0.0"})
+		require.NoError(t, err)
+		expectedPath := app.InstallationPath(installationPath, "testRepo", "testApp", "1.0.0")
+		assert.Equal(t, expectedPath, installed.Executables()[0].Path)
+		request, err := os.ReadFile(expectedPath)
+		require.NoError(t, err)
+		assert.JSONEq(t, `{
+			"apiVersion": "v1",
+			"repository": "testRepo",
+			"type": "test",
+			"config": {"url": "https://store.example.com"},
+			"app": {"name": "testApp", "version": "1.0.0"},
+			"installationPath": "`+expectedPath+`"
+		}`, string(request))
+	})
+
+	t.Run("failing plugin", func(t *testing.T) {
+		executable := writePlugin(t, "echo 'app not found' >&2\nexit 3\n")
+		repo, err := NewFactory("test", executable)("testRepo", t.TempDir(), nil)
+		require.NoError(t, err)
+
+		_, err = repo.InstallApp(&app.Reference{Name: "testApp", Version: "1.0.0"})
+		assert.ErrorContains(t, err, "exit status 3: app not found")
+	})
+
+	t.Run("checksum mismatch", func(t *testing.T) {
+		executable := writePlugin(t, `
+TARGET=$(echo "$REQUEST" | sed 's/.*"installationPath":"\([^"]*\)".*/\1/')
+echo content > "$TARGET"
+echo "{\"path\":\"$TARGET\",\"checksum\":\"0000\"}"
+`)
+		repo, err := NewFactory("test", executable)("testRepo", t.TempDir(), nil)
+		require.NoError(t, err)
+
+		_, err = repo.InstallApp(&app.Reference{Name: "testApp", Version: "1.0.0"})
+		assert.ErrorContains(t, err, "checksum '0000' of plugin response does not match")
+	})
+
+	t.Run("path outside of the installation directory", func(t *testing.T) {
+		executable := writePlugin(t, `echo '{"path":"/bin/sh","checksum":"0000"}'`)
+		repo, err := NewFactory("test", executable)("testRepo", t.TempDir(), nil)
+		require.NoError(t, err)
+
+		_, err = repo.InstallApp(&app.Reference{Name: "testApp", Version: "1.0.0"})
+		assert.ErrorContains(t, err, "plugin returned path /bin/sh outside of")
+	})
+}
@@ -0,0 +1,134 @@
+package s3
+
+import (
+	"fmt"
+
+	"github.com/minio/minio-go/v7/pkg/credentials"
+)
+
+type AuthType string
+
+const (
+	AccessKeyAuthType   AuthType = "access_key"
+	EnvironmentAuthType AuthType = "environment"
+)
+
+type Auth struct {
+	// Type of the authentication
+	Type AuthType
+	// Config of the authentication
+	Config AuthConfig
+}
+
+type AuthConfig interface {
+	// Credentials returns the credentials used to sign the requests
+	Credentials() *credentials.Credentials
+}
+
+var supportedAuthTypes = []AuthType{AccessKeyAuthType, EnvironmentAuthType}
+
+const accessKeyIDKey = "access_key_id"
+const secretAccessKeyKey = "secret_access_key"
+const sessionTokenKey = "session_token"
+
+type AuthFactory struct {
+	toAuthConfig map[AuthType]func(map[string]interface{}) (AuthConfig, error)
+}
+
+func newAuthFactory() *AuthFactory {
+	toAuthConfig := make(map[AuthType]func(map[string]interface{}) (AuthConfig, error))
+	toAuthConfig[AccessKeyAuthType] = newAccessKeyAuth
+	toAuthConfig[EnvironmentAuthType] = newEnvironmentAuth
+	return &AuthFactory{
+		toAuthConfig: toAuthConfig,
+	}
+}
+
+// NewAuth creates the auth of the config, it allows other packages to reuse the auth types of the repository
+func NewAuth(config map[string]interface{}) (*Auth, error) {
+	return newAuthFactory().newAuth(config)
+}
+
+// newAuth creates a new Auth object based on the given config
+func (f *AuthFactory) newAuth(config map[string]interface{}) (*Auth, error) {
+	authType, ok := config["type"].(string)
+	if !ok {
+		return nil, fmt.Errorf("auth type must be a string")
+	}
+	toAuthConfig, ok := f.toAuthConfig[AuthType(authType)]
+	if !ok {
+		return nil, fmt.Errorf("auth type %s is not supported, supported auth types are %v", authType, supportedAuthTypes)
+	}
+	authConfig, err := toAuthConfig(config)
+	if err != nil {
+		return nil, fmt.Errorf("error creating auth: %w", err)
+	}
+	return &Auth{
+		Type:   AuthType(authType),
+		Config: authConfig,
+	}, nil
+}
+
+// AccessKeyAuth uses static access keys, optionally together with a session token of temporary credentials
+type AccessKeyAuth struct {
+	AccessKeyID     string
+	SecretAccessKey string
+	SessionToken    string
+}
+
+func newAccessKeyAuth(config map[string]interface{}) (AuthConfig, error) {
+	if config[accessKeyIDKey] == nil {
+		return AccessKeyAuth{}, fmt.Errorf("missing '%s' in access key auth config", accessKeyIDKey)
+	}
+	if config[secretAccessKeyKey] == nil {
+		return AccessKeyAuth{}, fmt.Errorf("missing '%s' in access key auth config", secretAccessKeyKey)
+	}
+	accessKeyID, ok := config[accessKeyIDKey].(string)
+	if !ok {
+		return AccessKeyAuth{}, fmt.Errorf("%s must be a string", accessKeyIDKey)
+	}
+	secretAccessKey, ok := config[secretAccessKeyKey].(string)
+	if !ok {
+		return AccessKeyAuth{}, fmt.Errorf("%s must be a string", secretAccessKeyKey)
+	}
+	var sessionToken string
+	if config[sessionTokenKey] != nil {
+		sessionToken, ok = config[sessionTokenKey].(string)
+		if !ok {
+			return AccessKeyAuth{}, fmt.Errorf("%s must be a string", sessionTokenKey)
+		}
+	}
+	if accessKeyID == "" {
+		return AccessKeyAuth{}, fmt.Errorf("missing '%s' in access key auth config", accessKeyIDKey)
+	}
+	if secretAccessKey == "" {
+		return AccessKeyAuth{}, fmt.Errorf("missing '%s' in access key auth config", secretAccessKeyKey)
+	}
+	return AccessKeyAuth{
+		AccessKeyID:     accessKeyID,
+		SecretAccessKey: secretAccessKey,
+		SessionToken:    sessionToken,
+	}, nil
+}
+
+func (a AccessKeyAuth) Credentials() *credentials.Credentials {
+	return credentials.NewStaticV4(a.AccessKeyID, a.SecretAccessKey, a.SessionToken)
+}
+
+// EnvironmentAuth resolves the credentials from the environment of the onyx process.
+// The first provider returning credentials wins:
+// AWS_* variables, MINIO_* variables, the shared AWS credentials file and IAM (instance profile, ECS or web identity)
+type EnvironmentAuth struct{}
+
+func newEnvironmentAuth(config map[string]interface{}) (AuthConfig, error) {
+	return EnvironmentAuth{}, nil
+}
+
+func (e EnvironmentAuth) Credentials() *credentials.Credentials {
+	return credentials.NewChainCredentials([]credentials.Provider{
+		&credentials.EnvAWS{},
+		&credentials.EnvMinio{},
+		&credentials.FileAWSCredentials{},
+		&credentials.IAM{},
+	})
+}
@@ -0,0 +1,88 @@
+package s3
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAuth(t *testing.T) {
+	authFactory := newAuthFactory()
+
+	t.Run("Access Key", func(t *testing.T) {
+		t.Run("Valid", func(t *testing.T) {
+			auth, err := authFactory.newAuth(map[string]interface{}{
+				"type":              "access_key",
+				"access_key_id":     "testKeyID",
+				"secret_access_key": "testSecret",
+			})
+			assert.NoError(t, err)
+			assert.Equal(t, AccessKeyAuthType, auth.Type)
+
+			value, err := auth.Config.Credentials().Get()
+			assert.NoError(t, err)
+			assert.Equal(t, "testKeyID", value.AccessKeyID)
+			assert.Equal(t, "testSecret", value.SecretAccessKey)
+			assert.Equal(t, "", value.SessionToken)
+		})
+
+		t.Run("With session token", func(t *testing.T) {
+			auth, err := authFactory.newAuth(map[string]interface{}{
+				"type":              "access_key",
+				"access_key_id":     "testKeyID",
+				"secret_access_key": "testSecret",
+				"session_token":     "testSession",
+			})
+			assert.NoError(t, err)
+
+			value, err := auth.Config.Credentials().Get()
+			assert.NoError(t, err)
+			assert.Equal(t, "testSession", value.SessionToken)
+		})
+
+		t.Run("Missing secret_access_key", func(t *testing.T) {
+			_, err := authFactory.newAuth(map[string]interface{}{
+				"type":          "access_key",
+				"access_key_id": "testKeyID",
+			})
+			assert.Error(t, err)
+			assert.Equal(t, "error creating auth: missing 'secret_access_key' in access key auth config", err.Error())
+		})
+
+		t.Run("Empty access_key_id", func(t *testing.T) {
+			_, err := authFactory.newAuth(map[string]interface{}{
+				"type":              "access_key",
+				"access_key_id":     "",
+				"secret_access_key": "testSecret",
+			})
+			assert.Error(t, err)
+			assert.Equal(t, "error creating auth: missing 'access_key_id' in access key auth config", err.Error())
+		})
+	})
+
+	t.Run("Environment", func(t *testing.T) {
+		t.Setenv("AWS_ACCESS_KEY_ID", "envKeyID")
+		t.Setenv("AWS_SECRET_ACCESS_KEY", "envSecret")
+		t.Setenv("AWS_SESSION_TOKEN", "envSession")
+
+		auth, err := authFactory.newAuth(map[string]interface{}{
+			"type": "environment",
+		})
+		assert.NoError(t, err)
+		assert.Equal(t, EnvironmentAuthType, auth.Type)
+
+		value, err := auth.Config.Credentials().Get()
+		assert.NoError(t, err)
+		assert.Equal(t, "envKeyID", value.AccessKeyID)
+		assert.Equal(t, "envSecret", value.SecretAccessKey)
+		assert.Equal(t, "envSession", value.SessionToken)
+	})
+
+	t.Run("Unknown type", func(t *testing.T) {
+		_, err := authFactory.newAuth(map[string]interface{}{
+			"type": "unknown",
+		})
+		assert.Error(t, err)
+		assert.Equal(t, "auth type unknown is not supported, supported auth types are [access_key environment]", err.Error())
+	})
+}
@@ -0,0 +1,129 @@
+package s3
+
+import (
+	"fmt"
+
+	"github.com/B-S-F/onyx/pkg/repository"
+	"github.com/B-S-F/onyx/pkg/repository/app"
+	"github.com/B-S-F/onyx/pkg/repository/signature"
+)
+
+const BucketKey = "bucket"
+const PrefixKey = "prefix"
+const RegionKey = "region"
+const EndpointKey = "endpoint"
+const PathStyleKey = "path_style"
+
+const defaultEndpoint = "https://s3.amazonaws.com"
+
+type Config struct {
+	// Name of the bucket containing the apps
+	Bucket string
+	// Object key template of the apps
+	// Example "autopilots/{name}/{version}/{name}"
+	Prefix string
+	// Region of the bucket
+	// Example "eu-central-1"
+	Region string
+	// Endpoint of the S3 compatible storage, defaults to AWS S3
+	// Example "http://localhost:9000"
+	Endpoint string
+	// Address the bucket as part of the path instead of the host name (required by most MinIO setups)
+	PathStyle bool
+	// Auth configuration, defaults to the environment credential chain
+	Auth *Auth
+	// Archive configuration, if set the apps are archives which are extracted after download
+	Archive *app.ArchiveConfig
+	// Signature configuration, if set the signatures of the apps are verified after download
+	Signature *signature.Config
+}
+
+func (c *Config) Type() string {
+	return "s3"
+}
+
+func newConfig(config map[string]interface{}) (repository.Config, error) {
+	authFactory := newAuthFactory()
+	bucket, err := requiredString(config, BucketKey)
+	if err != nil {
+		return nil, err
+	}
+	prefix, err := requiredString(config, PrefixKey)
+	if err != nil {
+		return nil, err
+	}
+	region, err := optionalString(config, RegionKey)
+	if err != nil {
+		return nil, err
+	}
+	endpoint, err := optionalString(config, EndpointKey)
+	if err != nil {
+		return nil, err
+	}
+	if endpoint == "" {
+		endpoint = defaultEndpoint
+	}
+	var pathStyle bool
+	if config[PathStyleKey] != nil {
+		var ok bool
+		pathStyle, ok = config[PathStyleKey].(bool)
+		if !ok {
+			return nil, fmt.Errorf("%s must be a boolean", PathStyleKey)
+		}
+	}
+	authConfig := map[string]interface{}{"type": string(EnvironmentAuthType)}
+	if config["auth"] != nil {
+		var ok bool
+		authConfig, ok = config["auth"].(map[string]interface{})
+		if !ok {
+			return nil, fmt.Errorf("auth must be a map")
+		}
+	}
+	archive, err := app.NewArchiveConfig(config)
+	if err != nil {
+		return nil, fmt.Errorf("error creating archive config: %w", err)
+	}
+	signatureConfig, err := signature.NewConfig(config)
+	if err != nil {
+		return nil, fmt.Errorf("error creating signature config: %w", err)
+	}
+	auth, err := authFactory.newAuth(authConfig)
+	if err != nil {
+		return nil, fmt.Errorf("error creating auth: %w", err)
+	}
+	return &Config{
+		Bucket:    bucket,
+		Prefix:    prefix,
+		Region:    region,
+		Endpoint:  endpoint,
+		PathStyle: pathStyle,
+		Auth:      auth,
+		Archive:   archive,
+		Signature: signatureConfig,
+	}, nil
+}
+
+func requiredString(config map[string]interface{}, key string) (string, error) {
+	if config[key] == nil {
+		return "", fmt.Errorf("missing '%s' in config", key)
+	}
+	value, ok := config[key].(string)
+	if !ok {
+		return "", fmt.Errorf("%s must be a string", key)
+	}
+	if value == "" {
+		return "", fmt.Errorf("missing '%s' in config", key)
+	}
+	return value, nil
+}
+
+func optionalString(config map[string]interface{}, key string) (string, error) {
+	if config[key] == nil {
+		return "", nil
+	}
+	value, ok := config[key].(string)
+	if !ok {
+		return "", fmt.Errorf("%s must be a string", key)
+	}
+	return value, nil
+}
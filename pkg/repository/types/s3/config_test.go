@@ -0,0 +1,77 @@
+package s3
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestConfig(t *testing.T) {
+	t.Run("Type", func(t *testing.T) {
+		config := &Config{}
+		assert.Equal(t, "s3", config.Type())
+	})
+
+	t.Run("NewConfig", func(t *testing.T) {
+		t.Run("Defaults", func(t *testing.T) {
+			config, err := newConfig(map[string]interface{}{
+				"bucket": "testBucket",
+				"prefix": "apps/{name}/{version}",
+			})
+
+			assert.NoError(t, err)
+			assert.Equal(t, "testBucket", config.(*Config).Bucket)
+			assert.Equal(t, "apps/{name}/{version}", config.(*Config).Prefix)
+			assert.Equal(t, "https://s3.amazonaws.com", config.(*Config).Endpoint)
+			assert.False(t, config.(*Config).PathStyle)
+			assert.Equal(t, EnvironmentAuthType, config.(*Config).Auth.Type)
+		})
+
+		t.Run("MinIO", func(t *testing.T) {
+			config, err := newConfig(map[string]interface{}{
+				"bucket":     "testBucket",
+				"prefix":     "apps/{name}/{version}",
+				"region":     "eu-central-1",
+				"endpoint":   "http://localhost:9000",
+				"path_style": true,
+				"auth": map[string]interface{}{
+					"type":              "access_key",
+					"access_key_id":     "testKeyID",
+					"secret_access_key": "testSecret",
+				},
+			})
+
+			assert.NoError(t, err)
+			assert.Equal(t, "eu-central-1", config.(*Config).Region)
+			assert.Equal(t, "http://localhost:9000", config.(*Config).Endpoint)
+			assert.True(t, config.(*Config).PathStyle)
+			assert.Equal(t, AccessKeyAuthType, config.(*Config).Auth.Type)
+		})
+
+		t.Run("Missing bucket", func(t *testing.T) {
+			_, err := newConfig(map[string]interface{}{
+				"prefix": "apps/{name}/{version}",
+			})
+			assert.Error(t, err)
+			assert.Equal(t, "missing 'bucket' in config", err.Error())
+		})
+
+		t.Run("Missing prefix", func(t *testing.T) {
+			_, err := newConfig(map[string]interface{}{
+				"bucket": "testBucket",
+			})
+			assert.Error(t, err)
+			assert.Equal(t, "missing 'prefix' in config", err.Error())
+		})
+
+		t.Run("Invalid path_style", func(t *testing.T) {
+			_, err := newConfig(map[string]interface{}{
+				"bucket":     "testBucket",
+				"prefix":     "apps/{name}/{version}",
+				"path_style": "true",
+			})
+			assert.Error(t, err)
+			assert.Equal(t, "path_style must be a boolean", err.Error())
+		})
+	})
+}
@@ -0,0 +1,183 @@
+package s3
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/B-S-F/onyx/pkg/repository"
+	"github.com/B-S-F/onyx/pkg/repository/app"
+	"github.com/B-S-F/onyx/pkg/repository/download"
+	"github.com/minio/minio-go/v7"
+)
+
+type Repository struct {
+	Config           Config
+	Client           *minio.Client
+	RepoName         string
+	InstallationPath string
+}
+
+func NewRepository(name string, installationPath string, config map[string]interface{}) (repository.Repository, error) {
+	if config == nil {
+		return nil, fmt.Errorf("config is nil")
+	}
+	parsed, err := newConfig(config)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create config: %w", err)
+	}
+	client, err := NewClient(parsed.(*Config))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create s3 client: %w", err)
+	}
+	return &Repository{
+		Config:           *parsed.(*Config),
+		Client:           client,
+		RepoName:         name,
+		InstallationPath: installationPath,
+	}, nil
+}
+
+// NewClient creates the s3 client for the configured endpoint, a missing scheme defaults to https and a missing endpoint to AWS S3
+func NewClient(config *Config) (*minio.Client, error) {
+	endpoint := config.Endpoint
+	if endpoint == "" {
+		endpoint = defaultEndpoint
+	}
+	if !strings.Contains(endpoint, "://") {
+		endpoint = "https://" + endpoint
+	}
+	endpointURL, err := url.Parse(endpoint)
+	if err != nil {
+		return nil, fmt.Errorf("error parsing endpoint: %w", err)
+	}
+	if endpointURL.Scheme != "http" && endpointURL.Scheme != "https" {
+		return nil, fmt.Errorf("unsupported endpoint scheme '%s'", endpointURL.Scheme)
+	}
+	if endpointURL.Path != "" && endpointURL.Path != "/" {
+		return nil, fmt.Errorf("endpoint must not contain a path")
+	}
+	bucketLookup := minio.BucketLookupAuto
+	if config.PathStyle {
+		bucketLookup = minio.BucketLookupPath
+	}
+	return minio.New(endpointURL.Host, &minio.Options{
+		Creds:        config.Auth.Config.Credentials(),
+		Secure:       endpointURL.Scheme == "https",
+		Region:       config.Region,
+		BucketLookup: bucketLookup,
+	})
+}
+
+// InstallApp downloads the app from the bucket, saves it to the installation path and makes it executable
+func (r *Repository) InstallApp(appReference *app.Reference) (app.App, error) {
+	if r.InstallationPath == "" {
+		return nil, fmt.Errorf("installation path is not set")
+	}
+	objectKey, err := r.getObjectKey(appReference.Name, appReference.Version)
+	if err != nil {
+		return nil, fmt.Errorf("failed to install app %s: %w", appReference, err)
+	}
+
+	outputPath := app.InstallationPath(r.InstallationPath, r.RepoName, appReference.Name, appReference.Version)
+	outputDir := filepath.Dir(outputPath)
+	err = os.MkdirAll(outputDir, os.ModePerm)
+	if err != nil {
+		return nil, fmt.Errorf("failed to install app %s: %w", appReference, err)
+	}
+
+	err = r.downloadFile(objectKey, outputPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to install app %s: %w", appReference, err)
+	}
+
+	provenance := app.Provenance{Source: r.source(objectKey)}
+	if r.Config.Signature != nil {
+		provenance.Signature, err = r.Config.Signature.Verify(outputPath, func(signaturePath string) error {
+			return r.downloadFile(objectKey+r.Config.Signature.Suffix, signaturePath)
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to install app %s: %w", appReference, err)
+		}
+	}
+
+	checksum, err := app.CalculateFileChecksum(outputPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to install app %s: %w", appReference, err)
+	}
+
+	if r.Config.Archive != nil {
+		installed, err := app.NewArchiveApp(r.RepoName, appReference, checksum, outputPath, r.Config.Archive)
+		if err != nil {
+			return nil, fmt.Errorf("failed to install app %s: %w", appReference, err)
+		}
+		return app.WithProvenance(installed, provenance), nil
+	}
+	return app.WithProvenance(app.NewBinaryApp(r.RepoName, appReference.Name, appReference.Version, checksum, outputPath), provenance), nil
+}
+
+// source is the URL of the object in path style without credentials
+func (r *Repository) source(objectKey string) string {
+	endpoint := strings.TrimSuffix(r.Config.Endpoint, "/")
+	if !strings.Contains(endpoint, "://") {
+		endpoint = "https://" + endpoint
+	}
+	return download.RedactURL(endpoint + "/" + r.Config.Bucket + "/" + strings.TrimPrefix(objectKey, "/"))
+}
+
+// Download the object from the bucket, save it in the outputPath and make it executable
+func (r *Repository) downloadFile(objectKey, outputPath string) error {
+	ctx := context.Background()
+	err := r.Client.FGetObject(ctx, r.Config.Bucket, objectKey, outputPath, minio.GetObjectOptions{})
+	if err != nil {
+		return fmt.Errorf("failed to download object '%s' from bucket '%s': %w", objectKey, r.Config.Bucket, err)
+	}
+	err = os.Chmod(outputPath, 0755)
+	if err != nil {
+		return fmt.Errorf("error changing file permissions: %w", err)
+	}
+	return nil
+}
+
+// Replace {name} and {version} in the prefix with the actual app name and version
+func (r *Repository) getObjectKey(appName, appVersion string) (string, error) {
+	prefix := r.Config.Prefix
+	if prefix == "" {
+		return "", fmt.Errorf("prefix is not set")
+	}
+	if !strings.Contains(prefix, "{name}") {
+		return "", fmt.Errorf("prefix does not contain {name} placeholder")
+	}
+	if !strings.Contains(prefix, "{version}") {
+		return "", fmt.Errorf("prefix does not contain {version} placeholder")
+	}
+	prefix = strings.ReplaceAll(prefix, "{name}", appName)
+	prefix = strings.ReplaceAll(prefix, "{version}", appVersion)
+	return strings.TrimPrefix(path.Clean("/"+prefix), "/"), nil
+}
+
+// HasApp checks if the object of the app exists without downloading it
+func (r *Repository) HasApp(appReference *app.Reference) (bool, error) {
+	objectKey, err := r.getObjectKey(appReference.Name, appReference.Version)
+	if err != nil {
+		return false, err
+	}
+	_, err = r.Client.StatObject(context.Background(), r.Config.Bucket, objectKey, minio.StatObjectOptions{})
+	if err != nil {
+		response := minio.ToErrorResponse(err)
+		if response.StatusCode == http.StatusNotFound || response.Code == "NoSuchKey" {
+			return false, nil
+		}
+		return false, fmt.Errorf("failed to check object '%s' in bucket '%s': %w", objectKey, r.Config.Bucket, err)
+	}
+	return true, nil
+}
+
+func (r *Repository) Name() string {
+	return r.RepoName
+}
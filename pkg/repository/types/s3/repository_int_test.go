@@ -0,0 +1,144 @@
+//go:build integration
+// +build integration
+
+package s3
+
+import (
+	"crypto/md5"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/B-S-F/onyx/pkg/repository/app"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newFakeS3 serves path-style object requests and only accepts requests signed with the given credentials
+func newFakeS3(t *testing.T, accessKeyID, sessionToken string, objects map[string][]byte) *httptest.Server {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !strings.Contains(r.Header.Get("Authorization"), "Credential="+accessKeyID+"/") || r.Header.Get("X-Amz-Security-Token") != sessionToken {
+			w.WriteHeader(http.StatusForbidden)
+			_, _ = w.Write([]byte(`<?xml version="1.0" encoding="UTF-8"?><Error><Code>AccessDenied</Code><Message>Access Denied</Message></Error>`))
+			return
+		}
+		content, ok := objects[r.URL.Path]
+		if !ok {
+			w.Header().Set("Content-Type", "application/xml")
+			w.WriteHeader(http.StatusNotFound)
+			if r.Method != http.MethodHead {
+				_, _ = w.Write([]byte(`<?xml version="1.0" encoding="UTF-8"?><Error><Code>NoSuchKey</Code><Message>The specified key does not exist.</Message></Error>`))
+			}
+			return
+		}
+		etag := md5.Sum(content)
+		w.Header().Set("ETag", `"`+hex.EncodeToString(etag[:])+`"`)
+		w.Header().Set("Last-Modified", time.Now().UTC().Format(http.TimeFormat))
+		w.Header().Set("Content-Type", "application/octet-stream")
+		w.Header().Set("Content-Length", fmt.Sprint(len(content)))
+		if r.Method == http.MethodHead {
+			return
+		}
+		_, _ = w.Write(content)
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestInstallApp(t *testing.T) {
+	content := []byte("#!/bin/bash\necho hello\n")
+	objects := map[string][]byte{"/apps/autopilots/testApp/1.0.0/testApp": content}
+
+	t.Run("should install app with session credentials", func(t *testing.T) {
+		server := newFakeS3(t, "testKeyID", "testSession", objects)
+		repo, err := NewRepository("testRepo", t.TempDir(), map[string]interface{}{
+			"bucket":     "apps",
+			"prefix":     "autopilots/{name}/{version}/{name}",
+			"region":     "us-east-1",
+			"endpoint":   server.URL,
+			"path_style": true,
+			"auth": map[string]interface{}{
+				"type":              "access_key",
+				"access_key_id":     "testKeyID",
+				"secret_access_key": "testSecret",
+				"session_token":     "testSession",
+			},
+		})
+		require.NoError(t, err)
+
+		installed, err := repo.InstallApp(&app.Reference{Name: "testApp", Version: "1.0.0"})
+		require.NoError(t, err)
+		downloaded, err := os.ReadFile(installed.ExecutablePath())
+		require.NoError(t, err)
+		assert.Equal(t, content, downloaded)
+		info, err := os.Stat(installed.ExecutablePath())
+		require.NoError(t, err)
+		assert.Equal(t, os.FileMode(0755), info.Mode().Perm())
+		checksum, err := app.CalculateFileChecksum(installed.ExecutablePath())
+		require.NoError(t, err)
+		assert.Equal(t, checksum, installed.Checksum())
+	})
+
+	t.Run("should install app with environment credentials", func(t *testing.T) {
+		t.Setenv("AWS_ACCESS_KEY_ID", "envKeyID")
+		t.Setenv("AWS_SECRET_ACCESS_KEY", "envSecret")
+		t.Setenv("AWS_SESSION_TOKEN", "")
+		server := newFakeS3(t, "envKeyID", "", objects)
+		repo, err := NewRepository("testRepo", t.TempDir(), map[string]interface{}{
+			"bucket":     "apps",
+			"prefix":     "autopilots/{name}/{version}/{name}",
+			"region":     "us-east-1",
+			"endpoint":   server.URL,
+			"path_style": true,
+		})
+		require.NoError(t, err)
+
+		_, err = repo.InstallApp(&app.Reference{Name: "testApp", Version: "1.0.0"})
+		assert.NoError(t, err)
+	})
+
+	t.Run("should fail for missing object", func(t *testing.T) {
+		server := newFakeS3(t, "testKeyID", "", objects)
+		repo, err := NewRepository("testRepo", t.TempDir(), map[string]interface{}{
+			"bucket":     "apps",
+			"prefix":     "autopilots/{name}/{version}/{name}",
+			"region":     "us-east-1",
+			"endpoint":   server.URL,
+			"path_style": true,
+			"auth": map[string]interface{}{
+				"type":              "access_key",
+				"access_key_id":     "testKeyID",
+				"secret_access_key": "testSecret",
+			},
+		})
+		require.NoError(t, err)
+
+		_, err = repo.InstallApp(&app.Reference{Name: "testApp", Version: "2.0.0"})
+		assert.ErrorContains(t, err, "failed to download object 'autopilots/testApp/2.0.0/testApp' from bucket 'apps'")
+	})
+
+	t.Run("should fail with wrong credentials", func(t *testing.T) {
+		server := newFakeS3(t, "testKeyID", "", objects)
+		repo, err := NewRepository("testRepo", t.TempDir(), map[string]interface{}{
+			"bucket":     "apps",
+			"prefix":     "autopilots/{name}/{version}/{name}",
+			"region":     "us-east-1",
+			"endpoint":   server.URL,
+			"path_style": true,
+			"auth": map[string]interface{}{
+				"type":              "access_key",
+				"access_key_id":     "otherKeyID",
+				"secret_access_key": "testSecret",
+			},
+		})
+		require.NoError(t, err)
+
+		_, err = repo.InstallApp(&app.Reference{Name: "testApp", Version: "1.0.0"})
+		assert.Error(t, err)
+	})
+}
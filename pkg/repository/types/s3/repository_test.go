@@ -0,0 +1,88 @@
+package s3
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRepository(t *testing.T) {
+	t.Run("NewRepository", func(t *testing.T) {
+		t.Run("should create repository", func(t *testing.T) {
+			repo, err := NewRepository("name", "/test/path", map[string]interface{}{
+				"bucket":   "testBucket",
+				"prefix":   "apps/{name}/{version}",
+				"endpoint": "http://localhost:9000",
+			})
+
+			assert.NoError(t, err)
+			assert.Equal(t, "name", repo.Name())
+			assert.Equal(t, "/test/path", repo.(*Repository).InstallationPath)
+			assert.Equal(t, "localhost:9000", repo.(*Repository).Client.EndpointURL().Host)
+			assert.Equal(t, "http", repo.(*Repository).Client.EndpointURL().Scheme)
+		})
+
+		t.Run("should default to https without scheme", func(t *testing.T) {
+			repo, err := NewRepository("name", "/test/path", map[string]interface{}{
+				"bucket":   "testBucket",
+				"prefix":   "apps/{name}/{version}",
+				"endpoint": "minio.example.com",
+			})
+
+			assert.NoError(t, err)
+			assert.Equal(t, "https", repo.(*Repository).Client.EndpointURL().Scheme)
+		})
+
+		t.Run("should fail with an endpoint path", func(t *testing.T) {
+			_, err := NewRepository("name", "/test/path", map[string]interface{}{
+				"bucket":   "testBucket",
+				"prefix":   "apps/{name}/{version}",
+				"endpoint": "http://localhost:9000/bucket",
+			})
+
+			assert.Error(t, err)
+		})
+
+		t.Run("should fail without config", func(t *testing.T) {
+			_, err := NewRepository("name", "/test/path", nil)
+			assert.Error(t, err)
+		})
+	})
+
+	t.Run("getObjectKey", func(t *testing.T) {
+		testCases := map[string]struct {
+			prefix  string
+			want    string
+			wantErr string
+		}{
+			"should replace placeholders": {
+				prefix: "apps/{name}/{version}/{name}",
+				want:   "apps/app/1.0.0/app",
+			},
+			"should clean the key": {
+				prefix: "/apps//{name}-{version}",
+				want:   "apps/app-1.0.0",
+			},
+			"should fail without name": {
+				prefix:  "apps/{version}",
+				wantErr: "prefix does not contain {name} placeholder",
+			},
+			"should fail without version": {
+				prefix:  "apps/{name}",
+				wantErr: "prefix does not contain {version} placeholder",
+			},
+		}
+		for name, tc := range testCases {
+			t.Run(name, func(t *testing.T) {
+				repo := &Repository{Config: Config{Prefix: tc.prefix}}
+				key, err := repo.getObjectKey("app", "1.0.0")
+				if tc.wantErr != "" {
+					assert.EqualError(t, err, tc.wantErr)
+					return
+				}
+				assert.NoError(t, err)
+				assert.Equal(t, tc.want, key)
+			})
+		}
+	})
+}
@@ -1,6 +1,8 @@
 package common
 
 import (
+	"bytes"
+	"encoding/json"
 	"strings"
 
 	"gopkg.in/yaml.v3"
@@ -30,8 +32,25 @@ func (m StringMap) MarshalYAML() (interface{}, error) {
 	return &node, nil
 }
 
+// MarshalJSON writes the values like MarshalYAML
+func (m StringMap) MarshalJSON() ([]byte, error) {
+	if m == nil {
+		return []byte("null"), nil
+	}
+	trimmed := make(map[string]string, len(m))
+	for k, v := range m {
+		trimmed[k] = trimLeftSpace(v)
+	}
+	return marshalJSON(trimmed)
+}
+
 type MultilineString string
 
+// MarshalJSON writes the string like MarshalYAML
+func (m MultilineString) MarshalJSON() ([]byte, error) {
+	return marshalJSON(trimLeftSpace(string(m)))
+}
+
 func (m MultilineString) MarshalYAML() (interface{}, error) {
 	node := yaml.Node{
 		Kind:  yaml.ScalarNode,
@@ -42,6 +61,17 @@ func (m MultilineString) MarshalYAML() (interface{}, error) {
 	return &node, nil
 }
 
+// marshalJSON does not escape HTML characters, which are common in the justifications of autopilots
+func marshalJSON(v interface{}) ([]byte, error) {
+	var buffer bytes.Buffer
+	encoder := json.NewEncoder(&buffer)
+	encoder.SetEscapeHTML(false)
+	if err := encoder.Encode(v); err != nil {
+		return nil, err
+	}
+	return bytes.TrimSuffix(buffer.Bytes(), []byte("\n")), nil
+}
+
 func trimLeftSpace(s string) string {
 	return strings.TrimLeft(s, " \t")
 }
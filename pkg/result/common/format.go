@@ -0,0 +1,68 @@
+package common
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	FORMAT_YAML = "yaml"
+	FORMAT_JSON = "json"
+	// FORMAT_BOTH writes the result file in YAML and JSON
+	FORMAT_BOTH = "both"
+)
+
+// Formats lists the formats of the result files
+var Formats = []string{FORMAT_YAML, FORMAT_JSON, FORMAT_BOTH}
+
+// ResultFiles returns the names of the result files for the format, e.g. qg-result.yaml and qg-result.json for both.
+// The first file is the main result file, which is e.g. attested in the evidence. YAML is the default.
+func ResultFiles(name string, format string) ([]string, error) {
+	base := strings.TrimSuffix(name, filepath.Ext(name))
+	switch format {
+	case "", FORMAT_YAML:
+		return []string{base + ".yaml"}, nil
+	case FORMAT_JSON:
+		return []string{base + ".json"}, nil
+	case FORMAT_BOTH:
+		return []string{base + ".yaml", base + ".json"}, nil
+	default:
+		return nil, fmt.Errorf("unsupported result format '%s', must be one of: %s", format, strings.Join(Formats, ", "))
+	}
+}
+
+// FormatOf returns the format of a result file based on its extension, YAML is the default
+func FormatOf(path string) string {
+	if strings.EqualFold(filepath.Ext(path), ".json") {
+		return FORMAT_JSON
+	}
+	return FORMAT_YAML
+}
+
+// Marshal encodes the result in the format, JSON is indented
+func Marshal(result interface{}, format string) ([]byte, error) {
+	if format != FORMAT_JSON {
+		return yaml.Marshal(result)
+	}
+	var buffer bytes.Buffer
+	encoder := json.NewEncoder(&buffer)
+	encoder.SetEscapeHTML(false)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(result); err != nil {
+		return nil, err
+	}
+	return buffer.Bytes(), nil
+}
+
+// DetectFormat returns JSON for content which starts with an object, otherwise YAML
+func DetectFormat(content []byte) string {
+	if bytes.HasPrefix(bytes.TrimLeft(content, " \t\r\n\uFEFF"), []byte("{")) {
+		return FORMAT_JSON
+	}
+	return FORMAT_YAML
+}
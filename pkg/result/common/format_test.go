@@ -0,0 +1,47 @@
+//go:build unit
+// +build unit
+
+package common
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestResultFiles(t *testing.T) {
+	testCases := map[string][]string{
+		"":          {"qg-result.yaml"},
+		FORMAT_YAML: {"qg-result.yaml"},
+		FORMAT_JSON: {"qg-result.json"},
+		FORMAT_BOTH: {"qg-result.yaml", "qg-result.json"},
+	}
+	for format, expected := range testCases {
+		t.Run("format "+format, func(t *testing.T) {
+			files, err := ResultFiles("qg-result.yaml", format)
+			require.NoError(t, err)
+			assert.Equal(t, expected, files)
+		})
+	}
+
+	_, err := ResultFiles("qg-result.yaml", "xml")
+	assert.EqualError(t, err, "unsupported result format 'xml', must be one of: yaml, json, both")
+}
+
+func TestMarshal(t *testing.T) {
+	result := struct {
+		Reason   MultilineString `yaml:"reason" json:"reason"`
+		Metadata StringMap       `yaml:"metadata" json:"metadata"`
+	}{Reason: "  <b>ok</b>\n", Metadata: StringMap{"key": "  value"}}
+
+	content, err := Marshal(result, FORMAT_JSON)
+
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"reason":"<b>ok</b>\n","metadata":{"key":"value"}}`, string(content))
+	assert.Contains(t, string(content), "<b>ok</b>")
+	assert.Equal(t, FORMAT_JSON, DetectFormat(content))
+	assert.Equal(t, FORMAT_YAML, DetectFormat([]byte("reason: ok")))
+	assert.Equal(t, FORMAT_JSON, FormatOf("result.JSON"))
+	assert.Equal(t, FORMAT_YAML, FormatOf("result.yaml"))
+}
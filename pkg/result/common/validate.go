@@ -0,0 +1,46 @@
+package common
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/B-S-F/onyx/pkg/schema"
+)
+
+// Validator validates result files against the schema of a result version, the schema is created on first use
+type Validator struct {
+	result interface{}
+	once   sync.Once
+	schema *schema.Schema
+	err    error
+}
+
+// NewValidator creates a validator for the schema of the empty result struct
+func NewValidator(result interface{}) *Validator {
+	return &Validator{result: result}
+}
+
+// Violations returns the violations of the schema by the YAML or JSON content of a result file
+func (v *Validator) Violations(content []byte) ([]string, error) {
+	v.once.Do(func() {
+		v.schema = &schema.Schema{}
+		v.err = v.schema.Load(v.result)
+	})
+	if v.err != nil {
+		return nil, fmt.Errorf("error loading result schema: %w", v.err)
+	}
+	return v.schema.Violations(content)
+}
+
+// Validate returns an error listing all violations of the schema by the content of a result file
+func (v *Validator) Validate(content []byte) error {
+	violations, err := v.Violations(content)
+	if err != nil {
+		return err
+	}
+	if len(violations) > 0 {
+		return fmt.Errorf("result does not match the schema:\n  - %s", strings.Join(violations, "\n  - "))
+	}
+	return nil
+}
@@ -0,0 +1,109 @@
+package result
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/B-S-F/onyx/pkg/result/common"
+	v1 "github.com/B-S-F/onyx/pkg/result/v1"
+	v2 "github.com/B-S-F/onyx/pkg/v2/result"
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	VERSION_V1 = "v1"
+	VERSION_V2 = "v2"
+)
+
+// File is a loaded result file, depending on the version either V1 or V2 is set
+type File struct {
+	// Version of the result from its metadata
+	Version string
+	// Format of the result file, yaml or json
+	Format string
+	V1     *v1.Result
+	V2     *v2.Result
+	// content of the result file, which is validated
+	content []byte
+}
+
+// Load reads a result file in YAML or JSON and detects its version
+func Load(path string) (*File, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("error reading result file '%s': %w", path, err)
+	}
+	file, err := Parse(content)
+	if err != nil {
+		return nil, fmt.Errorf("error loading result file '%s': %w", path, err)
+	}
+	return file, nil
+}
+
+// Parse decodes the content of a result file, the format is detected from the content and the version from the metadata
+func Parse(content []byte) (*File, error) {
+	format := common.DetectFormat(content)
+	var header struct {
+		Metadata struct {
+			Version string `yaml:"version" json:"version"`
+		} `yaml:"metadata" json:"metadata"`
+	}
+	if err := decode(content, format, &header); err != nil {
+		return nil, err
+	}
+	file := &File{Version: header.Metadata.Version, Format: format, content: content}
+	switch file.Version {
+	case VERSION_V1:
+		file.V1 = &v1.Result{}
+		if err := decode(content, format, file.V1); err != nil {
+			return nil, err
+		}
+	case VERSION_V2:
+		file.V2 = &v2.Result{}
+		if err := decode(content, format, file.V2); err != nil {
+			return nil, err
+		}
+	case "":
+		return nil, fmt.Errorf("result has no 'metadata.version'")
+	default:
+		return nil, fmt.Errorf("unsupported result version '%s', must be one of: %s, %s", file.Version, VERSION_V1, VERSION_V2)
+	}
+	return file, nil
+}
+
+func decode(content []byte, format string, out interface{}) error {
+	if format == common.FORMAT_JSON {
+		if err := json.Unmarshal(content, out); err != nil {
+			return fmt.Errorf("error parsing result as json: %w", err)
+		}
+		return nil
+	}
+	if err := yaml.Unmarshal(content, out); err != nil {
+		return fmt.Errorf("error parsing result as yaml: %w", err)
+	}
+	return nil
+}
+
+// OverallStatus returns the overall status of the result independent of its version
+func (f *File) OverallStatus() string {
+	if f.V2 != nil {
+		return f.V2.OverallStatus
+	}
+	if f.V1 != nil {
+		return f.V1.OverallStatus
+	}
+	return ""
+}
+
+// ValidateSchema checks the result file against the schema of its version
+func (f *File) ValidateSchema() error {
+	switch f.Version {
+	case VERSION_V1:
+		return v1.Validate(f.content)
+	case VERSION_V2:
+		return v2.Validate(f.content)
+	default:
+		return fmt.Errorf("unsupported result version '%s', must be one of: %s, %s", f.Version, VERSION_V1, VERSION_V2)
+	}
+}
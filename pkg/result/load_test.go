@@ -0,0 +1,141 @@
+//go:build unit
+// +build unit
+
+package result
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/B-S-F/onyx/pkg/result/common"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const v2ResultYAML = `metadata:
+  version: v2
+header:
+  name: test
+  version: "1.0"
+  date: "2024-01-01 12:00"
+  toolVersion: "0.1.0"
+overallStatus: GREEN
+statistics:
+  counted-checks: 1
+  counted-automated-checks: 0
+  counted-manual-check: 1
+  counted-unanswered-checks: 0
+  counted-skipped-checks: 0
+  degree-of-automation: 0
+  degree-of-completion: 100
+chapters:
+  "1":
+    title: chapter
+    status: GREEN
+    requirements:
+      "1":
+        title: requirement
+        status: GREEN
+        checks:
+          "1":
+            title: check
+            type: manual
+            evaluation:
+              status: GREEN
+              reason: all good
+`
+
+func TestLoad(t *testing.T) {
+	testCases := map[string]struct {
+		content string
+		version string
+		format  string
+	}{
+		"v2 yaml": {content: v2ResultYAML, version: VERSION_V2, format: common.FORMAT_YAML},
+		"v2 json": {content: `{"metadata":{"version":"v2"},"header":{"name":"test"},"overallStatus":"RED","chapters":{"1":{"requirements":{"1":{"checks":{"1":{"evaluation":{"status":"RED"}}}}}}}}`, version: VERSION_V2, format: common.FORMAT_JSON},
+		"v1 yaml": {content: "metadata:\n  version: v1\nheader:\n  name: test\noverallStatus: FAILED\n", version: VERSION_V1, format: common.FORMAT_YAML},
+	}
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "qg-result")
+			require.NoError(t, os.WriteFile(path, []byte(tc.content), 0644))
+
+			file, err := Load(path)
+
+			require.NoError(t, err)
+			assert.Equal(t, tc.version, file.Version)
+			assert.Equal(t, tc.format, file.Format)
+			assert.Equal(t, tc.version == VERSION_V1, file.V1 != nil)
+			assert.Equal(t, tc.version == VERSION_V2, file.V2 != nil)
+			assert.NotEmpty(t, file.OverallStatus())
+		})
+	}
+
+	t.Run("should load the json written for a yaml result", func(t *testing.T) {
+		file, err := Parse([]byte(v2ResultYAML))
+		require.NoError(t, err)
+		content, err := common.Marshal(file.V2, common.FORMAT_JSON)
+		require.NoError(t, err)
+
+		loaded, err := Parse(content)
+
+		require.NoError(t, err)
+		assert.Equal(t, file.V2, loaded.V2)
+	})
+}
+
+func TestParseErrors(t *testing.T) {
+	testCases := map[string]struct {
+		content string
+		err     string
+	}{
+		"no version":      {content: "header:\n  name: test\n", err: "result has no 'metadata.version'"},
+		"unknown version": {content: "metadata:\n  version: v3\n", err: "unsupported result version 'v3'"},
+		"broken json":     {content: `{"metadata":`, err: "error parsing result as json"},
+		"broken yaml":     {content: "metadata: [", err: "error parsing result as yaml"},
+	}
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			_, err := Parse([]byte(tc.content))
+			assert.ErrorContains(t, err, tc.err)
+		})
+	}
+}
+
+func TestValidateSchema(t *testing.T) {
+	t.Run("should accept valid v1 and v2 results", func(t *testing.T) {
+		for _, content := range [][]byte{[]byte(v2ResultYAML), readFile(t, "testdata/result.golden")} {
+			file, err := Parse(content)
+			require.NoError(t, err)
+			assert.NoError(t, file.ValidateSchema())
+		}
+	})
+
+	t.Run("should list the violations of the schema", func(t *testing.T) {
+		file, err := Parse([]byte(strings.Replace(v2ResultYAML, "status: GREEN\n              reason", "status: PURPLE\n              reason", 1)))
+		require.NoError(t, err)
+
+		err = file.ValidateSchema()
+
+		assert.ErrorContains(t, err, "result does not match the schema")
+		assert.ErrorContains(t, err, "chapters.1.requirements.1.checks.1.evaluation.status must be one of the following")
+	})
+
+	t.Run("should validate json results", func(t *testing.T) {
+		file, err := Parse([]byte(`{"metadata":{"version":"v2"},"header":{"name":"test"}}`))
+		require.NoError(t, err)
+
+		err = file.ValidateSchema()
+
+		assert.ErrorContains(t, err, "overallStatus is required")
+		assert.ErrorContains(t, err, "header: version is required")
+	})
+}
+
+func readFile(t *testing.T, path string) []byte {
+	content, err := os.ReadFile(path)
+	require.NoError(t, err)
+	return content
+}
@@ -37,12 +37,12 @@ type ExecutionInformation struct {
 	// Example
 	// 	- "Hello World"
 	// 	- "This is my log"
-	Logs []string `yaml:"logs,omitempty" json:"logs" jsonschema:"optional"`
+	Logs []string `yaml:"logs,omitempty" json:"logs,omitempty" jsonschema:"optional"`
 	// Error logs from the execution of the autopilot
 	// Example
 	// 	- "Hello Error"
 	// 	- "This is my error log"
-	ErrorLogs []string `yaml:"errorLogs,omitempty" json:"errorLogs" jsonschema:"optional"`
+	ErrorLogs []string `yaml:"errorLogs,omitempty" json:"errorLogs,omitempty" jsonschema:"optional"`
 	// Path where the evidence of the autopilot is stored
 	EvidencePath string `yaml:"evidencePath" json:"evidencePath" jsonschema:"required"`
 	// Exit code of the autopilot
@@ -64,39 +64,39 @@ type AutopilotResult struct {
 	// Example
 	// 	- "foo": "bar"
 	// 	- "baz": "qux"
-	Metadata common.StringMap `yaml:"metadata,omitempty" json:"metadata" jsonschema:"optional"`
+	Metadata common.StringMap `yaml:"metadata,omitempty" json:"metadata,omitempty" jsonschema:"optional"`
 }
 
 // Contains the results of a check
 type CheckResult struct {
 	// Name of the autopilot
 	// Example "my-autopilot"
-	Autopilot string `yaml:"autopilot,omitempty" json:"autopilot" jsonschema:"optional"`
+	Autopilot string `yaml:"autopilot,omitempty" json:"autopilot,omitempty" jsonschema:"optional"`
 	// Status of the autopilot
 	// Example "GREEN"
-	Status string `yaml:"status" json:"status" jsonschema:"required, enum=GREEN,enum=YELLOW,enum=RED,enum=NA,enum=UNANSWERED,enum=FAILED,enum=ERROR"`
+	Status string `yaml:"status" json:"status" jsonschema:"required,enum=GREEN,enum=YELLOW,enum=RED,enum=NA,enum=UNANSWERED,enum=SKIPPED,enum=FAILED,enum=ERROR"`
 	// Reason associated with the status
 	// Example "This is my reason"
 	Reason string `yaml:"reason" json:"reason" jsonschema:"required"`
 	// Results of the autopilot
-	Results []AutopilotResult `yaml:"results,omitempty" json:"results" jsonschema:"optional"`
+	Results []AutopilotResult `yaml:"results,omitempty" json:"results,omitempty" jsonschema:"optional"`
 	// Outputs of the autopilot
-	Outputs map[string]string `yaml:"outputs,omitempty" json:"outputs" jsonschema:"optional"`
+	Outputs map[string]string `yaml:"outputs,omitempty" json:"outputs,omitempty" jsonschema:"optional"`
 	// Execution information of the autopilot
-	Execution ExecutionInformation `yaml:"execution,omitempty" json:"execution" jsonschema:"optional"`
+	Execution ExecutionInformation `yaml:"execution,omitempty" json:"execution,omitempty" jsonschema:"optional"`
 }
 
 // Contains information about a check
 type Check struct {
 	// Title of the check
 	// Example "My Check"
-	Title string `yaml:"title,omitempty" json:"title" jsonschema:"required"`
+	Title string `yaml:"title,omitempty" json:"title,omitempty" jsonschema:"optional"`
 	// Status of the check (is derived from the autopilot status)
 	// Example "GREEN"
-	Status string `yaml:"status" json:"status" jsonschema:"required, enum=GREEN,enum=YELLOW,enum=RED,enum=NA,enum=UNANSWERED,enum=FAILED,enum=ERROR"`
+	Status string `yaml:"status" json:"status" jsonschema:"required,enum=GREEN,enum=YELLOW,enum=RED,enum=NA,enum=UNANSWERED,enum=SKIPPED,enum=FAILED,enum=ERROR"`
 	// Type of the check
-	// Example "autopilot"
-	Type string `yaml:"type" json:"type" jsonschema:"required,enum=autopilot,enum=manual"`
+	// Example "Automation"
+	Type string `yaml:"type" json:"type" jsonschema:"required,enum=Automation,enum=Manual,enum=None"`
 	// Evaluation of the check containing the result
 	Evaluation CheckResult `yaml:"evaluation" json:"evaluation" jsonschema:"required"`
 }
@@ -105,28 +105,28 @@ type Check struct {
 type Requirement struct {
 	// Title of the requirement
 	// Example "My Requirement"
-	Title string `yaml:"title,omitempty" json:"title" jsonschema:"required"`
+	Title string `yaml:"title,omitempty" json:"title,omitempty" jsonschema:"optional"`
 	// Text of the requirement
 	// Example "This is my requirement"
-	Text string `yaml:"text,omitempty" json:"text" jsonschema:"optional"`
+	Text string `yaml:"text,omitempty" json:"text,omitempty" jsonschema:"optional"`
 	// Status of the requirement (is composed of the status of the checks)
 	// Example "GREEN"
-	Status string `yaml:"status" json:"status" jsonschema:"required, enum=GREEN,enum=YELLOW,enum=RED,enum=NA,enum=UNANSWERED,enum=FAILED,enum=ERROR"`
+	Status string `yaml:"status" json:"status" jsonschema:"required,enum=GREEN,enum=YELLOW,enum=RED,enum=NA,enum=UNANSWERED,enum=SKIPPED,enum=FAILED,enum=ERROR"`
 	// Checks to answer the requirement
-	Checks map[string]*Check `yaml:"checks,omitempty" json:"checks" jsonschema:"required"`
+	Checks map[string]*Check `yaml:"checks,omitempty" json:"checks,omitempty" jsonschema:"optional"`
 }
 
 // Contains information about a chapter
 type Chapter struct {
 	// Title of the chapter
 	// Example "My Chapter"
-	Title string `yaml:"title,omitempty" json:"title" jsonschema:"required"`
+	Title string `yaml:"title,omitempty" json:"title,omitempty" jsonschema:"optional"`
 	// Text of the chapter
 	// Example "This is my chapter"
-	Text string `yaml:"text,omitempty" json:"text" jsonschema:"optional"`
+	Text string `yaml:"text,omitempty" json:"text,omitempty" jsonschema:"optional"`
 	// Status of the chapter (is composed of the status of the requirements)
 	// Example "GREEN"
-	Status string `yaml:"status" json:"status" jsonschema:"required, enum=GREEN,enum=YELLOW,enum=RED,enum=NA,enum=UNANSWERED,enum=FAILED,enum=ERROR"`
+	Status string `yaml:"status" json:"status" jsonschema:"required,enum=GREEN,enum=YELLOW,enum=RED,enum=NA,enum=UNANSWERED,enum=SKIPPED,enum=FAILED,enum=ERROR"`
 	// Requirements to answer the chapter
 	Requirements map[string]*Requirement `yaml:"requirements" json:"requirements" jsonschema:"required"`
 }
@@ -162,11 +162,18 @@ type Result struct {
 	// Header of the result
 	Header Header `yaml:"header" json:"header" jsonschema:"required"`
 	// Overall status of the result (is composed of the status of the chapters)
-	OverallStatus string `yaml:"overallStatus" json:"overallStatus" jsonschema:"required, enum=GREEN,enum=YELLOW,enum=RED,enum=NA,enum=UNANSWERED,enum=FAILED,enum=ERROR"`
+	OverallStatus string `yaml:"overallStatus" json:"overallStatus" jsonschema:"required,enum=GREEN,enum=YELLOW,enum=RED,enum=NA,enum=UNANSWERED,enum=SKIPPED,enum=FAILED,enum=ERROR"`
 	// Statistics of the result
 	Statistics Statistics `yaml:"statistics" json:"statistics" jsonschema:"required"`
 	// Chapters containing requirements and checks
 	Chapters map[string]*Chapter `yaml:"chapters" json:"chapters" jsonschema:"required"`
 	// Finalize step
-	Finalize *Finalize `yaml:"finalize,omitempty" json:"finalize" jsonschema:"optional"`
+	Finalize *Finalize `yaml:"finalize,omitempty" json:"finalize,omitempty" jsonschema:"optional"`
+}
+
+var validator = common.NewValidator(Result{})
+
+// Validate checks the YAML or JSON content of a v1 result file against the v1 result schema
+func Validate(content []byte) error {
+	return validator.Validate(content)
 }
@@ -81,8 +81,9 @@ func (s *Subprocess) parseOutput(input *Input, exitCode int, stdout, stderr stri
 	out := &Output{}
 	out.WorkDir = input.WorkDir
 	out.ExitCode = exitCode
-	outStr := helper.HideSecretsInString(stdout, input.Secrets)
-	errStr := helper.HideSecretsInString(stderr, input.Secrets)
+	masker := helper.NewMasker(input.Secrets)
+	outStr := masker.Mask(stdout)
+	errStr := masker.Mask(stderr)
 	out.parseLogStrings(outStr, errStr)
 	return out
 }
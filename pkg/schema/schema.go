@@ -78,6 +78,25 @@ func (s *Schema) Validate(yamlData []byte) error {
 	return nil
 }
 
+// Violations validates YAML or JSON data against the schema and returns the violations of the schema.
+// Unlike Validate, it does not check replace patterns and does not log the violations
+func (s *Schema) Violations(data []byte) ([]string, error) {
+	var value interface{}
+	err := yaml.Unmarshal(data, &value)
+	if err != nil {
+		return nil, errors.Wrapf(err, "error unmarshalling data: %s", err)
+	}
+	result, err := s.validator.Validate(gojsonschema.NewGoLoader(&value))
+	if err != nil {
+		return nil, errors.Wrapf(err, "error validating data: %s", err)
+	}
+	violations := make([]string, 0, len(result.Errors()))
+	for _, desc := range result.Errors() {
+		violations = append(violations, desc.String())
+	}
+	return violations, nil
+}
+
 func loadSchema(anySchema interface{}) ([]byte, *gojsonschema.Schema, error) {
 	JSONSchema, err := createJSONSchema(anySchema)
 	if err != nil {
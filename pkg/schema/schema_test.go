@@ -175,3 +175,16 @@ func TestCreateSchemaValidator(t *testing.T) {
 	assert.NoError(t, err)
 	assert.True(t, result.Valid())
 }
+
+func TestSchemaViolations(t *testing.T) {
+	schema := &Schema{}
+	require.NoError(t, schema.Load(configMock{}))
+
+	violations, err := schema.Violations([]byte("name: ${{ test.invalid }}\nversion: 1.0"))
+	require.NoError(t, err)
+	assert.Equal(t, []string{"version: Invalid type. Expected: string, given: integer"}, violations)
+
+	violations, err = schema.Violations([]byte(`{"name": "test", "other": true}`))
+	require.NoError(t, err)
+	assert.Equal(t, []string{"(root): Additional property other is not allowed"}, violations)
+}
@@ -0,0 +1,146 @@
+package secrets
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"filippo.io/age"
+	"filippo.io/age/armor"
+)
+
+const (
+	// ENCRYPTED_SUFFIX is appended to the name of encrypted secrets files, e.g. .secrets.age
+	ENCRYPTED_SUFFIX = ".age"
+	// AGE_IDENTITY_ENV contains age identities, e.g. a CI variable with an AGE-SECRET-KEY-1... line
+	AGE_IDENTITY_ENV = "ONYX_AGE_IDENTITY"
+	// AGE_IDENTITY_FILE_ENV contains the path of an age identity file
+	AGE_IDENTITY_FILE_ENV = "ONYX_AGE_IDENTITY_FILE"
+
+	ageHeader = "age-encryption.org/"
+)
+
+// IsEncrypted checks if the content is encrypted with age, in binary or armored format
+func IsEncrypted(content []byte) bool {
+	trimmed := bytes.TrimLeft(content, " \t\r\n")
+	return bytes.HasPrefix(trimmed, []byte(ageHeader)) || bytes.HasPrefix(trimmed, []byte(armor.Header))
+}
+
+// Identities reads the age identities from the identity file, or from the environment if it is empty
+func Identities(identityFile string) ([]age.Identity, error) {
+	if identityFile == "" {
+		identityFile = os.Getenv(AGE_IDENTITY_FILE_ENV)
+	}
+	if identityFile != "" {
+		file, err := os.Open(identityFile)
+		if err != nil {
+			return nil, fmt.Errorf("error opening age identity file: %w", err)
+		}
+		defer file.Close()
+		identities, err := age.ParseIdentities(file)
+		if err != nil {
+			return nil, fmt.Errorf("error parsing age identity file %s: %w", identityFile, err)
+		}
+		return identities, nil
+	}
+	if value := os.Getenv(AGE_IDENTITY_ENV); value != "" {
+		identities, err := age.ParseIdentities(strings.NewReader(value))
+		if err != nil {
+			return nil, fmt.Errorf("error parsing age identities of %s: %w", AGE_IDENTITY_ENV, err)
+		}
+		return identities, nil
+	}
+	return nil, fmt.Errorf("no age identity found, set %s or %s", AGE_IDENTITY_FILE_ENV, AGE_IDENTITY_ENV)
+}
+
+// Recipients parses age recipients like age1... and the recipients listed in the recipients files
+func Recipients(values []string, recipientsFiles []string) ([]age.Recipient, error) {
+	var recipients []age.Recipient
+	for _, value := range values {
+		recipient, err := age.ParseX25519Recipient(value)
+		if err != nil {
+			return nil, fmt.Errorf("error parsing age recipient %s: %w", value, err)
+		}
+		recipients = append(recipients, recipient)
+	}
+	for _, recipientsFile := range recipientsFiles {
+		content, err := os.ReadFile(recipientsFile)
+		if err != nil {
+			return nil, fmt.Errorf("error reading age recipients file: %w", err)
+		}
+		parsed, err := age.ParseRecipients(bytes.NewReader(content))
+		if err != nil {
+			return nil, fmt.Errorf("error parsing age recipients file %s: %w", recipientsFile, err)
+		}
+		recipients = append(recipients, parsed...)
+	}
+	return recipients, nil
+}
+
+// RecipientsOf returns the recipients of the X25519 identities, other identities are skipped
+func RecipientsOf(identities []age.Identity) []age.Recipient {
+	var recipients []age.Recipient
+	for _, identity := range identities {
+		if x25519, ok := identity.(*age.X25519Identity); ok {
+			recipients = append(recipients, x25519.Recipient())
+		}
+	}
+	return recipients
+}
+
+// Decrypt decrypts binary or armored age content in memory
+func Decrypt(content []byte, identities []age.Identity) ([]byte, error) {
+	var src io.Reader = bytes.NewReader(bytes.TrimLeft(content, " \t\r\n"))
+	buffered := bufio.NewReader(src)
+	if start, _ := buffered.Peek(len(armor.Header)); string(start) == armor.Header {
+		src = armor.NewReader(buffered)
+	} else {
+		src = buffered
+	}
+	reader, err := age.Decrypt(src, identities...)
+	if err != nil {
+		return nil, fmt.Errorf("error decrypting age content: %w", err)
+	}
+	plain, err := io.ReadAll(reader)
+	if err != nil {
+		return nil, fmt.Errorf("error decrypting age content: %w", err)
+	}
+	return plain, nil
+}
+
+// Encrypt encrypts the content for the recipients, armored content can be reviewed in text based tools
+func Encrypt(content []byte, recipients []age.Recipient, armored bool) ([]byte, error) {
+	if len(recipients) == 0 {
+		return nil, fmt.Errorf("no age recipient given")
+	}
+	var encrypted bytes.Buffer
+	var dst io.WriteCloser = nopCloser{&encrypted}
+	if armored {
+		dst = armor.NewWriter(&encrypted)
+	}
+	writer, err := age.Encrypt(dst, recipients...)
+	if err != nil {
+		return nil, fmt.Errorf("error encrypting age content: %w", err)
+	}
+	if _, err := writer.Write(content); err != nil {
+		return nil, fmt.Errorf("error encrypting age content: %w", err)
+	}
+	if err := writer.Close(); err != nil {
+		return nil, fmt.Errorf("error encrypting age content: %w", err)
+	}
+	if err := dst.Close(); err != nil {
+		return nil, fmt.Errorf("error encrypting age content: %w", err)
+	}
+	return encrypted.Bytes(), nil
+}
+
+type nopCloser struct {
+	io.Writer
+}
+
+func (nopCloser) Close() error {
+	return nil
+}
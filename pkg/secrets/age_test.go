@@ -0,0 +1,105 @@
+package secrets
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"filippo.io/age"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEncryptDecrypt(t *testing.T) {
+	identity, err := age.GenerateX25519Identity()
+	require.NoError(t, err)
+	other, err := age.GenerateX25519Identity()
+	require.NoError(t, err)
+	plain := []byte(`{"TOKEN":"abc"}`)
+
+	for name, armored := range map[string]bool{"armored": true, "binary": false} {
+		t.Run(name, func(t *testing.T) {
+			encrypted, err := Encrypt(plain, []age.Recipient{identity.Recipient()}, armored)
+			require.NoError(t, err)
+
+			assert.True(t, IsEncrypted(encrypted))
+			assert.NotContains(t, string(encrypted), "abc")
+
+			decrypted, err := Decrypt(encrypted, []age.Identity{identity})
+			assert.NoError(t, err)
+			assert.Equal(t, plain, decrypted)
+
+			_, err = Decrypt(encrypted, []age.Identity{other})
+			assert.ErrorContains(t, err, "error decrypting age content")
+		})
+	}
+
+	t.Run("encryption requires a recipient", func(t *testing.T) {
+		_, err := Encrypt(plain, nil, true)
+
+		assert.ErrorContains(t, err, "no age recipient given")
+	})
+
+	t.Run("plain content is not encrypted", func(t *testing.T) {
+		assert.False(t, IsEncrypted(plain))
+	})
+}
+
+func TestIdentities(t *testing.T) {
+	identity, err := age.GenerateX25519Identity()
+	require.NoError(t, err)
+	identityFile := filepath.Join(t.TempDir(), "keys.txt")
+	require.NoError(t, os.WriteFile(identityFile, []byte("# created for tests\n"+identity.String()+"\n"), 0600))
+
+	t.Run("identity file", func(t *testing.T) {
+		t.Setenv(AGE_IDENTITY_FILE_ENV, "")
+		t.Setenv(AGE_IDENTITY_ENV, "")
+
+		identities, err := Identities(identityFile)
+
+		assert.NoError(t, err)
+		assert.Equal(t, []age.Recipient{identity.Recipient()}, RecipientsOf(identities))
+	})
+
+	t.Run("identity file from the environment", func(t *testing.T) {
+		t.Setenv(AGE_IDENTITY_FILE_ENV, identityFile)
+
+		identities, err := Identities("")
+
+		assert.NoError(t, err)
+		assert.Len(t, identities, 1)
+	})
+
+	t.Run("identity from the environment", func(t *testing.T) {
+		t.Setenv(AGE_IDENTITY_FILE_ENV, "")
+		t.Setenv(AGE_IDENTITY_ENV, identity.String())
+
+		identities, err := Identities("")
+
+		assert.NoError(t, err)
+		assert.Len(t, identities, 1)
+	})
+
+	t.Run("no identity", func(t *testing.T) {
+		t.Setenv(AGE_IDENTITY_FILE_ENV, "")
+		t.Setenv(AGE_IDENTITY_ENV, "")
+
+		_, err := Identities("")
+
+		assert.ErrorContains(t, err, "no age identity found")
+	})
+}
+
+func TestRecipients(t *testing.T) {
+	identity, err := age.GenerateX25519Identity()
+	require.NoError(t, err)
+	recipientsFile := filepath.Join(t.TempDir(), "recipients.txt")
+	require.NoError(t, os.WriteFile(recipientsFile, []byte(identity.Recipient().String()+"\n"), 0644))
+
+	recipients, err := Recipients([]string{identity.Recipient().String()}, []string{recipientsFile})
+	assert.NoError(t, err)
+	assert.Len(t, recipients, 2)
+
+	_, err = Recipients([]string{"invalid"}, nil)
+	assert.ErrorContains(t, err, "error parsing age recipient invalid")
+}
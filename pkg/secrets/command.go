@@ -0,0 +1,120 @@
+package secrets
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os/exec"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const DEFAULT_TIMEOUT = 30 * time.Second
+
+// RunCommand runs the command without a shell and returns its output on stdout, the output on stderr is added to errors
+func RunCommand(command []string, env []string, timeout time.Duration) ([]byte, error) {
+	if len(command) == 0 {
+		return nil, fmt.Errorf("missing command")
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	var stdout, stderr bytes.Buffer
+	cmd := exec.CommandContext(ctx, command[0], command[1:]...)
+	cmd.Env = env
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	// children of the command might keep stdout open after it was killed
+	cmd.WaitDelay = time.Second
+	err := cmd.Run()
+	if ctx.Err() == context.DeadlineExceeded {
+		return nil, fmt.Errorf("command %s timed out after %s", command[0], timeout)
+	}
+	if err != nil {
+		if message := strings.TrimSpace(stderr.String()); message != "" {
+			return nil, fmt.Errorf("command %s failed: %w: %s", command[0], err, message)
+		}
+		return nil, fmt.Errorf("command %s failed: %w", command[0], err)
+	}
+	return stdout.Bytes(), nil
+}
+
+// ParseSecrets parses a flat JSON object, numbers and booleans are converted to strings
+func ParseSecrets(content []byte) (map[string]string, error) {
+	var values map[string]interface{}
+	decoder := json.NewDecoder(bytes.NewReader(content))
+	decoder.UseNumber()
+	if err := decoder.Decode(&values); err != nil {
+		return nil, fmt.Errorf("could not parse json data: %w", err)
+	}
+	return ToSecrets(values)
+}
+
+// ToSecrets converts the values of a map to strings, nested values are rejected
+func ToSecrets(values map[string]interface{}) (map[string]string, error) {
+	secrets := make(map[string]string, len(values))
+	for name, value := range values {
+		switch v := value.(type) {
+		case string:
+			secrets[name] = v
+		case json.Number:
+			secrets[name] = v.String()
+		case float64:
+			secrets[name] = strconv.FormatFloat(v, 'f', -1, 64)
+		case int:
+			secrets[name] = strconv.Itoa(v)
+		case bool:
+			secrets[name] = strconv.FormatBool(v)
+		default:
+			return nil, fmt.Errorf("value of secret '%s' must be a string", name)
+		}
+	}
+	return secrets, nil
+}
+
+// ParseTimeout reads the optional 'timeout' setting like '30s'
+func ParseTimeout(config map[string]interface{}, defaultTimeout time.Duration) (time.Duration, error) {
+	if config["timeout"] == nil {
+		return defaultTimeout, nil
+	}
+	value, ok := config["timeout"].(string)
+	if !ok {
+		return 0, fmt.Errorf("timeout must be a string")
+	}
+	timeout, err := time.ParseDuration(value)
+	if err != nil || timeout <= 0 {
+		return 0, fmt.Errorf("timeout must be a positive duration like '30s'")
+	}
+	return timeout, nil
+}
+
+// ParseCommand reads a command given as list or as string which is split at whitespace
+func ParseCommand(config map[string]interface{}, key string) ([]string, error) {
+	switch value := config[key].(type) {
+	case nil:
+		return nil, fmt.Errorf("missing '%s' in config", key)
+	case string:
+		command := strings.Fields(value)
+		if len(command) == 0 {
+			return nil, fmt.Errorf("%s must not be empty", key)
+		}
+		return command, nil
+	case []interface{}:
+		command := make([]string, 0, len(value))
+		for _, part := range value {
+			partString, ok := part.(string)
+			if !ok {
+				return nil, fmt.Errorf("%s must be a list of strings", key)
+			}
+			command = append(command, partString)
+		}
+		if len(command) == 0 {
+			return nil, fmt.Errorf("%s must not be empty", key)
+		}
+		return command, nil
+	default:
+		return nil, fmt.Errorf("%s must be a string or a list of strings", key)
+	}
+}
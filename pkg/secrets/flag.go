@@ -0,0 +1,28 @@
+package secrets
+
+import (
+	"fmt"
+	"strings"
+)
+
+// ParseProviderFlag parses a provider given as "<type>" or "<type>:<key>=<value>,<key>=<value>"
+// Example "vault:address=http://127.0.0.1:8200,path=onyx"
+func ParseProviderFlag(value string) (ProviderConfig, error) {
+	typeName, settings, _ := strings.Cut(value, ":")
+	typeName = strings.TrimSpace(typeName)
+	if typeName == "" {
+		return ProviderConfig{}, fmt.Errorf("missing type in secret provider '%s'", value)
+	}
+	config := make(map[string]interface{})
+	if strings.TrimSpace(settings) != "" {
+		for _, setting := range strings.Split(settings, ",") {
+			key, settingValue, ok := strings.Cut(setting, "=")
+			key = strings.TrimSpace(key)
+			if !ok || key == "" {
+				return ProviderConfig{}, fmt.Errorf("invalid setting '%s' in secret provider '%s', expected <key>=<value>", setting, value)
+			}
+			config[key] = settingValue
+		}
+	}
+	return ProviderConfig{Type: typeName, Config: config}, nil
+}
@@ -0,0 +1,96 @@
+package secrets
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/B-S-F/onyx/pkg/logger"
+)
+
+type Config interface {
+	Type() string
+}
+
+// SecretProvider reads secrets from a single source
+type SecretProvider interface {
+	Secrets() (map[string]string, error)
+	Name() string
+}
+
+// ProviderConfig selects a secret provider in onyx.yaml or via the --secret-provider flag
+type ProviderConfig struct {
+	// Name used in logs and errors, defaults to the type
+	Name string `mapstructure:"name"`
+	// Type of the provider, e.g. "env" or "vault"
+	Type string `mapstructure:"type"`
+	// Config is passed to the provider which is responsible for validating it
+	Config map[string]interface{} `mapstructure:"config"`
+}
+
+func (p ProviderConfig) name() string {
+	if p.Name != "" {
+		return p.Name
+	}
+	return p.Type
+}
+
+type ProviderFactory struct {
+	toProvider map[string]func(name string, config map[string]interface{}) (SecretProvider, error)
+}
+
+func (f *ProviderFactory) New(name string, typeName string, config map[string]interface{}) (SecretProvider, error) {
+	if toProvider, ok := f.toProvider[typeName]; ok {
+		if config == nil {
+			config = map[string]interface{}{}
+		}
+		return toProvider(name, config)
+	}
+	return nil, fmt.Errorf("unsupported secret provider type: %s", typeName)
+}
+
+func (f *ProviderFactory) Register(typeName string, conversion func(name string, config map[string]interface{}) (SecretProvider, error)) {
+	f.toProvider[typeName] = conversion
+}
+
+func NewProviderFactory() *ProviderFactory {
+	return &ProviderFactory{
+		toProvider: make(map[string]func(name string, config map[string]interface{}) (SecretProvider, error)),
+	}
+}
+
+// NewProviders creates the configured providers in their declared order
+func (f *ProviderFactory) NewProviders(configs []ProviderConfig) ([]SecretProvider, error) {
+	providers := make([]SecretProvider, 0, len(configs))
+	for _, config := range configs {
+		provider, err := f.New(config.name(), config.Type, config.Config)
+		if err != nil {
+			return nil, fmt.Errorf("error creating secret provider '%s': %w", config.name(), err)
+		}
+		providers = append(providers, provider)
+	}
+	return providers, nil
+}
+
+// Load reads the secrets of all providers, secrets of later providers replace secrets with the same name of earlier ones
+func Load(providers []SecretProvider) (map[string]string, error) {
+	secrets := make(map[string]string)
+	for _, provider := range providers {
+		provided, err := provider.Secrets()
+		if err != nil {
+			return nil, fmt.Errorf("error reading secrets from provider '%s': %w", provider.Name(), err)
+		}
+		names := make([]string, 0, len(provided))
+		for name := range provided {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			if _, ok := secrets[name]; ok {
+				logger.Get().Debugf("secret '%s' is replaced by provider '%s'", name, provider.Name())
+			}
+			secrets[name] = provided[name]
+		}
+		logger.Get().Infof("read %d secrets from provider '%s'", len(provided), provider.Name())
+	}
+	return secrets, nil
+}
@@ -0,0 +1,142 @@
+package secrets
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type mockProvider struct {
+	name    string
+	secrets map[string]string
+	err     error
+}
+
+func (m *mockProvider) Secrets() (map[string]string, error) {
+	return m.secrets, m.err
+}
+
+func (m *mockProvider) Name() string {
+	return m.name
+}
+
+func TestProviderFactory(t *testing.T) {
+	factory := NewProviderFactory()
+	factory.Register("mock", func(name string, config map[string]interface{}) (SecretProvider, error) {
+		if config["fail"] != nil {
+			return nil, assert.AnError
+		}
+		return &mockProvider{name: name}, nil
+	})
+
+	t.Run("providers are created in their declared order", func(t *testing.T) {
+		providers, err := factory.NewProviders([]ProviderConfig{
+			{Type: "mock"},
+			{Name: "second", Type: "mock"},
+		})
+
+		assert.NoError(t, err)
+		assert.Len(t, providers, 2)
+		assert.Equal(t, "mock", providers[0].Name())
+		assert.Equal(t, "second", providers[1].Name())
+	})
+
+	t.Run("error names the provider", func(t *testing.T) {
+		_, err := factory.NewProviders([]ProviderConfig{{Name: "broken", Type: "mock", Config: map[string]interface{}{"fail": true}}})
+
+		assert.ErrorContains(t, err, "error creating secret provider 'broken'")
+	})
+
+	t.Run("unsupported type", func(t *testing.T) {
+		_, err := factory.NewProviders([]ProviderConfig{{Type: "unknown"}})
+
+		assert.ErrorContains(t, err, "unsupported secret provider type: unknown")
+	})
+}
+
+func TestLoad(t *testing.T) {
+	t.Run("later providers replace secrets of earlier ones", func(t *testing.T) {
+		secrets, err := Load([]SecretProvider{
+			&mockProvider{name: "first", secrets: map[string]string{"A": "1", "B": "1"}},
+			&mockProvider{name: "second", secrets: map[string]string{"B": "2"}},
+		})
+
+		assert.NoError(t, err)
+		assert.Equal(t, map[string]string{"A": "1", "B": "2"}, secrets)
+	})
+
+	t.Run("error names the failing provider", func(t *testing.T) {
+		_, err := Load([]SecretProvider{
+			&mockProvider{name: "first", secrets: map[string]string{"A": "1"}},
+			&mockProvider{name: "vault", err: assert.AnError},
+		})
+
+		assert.ErrorContains(t, err, "error reading secrets from provider 'vault'")
+		assert.ErrorIs(t, err, assert.AnError)
+	})
+}
+
+func TestParseProviderFlag(t *testing.T) {
+	testCases := map[string]struct {
+		value   string
+		want    ProviderConfig
+		wantErr string
+	}{
+		"type only": {
+			value: "file",
+			want:  ProviderConfig{Type: "file", Config: map[string]interface{}{}},
+		},
+		"type with settings": {
+			value: "vault:address=http://127.0.0.1:8200,path=onyx/prod",
+			want: ProviderConfig{Type: "vault", Config: map[string]interface{}{
+				"address": "http://127.0.0.1:8200",
+				"path":    "onyx/prod",
+			}},
+		},
+		"value containing an equal sign": {
+			value: "env:prefix=A=",
+			want:  ProviderConfig{Type: "env", Config: map[string]interface{}{"prefix": "A="}},
+		},
+		"missing type": {
+			value:   ":prefix=A",
+			wantErr: "missing type",
+		},
+		"invalid setting": {
+			value:   "env:prefix",
+			wantErr: "invalid setting 'prefix'",
+		},
+	}
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			got, err := ParseProviderFlag(tc.value)
+
+			if tc.wantErr != "" {
+				assert.ErrorContains(t, err, tc.wantErr)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tc.want, got)
+		})
+	}
+}
+
+func TestParseSecrets(t *testing.T) {
+	t.Run("scalars are converted to strings", func(t *testing.T) {
+		secrets, err := ParseSecrets([]byte(`{"TOKEN":"abc","PORT":8080,"ENABLED":true}`))
+
+		assert.NoError(t, err)
+		assert.Equal(t, map[string]string{"TOKEN": "abc", "PORT": "8080", "ENABLED": "true"}, secrets)
+	})
+
+	t.Run("nested values are rejected", func(t *testing.T) {
+		_, err := ParseSecrets([]byte(`{"NESTED":{"A":"b"}}`))
+
+		assert.ErrorContains(t, err, "value of secret 'NESTED' must be a string")
+	})
+
+	t.Run("invalid json", func(t *testing.T) {
+		_, err := ParseSecrets([]byte(`not json`))
+
+		assert.ErrorContains(t, err, "could not parse json data")
+	})
+}
@@ -0,0 +1,31 @@
+package env
+
+import (
+	"fmt"
+
+	"github.com/B-S-F/onyx/pkg/secrets"
+)
+
+type Config struct {
+	// Prefix of the environment variables which are read, it is removed from the secret names
+	// Example "ONYX_SECRET_" provides the variable ONYX_SECRET_TOKEN as secret TOKEN
+	Prefix string
+}
+
+func (c Config) Type() string {
+	return "env"
+}
+
+func newConfig(config map[string]interface{}) (secrets.Config, error) {
+	if config["prefix"] == nil {
+		return nil, fmt.Errorf("missing 'prefix' in config")
+	}
+	prefix, ok := config["prefix"].(string)
+	if !ok {
+		return nil, fmt.Errorf("prefix must be a string")
+	}
+	if prefix == "" {
+		return nil, fmt.Errorf("prefix must not be empty, otherwise the whole environment would be provided as secrets")
+	}
+	return Config{Prefix: prefix}, nil
+}
@@ -0,0 +1,45 @@
+package env
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/B-S-F/onyx/pkg/secrets"
+)
+
+// Provider reads the secrets from environment variables with a prefix
+type Provider struct {
+	Config       Config
+	ProviderName string
+	environ      func() []string
+}
+
+func NewProvider(name string, config map[string]interface{}) (secrets.SecretProvider, error) {
+	parsed, err := newConfig(config)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create config: %w", err)
+	}
+	return &Provider{
+		Config:       parsed.(Config),
+		ProviderName: name,
+		environ:      os.Environ,
+	}, nil
+}
+
+func (p *Provider) Secrets() (map[string]string, error) {
+	provided := make(map[string]string)
+	for _, variable := range p.environ() {
+		key, value, _ := strings.Cut(variable, "=")
+		name, ok := strings.CutPrefix(key, p.Config.Prefix)
+		if !ok || name == "" {
+			continue
+		}
+		provided[name] = value
+	}
+	return provided, nil
+}
+
+func (p *Provider) Name() string {
+	return p.ProviderName
+}
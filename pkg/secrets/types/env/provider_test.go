@@ -0,0 +1,40 @@
+package env
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewProvider(t *testing.T) {
+	t.Run("missing prefix", func(t *testing.T) {
+		_, err := NewProvider("env", map[string]interface{}{})
+
+		assert.ErrorContains(t, err, "missing 'prefix' in config")
+	})
+
+	t.Run("empty prefix", func(t *testing.T) {
+		_, err := NewProvider("env", map[string]interface{}{"prefix": ""})
+
+		assert.ErrorContains(t, err, "prefix must not be empty")
+	})
+}
+
+func TestSecrets(t *testing.T) {
+	provider := &Provider{
+		Config:       Config{Prefix: "ONYX_SECRET_"},
+		ProviderName: "env",
+		environ: func() []string {
+			return []string{
+				"ONYX_SECRET_TOKEN=abc=def",
+				"ONYX_SECRET_=ignored",
+				"HOME=/root",
+			}
+		},
+	}
+
+	secrets, err := provider.Secrets()
+
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]string{"TOKEN": "abc=def"}, secrets)
+}
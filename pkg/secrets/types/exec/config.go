@@ -0,0 +1,31 @@
+package exec
+
+import (
+	"time"
+
+	"github.com/B-S-F/onyx/pkg/secrets"
+)
+
+type Config struct {
+	// Command which prints the secrets as flat JSON object on stdout, it is run without a shell
+	// Example ["pass-export", "--json"] or "pass-export --json"
+	Command []string
+	// Timeout of the command
+	Timeout time.Duration
+}
+
+func (c Config) Type() string {
+	return "exec"
+}
+
+func newConfig(config map[string]interface{}) (secrets.Config, error) {
+	command, err := secrets.ParseCommand(config, "command")
+	if err != nil {
+		return nil, err
+	}
+	timeout, err := secrets.ParseTimeout(config, secrets.DEFAULT_TIMEOUT)
+	if err != nil {
+		return nil, err
+	}
+	return Config{Command: command, Timeout: timeout}, nil
+}
@@ -0,0 +1,40 @@
+package exec
+
+import (
+	"fmt"
+
+	"github.com/B-S-F/onyx/pkg/secrets"
+)
+
+// Provider runs a command and reads the secrets from its JSON output
+type Provider struct {
+	Config       Config
+	ProviderName string
+}
+
+func NewProvider(name string, config map[string]interface{}) (secrets.SecretProvider, error) {
+	parsed, err := newConfig(config)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create config: %w", err)
+	}
+	return &Provider{
+		Config:       parsed.(Config),
+		ProviderName: name,
+	}, nil
+}
+
+func (p *Provider) Secrets() (map[string]string, error) {
+	output, err := secrets.RunCommand(p.Config.Command, nil, p.Config.Timeout)
+	if err != nil {
+		return nil, err
+	}
+	provided, err := secrets.ParseSecrets(output)
+	if err != nil {
+		return nil, fmt.Errorf("error decoding output of command %s: %w", p.Config.Command[0], err)
+	}
+	return provided, nil
+}
+
+func (p *Provider) Name() string {
+	return p.ProviderName
+}
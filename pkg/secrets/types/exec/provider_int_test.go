@@ -0,0 +1,67 @@
+//go:build integration
+// +build integration
+
+package exec
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func writeScript(t *testing.T, script string) string {
+	path := filepath.Join(t.TempDir(), "secrets.sh")
+	require.NoError(t, os.WriteFile(path, []byte("#!/bin/sh\n"+script), 0755))
+	return path
+}
+
+func TestSecrets(t *testing.T) {
+	testCases := map[string]struct {
+		script  string
+		args    []interface{}
+		timeout string
+		want    map[string]string
+		wantErr string
+	}{
+		"secrets are read from stdout": {
+			script: `echo "{\"TOKEN\":\"$1\"}"`,
+			args:   []interface{}{"abc"},
+			want:   map[string]string{"TOKEN": "abc"},
+		},
+		"stderr is added to errors": {
+			script:  "echo 'not logged in' >&2\nexit 1",
+			wantErr: "not logged in",
+		},
+		"invalid output": {
+			script:  "echo 'TOKEN=abc'",
+			wantErr: "error decoding output of command",
+		},
+		"timeout": {
+			script:  "sleep 5",
+			timeout: "100ms",
+			wantErr: "timed out after 100ms",
+		},
+	}
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			config := map[string]interface{}{"command": append([]interface{}{writeScript(t, tc.script)}, tc.args...)}
+			if tc.timeout != "" {
+				config["timeout"] = tc.timeout
+			}
+			provider, err := NewProvider("exec", config)
+			require.NoError(t, err)
+
+			secrets, err := provider.Secrets()
+
+			if tc.wantErr != "" {
+				assert.ErrorContains(t, err, tc.wantErr)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tc.want, secrets)
+		})
+	}
+}
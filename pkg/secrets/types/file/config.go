@@ -0,0 +1,31 @@
+package file
+
+import (
+	"fmt"
+
+	"github.com/B-S-F/onyx/pkg/secrets"
+)
+
+type Config struct {
+	// Path of the JSON file, defaults to the secrets file in the input folder
+	Path string
+}
+
+func (c Config) Type() string {
+	return "file"
+}
+
+func newConfig(defaultPath string, config map[string]interface{}) (secrets.Config, error) {
+	path := defaultPath
+	if config["path"] != nil {
+		value, ok := config["path"].(string)
+		if !ok {
+			return nil, fmt.Errorf("path must be a string")
+		}
+		path = value
+	}
+	if path == "" {
+		return nil, fmt.Errorf("missing 'path' in config")
+	}
+	return Config{Path: path}, nil
+}
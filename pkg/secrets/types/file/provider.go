@@ -0,0 +1,38 @@
+package file
+
+import (
+	"fmt"
+
+	"github.com/B-S-F/onyx/pkg/reader"
+	"github.com/B-S-F/onyx/pkg/secrets"
+)
+
+// Provider reads the secrets from a JSON file like the .secrets file of the input folder
+type Provider struct {
+	Config       Config
+	ProviderName string
+	reader       reader.FileReader
+}
+
+// NewFactory returns the constructor of file providers, the default path is used if the config has no 'path'
+func NewFactory(defaultPath string) func(name string, config map[string]interface{}) (secrets.SecretProvider, error) {
+	return func(name string, config map[string]interface{}) (secrets.SecretProvider, error) {
+		parsed, err := newConfig(defaultPath, config)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create config: %w", err)
+		}
+		return &Provider{
+			Config:       parsed.(Config),
+			ProviderName: name,
+			reader:       reader.New(),
+		}, nil
+	}
+}
+
+func (p *Provider) Secrets() (map[string]string, error) {
+	return p.reader.ReadJsonMap(p.Config.Path)
+}
+
+func (p *Provider) Name() string {
+	return p.ProviderName
+}
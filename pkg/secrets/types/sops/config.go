@@ -0,0 +1,59 @@
+package sops
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/B-S-F/onyx/pkg/secrets"
+)
+
+const DEFAULT_EXECUTABLE = "sops"
+
+type Config struct {
+	// File encrypted with sops, its decrypted content must be a flat map
+	File string
+	// Executable of sops, defaults to sops in the PATH
+	Executable string
+	// AgeKeyFile is passed as SOPS_AGE_KEY_FILE, otherwise the environment of onyx decides which keys are used
+	AgeKeyFile string
+	// Timeout of the decryption
+	Timeout time.Duration
+}
+
+func (c Config) Type() string {
+	return "sops"
+}
+
+func newConfig(config map[string]interface{}) (secrets.Config, error) {
+	if config["file"] == nil {
+		return nil, fmt.Errorf("missing 'file' in config")
+	}
+	file, ok := config["file"].(string)
+	if !ok || file == "" {
+		return nil, fmt.Errorf("file must be a non-empty string")
+	}
+	parsed := Config{
+		File:       file,
+		Executable: DEFAULT_EXECUTABLE,
+	}
+	if config["executable"] != nil {
+		executable, ok := config["executable"].(string)
+		if !ok || executable == "" {
+			return nil, fmt.Errorf("executable must be a non-empty string")
+		}
+		parsed.Executable = executable
+	}
+	if config["age-key-file"] != nil {
+		ageKeyFile, ok := config["age-key-file"].(string)
+		if !ok {
+			return nil, fmt.Errorf("age-key-file must be a string")
+		}
+		parsed.AgeKeyFile = ageKeyFile
+	}
+	timeout, err := secrets.ParseTimeout(config, secrets.DEFAULT_TIMEOUT)
+	if err != nil {
+		return nil, err
+	}
+	parsed.Timeout = timeout
+	return parsed, nil
+}
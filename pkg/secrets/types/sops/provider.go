@@ -0,0 +1,46 @@
+package sops
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/B-S-F/onyx/pkg/secrets"
+)
+
+// Provider decrypts a sops file, e.g. encrypted with age, by running the sops executable
+type Provider struct {
+	Config       Config
+	ProviderName string
+}
+
+func NewProvider(name string, config map[string]interface{}) (secrets.SecretProvider, error) {
+	parsed, err := newConfig(config)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create config: %w", err)
+	}
+	return &Provider{
+		Config:       parsed.(Config),
+		ProviderName: name,
+	}, nil
+}
+
+func (p *Provider) Secrets() (map[string]string, error) {
+	env := os.Environ()
+	if p.Config.AgeKeyFile != "" {
+		env = append(env, "SOPS_AGE_KEY_FILE="+p.Config.AgeKeyFile)
+	}
+	command := []string{p.Config.Executable, "--decrypt", "--output-type", "json", p.Config.File}
+	output, err := secrets.RunCommand(command, env, p.Config.Timeout)
+	if err != nil {
+		return nil, fmt.Errorf("error decrypting %s: %w", p.Config.File, err)
+	}
+	provided, err := secrets.ParseSecrets(output)
+	if err != nil {
+		return nil, fmt.Errorf("error decoding decrypted %s: %w", p.Config.File, err)
+	}
+	return provided, nil
+}
+
+func (p *Provider) Name() string {
+	return p.ProviderName
+}
@@ -0,0 +1,60 @@
+//go:build integration
+// +build integration
+
+package sops
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// writeSops creates a stand-in for sops which prints its arguments and the age key file as decrypted secrets
+func writeSops(t *testing.T) string {
+	path := filepath.Join(t.TempDir(), "sops")
+	script := `#!/bin/sh
+[ -f "$4" ] || { echo "failed to read $4" >&2; exit 128; }
+echo "{\"ARGS\":\"$1 $2 $3\",\"AGE_KEY_FILE\":\"$SOPS_AGE_KEY_FILE\"}"
+`
+	require.NoError(t, os.WriteFile(path, []byte(script), 0755))
+	return path
+}
+
+func TestSecrets(t *testing.T) {
+	executable := writeSops(t)
+	file := filepath.Join(t.TempDir(), "secrets.enc.json")
+	require.NoError(t, os.WriteFile(file, []byte("{}"), 0644))
+
+	t.Run("file is decrypted with the age key file", func(t *testing.T) {
+		provider, err := NewProvider("sops", map[string]interface{}{
+			"file":         file,
+			"executable":   executable,
+			"age-key-file": "/keys/age.txt",
+		})
+		require.NoError(t, err)
+
+		secrets, err := provider.Secrets()
+
+		assert.NoError(t, err)
+		assert.Equal(t, map[string]string{
+			"ARGS":         "--decrypt --output-type json",
+			"AGE_KEY_FILE": "/keys/age.txt",
+		}, secrets)
+	})
+
+	t.Run("error names the file", func(t *testing.T) {
+		provider, err := NewProvider("sops", map[string]interface{}{
+			"file":       "missing.enc.json",
+			"executable": executable,
+		})
+		require.NoError(t, err)
+
+		_, err = provider.Secrets()
+
+		assert.ErrorContains(t, err, "error decrypting missing.enc.json")
+		assert.ErrorContains(t, err, "failed to read missing.enc.json")
+	})
+}
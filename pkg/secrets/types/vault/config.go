@@ -0,0 +1,120 @@
+package vault
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/B-S-F/onyx/pkg/secrets"
+)
+
+const DEFAULT_MOUNT = "secret"
+
+type Config struct {
+	// Address of the vault server, defaults to VAULT_ADDR
+	// Example "https://vault.example.com:8200"
+	Address string
+	// Token used for authentication, defaults to the content of TokenFile or VAULT_TOKEN
+	Token string
+	// TokenFile contains the token, e.g. written by a vault agent
+	TokenFile string
+	// Namespace of vault enterprise, defaults to VAULT_NAMESPACE
+	Namespace string
+	// Mount path of the KV secrets engine
+	Mount string
+	// Path of the secret in the KV secrets engine, all its keys are provided as secrets
+	Path string
+	// KVVersion of the secrets engine, 1 or 2
+	KVVersion int
+	// Timeout of the request
+	Timeout time.Duration
+}
+
+func (c Config) Type() string {
+	return "vault"
+}
+
+func newConfig(config map[string]interface{}) (secrets.Config, error) {
+	parsed := Config{
+		Address:   os.Getenv("VAULT_ADDR"),
+		Token:     os.Getenv("VAULT_TOKEN"),
+		Namespace: os.Getenv("VAULT_NAMESPACE"),
+		Mount:     DEFAULT_MOUNT,
+		KVVersion: 2,
+	}
+	for key, target := range map[string]*string{
+		"address":    &parsed.Address,
+		"token":      &parsed.Token,
+		"token-file": &parsed.TokenFile,
+		"namespace":  &parsed.Namespace,
+		"mount":      &parsed.Mount,
+		"path":       &parsed.Path,
+	} {
+		if config[key] == nil {
+			continue
+		}
+		value, ok := config[key].(string)
+		if !ok {
+			return nil, fmt.Errorf("%s must be a string", key)
+		}
+		*target = value
+	}
+	if parsed.Address == "" {
+		return nil, fmt.Errorf("missing 'address' in config and VAULT_ADDR is not set")
+	}
+	parsed.Address = strings.TrimSuffix(parsed.Address, "/")
+	parsed.Mount = strings.Trim(parsed.Mount, "/")
+	parsed.Path = strings.Trim(parsed.Path, "/")
+	if parsed.Path == "" {
+		return nil, fmt.Errorf("missing 'path' in config")
+	}
+	if parsed.Mount == "" {
+		return nil, fmt.Errorf("mount must not be empty")
+	}
+	if parsed.TokenFile != "" {
+		content, err := os.ReadFile(parsed.TokenFile)
+		if err != nil {
+			return nil, fmt.Errorf("error reading token file: %w", err)
+		}
+		parsed.Token = strings.TrimSpace(string(content))
+	}
+	if parsed.Token == "" {
+		return nil, fmt.Errorf("missing 'token' or 'token-file' in config and VAULT_TOKEN is not set")
+	}
+	if config["kv-version"] != nil {
+		version, err := parseKVVersion(config["kv-version"])
+		if err != nil {
+			return nil, err
+		}
+		parsed.KVVersion = version
+	}
+	timeout, err := secrets.ParseTimeout(config, secrets.DEFAULT_TIMEOUT)
+	if err != nil {
+		return nil, err
+	}
+	parsed.Timeout = timeout
+	return parsed, nil
+}
+
+// parseKVVersion accepts numbers from onyx.yaml and strings from the --secret-provider flag
+func parseKVVersion(value interface{}) (int, error) {
+	var version int
+	switch v := value.(type) {
+	case int:
+		version = v
+	case float64:
+		version = int(v)
+	case string:
+		parsedVersion, err := strconv.Atoi(v)
+		if err != nil {
+			return 0, fmt.Errorf("kv-version must be 1 or 2")
+		}
+		version = parsedVersion
+	}
+	if version != 1 && version != 2 {
+		return 0, fmt.Errorf("kv-version must be 1 or 2")
+	}
+	return version, nil
+}
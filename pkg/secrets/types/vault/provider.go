@@ -0,0 +1,94 @@
+package vault
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/B-S-F/onyx/pkg/secrets"
+)
+
+// Provider reads the secrets from a secret of the HashiCorp Vault KV secrets engine
+type Provider struct {
+	Config       Config
+	ProviderName string
+	client       *http.Client
+}
+
+type response struct {
+	Data   map[string]interface{} `json:"data"`
+	Errors []string               `json:"errors"`
+}
+
+func NewProvider(name string, config map[string]interface{}) (secrets.SecretProvider, error) {
+	parsed, err := newConfig(config)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create config: %w", err)
+	}
+	vaultConfig := parsed.(Config)
+	return &Provider{
+		Config:       vaultConfig,
+		ProviderName: name,
+		client:       &http.Client{Timeout: vaultConfig.Timeout},
+	}, nil
+}
+
+func (p *Provider) Secrets() (map[string]string, error) {
+	request, err := http.NewRequest(http.MethodGet, p.url(), nil)
+	if err != nil {
+		return nil, fmt.Errorf("error creating request: %w", err)
+	}
+	request.Header.Set("X-Vault-Token", p.Config.Token)
+	if p.Config.Namespace != "" {
+		request.Header.Set("X-Vault-Namespace", p.Config.Namespace)
+	}
+	resp, err := p.client.Do(request)
+	if err != nil {
+		return nil, fmt.Errorf("error requesting secret %s/%s: %w", p.Config.Mount, p.Config.Path, err)
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("error reading response: %w", err)
+	}
+
+	var decoded response
+	decodeErr := json.Unmarshal(body, &decoded)
+	if resp.StatusCode != http.StatusOK {
+		if decodeErr == nil && len(decoded.Errors) > 0 {
+			return nil, fmt.Errorf("error reading secret %s/%s: %s: %s", p.Config.Mount, p.Config.Path, resp.Status, strings.Join(decoded.Errors, ", "))
+		}
+		return nil, fmt.Errorf("error reading secret %s/%s: %s", p.Config.Mount, p.Config.Path, resp.Status)
+	}
+	if decodeErr != nil {
+		return nil, fmt.Errorf("error decoding response: %w", decodeErr)
+	}
+
+	data := decoded.Data
+	if p.Config.KVVersion == 2 {
+		// the secret of a KV v2 engine is wrapped with its metadata
+		nested, ok := data["data"].(map[string]interface{})
+		if !ok {
+			return nil, fmt.Errorf("secret %s/%s has no data, it might be deleted", p.Config.Mount, p.Config.Path)
+		}
+		data = nested
+	}
+	provided, err := secrets.ToSecrets(data)
+	if err != nil {
+		return nil, fmt.Errorf("error reading secret %s/%s: %w", p.Config.Mount, p.Config.Path, err)
+	}
+	return provided, nil
+}
+
+func (p *Provider) url() string {
+	if p.Config.KVVersion == 2 {
+		return fmt.Sprintf("%s/v1/%s/data/%s", p.Config.Address, p.Config.Mount, p.Config.Path)
+	}
+	return fmt.Sprintf("%s/v1/%s/%s", p.Config.Address, p.Config.Mount, p.Config.Path)
+}
+
+func (p *Provider) Name() string {
+	return p.ProviderName
+}
@@ -0,0 +1,154 @@
+//go:build integration
+// +build integration
+
+package vault
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const devRootToken = "root"
+
+// devServer behaves like a vault server started with 'vault server -dev' with a KV v2 engine at secret/ and a KV v1 engine at kv/
+func devServer(t *testing.T) *httptest.Server {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if r.Header.Get("X-Vault-Token") != devRootToken {
+			w.WriteHeader(http.StatusForbidden)
+			_, _ = w.Write([]byte(`{"errors":["permission denied"]}`))
+			return
+		}
+		var body interface{}
+		switch r.URL.Path {
+		case "/v1/secret/data/onyx":
+			body = map[string]interface{}{
+				"data": map[string]interface{}{
+					"data":     map[string]interface{}{"TOKEN": "abc", "PORT": 8080},
+					"metadata": map[string]interface{}{"version": 3},
+				},
+			}
+		case "/v1/secret/data/deleted":
+			body = map[string]interface{}{
+				"data": map[string]interface{}{
+					"data":     nil,
+					"metadata": map[string]interface{}{"deletion_time": "2024-01-01T00:00:00Z"},
+				},
+			}
+		case "/v1/kv/onyx":
+			body = map[string]interface{}{
+				"data": map[string]interface{}{"PASSWORD": "secret"},
+			}
+		default:
+			w.WriteHeader(http.StatusNotFound)
+			_, _ = w.Write([]byte(`{"errors":[]}`))
+			return
+		}
+		require.NoError(t, json.NewEncoder(w).Encode(body))
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestSecrets(t *testing.T) {
+	server := devServer(t)
+
+	testCases := map[string]struct {
+		config  map[string]interface{}
+		want    map[string]string
+		wantErr string
+	}{
+		"kv v2": {
+			config: map[string]interface{}{"path": "onyx"},
+			want:   map[string]string{"TOKEN": "abc", "PORT": "8080"},
+		},
+		"kv v1": {
+			config: map[string]interface{}{"mount": "kv", "path": "onyx", "kv-version": "1"},
+			want:   map[string]string{"PASSWORD": "secret"},
+		},
+		"missing secret": {
+			config:  map[string]interface{}{"path": "missing"},
+			wantErr: "error reading secret secret/missing: 404 Not Found",
+		},
+		"deleted secret": {
+			config:  map[string]interface{}{"path": "deleted"},
+			wantErr: "secret secret/deleted has no data",
+		},
+		"invalid token": {
+			config:  map[string]interface{}{"path": "onyx", "token": "invalid"},
+			wantErr: "403 Forbidden: permission denied",
+		},
+	}
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			config := map[string]interface{}{"address": server.URL, "token": devRootToken}
+			for key, value := range tc.config {
+				config[key] = value
+			}
+			provider, err := NewProvider("vault", config)
+			require.NoError(t, err)
+
+			secrets, err := provider.Secrets()
+
+			if tc.wantErr != "" {
+				assert.ErrorContains(t, err, tc.wantErr)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tc.want, secrets)
+		})
+	}
+}
+
+func TestNewProvider(t *testing.T) {
+	t.Setenv("VAULT_ADDR", "")
+	t.Setenv("VAULT_TOKEN", "")
+
+	testCases := map[string]struct {
+		config  map[string]interface{}
+		wantErr string
+	}{
+		"missing address": {
+			config:  map[string]interface{}{"path": "onyx", "token": devRootToken},
+			wantErr: "missing 'address' in config",
+		},
+		"missing token": {
+			config:  map[string]interface{}{"address": "http://127.0.0.1:8200", "path": "onyx"},
+			wantErr: "missing 'token' or 'token-file' in config",
+		},
+		"missing path": {
+			config:  map[string]interface{}{"address": "http://127.0.0.1:8200", "token": devRootToken},
+			wantErr: "missing 'path' in config",
+		},
+		"invalid kv version": {
+			config:  map[string]interface{}{"address": "http://127.0.0.1:8200", "token": devRootToken, "path": "onyx", "kv-version": 3},
+			wantErr: "kv-version must be 1 or 2",
+		},
+	}
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			_, err := NewProvider("vault", tc.config)
+
+			assert.ErrorContains(t, err, tc.wantErr)
+		})
+	}
+
+	t.Run("address and token from the environment", func(t *testing.T) {
+		t.Setenv("VAULT_ADDR", "http://127.0.0.1:8200/")
+		t.Setenv("VAULT_TOKEN", devRootToken)
+
+		provider, err := NewProvider("vault", map[string]interface{}{"path": "/onyx/"})
+
+		require.NoError(t, err)
+		config := provider.(*Provider).Config
+		assert.Equal(t, "http://127.0.0.1:8200", config.Address)
+		assert.Equal(t, devRootToken, config.Token)
+		assert.Equal(t, "onyx", config.Path)
+		assert.Equal(t, 2, config.KVVersion)
+	})
+}
@@ -19,12 +19,21 @@ type Config struct {
 	Default Default `yaml:"default,omitempty" json:"default,omitempty" jsonschema:"optional"`
 	// Global environment variables to be available in all autopilots
 	Env map[string]string `yaml:"env,omitempty" json:"env,omitempty" jsonschema:"optional"`
+	// Host environment variables passed to all autopilots and the finalizer, defaults to PATH, HOME, LANG and TMPDIR
+	// Example "none"
+	EnvInherit EnvInherit `yaml:"env-inherit,omitempty" json:"env-inherit,omitempty" jsonschema:"optional"`
 	// Repositories to fetch external apps from
 	Repositories []Repository `yaml:"repositories" json:"repositories" jsonschema:"optional"`
+	// Resolution of apps without repository prefix which are provided by multiple repositories,
+	// 'first' uses the first repository, 'strict' fails. Defaults to 'first'
+	// Example "strict"
+	Resolution string `yaml:"resolution,omitempty" json:"resolution,omitempty" jsonschema:"optional,enum=first,enum=strict"`
 	// Autopilot configurations
 	Autopilots map[string]Autopilot `yaml:"autopilots" json:"autopilots" jsonschema:"optional"`
 	// Finalize configuration
 	Finalize *Finalize `yaml:"finalize,omitempty" json:"finalize,omitempty" jsonschema:"optional"`
+	// Targets the result file and the evidence archive are uploaded to after the run
+	Publish []PublishTarget `yaml:"publish,omitempty" json:"publish,omitempty" jsonschema:"optional"`
 	// Chapters of the project
 	Chapters map[string]Chapter `yaml:"chapters" json:"chapters" jsonschema:"required"`
 }
@@ -66,6 +75,10 @@ type Repository struct {
 	// 		username: "my-username"
 	//		password: "my-password"
 	Config map[string]interface{} `yaml:"configuration" json:"configuration" jsonschema:"required"`
+	// Priority of the repository for apps without repository prefix, repositories with a higher priority are asked first.
+	// Repositories with the same priority are asked in the order of declaration.
+	// Example 10
+	Priority int `yaml:"priority,omitempty" json:"priority,omitempty" jsonschema:"optional"`
 }
 
 type Autopilot struct {
@@ -79,6 +92,17 @@ type Autopilot struct {
 	// 	FOO: bar
 	// 	BAZ: qux
 	Env map[string]string `yaml:"env,omitempty" json:"env,omitempty" jsonschema:"optional"`
+	// Secrets the autopilot is allowed to use, only these can be referenced in the autopilot and in the global env and are passed to it.
+	// If the list is omitted, the autopilot can use all secrets.
+	// Example
+	// 	- JIRA_TOKEN
+	// 	- SHAREPOINT_PASSWORD
+	Secrets []string `yaml:"secrets,omitempty" json:"secrets,omitempty" jsonschema:"optional"`
+	// Host environment variables passed to the steps and the evaluation, overrides the global setting
+	// Example
+	// 	- HTTPS_PROXY
+	// 	- AWS_*
+	EnvInherit EnvInherit `yaml:"env-inherit,omitempty" json:"env-inherit,omitempty" jsonschema:"optional"`
 	// Steps to be executed by the autopilot
 	// Example
 	// 	- title: "step-1"
@@ -113,6 +137,9 @@ type Step struct {
 	// 	FOO: bar
 	// 	BAZ: qux
 	Env map[string]string `yaml:"env,omitempty" json:"env,omitempty" jsonschema:"optional"`
+	// Host environment variables passed to the step, overrides the autopilot setting
+	// Example "all"
+	EnvInherit EnvInherit `yaml:"env-inherit,omitempty" json:"env-inherit,omitempty" jsonschema:"optional"`
 	// Configuration files needed by the autopilot
 	// Example
 	// 	- my-config.yaml
@@ -159,6 +186,20 @@ type Finalize struct {
 	Run string `yaml:"run" json:"run" jsonschema:"required"`
 }
 
+type PublishTarget struct {
+	Name string `yaml:"name" json:"name" jsonschema:"required"`
+	// Type of the target
+	// Example "azure-blob-storage"
+	Type string `yaml:"type" json:"type" jsonschema:"required,enum=directory,enum=azure-blob-storage,enum=s3,enum=http"`
+	// Configuration of the target
+	// Example
+	// 	url: "https://my-file-server.com/qg-results/{file}"
+	// 	auth:
+	//		type: "token"
+	// 		token: ${{ secrets.UPLOAD_TOKEN }}
+	Config map[string]interface{} `yaml:"configuration" json:"configuration" jsonschema:"required"`
+}
+
 // Contains a configuration to answer a chapter
 type Chapter struct {
 	// Requirements to answer the chapter
@@ -282,6 +323,7 @@ func (c *Config) CreateExecutionPlan() (*model.ExecutionPlan, error) {
 		return nil, errors.Wrap(err, "failed to deep copy 'Env'")
 	}
 
+	ep.Resolution = c.Resolution
 	repositoryNames := make(map[string]bool)
 	if len(c.Repositories) > 0 {
 		ep.Repositories = make([]configuration.Repository, 0, len(c.Repositories))
@@ -292,8 +334,9 @@ func (c *Config) CreateExecutionPlan() (*model.ExecutionPlan, error) {
 			repositoryNames[repo.Name] = true
 
 			rep := configuration.Repository{
-				Name: repo.Name,
-				Type: repo.Type,
+				Name:     repo.Name,
+				Type:     repo.Type,
+				Priority: repo.Priority,
 			}
 
 			rep.Config, err = deepCopyMap(repo.Config)
@@ -315,7 +358,7 @@ func (c *Config) CreateExecutionPlan() (*model.ExecutionPlan, error) {
 				}
 
 				if check.isAutomation() {
-					autopilotItem, err := createAutopilotCheck(logger, chapIndex, chapter, reqIndex, requirement, checkIndex, check, c.Autopilots, repositoryNames)
+					autopilotItem, err := createAutopilotCheck(logger, chapIndex, chapter, reqIndex, requirement, checkIndex, check, c.Autopilots, repositoryNames, c.EnvInherit)
 					if err != nil {
 						return nil, errors.Wrap(err, "failed to create autopilotCheck")
 					}
@@ -327,9 +370,27 @@ func (c *Config) CreateExecutionPlan() (*model.ExecutionPlan, error) {
 		}
 	}
 
+	if len(c.Publish) > 0 {
+		ep.Publish = make([]model.PublishTarget, 0, len(c.Publish))
+		for _, target := range c.Publish {
+			publishTarget := model.PublishTarget{
+				Name: target.Name,
+				Type: target.Type,
+			}
+
+			publishTarget.Config, err = deepCopyMap(target.Config)
+			if err != nil {
+				return nil, errors.Wrap(err, "failed to deep copy 'publish.Config'")
+			}
+
+			ep.Publish = append(ep.Publish, publishTarget)
+		}
+	}
+
 	if c.hasFinalize() {
 		finalize := &model.Finalize{
-			Run: c.Finalize.Run,
+			Run:        c.Finalize.Run,
+			EnvInherit: resolveEnvInherit(c.EnvInherit),
 		}
 
 		finalize.Env, err = deepCopyMap(c.Finalize.Env)
@@ -24,6 +24,20 @@ func TestConfig_CreateExecutionPlan(t *testing.T) {
 			input: func() *Config { return simpleConfig() },
 			want:  want{execPlan: func() *model.ExecutionPlan { return simpleExecPlan() }},
 		},
+		"should-create-execPlan-with-declared-secrets": {
+			input: func() *Config {
+				cfg := simpleConfig()
+				autopilot := cfg.Autopilots["pdf-checker"]
+				autopilot.Secrets = []string{}
+				cfg.Autopilots["pdf-checker"] = autopilot
+				return cfg
+			},
+			want: want{execPlan: func() *model.ExecutionPlan {
+				ep := simpleExecPlan()
+				ep.AutopilotChecks[0].Autopilot.RestrictSecrets = true
+				return ep
+			}},
+		},
 		"should-create-execPlan-when-finalize-is-nil": {
 			input: func() *Config {
 				cfg := simpleConfig()
@@ -86,6 +100,18 @@ func TestConfig_CreateExecutionPlan(t *testing.T) {
 				return ep
 			}},
 		},
+		"should-create-execPlan-with-publish-targets": {
+			input: func() *Config {
+				cfg := simpleConfig()
+				cfg.Publish = []PublishTarget{{Name: "share", Type: "directory", Config: map[string]interface{}{"path": "/mnt/share"}}}
+				return cfg
+			},
+			want: want{execPlan: func() *model.ExecutionPlan {
+				ep := simpleExecPlan()
+				ep.Publish = []model.PublishTarget{{Name: "share", Type: "directory", Config: map[string]interface{}{"path": "/mnt/share"}}}
+				return ep
+			}},
+		},
 		"should-create-execPlan-when-default-vars-is-nil": {
 			input: func() *Config {
 				cfg := simpleConfig()
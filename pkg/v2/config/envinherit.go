@@ -0,0 +1,60 @@
+package config
+
+import (
+	"path"
+
+	model "github.com/B-S-F/onyx/pkg/v2/model"
+	"github.com/invopop/jsonschema"
+	"github.com/pkg/errors"
+	"gopkg.in/yaml.v3"
+)
+
+// EnvInherit selects the host environment variables which are passed to autopilots.
+// It is either 'all', 'none' or a list of variable names and patterns like 'AWS_*'.
+type EnvInherit []string
+
+func (e *EnvInherit) UnmarshalYAML(node *yaml.Node) error {
+	if node.Kind == yaml.ScalarNode {
+		*e = EnvInherit{node.Value}
+		return nil
+	}
+	var names []string
+	if err := node.Decode(&names); err != nil {
+		return errors.Wrap(err, "env-inherit must be 'all', 'none' or a list of variable names")
+	}
+	// an empty list inherits nothing, it must not fall back to the defaults
+	*e = append(EnvInherit{}, names...)
+	return nil
+}
+
+func (EnvInherit) JSONSchema() *jsonschema.Schema {
+	return &jsonschema.Schema{
+		Description: "Host environment variables passed to the autopilots: 'all', 'none' or a list of variable names and patterns like 'AWS_*'",
+		OneOf: []*jsonschema.Schema{
+			{Type: "string", Enum: []interface{}{model.EnvInheritAll, model.EnvInheritNone}},
+			{Type: "array", Items: &jsonschema.Schema{Type: "string"}},
+		},
+	}
+}
+
+func (e EnvInherit) validate() error {
+	for _, name := range e {
+		if (name == model.EnvInheritAll || name == model.EnvInheritNone) && len(e) > 1 {
+			return errors.Errorf("'%s' can't be combined with other variables", name)
+		}
+		if _, err := path.Match(name, ""); err != nil {
+			return errors.Errorf("invalid pattern '%s'", name)
+		}
+	}
+	return nil
+}
+
+// resolveEnvInherit returns the most specific configured setting, nil selects the defaults
+func resolveEnvInherit(levels ...EnvInherit) []string {
+	for _, level := range levels {
+		if level != nil {
+			return append([]string{}, level...)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,36 @@
+package config
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v3"
+)
+
+func TestEnvInherit_UnmarshalYAML(t *testing.T) {
+	tests := map[string]struct {
+		input string
+		want  EnvInherit
+	}{
+		"not-set":  {input: "other: value", want: nil},
+		"all":      {input: "env-inherit: all", want: EnvInherit{"all"}},
+		"none":     {input: "env-inherit: none", want: EnvInherit{"none"}},
+		"list":     {input: "env-inherit: [HTTPS_PROXY, AWS_*]", want: EnvInherit{"HTTPS_PROXY", "AWS_*"}},
+		"empty":    {input: "env-inherit: []", want: EnvInherit{}},
+		"one-name": {input: "env-inherit:\n  - PATH", want: EnvInherit{"PATH"}},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			var step Step
+			require.NoError(t, yaml.Unmarshal([]byte(tt.input), &step))
+			assert.Equal(t, tt.want, step.EnvInherit)
+		})
+	}
+}
+
+func TestResolveEnvInherit(t *testing.T) {
+	assert.Nil(t, resolveEnvInherit(nil, nil))
+	assert.Equal(t, []string{"AWS_*"}, resolveEnvInherit(nil, EnvInherit{"AWS_*"}, EnvInherit{"all"}))
+	assert.Equal(t, []string{}, resolveEnvInherit(EnvInherit{}, EnvInherit{"all"}))
+}
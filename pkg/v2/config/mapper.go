@@ -56,6 +56,7 @@ func createAutopilotCheck(
 	checkIndex string, check Check,
 	configAutopilots map[string]Autopilot,
 	repositoryNames map[string]bool,
+	envInherit EnvInherit,
 ) (model.AutopilotCheck, error) {
 	autopilotItem := model.AutopilotCheck{
 		Item: createItem(chapIndex, chapter, reqIndex, requirement, checkIndex, check),
@@ -99,7 +100,8 @@ func createAutopilotCheck(
 
 	// map Evaluate
 	evaluate := model.Evaluate{
-		Run: autopilot.Evaluate.Run,
+		Run:        autopilot.Evaluate.Run,
+		EnvInherit: resolveEnvInherit(autopilot.EnvInherit, envInherit),
 	}
 
 	evaluate.Env, err = deepCopyMap(autopilot.Evaluate.Env)
@@ -128,6 +130,7 @@ func createAutopilotCheck(
 		if err != nil {
 			return model.AutopilotCheck{}, errors.Wrapf(err, "failed to convert 'autopilot.Steps[%d]' to domain Step", idx)
 		}
+		domainStep.EnvInherit = resolveEnvInherit(step.EnvInherit, autopilot.EnvInherit, envInherit)
 		domainSteps = append(domainSteps, domainStep)
 	}
 
@@ -142,9 +145,11 @@ func createAutopilotCheck(
 
 	// map Autopilot
 	autopilotItem.Autopilot = model.Autopilot{
-		Name:     check.Automation.Autopilot,
-		Env:      autopilotEnv,
-		Evaluate: evaluate,
+		Name:            check.Automation.Autopilot,
+		Env:             autopilotEnv,
+		Evaluate:        evaluate,
+		Secrets:         append([]string(nil), autopilot.Secrets...),
+		RestrictSecrets: autopilot.Secrets != nil,
 	}
 
 	if !hasCycle {
@@ -32,6 +32,29 @@ func Validate(config interface{}) error {
 				}
 			}
 		}
+		// validate env-inherit
+		if err := cfg.EnvInherit.validate(); err != nil {
+			return errors.Wrap(err, "invalid env-inherit")
+		}
+		for name, autopilot := range cfg.Autopilots {
+			if err := autopilot.EnvInherit.validate(); err != nil {
+				return errors.Wrapf(err, "invalid env-inherit in autopilot '%s'", name)
+			}
+			for _, step := range autopilot.Steps {
+				if err := step.EnvInherit.validate(); err != nil {
+					return errors.Wrapf(err, "invalid env-inherit in step '%s' of autopilot '%s'", step.ID, name)
+				}
+			}
+		}
+		// validate declared secrets
+		secretNamePattern := regexp.MustCompile(`^[a-zA-Z0-9_]+$`)
+		for name, autopilot := range cfg.Autopilots {
+			for _, secret := range autopilot.Secrets {
+				if !secretNamePattern.MatchString(secret) {
+					return errors.Errorf("invalid secret '%s' in autopilot '%s', only alphanumeric characters and underscores are allowed", secret, name)
+				}
+			}
+		}
 		// validate repositories
 		repositoryNames := make(map[string]bool)
 		for _, repo := range cfg.Repositories {
@@ -41,6 +64,20 @@ func Validate(config interface{}) error {
 			}
 			repositoryNames[repo.Name] = true
 		}
+		// validate publish targets
+		publishTargetNames := make(map[string]bool)
+		for _, target := range cfg.Publish {
+			if target.Name == "" {
+				return errors.Errorf("publish target of type '%s' has no name", target.Type)
+			}
+			if publishTargetNames[target.Name] {
+				return errors.Errorf("publish target with name %s already exists", target.Name)
+			}
+			publishTargetNames[target.Name] = true
+		}
+		if cfg.Resolution != "" && cfg.Resolution != "first" && cfg.Resolution != "strict" {
+			return errors.Errorf("invalid resolution '%s', must be 'first' or 'strict'", cfg.Resolution)
+		}
 		// validate checks
 		for _, chap := range cfg.Chapters {
 			for _, req := range chap.Requirements {
@@ -47,6 +47,59 @@ func TestCustomValidationForV2(t *testing.T) {
 			},
 			want: nil,
 		},
+		"valid-resolution": {
+			input: &Config{Resolution: "strict"},
+			want:  nil,
+		},
+		"valid-secrets": {
+			input: &Config{
+				Autopilots: map[string]Autopilot{
+					"jira-fetcher": {Secrets: []string{"JIRA_TOKEN", "jira_user_2"}},
+				},
+			},
+			want: nil,
+		},
+		"invalid-secrets": {
+			input: &Config{
+				Autopilots: map[string]Autopilot{
+					"jira-fetcher": {Secrets: []string{"secrets.JIRA_TOKEN"}},
+				},
+			},
+			want: errors.New("invalid secret 'secrets.JIRA_TOKEN' in autopilot 'jira-fetcher', only alphanumeric characters and underscores are allowed"),
+		},
+		"valid-env-inherit": {
+			input: &Config{
+				EnvInherit: EnvInherit{"none"},
+				Autopilots: map[string]Autopilot{
+					"fetcher": {EnvInherit: EnvInherit{"HTTPS_PROXY", "AWS_*"}, Steps: []Step{{ID: "fetch", EnvInherit: EnvInherit{"all"}}}},
+				},
+			},
+			want: nil,
+		},
+		"invalid-env-inherit-combination": {
+			input: &Config{EnvInherit: EnvInherit{"all", "PATH"}},
+			want:  errors.New("invalid env-inherit: 'all' can't be combined with other variables"),
+		},
+		"invalid-env-inherit-pattern": {
+			input: &Config{
+				Autopilots: map[string]Autopilot{
+					"fetcher": {Steps: []Step{{ID: "fetch", EnvInherit: EnvInherit{"AWS_[*"}}}},
+				},
+			},
+			want: errors.New("invalid env-inherit in step 'fetch' of autopilot 'fetcher': invalid pattern 'AWS_[*'"),
+		},
+		"invalid-resolution": {
+			input: &Config{Resolution: "last"},
+			want:  errors.New("invalid resolution 'last', must be 'first' or 'strict'"),
+		},
+		"publish-target-without-name": {
+			input: &Config{Publish: []PublishTarget{{Type: "s3"}}},
+			want:  errors.New("publish target of type 's3' has no name"),
+		},
+		"duplicate-publish-target": {
+			input: &Config{Publish: []PublishTarget{{Name: "results", Type: "s3"}, {Name: "results", Type: "http"}}},
+			want:  errors.New("publish target with name results already exists"),
+		},
 		"invalid-depends": {
 			input: &Config{
 				Autopilots: map[string]Autopilot{
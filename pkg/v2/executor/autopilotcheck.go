@@ -113,7 +113,7 @@ func (a *AutopilotExecutor) ExecuteAutopilotCheck(item *model.AutopilotCheck, en
 			runtimeEnv := helper.MergeMaps(env, step.Env, item.Autopilot.Env, specialEnv)
 			// do run
 			a.logger.Info(fmt.Sprintf("starting autopilot '%s' step '%s'", item.Autopilot.Name, step.ID))
-			runnerOutput, err := StartRunner(stepDirs.workDir, step.Run, runtimeEnv, secrets, a.logger, a.runner, a.timeout)
+			runnerOutput, err := StartRunner(stepDirs.workDir, step.Run, runtimeEnv, secrets, step.EnvInherit, a.logger, a.runner, a.timeout)
 			if err != nil {
 				return nil, errors.Wrap(err, fmt.Sprintf("failed to run autopilot '%s' step '%s'", item.Autopilot.Name, step.ID))
 			}
@@ -149,7 +149,7 @@ func (a *AutopilotExecutor) ExecuteAutopilotCheck(item *model.AutopilotCheck, en
 	}
 	runtimeEnv := helper.MergeMaps(env, item.Autopilot.Evaluate.Env, specialEnv)
 	a.logger.Info("doing evaluation")
-	evalOutput, err := StartRunner(evalDir.String(), item.Autopilot.Evaluate.Run, runtimeEnv, secrets, a.logger, a.runner, a.timeout)
+	evalOutput, err := StartRunner(evalDir.String(), item.Autopilot.Evaluate.Run, runtimeEnv, secrets, item.Autopilot.Evaluate.EnvInherit, a.logger, a.runner, a.timeout)
 	if err != nil {
 		return nil, errors.Wrap(err, fmt.Sprintf("failed to run autopilot '%s' evaluation", item.Autopilot.Name))
 	}
@@ -32,7 +32,7 @@ func TestStartRunner(t *testing.T) {
 		}
 		env, secrets := map[string]string{"env": "value"}, map[string]string{"secret": "value"}
 		// act
-		output, err := StartRunner(workDir, run, env, secrets, nopLogger, runner.NewSubprocess(nopLogger), 5*time.Minute)
+		output, err := StartRunner(workDir, run, env, secrets, nil, nopLogger, runner.NewSubprocess(nopLogger), 5*time.Minute)
 		// assert
 		assert.NoError(t, err)
 		assert.Equal(t, want, output)
@@ -46,7 +46,7 @@ func TestStartRunner(t *testing.T) {
 		}
 		env, secrets := map[string]string{"env": "value"}, map[string]string{"secret": "value"}
 		// act
-		output, err := StartRunner(workDir, run, env, secrets, nopLogger, runner.NewSubprocess(nopLogger), 5*time.Minute)
+		output, err := StartRunner(workDir, run, env, secrets, nil, nopLogger, runner.NewSubprocess(nopLogger), 5*time.Minute)
 		// assert
 		assert.NoError(t, err)
 		assert.NotNil(t, output.Logs)
@@ -38,7 +38,7 @@ func (f *FinalizeExecutor) Execute(item *model.Finalize, env, secrets map[string
 	}
 	specialEnv := map[string]string{"result_path": f.rootWorkDir}
 	runtimeEnv := helper.MergeMaps(env, item.Env, specialEnv)
-	runnerOutput, err := StartRunner(f.rootWorkDir, item.Run, runtimeEnv, secrets, f.logger, f.runner, f.timeout)
+	runnerOutput, err := StartRunner(f.rootWorkDir, item.Run, runtimeEnv, secrets, item.EnvInherit, f.logger, f.runner, f.timeout)
 	if err != nil {
 		return nil, errors.Wrap(err, "failed to run finalize")
 	}
@@ -11,6 +11,29 @@ type AutopilotCheck struct {
 	AppReferences  []*conf.AppReference
 	ValidationErrs []error
 	AppPath        string
+	Apps           []InstalledApp
+}
+
+// InstalledApp is an app which was installed for an autopilot check
+type InstalledApp struct {
+	// Reference as written in the config
+	Reference string
+	// Repository which provided the app
+	Repository string
+	// Checksum of the installed app
+	Checksum string
+	// Source the app was downloaded from, without credentials
+	Source string
+	// Signature of the app, nil if the repository does not verify signatures
+	Signature *AppSignature
+}
+
+// AppSignature is the signature verification of an installed app
+type AppSignature struct {
+	Type   string
+	Status string
+	KeyID  string
+	Signer string
 }
 
 type StepResult struct {
@@ -12,7 +12,9 @@ type ExecutionPlan struct {
 	AutopilotChecks []AutopilotCheck
 	ManualChecks    []ManualCheck
 	Repositories    []conf.Repository
+	Resolution      string
 	Finalize        *Finalize
+	Publish         []PublishTarget
 }
 
 type Item struct {
@@ -26,6 +28,23 @@ type Autopilot struct {
 	Evaluate Evaluate
 	Name     string
 	Steps    [][]Step
+	// Secrets declared by the autopilot, only used if RestrictSecrets is set
+	Secrets         []string
+	RestrictSecrets bool
+}
+
+// AllowedSecrets returns the secrets the autopilot is allowed to use
+func (a Autopilot) AllowedSecrets(secrets map[string]string) map[string]string {
+	if !a.RestrictSecrets {
+		return secrets
+	}
+	allowed := make(map[string]string, len(a.Secrets))
+	for _, name := range a.Secrets {
+		if value, ok := secrets[name]; ok {
+			allowed[name] = value
+		}
+	}
+	return allowed
 }
 
 type Step struct {
@@ -35,10 +54,14 @@ type Step struct {
 	Configs map[string]string
 	Run     string
 	Depends []string
+	// EnvInherit selects the inherited host environment variables, nil selects DefaultEnvInherit
+	EnvInherit []string
 }
 
 type Evaluate struct {
 	Env     map[string]string
 	Configs map[string]string
 	Run     string
+	// EnvInherit selects the inherited host environment variables, nil selects DefaultEnvInherit
+	EnvInherit []string
 }
@@ -0,0 +1,11 @@
+package model
+
+const (
+	// EnvInheritAll passes the whole host environment to the autopilots
+	EnvInheritAll = "all"
+	// EnvInheritNone passes no host environment variables to the autopilots
+	EnvInheritNone = "none"
+)
+
+// DefaultEnvInherit are the host environment variables passed to autopilots if nothing is configured
+var DefaultEnvInherit = []string{"PATH", "HOME", "LANG", "TMPDIR"}
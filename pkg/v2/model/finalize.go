@@ -4,6 +4,8 @@ type Finalize struct {
 	Env     map[string]string
 	Configs map[string]string
 	Run     string
+	// EnvInherit selects the inherited host environment variables, nil selects DefaultEnvInherit
+	EnvInherit []string
 }
 
 type FinalizeResult struct {
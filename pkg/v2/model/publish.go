@@ -0,0 +1,8 @@
+package model
+
+// PublishTarget receives the result file and the evidence archive after the run
+type PublishTarget struct {
+	Name   string
+	Type   string
+	Config map[string]interface{}
+}
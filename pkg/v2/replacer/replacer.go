@@ -1,7 +1,10 @@
 package replacer
 
 import (
+	"encoding/json"
 	"fmt"
+	"regexp"
+	"sort"
 
 	"github.com/B-S-F/onyx/pkg/helper"
 	"github.com/B-S-F/onyx/pkg/logger"
@@ -14,6 +17,8 @@ var PatternEnd = `}}`
 var PatternVariableType = []string{"vars", "secrets", "env"}
 var DeprecatedVariableType = []string{"var", "secret", "envs"}
 
+var variableNamePattern = regexp.MustCompile(`\.([a-zA-Z0-9_]+) *` + PatternEnd + `$`)
+
 type Runner struct {
 	ep        *model.ExecutionPlan
 	variables *map[string]string
@@ -73,6 +78,11 @@ func (r *Runner) replace(varType string, scope Scope) {
 
 func (r *Runner) replaceInitialExecutionPlan(varType string) {
 	r.logger.Info(fmt.Sprintf("replacing '%s' variables in execution plan", varType))
+	// secrets of the global Env are passed to all autopilots, so they have to be declared by restricted autopilots
+	var globalEnvSecrets []string
+	if varType == "secrets" {
+		globalEnvSecrets = r.referencedSecrets(r.ep.Env)
+	}
 	// replace global Env
 	var variablesList []map[string]string
 	if varType == "env" {
@@ -98,9 +108,16 @@ func (r *Runner) replaceInitialExecutionPlan(varType string) {
 			r.logger.Error(fmt.Errorf("error replacing '%s' in Repository: %w", varType, e).Error())
 		}
 	}
+	// Replace Publish targets
+	for i := range r.ep.Publish {
+		if e := r.replacer.Struct(&r.ep.Publish[i], *r.variables); e != nil {
+			r.logger.Error(fmt.Errorf("error replacing '%s' in Publish target: %w", varType, e).Error())
+		}
+	}
 
 	for i := range r.ep.AutopilotChecks {
-		r.replaceAutopilotItem(&r.ep.AutopilotChecks[i], varType)
+		item := &r.ep.AutopilotChecks[i]
+		r.forAutopilot(item, varType, globalEnvSecrets).replaceAutopilotItem(item, varType)
 	}
 
 	for i := range r.ep.ManualChecks {
@@ -210,6 +227,62 @@ func (r *Runner) replaceAutopilotItem(item *model.AutopilotCheck, varType string
 
 }
 
+// forAutopilot restricts the secrets to the ones declared by the autopilot.
+// References to undeclared secrets, also in the global Env, are validation errors, so the check is not executed.
+func (r *Runner) forAutopilot(item *model.AutopilotCheck, varType string, globalEnvSecrets []string) *Runner {
+	if varType != "secrets" || !item.Autopilot.RestrictSecrets {
+		return r
+	}
+	declared := make(map[string]bool, len(item.Autopilot.Secrets))
+	for _, name := range item.Autopilot.Secrets {
+		declared[name] = true
+	}
+	for _, name := range globalEnvSecrets {
+		if !declared[name] {
+			r.addValidationErr(item, fmt.Errorf("global env references secret '%s' which autopilot '%s' does not declare", name, item.Autopilot.Name))
+		}
+	}
+	for _, name := range r.referencedSecrets(item.Item, item.Autopilot, item.CheckEnv, item.AppReferences) {
+		if !declared[name] {
+			r.addValidationErr(item, fmt.Errorf("autopilot '%s' references secret '%s' which it does not declare", item.Autopilot.Name, name))
+		}
+	}
+	allowed := item.Autopilot.AllowedSecrets(*r.variables)
+	return &Runner{
+		ep:        r.ep,
+		variables: &allowed,
+		replacer:  r.replacer,
+		logger:    r.logger,
+	}
+}
+
+func (r *Runner) addValidationErr(item *model.AutopilotCheck, validationErr error) {
+	item.ValidationErrs = append(item.ValidationErrs, validationErr)
+	r.logger.Error(validationErr.Error())
+}
+
+// referencedSecrets lists the sorted names of the secrets referenced in the given values
+func (r *Runner) referencedSecrets(values ...interface{}) []string {
+	content, err := json.Marshal(values)
+	if err != nil {
+		r.logger.Error(fmt.Errorf("error searching secrets: %w", err).Error())
+		return nil
+	}
+	referenced := map[string]bool{}
+	for _, match := range r.replacer.ListMatches(string(content)) {
+		name := variableNamePattern.FindStringSubmatch(match)
+		if name != nil {
+			referenced[name[1]] = true
+		}
+	}
+	names := make([]string, 0, len(referenced))
+	for name := range referenced {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
 func (r *Runner) replaceCommonItem(item *model.Item, varType string) {
 	if e := r.replacer.Struct(&item.Chapter, *r.variables); e != nil {
 		r.logger.Error(fmt.Errorf("error replacing '%s' in Chapter: %w", varType, e).Error())
@@ -121,6 +121,20 @@ func TestReplaceRun(t *testing.T) {
 			},
 		},
 	}, executionPlan.Repositories, "repositories should be equal")
+	// publish targets
+	assert.Equal(t, []model.PublishTarget{
+		{
+			Name: "results",
+			Type: "http",
+			Config: map[string]interface{}{
+				"url": "https://github.com/{file}",
+				"auth": map[string]interface{}{
+					"type":  "token",
+					"token": "github_password",
+				},
+			},
+		},
+	}, executionPlan.Publish, "publish targets should be equal")
 	// autopilot item
 	autopilotItem := executionPlan.AutopilotChecks[0]
 	assert.Equal(t, config.Chapter{
@@ -481,6 +495,19 @@ func simpleExecPlan() *model.ExecutionPlan {
 				},
 			},
 		},
+		Publish: []model.PublishTarget{
+			{
+				Name: "results",
+				Type: "http",
+				Config: map[string]interface{}{
+					"url": "${{ env.GITHUB_URL }}/{file}",
+					"auth": map[string]interface{}{
+						"type":  "token",
+						"token": "${{ secrets.GITHUB_PASSWORD }}",
+					},
+				},
+			},
+		},
 		AutopilotChecks: []model.AutopilotCheck{
 			{
 				Item: model.Item{
@@ -592,3 +619,68 @@ func simpleExecPlan() *model.ExecutionPlan {
 		},
 	}
 }
+
+func TestReplaceRunWithDeclaredSecrets(t *testing.T) {
+	newAutopilotCheck := func(secrets []string, restrict bool) model.AutopilotCheck {
+		return model.AutopilotCheck{
+			Item:     model.Item{Check: config.Check{Id: "1"}},
+			CheckEnv: map[string]string{"TOKEN": "${{ secrets.SECRET1 }}"},
+			Autopilot: model.Autopilot{
+				Name:            "fetcher",
+				Env:             map[string]string{"PASSWORD": "${{ secret.GITHUB_PASSWORD }}"},
+				Steps:           [][]model.Step{{{ID: "fetch", Run: "fetch --user ${{ secrets.GITHUB_USERNAME }}"}}},
+				Secrets:         secrets,
+				RestrictSecrets: restrict,
+			},
+		}
+	}
+
+	t.Run("undeclared secrets are not replaced", func(t *testing.T) {
+		executionPlan := &model.ExecutionPlan{AutopilotChecks: []model.AutopilotCheck{
+			newAutopilotCheck([]string{"SECRET1", "GITHUB_USERNAME"}, true),
+		}}
+
+		err := Run(executionPlan, varsContent, secretsContent, Initial)
+
+		assert.NoError(t, err)
+		item := executionPlan.AutopilotChecks[0]
+		assert.Equal(t, "secrets_value1", item.CheckEnv["TOKEN"])
+		assert.Equal(t, "fetch --user github_username", item.Autopilot.Steps[0][0].Run)
+		assert.Empty(t, item.Autopilot.Env["PASSWORD"])
+		if assert.Len(t, item.ValidationErrs, 1) {
+			assert.EqualError(t, item.ValidationErrs[0], "autopilot 'fetcher' references secret 'GITHUB_PASSWORD' which it does not declare")
+		}
+	})
+
+	t.Run("global env must only reference declared secrets", func(t *testing.T) {
+		executionPlan := &model.ExecutionPlan{
+			Env: map[string]string{"GLOBAL_TOKEN": "${{ secrets.SECRET2 }}", "GLOBAL_USER": "${{ secrets.GITHUB_USERNAME }}"},
+			AutopilotChecks: []model.AutopilotCheck{
+				newAutopilotCheck([]string{"SECRET1", "GITHUB_USERNAME", "GITHUB_PASSWORD"}, true),
+				newAutopilotCheck(nil, false),
+			},
+		}
+
+		err := Run(executionPlan, varsContent, secretsContent, Initial)
+
+		assert.NoError(t, err)
+		restricted := executionPlan.AutopilotChecks[0]
+		if assert.Len(t, restricted.ValidationErrs, 1) {
+			assert.EqualError(t, restricted.ValidationErrs[0], "global env references secret 'SECRET2' which autopilot 'fetcher' does not declare")
+		}
+		assert.Empty(t, executionPlan.AutopilotChecks[1].ValidationErrs)
+	})
+
+	t.Run("all secrets are available without declaration", func(t *testing.T) {
+		executionPlan := &model.ExecutionPlan{AutopilotChecks: []model.AutopilotCheck{
+			newAutopilotCheck(nil, false),
+		}}
+
+		err := Run(executionPlan, varsContent, secretsContent, Initial)
+
+		assert.NoError(t, err)
+		item := executionPlan.AutopilotChecks[0]
+		assert.Equal(t, "github_password", item.Autopilot.Env["PASSWORD"])
+		assert.Empty(t, item.ValidationErrs)
+	})
+}
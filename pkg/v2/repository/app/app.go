@@ -30,7 +30,6 @@ func Initialize(ep *model.ExecutionPlan, appRegistry *registry.Registry) error {
 				return errors.Wrap(err, "error getting app")
 			}
 
-			appExecutablePath := app.ExecutablePath()
 			checkReference := fmt.Sprintf("%s_%s_%s", autopilotItem.Chapter.Id, autopilotItem.Requirement.Id, autopilotItem.Check.Id)
 			checkAppDirectory := filepath.Join(APP_DIRECTORY, checkReference)
 			err = os.MkdirAll(checkAppDirectory, 0755)
@@ -38,11 +37,13 @@ func Initialize(ep *model.ExecutionPlan, appRegistry *registry.Registry) error {
 				return errors.Wrapf(err, "error creating directory for app %s for check %s", app.Reference(), checkReference)
 			}
 			logger.Get().Infof("configured app %s with checksum %s for check %s", app.Reference(), app.Checksum(), checkReference)
+			autopilotItem.Apps = append(autopilotItem.Apps, installedApp(appReference, app))
 
-			symlinks := app.PossibleReferences()
-			err = helper.CreateSymlinks(appExecutablePath, checkAppDirectory, symlinks)
-			if err != nil {
-				return errors.Wrap(err, "error creating symlinks")
+			for _, executable := range app.Executables() {
+				err = helper.CreateSymlinks(executable.Path, checkAppDirectory, executable.References)
+				if err != nil {
+					return errors.Wrap(err, "error creating symlinks")
+				}
 			}
 			autopilotItem.AppPath = checkAppDirectory
 		}
@@ -63,3 +64,23 @@ func AppReferences(ep *model.ExecutionPlan) []*app.Reference {
 	}
 	return appReferences
 }
+
+// installedApp records the provenance of an app which was configured for a check
+func installedApp(reference *app.Reference, installed app.App) model.InstalledApp {
+	provenance := app.ProvenanceOf(installed)
+	installedApp := model.InstalledApp{
+		Reference:  reference.String(),
+		Repository: installed.Reference().Repository,
+		Checksum:   installed.Checksum(),
+		Source:     provenance.Source,
+	}
+	if provenance.Signature != nil {
+		installedApp.Signature = &model.AppSignature{
+			Type:   provenance.Signature.Type,
+			Status: provenance.Signature.Status,
+			KeyID:  provenance.Signature.KeyID,
+			Signer: provenance.Signature.Signer,
+		}
+	}
+	return installedApp
+}
@@ -8,14 +8,16 @@ import (
 	"github.com/pkg/errors"
 )
 
-func Initialize(ep *model.ExecutionPlan, repositories []repository.Repository) (*registry.Registry, error) {
+func Initialize(ep *model.ExecutionPlan, repositories []repository.Repository, parallelInstalls int) (*registry.Registry, error) {
 	appReferences := app.AppReferences(ep)
-	appRegistry := registry.NewRegistry(repositories)
-	for _, appReference := range appReferences {
-		err := appRegistry.Install(appReference)
-		if err != nil {
-			return nil, errors.Wrap(err, "error adding app to registry")
-		}
+	resolution, err := registry.ParseResolution(ep.Resolution)
+	if err != nil {
+		return nil, err
+	}
+	appRegistry := registry.NewRegistry(repositories, resolution)
+	err = appRegistry.InstallAll(appReferences, parallelInstalls)
+	if err != nil {
+		return nil, errors.Wrap(err, "error adding app to registry")
 	}
 	return appRegistry, nil
 }
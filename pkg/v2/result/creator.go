@@ -15,7 +15,6 @@ import (
 	"github.com/B-S-F/onyx/pkg/v2/model"
 	"github.com/pkg/errors"
 	"go.uber.org/zap"
-	"gopkg.in/yaml.v3"
 )
 
 const (
@@ -32,6 +31,8 @@ const (
 
 type Creator struct {
 	logger logger.Logger
+	// StrictValidation makes result files which do not match the schema an error instead of a warning
+	StrictValidation bool
 }
 
 func New(logger logger.Logger) *Creator {
@@ -141,11 +142,20 @@ func (c *Creator) extractLogs(logs []model.LogEntry, jsonLogKey string) []string
 	return results
 }
 
+// WriteResultFile writes the result as JSON if the path ends with .json, otherwise as YAML
 func (c *Creator) WriteResultFile(res Result, path string) error {
 	c.logger.Info(fmt.Sprintf("storing results in result file '%s'", filepath.Base(path)))
-	resYaml, err := yaml.Marshal(res)
+	format := common.FormatOf(path)
+	content, err := common.Marshal(res, format)
 	if err != nil {
-		return errors.Wrap(err, "failed to marshal result into yaml")
+		return errors.Wrapf(err, "failed to marshal result into %s", format)
+	}
+	err = Validate(content)
+	if err != nil {
+		if c.StrictValidation {
+			return err
+		}
+		c.logger.Warnf("%s", err)
 	}
 
 	file, err := os.Create(path)
@@ -154,7 +164,7 @@ func (c *Creator) WriteResultFile(res Result, path string) error {
 	}
 	defer file.Close()
 
-	_, err = file.Write(resYaml)
+	_, err = file.Write(content)
 	if err != nil {
 		return errors.Wrap(err, "failed to write result to output file")
 	}
@@ -224,6 +234,7 @@ func (c *Creator) addAutopilotResult(chapters map[string]*Chapter, a model.Autop
 				{
 					Name:  a.AutopilotCheck.Autopilot.Name,
 					Steps: steps,
+					Apps:  mapApps(a.AutopilotCheck.Apps),
 				},
 			},
 			Evaluation: Evaluation{
@@ -361,3 +372,28 @@ func getPriorityStatus(statusA, statusB string) string {
 func getPercentage(numerator, denominator uint) float64 {
 	return math.Round(float64(numerator)*10000.0/float64(denominator)) / 100.0
 }
+
+func mapApps(installedApps []model.InstalledApp) []App {
+	var apps []App
+	for _, installedApp := range installedApps {
+		app := App{
+			Reference:  installedApp.Reference,
+			Repository: installedApp.Repository,
+		}
+		app.Signature = mapSignature(installedApp.Signature)
+		apps = append(apps, app)
+	}
+	return apps
+}
+
+func mapSignature(signature *model.AppSignature) *Signature {
+	if signature == nil {
+		return nil
+	}
+	return &Signature{
+		Type:   signature.Type,
+		Status: signature.Status,
+		KeyID:  signature.KeyID,
+		Signer: signature.Signer,
+	}
+}
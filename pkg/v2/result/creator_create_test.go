@@ -167,6 +167,33 @@ func TestCreator_Create(t *testing.T) {
 				Statistics: Statistics{CountChecks: 1, CountAutomatedChecks: 1, PercentageDone: 100, PercentageAutomated: 100},
 			}},
 		},
+		"return_result_with_apps_and_their_repositories": {
+			args: args{
+				ep: *simpleExecPlan(),
+				runResult: model.RunResult{Autopilots: []model.AutopilotRun{
+					newAutopilotRunBuilder().apps(
+						model.InstalledApp{Reference: "sharepoint@1.0.0", Repository: "mirror", Signature: &model.AppSignature{Type: "gpg", Status: "verified", KeyID: "ABCDEF", Signer: "Release Bot"}},
+						model.InstalledApp{Reference: "internal::pdf@2.0.0", Repository: "internal"},
+					).get(),
+				}},
+			},
+			want: want{result: &Result{
+				Metadata:      Metadata{Version: "v2"},
+				Header:        Header{Version: "1.0", Name: "test"},
+				OverallStatus: "GREEN",
+				Chapters: map[string]*Chapter{
+					"1": func() *Chapter {
+						chapter := simpleAutomationChapter()
+						chapter.Requirements["1"].Checks["1"].Autopilots[0].Apps = []App{
+							{Reference: "sharepoint@1.0.0", Repository: "mirror", Signature: &Signature{Type: "gpg", Status: "verified", KeyID: "ABCDEF", Signer: "Release Bot"}},
+							{Reference: "internal::pdf@2.0.0", Repository: "internal"},
+						}
+						return chapter
+					}(),
+				},
+				Statistics: Statistics{CountChecks: 1, CountAutomatedChecks: 1, PercentageDone: 100, PercentageAutomated: 100},
+			}},
+		},
 		"return_result_when_multiple_autopilot_runs": {
 			args: args{
 				ep: *simpleExecPlan(),
@@ -325,6 +352,11 @@ func (a *autopilotRunBuilder) reason(reason string) *autopilotRunBuilder {
 	return a
 }
 
+func (a *autopilotRunBuilder) apps(apps ...model.InstalledApp) *autopilotRunBuilder {
+	a.autopilotRun.AutopilotCheck.Apps = apps
+	return a
+}
+
 func (a *autopilotRunBuilder) get() model.AutopilotRun {
 	return a.autopilotRun
 }
@@ -1,6 +1,8 @@
 package result
 
 import (
+	"encoding/json"
+	"errors"
 	"os"
 	"path/filepath"
 	"testing"
@@ -37,7 +39,7 @@ func TestCreator_WriteResultFile(t *testing.T) {
 	}
 	for name, tt := range tests {
 		t.Run(name, func(t *testing.T) {
-			c := &Creator{logger: logger.NewAutopilot()}
+			c := &Creator{logger: logger.NewAutopilot(), StrictValidation: true}
 
 			p := filepath.Join(t.TempDir(), tt.args.path)
 
@@ -61,6 +63,76 @@ func TestCreator_WriteResultFile(t *testing.T) {
 	}
 }
 
+func TestCreator_WriteResultFileJSON(t *testing.T) {
+	c := &Creator{logger: logger.NewAutopilot(), StrictValidation: true}
+	res := Result{
+		Metadata:      Metadata{Version: "v2"},
+		Header:        Header{Version: "1.0", Name: "test"},
+		OverallStatus: "GREEN",
+		Chapters:      map[string]*Chapter{"1": simpleManualChapter()},
+	}
+	p := filepath.Join(t.TempDir(), "result.json")
+
+	err := c.WriteResultFile(res, p)
+	require.NoError(t, err)
+
+	content, err := os.ReadFile(p)
+	require.NoError(t, err)
+	assert.Contains(t, string(content), `"overallStatus": "GREEN"`)
+	var readResult Result
+	require.NoError(t, json.Unmarshal(content, &readResult))
+	assert.Equal(t, res.Chapters["1"].Requirements["1"].Checks["1"].Evaluation, readResult.Chapters["1"].Requirements["1"].Checks["1"].Evaluation)
+}
+
+func TestCreator_WriteResultFileValidation(t *testing.T) {
+	res := Result{Metadata: Metadata{Version: "v2"}, OverallStatus: "PURPLE"}
+
+	t.Run("should write invalid results with a warning", func(t *testing.T) {
+		c := &Creator{logger: logger.NewAutopilot()}
+		p := filepath.Join(t.TempDir(), "result.yaml")
+
+		err := c.WriteResultFile(res, p)
+
+		assert.NoError(t, err)
+		assert.FileExists(t, p)
+	})
+
+	t.Run("should fail on invalid results with strict validation", func(t *testing.T) {
+		c := &Creator{logger: logger.NewAutopilot(), StrictValidation: true}
+		p := filepath.Join(t.TempDir(), "result.yaml")
+
+		err := c.WriteResultFile(res, p)
+
+		assert.ErrorContains(t, err, "result does not match the schema")
+		assert.NoFileExists(t, p)
+	})
+}
+
+func TestResult_Walk(t *testing.T) {
+	check := func() *Check { return &Check{Type: "manual"} }
+	res := Result{Chapters: map[string]*Chapter{
+		"10": {Requirements: map[string]*Requirement{"1": {Checks: map[string]*Check{"1": check()}}}},
+		"2": {Requirements: map[string]*Requirement{
+			"b": {Checks: map[string]*Check{"1": check()}},
+			"a": {Checks: map[string]*Check{"11": check(), "9": check()}},
+		}},
+	}}
+
+	var refs []string
+	err := res.Walk(func(ref CheckRef, chapter *Chapter, requirement *Requirement, check *Check) error {
+		refs = append(refs, ref.String())
+		return nil
+	})
+
+	require.NoError(t, err)
+	assert.Equal(t, []string{"2_a_9", "2_a_11", "2_b_1", "10_1_1"}, refs)
+
+	err = res.Walk(func(ref CheckRef, chapter *Chapter, requirement *Requirement, check *Check) error {
+		return errors.New("stop")
+	})
+	assert.EqualError(t, err, "stop")
+}
+
 func TestCreator_AppendFinalizeResult(t *testing.T) {
 	type args struct {
 		res            *Result
@@ -126,7 +198,7 @@ func TestCreator_AppendFinalizeResult(t *testing.T) {
 	}
 	for name, tt := range tests {
 		t.Run(name, func(t *testing.T) {
-			c := &Creator{logger: logger.NewAutopilot()}
+			c := &Creator{logger: logger.NewAutopilot(), StrictValidation: true}
 			err := c.AppendFinalizeResult(tt.args.res, tt.args.finalizeResult, tt.args.finalize)
 			require.NoError(t, err)
 
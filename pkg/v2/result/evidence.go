@@ -0,0 +1,24 @@
+package result
+
+import "github.com/B-S-F/onyx/pkg/evidence"
+
+// AppendEvidenceScan records the secrets found in the evidence files, nothing is recorded without findings
+func (c *Creator) AppendEvidenceScan(res *Result, policy string, findings []evidence.Finding) {
+	if len(findings) == 0 {
+		return
+	}
+	scan := &EvidenceScan{
+		Policy:   policy,
+		Findings: make([]EvidenceFinding, 0, len(findings)),
+	}
+	for _, finding := range findings {
+		scan.Findings = append(scan.Findings, EvidenceFinding{
+			File:     finding.File,
+			Offset:   finding.Offset,
+			Rule:     finding.Rule,
+			Secret:   finding.Secret,
+			Redacted: finding.Redacted,
+		})
+	}
+	res.EvidenceScan = scan
+}
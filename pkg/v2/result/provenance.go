@@ -0,0 +1,143 @@
+package result
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/B-S-F/onyx/pkg/helper"
+	"github.com/B-S-F/onyx/pkg/v2/model"
+	"github.com/pkg/errors"
+)
+
+// Inputs of a run which are recorded in the provenance
+type Inputs struct {
+	// Path of the config file
+	ConfigFile string
+	// Path of the vars file, it is optional
+	VarsFile string
+	// Input folder of the run
+	InputFolder string
+	// Paths relative to the input folder which are excluded from its digest, e.g. the secrets file
+	// or the output folder, an ignored folder is excluded with all its files
+	IgnoredFiles []string
+}
+
+// NewProvenance records the onyx version and the digests of the inputs,
+// it has to be called before the execution, as the execution may write its outputs into the input folder
+func NewProvenance(inputs Inputs) (*Provenance, error) {
+	configDigest, err := digestFile(inputs.ConfigFile)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to calculate digest of config file")
+	}
+	var varsDigest string
+	if inputs.VarsFile != "" {
+		varsDigest, err = digestFile(inputs.VarsFile)
+		if err != nil && !os.IsNotExist(err) {
+			return nil, errors.Wrap(err, "failed to calculate digest of vars file")
+		}
+	}
+	inputDigest, err := digestFolder(inputs.InputFolder, inputs.IgnoredFiles)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to calculate digest of input folder")
+	}
+	return &Provenance{
+		OnyxVersion:  helper.ToolVersion,
+		ConfigDigest: configDigest,
+		VarsDigest:   varsDigest,
+		InputDigest:  inputDigest,
+	}, nil
+}
+
+// AppendProvenance records the provenance of the inputs and the apps of every check
+func (c *Creator) AppendProvenance(res *Result, ep model.ExecutionPlan, provenance Provenance) {
+	provenance.Checks = mapCheckProvenances(ep.AutopilotChecks)
+	res.Provenance = &provenance
+}
+
+func mapCheckProvenances(autopilotChecks []model.AutopilotCheck) []CheckProvenance {
+	var checks []CheckProvenance
+	for _, autopilotCheck := range autopilotChecks {
+		if len(autopilotCheck.Apps) == 0 {
+			continue
+		}
+		check := CheckProvenance{
+			Chapter:     autopilotCheck.Chapter.Id,
+			Requirement: autopilotCheck.Requirement.Id,
+			Check:       autopilotCheck.Check.Id,
+		}
+		for _, installedApp := range autopilotCheck.Apps {
+			check.Apps = append(check.Apps, AppProvenance{
+				Reference:  installedApp.Reference,
+				Repository: installedApp.Repository,
+				Source:     installedApp.Source,
+				Checksum:   installedApp.Checksum,
+				Signature:  mapSignature(installedApp.Signature),
+			})
+		}
+		checks = append(checks, check)
+	}
+	sort.SliceStable(checks, func(i, j int) bool {
+		if checks[i].Chapter != checks[j].Chapter {
+			return lessID(checks[i].Chapter, checks[j].Chapter)
+		}
+		if checks[i].Requirement != checks[j].Requirement {
+			return lessID(checks[i].Requirement, checks[j].Requirement)
+		}
+		return lessID(checks[i].Check, checks[j].Check)
+	})
+	return checks
+}
+
+func digestFile(path string) (string, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer file.Close()
+	hash := sha256.New()
+	if _, err := io.Copy(hash, file); err != nil {
+		return "", err
+	}
+	return "sha256:" + hex.EncodeToString(hash.Sum(nil)), nil
+}
+
+// digestFolder hashes the sorted relative paths and contents of all regular files in the folder,
+// so the digest does not depend on the file system or on file modes and timestamps
+func digestFolder(folder string, ignoredFiles []string) (string, error) {
+	ignored := make(map[string]bool, len(ignoredFiles))
+	for _, ignoredFile := range ignoredFiles {
+		ignored[filepath.Clean(ignoredFile)] = true
+	}
+	hash := sha256.New()
+	err := filepath.WalkDir(folder, func(path string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		relative, err := filepath.Rel(folder, path)
+		if err != nil {
+			return err
+		}
+		if entry.IsDir() && relative != "." && ignored[relative] {
+			return filepath.SkipDir
+		}
+		if !entry.Type().IsRegular() || ignored[relative] {
+			return nil
+		}
+		fileDigest, err := digestFile(path)
+		if err != nil {
+			return err
+		}
+		_, err = fmt.Fprintf(hash, "%s %s\n", fileDigest, filepath.ToSlash(relative))
+		return err
+	})
+	if err != nil {
+		return "", err
+	}
+	return "sha256:" + hex.EncodeToString(hash.Sum(nil)), nil
+}
@@ -0,0 +1,116 @@
+package result
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/B-S-F/onyx/pkg/configuration"
+	"github.com/B-S-F/onyx/pkg/logger"
+	"github.com/B-S-F/onyx/pkg/v2/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewProvenance(t *testing.T) {
+	inputFolder := t.TempDir()
+	writeInput := func(name string, content string) {
+		path := filepath.Join(inputFolder, name)
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), 0755))
+		require.NoError(t, os.WriteFile(path, []byte(content), 0644))
+	}
+	writeInput("qg-config.yaml", "metadata:\n  version: v2\n")
+	writeInput(".vars", `{"VAR": "value"}`)
+	writeInput(".secrets", `{"SECRET": "value"}`)
+	writeInput("configs/fetcher.yaml", "url: https://example.com\n")
+	inputs := Inputs{
+		ConfigFile:   filepath.Join(inputFolder, "qg-config.yaml"),
+		VarsFile:     filepath.Join(inputFolder, ".vars"),
+		InputFolder:  inputFolder,
+		IgnoredFiles: []string{".vars", ".secrets", "qg-result.yaml", "output"},
+	}
+
+	provenance, err := NewProvenance(inputs)
+	require.NoError(t, err)
+
+	assert.Equal(t, "sha256:"+sha256Hex("metadata:\n  version: v2\n"), provenance.ConfigDigest)
+	assert.Equal(t, "sha256:"+sha256Hex(`{"VAR": "value"}`), provenance.VarsDigest)
+	assert.True(t, strings.HasPrefix(provenance.InputDigest, "sha256:"))
+	assert.Empty(t, provenance.Checks)
+
+	t.Run("input digest ignores the secrets and the outputs", func(t *testing.T) {
+		writeInput(".secrets", `{"SECRET": "changed"}`)
+		writeInput("qg-result.yaml", "header: {}\n")
+		writeInput("output/evidence.zip", "evidence")
+		unchanged, err := NewProvenance(inputs)
+		require.NoError(t, err)
+		assert.Equal(t, provenance.InputDigest, unchanged.InputDigest)
+
+		writeInput("configs/fetcher.yaml", "url: https://changed.example.com\n")
+		changed, err := NewProvenance(inputs)
+		require.NoError(t, err)
+		assert.NotEqual(t, provenance.InputDigest, changed.InputDigest)
+	})
+
+	t.Run("missing vars file", func(t *testing.T) {
+		withoutVars := inputs
+		withoutVars.VarsFile = filepath.Join(inputFolder, "missing")
+		provenance, err := NewProvenance(withoutVars)
+		require.NoError(t, err)
+		assert.Empty(t, provenance.VarsDigest)
+	})
+
+	t.Run("missing config file", func(t *testing.T) {
+		withoutConfig := inputs
+		withoutConfig.ConfigFile = filepath.Join(inputFolder, "missing")
+		_, err := NewProvenance(withoutConfig)
+		assert.ErrorContains(t, err, "failed to calculate digest of config file")
+	})
+}
+
+func TestCreator_AppendProvenance(t *testing.T) {
+	newItem := func(chapter string) model.Item {
+		return model.Item{
+			Chapter:     configuration.Chapter{Id: chapter},
+			Requirement: configuration.Requirement{Id: "1"},
+			Check:       configuration.Check{Id: "1"},
+		}
+	}
+	ep := model.ExecutionPlan{AutopilotChecks: []model.AutopilotCheck{
+		{Item: newItem("2"), Apps: []model.InstalledApp{
+			{Reference: "fetcher@1.0.0", Repository: "mirror", Checksum: "abc", Source: "https://mirror.example.com/fetcher/1.0.0",
+				Signature: &model.AppSignature{Type: "minisign", Status: "verified", KeyID: "1122334455667788"}},
+		}},
+		{Item: newItem("3")},
+		{Item: newItem("10"), Apps: []model.InstalledApp{
+			{Reference: "git-app@2.0.0", Repository: "git", Checksum: "0123abcd", Source: "https://github.com/my-org/git-app.git#v2.0.0"},
+		}},
+	}}
+	provenance := Provenance{OnyxVersion: "1.0.0", ConfigDigest: "sha256:config", InputDigest: "sha256:input"}
+	c := &Creator{logger: logger.NewAutopilot()}
+
+	var res Result
+	c.AppendProvenance(&res, ep, provenance)
+
+	require.NotNil(t, res.Provenance)
+	assert.Equal(t, "1.0.0", res.Provenance.OnyxVersion)
+	assert.Equal(t, "sha256:config", res.Provenance.ConfigDigest)
+	assert.Equal(t, "sha256:input", res.Provenance.InputDigest)
+	assert.Equal(t, []CheckProvenance{
+		{Chapter: "2", Requirement: "1", Check: "1", Apps: []AppProvenance{
+			{Reference: "fetcher@1.0.0", Repository: "mirror", Checksum: "abc", Source: "https://mirror.example.com/fetcher/1.0.0",
+				Signature: &Signature{Type: "minisign", Status: "verified", KeyID: "1122334455667788"}},
+		}},
+		{Chapter: "10", Requirement: "1", Check: "1", Apps: []AppProvenance{
+			{Reference: "git-app@2.0.0", Repository: "git", Checksum: "0123abcd", Source: "https://github.com/my-org/git-app.git#v2.0.0"},
+		}},
+	}, res.Provenance.Checks)
+}
+
+func sha256Hex(content string) string {
+	digest := sha256.Sum256([]byte(content))
+	return hex.EncodeToString(digest[:])
+}
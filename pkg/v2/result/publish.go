@@ -0,0 +1,18 @@
+package result
+
+import "github.com/B-S-F/onyx/pkg/publish"
+
+// AppendPublish records the locations of the uploads, the uploads follow after the result file is written
+func (c *Creator) AppendPublish(res *Result, uploads []publish.Upload) {
+	if len(uploads) == 0 {
+		return
+	}
+	res.Publish = make([]Publication, 0, len(uploads))
+	for _, upload := range uploads {
+		res.Publish = append(res.Publish, Publication{
+			Target:   upload.Target.Name(),
+			File:     upload.File,
+			Location: upload.Location,
+		})
+	}
+}
@@ -9,13 +9,19 @@ type Result struct {
 	// Header of the result
 	Header Header `yaml:"header" json:"header" jsonschema:"required"`
 	// Overall status of the result (is composed of the status of the chapters)
-	OverallStatus string `yaml:"overallStatus" json:"overallStatus" jsonschema:"required, enum=GREEN,enum=YELLOW,enum=RED,enum=NA,enum=UNANSWERED,enum=ERROR"`
+	OverallStatus string `yaml:"overallStatus" json:"overallStatus" jsonschema:"required,enum=GREEN,enum=YELLOW,enum=RED,enum=NA,enum=UNANSWERED,enum=SKIPPED,enum=ERROR"`
 	// Statistics of the result
 	Statistics Statistics `yaml:"statistics" json:"statistics" jsonschema:"required"`
 	// Chapters containing requirements and checks
 	Chapters map[string]*Chapter `yaml:"chapters" json:"chapters" jsonschema:"required"`
 	// Finalize step
-	Finalize *Finalize `yaml:"finalize,omitempty" json:"finalize" jsonschema:"optional"`
+	Finalize *Finalize `yaml:"finalize,omitempty" json:"finalize,omitempty" jsonschema:"optional"`
+	// Provenance of the inputs and apps of the run
+	Provenance *Provenance `yaml:"provenance,omitempty" json:"provenance,omitempty" jsonschema:"optional"`
+	// Secrets found in the evidence files, only present if there are findings
+	EvidenceScan *EvidenceScan `yaml:"evidenceScan,omitempty" json:"evidenceScan,omitempty" jsonschema:"optional"`
+	// Locations the result file and the evidence are uploaded to after the result is written
+	Publish []Publication `yaml:"publish,omitempty" json:"publish,omitempty" jsonschema:"optional"`
 }
 
 // Contains the metadata of the result
@@ -63,13 +69,13 @@ type Statistics struct {
 type Chapter struct {
 	// Title of the chapter
 	// Example "My Chapter"
-	Title string `yaml:"title,omitempty" json:"title" jsonschema:"required"`
+	Title string `yaml:"title,omitempty" json:"title,omitempty" jsonschema:"optional"`
 	// Text of the chapter
 	// Example "This is my chapter"
-	Text string `yaml:"text,omitempty" json:"text" jsonschema:"optional"`
+	Text string `yaml:"text,omitempty" json:"text,omitempty" jsonschema:"optional"`
 	// Status of the chapter (is composed of the status of the requirements)
 	// Example "GREEN"
-	Status string `yaml:"status" json:"status" jsonschema:"required, enum=GREEN,enum=YELLOW,enum=RED,enum=NA,enum=UNANSWERED,enum=ERROR"`
+	Status string `yaml:"status" json:"status" jsonschema:"required,enum=GREEN,enum=YELLOW,enum=RED,enum=NA,enum=UNANSWERED,enum=SKIPPED,enum=ERROR"`
 	// Requirements to answer the chapter
 	Requirements map[string]*Requirement `yaml:"requirements" json:"requirements" jsonschema:"required"`
 }
@@ -78,27 +84,27 @@ type Chapter struct {
 type Requirement struct {
 	// Title of the requirement
 	// Example "My Requirement"
-	Title string `yaml:"title,omitempty" json:"title" jsonschema:"required"`
+	Title string `yaml:"title,omitempty" json:"title,omitempty" jsonschema:"optional"`
 	// Text of the requirement
 	// Example "This is my requirement"
-	Text string `yaml:"text,omitempty" json:"text" jsonschema:"optional"`
+	Text string `yaml:"text,omitempty" json:"text,omitempty" jsonschema:"optional"`
 	// Status of the requirement (is composed of the status of the checks)
 	// Example "GREEN"
-	Status string `yaml:"status" json:"status" jsonschema:"required, enum=GREEN,enum=YELLOW,enum=RED,enum=NA,enum=UNANSWERED,enum=ERROR"`
+	Status string `yaml:"status" json:"status" jsonschema:"required,enum=GREEN,enum=YELLOW,enum=RED,enum=NA,enum=UNANSWERED,enum=SKIPPED,enum=ERROR"`
 	// Checks to answer the requirement
-	Checks map[string]*Check `yaml:"checks,omitempty" json:"checks" jsonschema:"required"`
+	Checks map[string]*Check `yaml:"checks,omitempty" json:"checks,omitempty" jsonschema:"optional"`
 }
 
 // Contains information about a check
 type Check struct {
 	// Title of the check
 	// Example "My Check"
-	Title string `yaml:"title,omitempty" json:"title" jsonschema:"required"`
+	Title string `yaml:"title,omitempty" json:"title,omitempty" jsonschema:"optional"`
 	// Type of the check
 	// Example "autopilot"
 	Type string `yaml:"type" json:"type" jsonschema:"required,enum=automation,enum=manual"`
 	// Evaluation of the check containing the result
-	Autopilots []Autopilot `yaml:"autopilots,omitempty" json:"autopilots" jsonschema:"optional"`
+	Autopilots []Autopilot `yaml:"autopilots,omitempty" json:"autopilots,omitempty" jsonschema:"optional"`
 	// Evaluation of the autopilot
 	Evaluation Evaluation `yaml:"evaluation" json:"evaluation" jsonschema:"required"`
 }
@@ -109,6 +115,111 @@ type Autopilot struct {
 	Name string `yaml:"name" json:"name" jsonschema:"required"`
 	// Steps of the autopilot
 	Steps []Step `yaml:"steps" json:"steps" jsonschema:"required"`
+	// Apps used by the autopilot
+	Apps []App `yaml:"apps,omitempty" json:"apps,omitempty" jsonschema:"optional"`
+}
+
+// Contains an app used by an autopilot
+type App struct {
+	// Reference of the app as written in the config
+	// Example "my-app@1.0.0"
+	Reference string `yaml:"reference" json:"reference" jsonschema:"required"`
+	// Repository which provided the app
+	// Example "my-repository"
+	Repository string `yaml:"repository" json:"repository" jsonschema:"required"`
+	// Signature of the app, only present if the repository verifies signatures
+	Signature *Signature `yaml:"signature,omitempty" json:"signature,omitempty" jsonschema:"optional"`
+}
+
+// Contains the signature verification of an app
+type Signature struct {
+	// Type of the signature
+	// Example "cosign"
+	Type string `yaml:"type" json:"type" jsonschema:"required,enum=cosign,enum=minisign,enum=gpg"`
+	// Status of the verification, failed verifications are only reported with the warn policy
+	// Example "verified"
+	Status string `yaml:"status" json:"status" jsonschema:"required,enum=verified,enum=failed"`
+	// Id of the key which verified the signature
+	// Example "sha256:5f0c..."
+	KeyID string `yaml:"keyId,omitempty" json:"keyId,omitempty" jsonschema:"optional"`
+	// Signer the key belongs to
+	// Example "Release Bot <release@example.com>"
+	Signer string `yaml:"signer,omitempty" json:"signer,omitempty" jsonschema:"optional"`
+}
+
+// Contains everything needed to reproduce the result
+type Provenance struct {
+	// Version of the onyx cli tool
+	// Example "0.1.0"
+	OnyxVersion string `yaml:"onyxVersion" json:"onyxVersion" jsonschema:"required"`
+	// Digest of the config file
+	// Example "sha256:5f0c..."
+	ConfigDigest string `yaml:"configDigest" json:"configDigest" jsonschema:"required"`
+	// Digest of the vars file, only present if the file exists
+	// Example "sha256:5f0c..."
+	VarsDigest string `yaml:"varsDigest,omitempty" json:"varsDigest,omitempty" jsonschema:"optional"`
+	// Digest of the input folder without the vars and secrets file
+	// Example "sha256:5f0c..."
+	InputDigest string `yaml:"inputDigest" json:"inputDigest" jsonschema:"required"`
+	// Apps used by the checks
+	Checks []CheckProvenance `yaml:"checks,omitempty" json:"checks,omitempty" jsonschema:"optional"`
+}
+
+// Contains the apps used by a check
+type CheckProvenance struct {
+	// Id of the chapter
+	Chapter string `yaml:"chapter" json:"chapter" jsonschema:"required"`
+	// Id of the requirement
+	Requirement string `yaml:"requirement" json:"requirement" jsonschema:"required"`
+	// Id of the check
+	Check string `yaml:"check" json:"check" jsonschema:"required"`
+	// Apps used by the check
+	Apps []AppProvenance `yaml:"apps" json:"apps" jsonschema:"required"`
+}
+
+// Contains the origin of an app
+type AppProvenance struct {
+	// Reference of the app as written in the config
+	// Example "my-app@1.0.0"
+	Reference string `yaml:"reference" json:"reference" jsonschema:"required"`
+	// Repository which provided the app
+	// Example "my-repository"
+	Repository string `yaml:"repository" json:"repository" jsonschema:"required"`
+	// Location the app was downloaded from, without credentials
+	// Example "https://my-file-server.com/my-app/1.0.0"
+	Source string `yaml:"source,omitempty" json:"source,omitempty" jsonschema:"optional"`
+	// Checksum of the app, the manifest digest for oci and the commit for git repositories
+	// Example "5f0c..."
+	Checksum string `yaml:"checksum" json:"checksum" jsonschema:"required"`
+	// Signature of the app, only present if the repository verifies signatures
+	Signature *Signature `yaml:"signature,omitempty" json:"signature,omitempty" jsonschema:"optional"`
+}
+
+// Contains the secrets found in the evidence files
+type EvidenceScan struct {
+	// Policy applied to the findings
+	// Example "redact"
+	Policy string `yaml:"policy" json:"policy" jsonschema:"required,enum=redact,enum=fail"`
+	// Findings in the evidence files
+	Findings []EvidenceFinding `yaml:"findings" json:"findings" jsonschema:"required"`
+}
+
+// Contains the location of a secret in an evidence file, never its value
+type EvidenceFinding struct {
+	// Path of the file in the evidence
+	// Example "my-chapter_my-requirement_my-check/my-step/files/response.json"
+	File string `yaml:"file" json:"file" jsonschema:"required"`
+	// Offset of the secret in bytes
+	// Example 1024
+	Offset int `yaml:"offset" json:"offset" jsonschema:"required"`
+	// Rule which found the secret, "secret" for known secret values
+	// Example "jwt"
+	Rule string `yaml:"rule" json:"rule" jsonschema:"required"`
+	// Name of the secret found by the "secret" rule
+	// Example "API_TOKEN"
+	Secret string `yaml:"secret,omitempty" json:"secret,omitempty" jsonschema:"optional"`
+	// Flag whether the secret was replaced in the file
+	Redacted bool `yaml:"redacted" json:"redacted" jsonschema:"required"`
 }
 
 // Contains the steps of an autopilot
@@ -127,9 +238,9 @@ type Step struct {
 	// - '{"source": "stderr", "text": "some error log"}'
 	Logs []string `yaml:"logs" json:"logs" jsonschema:"required"`
 	// Warning messages of the Step execution, derived from the generated structured logs
-	Warnings []string `yaml:"warnings,omitempty" json:"warnings" jsonschema:"optional"`
+	Warnings []string `yaml:"warnings,omitempty" json:"warnings,omitempty" jsonschema:"optional"`
 	// General info messages of the Step execution, derived from the generated structured logs
-	Messages []string `yaml:"messages,omitempty" json:"messages" jsonschema:"optional"`
+	Messages []string `yaml:"messages,omitempty" json:"messages,omitempty" jsonschema:"optional"`
 	// Configuration files of the step
 	ConfigFiles []string `yaml:"configFiles" json:"configFiles" jsonschema:"optional"`
 	// Output directory of the step
@@ -146,27 +257,27 @@ type Step struct {
 type Evaluation struct {
 	// Status of the autopilot
 	// Example "GREEN"
-	Status string `yaml:"status" json:"status" jsonschema:"required, enum=GREEN,enum=YELLOW,enum=RED,enum=ERROR"`
+	Status string `yaml:"status" json:"status" jsonschema:"required,enum=GREEN,enum=YELLOW,enum=RED,enum=NA,enum=UNANSWERED,enum=SKIPPED,enum=ERROR"`
 	// Reason associated with the status
 	// Example "This is my reason"
 	Reason string `yaml:"reason" json:"reason" jsonschema:"required"`
 	// Results of the autopilot
-	Results []EvaluationResult `yaml:"results,omitempty" json:"results" jsonschema:"optional"`
+	Results []EvaluationResult `yaml:"results,omitempty" json:"results,omitempty" jsonschema:"optional"`
 	// Structured logs of the evaluation, example:
 	// - '{"source": "stdout", "json": {"result":{"criterion":"Fixed RTC ticket with ID 1588653 must be risk assessed","fulfilled":false,"justification":"Please type the appropriate risk assessment for RTC Ticket with ID 1588653.","metadata":{"Id":1588653,"test-json":{"key":"value"}}}}}'
 	// - '{"source": "stdout", "text": "log message"}'
 	// - '{"source": "stdout", "json": {"warning": "Your config file will be deprecated next month"}}'
 	// - '{"source": "stdout", "json": {"message": "I am a message"}}'
 	// - '{"source": "stderr", "text": "some error log"}'
-	Logs []string `yaml:"logs,omitempty" json:"logs" jsonschema:"required"`
+	Logs []string `yaml:"logs,omitempty" json:"logs,omitempty" jsonschema:"optional"`
 	// Warning messages of the evaluation execution, derived from the generated structured logs
-	Warnings []string `yaml:"warnings,omitempty" json:"warnings" jsonschema:"optional"`
+	Warnings []string `yaml:"warnings,omitempty" json:"warnings,omitempty" jsonschema:"optional"`
 	// General info messages of the evaluation execution, derived from the generated structured logs
-	Messages []string `yaml:"messages,omitempty" json:"messages" jsonschema:"optional"`
+	Messages []string `yaml:"messages,omitempty" json:"messages,omitempty" jsonschema:"optional"`
 	// Configuration files of the evaluation
-	ConfigFiles []string `yaml:"configFiles,omitempty" json:"configFiles" jsonschema:"optional"`
+	ConfigFiles []string `yaml:"configFiles,omitempty" json:"configFiles,omitempty" jsonschema:"optional"`
 	// Exit code of the evaluation
-	ExitCode int `yaml:"exitCode,omitempty" json:"exitCode" jsonschema:"required"`
+	ExitCode int `yaml:"exitCode,omitempty" json:"exitCode,omitempty" jsonschema:"optional"`
 }
 
 // Contains one of potentially many results reported by an autopilot
@@ -184,7 +295,7 @@ type EvaluationResult struct {
 	// Example
 	// 	- "foo": "bar"
 	// 	- "baz": "qux"
-	Metadata common.StringMap `yaml:"metadata,omitempty" json:"metadata" jsonschema:"optional"`
+	Metadata common.StringMap `yaml:"metadata,omitempty" json:"metadata,omitempty" jsonschema:"optional"`
 }
 
 // Contains information about the finalization
@@ -196,11 +307,11 @@ type Finalize struct {
 	// - '{"source": "stdout", "json": {"warning": "Your config file will be deprecated next month"}}'
 	// - '{"source": "stdout", "json": {"message": "I am a message"}}'
 	// - '{"source": "stderr", "text": "some error log"}'
-	Logs []string `yaml:"logs,omitempty" json:"logs" jsonschema:"optional"`
+	Logs []string `yaml:"logs,omitempty" json:"logs,omitempty" jsonschema:"optional"`
 	// Warning messages of the Finalize execution, derived from the generated structured logs
-	Warnings []string `yaml:"warnings,omitempty" json:"warnings" jsonschema:"optional"`
+	Warnings []string `yaml:"warnings,omitempty" json:"warnings,omitempty" jsonschema:"optional"`
 	// General info messages of the Finalize execution, derived from the generated structured logs
-	Messages []string `yaml:"messages,omitempty" json:"messages" jsonschema:"optional"`
+	Messages []string `yaml:"messages,omitempty" json:"messages,omitempty" jsonschema:"optional"`
 	// Configuration files of the finalizer
 	ConfigFiles []string `yaml:"configFiles" json:"configFiles" jsonschema:"optional"`
 	// Exit code of the autopilot
@@ -210,3 +321,16 @@ type Finalize struct {
 func (r *Result) version() string {
 	return "v2"
 }
+
+// Contains the location a file is uploaded to
+type Publication struct {
+	// Name of the publish target
+	// Example "sharepoint-backup"
+	Target string `yaml:"target" json:"target" jsonschema:"required"`
+	// Name of the uploaded file
+	// Example "evidence.zip"
+	File string `yaml:"file" json:"file" jsonschema:"required"`
+	// Location of the file at the target
+	// Example "https://myaccount.blob.core.windows.net/qg-results/my-project/evidence.zip"
+	Location string `yaml:"location" json:"location" jsonschema:"required"`
+}
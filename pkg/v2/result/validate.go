@@ -0,0 +1,10 @@
+package result
+
+import "github.com/B-S-F/onyx/pkg/result/common"
+
+var validator = common.NewValidator(Result{})
+
+// Validate checks the YAML or JSON content of a v2 result file against the v2 result schema
+func Validate(content []byte) error {
+	return validator.Validate(content)
+}
@@ -0,0 +1,76 @@
+package result
+
+import (
+	"sort"
+	"strconv"
+)
+
+// CheckRef identifies a check of the result
+type CheckRef struct {
+	Chapter     string
+	Requirement string
+	Check       string
+}
+
+// String returns the reference as <chapter>_<requirement>_<check> like the evidence folders
+func (r CheckRef) String() string {
+	return r.Chapter + "_" + r.Requirement + "_" + r.Check
+}
+
+// Walk calls fn for every check of the result, ordered by chapter, requirement and check id.
+// Numeric ids are ordered by their value, so chapter 2 comes before chapter 10. Walk stops at the first error of fn.
+func (r *Result) Walk(fn func(ref CheckRef, chapter *Chapter, requirement *Requirement, check *Check) error) error {
+	for _, chapterId := range sortedIds(r.Chapters) {
+		chapter := r.Chapters[chapterId]
+		if chapter == nil {
+			continue
+		}
+		for _, requirementId := range sortedIds(chapter.Requirements) {
+			requirement := chapter.Requirements[requirementId]
+			if requirement == nil {
+				continue
+			}
+			for _, checkId := range sortedIds(requirement.Checks) {
+				check := requirement.Checks[checkId]
+				if check == nil {
+					continue
+				}
+				if err := fn(CheckRef{Chapter: chapterId, Requirement: requirementId, Check: checkId}, chapter, requirement, check); err != nil {
+					return err
+				}
+			}
+		}
+	}
+	return nil
+}
+
+func sortedIds[T any](m map[string]T) []string {
+	ids := make([]string, 0, len(m))
+	for id := range m {
+		ids = append(ids, id)
+	}
+	SortIDs(ids)
+	return ids
+}
+
+// SortIDs sorts chapter, requirement or check ids like Walk, numeric ids by their value before other ids
+func SortIDs(ids []string) {
+	sort.Slice(ids, func(i, j int) bool {
+		return lessID(ids[i], ids[j])
+	})
+}
+
+// lessID reports whether id a is sorted before id b by SortIDs
+func lessID(a, b string) bool {
+	numA, errA := strconv.Atoi(a)
+	numB, errB := strconv.Atoi(b)
+	switch {
+	case errA == nil && errB == nil && numA != numB:
+		return numA < numB
+	case errA == nil && errB != nil:
+		return true
+	case errA != nil && errB == nil:
+		return false
+	}
+	return a < b
+}
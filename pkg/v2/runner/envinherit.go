@@ -0,0 +1,43 @@
+package runner
+
+import (
+	"path"
+	"sort"
+	"strings"
+
+	"github.com/B-S-F/onyx/pkg/v2/model"
+)
+
+// inheritEnv filters the host environment by the env-inherit setting and returns the inherited variables and their names.
+// A nil setting selects model.DefaultEnvInherit.
+func inheritEnv(environ []string, envInherit []string) ([]string, []string) {
+	if envInherit == nil {
+		envInherit = model.DefaultEnvInherit
+	}
+	var inherited, names []string
+	for _, variable := range environ {
+		name, _, _ := strings.Cut(variable, "=")
+		if name == "" || !inherits(envInherit, name) {
+			continue
+		}
+		inherited = append(inherited, variable)
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return inherited, names
+}
+
+func inherits(envInherit []string, name string) bool {
+	for _, pattern := range envInherit {
+		switch pattern {
+		case model.EnvInheritAll:
+			return true
+		case model.EnvInheritNone:
+			return false
+		}
+		if matched, _ := path.Match(pattern, name); matched {
+			return true
+		}
+	}
+	return false
+}
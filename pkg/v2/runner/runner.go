@@ -19,6 +19,8 @@ type Input struct {
 	Env     map[string]string
 	Secrets map[string]string
 	WorkDir string
+	// EnvInherit selects the inherited host environment variables, nil selects model.DefaultEnvInherit
+	EnvInherit []string
 }
 
 type Output struct {
@@ -57,7 +57,9 @@ func (s *Subprocess) initCommand(input *Input, timeout time.Duration) (*exec.Cmd
 	if input.WorkDir != "" {
 		cmd.Dir = input.WorkDir
 	}
-	cmd.Env = append(cmd.Env, os.Environ()...)
+	inherited, names := inheritEnv(os.Environ(), input.EnvInherit)
+	s.logger.Infof("inheriting host environment variables: %s", strings.Join(names, ", "))
+	cmd.Env = append(cmd.Env, inherited...)
 	for k, v := range input.Env {
 		cmd.Env = append(cmd.Env, []string{fmt.Sprintf("%s=%s", k, v)}...)
 	}
@@ -87,8 +89,9 @@ func (s *Subprocess) parseOutput(input *Input, exitCode int, stdout, stderr stri
 	out := &Output{}
 	out.WorkDir = input.WorkDir
 	out.ExitCode = exitCode
-	outStr := helper.HideSecretsInString(stdout, input.Secrets)
-	errStr := helper.HideSecretsInString(stderr, input.Secrets)
+	masker := helper.NewMasker(input.Secrets)
+	outStr := masker.Mask(stdout)
+	errStr := masker.Mask(stderr)
 	err := out.parseLogStrings(outStr, errStr)
 	if err != nil {
 		return nil, err
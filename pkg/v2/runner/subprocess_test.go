@@ -188,8 +188,22 @@ func TestInitCommand(t *testing.T) {
 		assert.Equal(t, workDir, result.Dir)
 	})
 
-	t.Run("should return a command with system env set", func(t *testing.T) {
+	t.Run("should return a command with system env set if all is inherited", func(t *testing.T) {
 		// arrange
+		input := &Input{
+			Cmd:        cmd,
+			Args:       args,
+			EnvInherit: []string{"all"},
+		}
+		// act
+		result, _, _ := s.initCommand(input, timeout)
+		// assert
+		assert.Equal(t, os.Environ(), result.Env)
+	})
+	t.Run("should return a command with the default system env set", func(t *testing.T) {
+		// arrange
+		t.Setenv("HOME", "/home/onyx")
+		t.Setenv("AWS_SECRET_ACCESS_KEY", "secret")
 		input := &Input{
 			Cmd:  cmd,
 			Args: args,
@@ -197,7 +211,38 @@ func TestInitCommand(t *testing.T) {
 		// act
 		result, _, _ := s.initCommand(input, timeout)
 		// assert
-		assert.Equal(t, os.Environ(), result.Env)
+		assert.Contains(t, result.Env, "HOME=/home/onyx")
+		assert.NotContains(t, result.Env, "AWS_SECRET_ACCESS_KEY=secret")
+	})
+	t.Run("should return a command with the allowed system env set", func(t *testing.T) {
+		// arrange
+		t.Setenv("HOME", "/home/onyx")
+		t.Setenv("AWS_REGION", "eu-central-1")
+		t.Setenv("HTTPS_PROXY", "http://proxy:3128")
+		input := &Input{
+			Cmd:        cmd,
+			Args:       args,
+			EnvInherit: []string{"AWS_*", "HTTPS_PROXY"},
+		}
+		// act
+		result, _, _ := s.initCommand(input, timeout)
+		// assert
+		assert.Contains(t, result.Env, "AWS_REGION=eu-central-1")
+		assert.Contains(t, result.Env, "HTTPS_PROXY=http://proxy:3128")
+		assert.NotContains(t, result.Env, "HOME=/home/onyx")
+	})
+	t.Run("should return a command without system env if none is inherited", func(t *testing.T) {
+		// arrange
+		input := &Input{
+			Cmd:        cmd,
+			Args:       args,
+			Env:        map[string]string{"key": "value"},
+			EnvInherit: []string{"none"},
+		}
+		// act
+		result, _, _ := s.initCommand(input, timeout)
+		// assert
+		assert.Equal(t, []string{"key=value"}, result.Env)
 	})
 	t.Run("should return a command with env set", func(t *testing.T) {
 		// arrange
@@ -0,0 +1,96 @@
+package zip
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"github.com/klauspost/compress/zstd"
+)
+
+// fileHeader is the format independent header of an archived file
+type fileHeader struct {
+	name     string
+	size     int64
+	mode     os.FileMode
+	modified time.Time
+}
+
+// archiveWriter writes the files of an archive, the content of a file is written to the returned writer
+type archiveWriter interface {
+	create(header fileHeader) (io.Writer, error)
+	Close() error
+}
+
+func newArchiveWriter(output io.Writer, options Options) (archiveWriter, error) {
+	switch options.format() {
+	case FORMAT_ZIP:
+		return &zipArchive{writer: zip.NewWriter(output)}, nil
+	case FORMAT_TAR_GZ:
+		// the gzip header contains neither a name nor a modification time, so the output is reproducible
+		compressor := gzip.NewWriter(output)
+		return &tarArchive{writer: tar.NewWriter(compressor), compressor: compressor}, nil
+	case FORMAT_TAR_ZST:
+		zstdOptions := []zstd.EOption{}
+		if options.Reproducible {
+			zstdOptions = append(zstdOptions, zstd.WithEncoderConcurrency(1))
+		}
+		compressor, err := zstd.NewWriter(output, zstdOptions...)
+		if err != nil {
+			return nil, err
+		}
+		return &tarArchive{writer: tar.NewWriter(compressor), compressor: compressor}, nil
+	default:
+		return nil, fmt.Errorf("unsupported archive format '%s'", options.Format)
+	}
+}
+
+type zipArchive struct {
+	writer *zip.Writer
+}
+
+func (z *zipArchive) create(header fileHeader) (io.Writer, error) {
+	zipHeader := &zip.FileHeader{
+		Name:               header.name,
+		Method:             zip.Deflate,
+		Modified:           header.modified,
+		UncompressedSize64: uint64(header.size),
+	}
+	zipHeader.SetMode(header.mode)
+	return z.writer.CreateHeader(zipHeader)
+}
+
+func (z *zipArchive) Close() error {
+	return z.writer.Close()
+}
+
+type tarArchive struct {
+	writer     *tar.Writer
+	compressor io.WriteCloser
+}
+
+func (t *tarArchive) create(header fileHeader) (io.Writer, error) {
+	err := t.writer.WriteHeader(&tar.Header{
+		Typeflag: tar.TypeReg,
+		Name:     header.name,
+		Size:     header.size,
+		Mode:     int64(header.mode.Perm()),
+		ModTime:  header.modified.Truncate(time.Second),
+	})
+	if err != nil {
+		return nil, err
+	}
+	return t.writer, nil
+}
+
+func (t *tarArchive) Close() error {
+	err := t.writer.Close()
+	if cerr := t.compressor.Close(); err == nil {
+		err = cerr
+	}
+	return err
+}
@@ -0,0 +1,96 @@
+package zip
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// filter decides by include and exclude globs which files are archived
+type filter struct {
+	include []*regexp.Regexp
+	exclude []*regexp.Regexp
+}
+
+func newFilter(include, exclude []string) (filter, error) {
+	var f filter
+	var err error
+	if f.include, err = compileGlobs(include); err != nil {
+		return filter{}, err
+	}
+	if f.exclude, err = compileGlobs(exclude); err != nil {
+		return filter{}, err
+	}
+	return f, nil
+}
+
+// matches returns true if the slash separated path matches an include glob, if there are any, and no exclude glob
+func (f filter) matches(path string) bool {
+	if len(f.include) > 0 && !matchesAny(f.include, path) {
+		return false
+	}
+	return !matchesAny(f.exclude, path)
+}
+
+func matchesAny(globs []*regexp.Regexp, path string) bool {
+	for _, glob := range globs {
+		if glob.MatchString(path) {
+			return true
+		}
+	}
+	return false
+}
+
+func compileGlobs(globs []string) ([]*regexp.Regexp, error) {
+	compiled := make([]*regexp.Regexp, 0, len(globs))
+	for _, glob := range globs {
+		regex, err := compileGlob(glob)
+		if err != nil {
+			return nil, fmt.Errorf("invalid glob '%s': %w", glob, err)
+		}
+		compiled = append(compiled, regex)
+	}
+	return compiled, nil
+}
+
+// compileGlob converts a glob to a regular expression, * and ? do not match slashes and ** matches any number of directories
+// Globs without a slash match files in any directory, e.g. *.pdf matches 1_1/report.pdf
+func compileGlob(glob string) (*regexp.Regexp, error) {
+	glob = strings.TrimPrefix(glob, "/")
+	var regex strings.Builder
+	regex.WriteString("^")
+	if !strings.Contains(glob, "/") {
+		regex.WriteString("(?:.*/)?")
+	}
+	for i := 0; i < len(glob); i++ {
+		switch c := glob[i]; c {
+		case '*':
+			if strings.HasPrefix(glob[i:], "**/") {
+				regex.WriteString("(?:.*/)?")
+				i += 2
+			} else if strings.HasPrefix(glob[i:], "**") {
+				regex.WriteString(".*")
+				i++
+			} else {
+				regex.WriteString("[^/]*")
+			}
+		case '?':
+			regex.WriteString("[^/]")
+		case '[':
+			end := strings.IndexByte(glob[i+1:], ']')
+			if end < 0 {
+				return nil, fmt.Errorf("missing ]")
+			}
+			class := glob[i+1 : i+1+end]
+			if strings.HasPrefix(class, "!") {
+				class = "^" + class[1:]
+			}
+			regex.WriteString("[" + class + "]")
+			i += end + 1
+		default:
+			regex.WriteString(regexp.QuoteMeta(string(c)))
+		}
+	}
+	regex.WriteString("$")
+	return regexp.Compile(regex.String())
+}
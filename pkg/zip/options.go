@@ -0,0 +1,108 @@
+package zip
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	FORMAT_ZIP     = "zip"
+	FORMAT_TAR_GZ  = "tar.gz"
+	FORMAT_TAR_ZST = "tar.zst"
+
+	// SIZE_POLICY_FAIL fails the archiving if a file exceeds the maximum file size
+	SIZE_POLICY_FAIL = "fail"
+	// SIZE_POLICY_TRUNCATE archives the first bytes of files which exceed the maximum file size
+	SIZE_POLICY_TRUNCATE = "truncate"
+)
+
+// Formats are the supported archive formats
+var Formats = []string{FORMAT_ZIP, FORMAT_TAR_GZ, FORMAT_TAR_ZST}
+
+// REPRODUCIBLE_TIME is the modification time of the files of reproducible archives, if SOURCE_DATE_EPOCH is not set
+var REPRODUCIBLE_TIME = time.Date(1980, 1, 1, 0, 0, 0, 0, time.UTC)
+
+// Options of the archive
+type Options struct {
+	// Format of the archive, defaults to zip
+	Format string
+	// Reproducible archives have the same content for the same files, the modification times and permissions are normalized
+	Reproducible bool
+	// Include are globs of the archived files, all files are archived if empty
+	Include []string
+	// Exclude are globs of files which are not archived
+	Exclude []string
+	// MaxFileSize is the maximum size of a file in bytes, unlimited if 0
+	MaxFileSize int64
+	// SizePolicy decides what happens with files exceeding the maximum file size, defaults to fail
+	SizePolicy string
+}
+
+// Validate checks the format, size policy and globs
+func (o Options) Validate() error {
+	if !isFormat(o.format()) {
+		return fmt.Errorf("unsupported archive format '%s', expected one of %s", o.Format, strings.Join(Formats, ", "))
+	}
+	if o.SizePolicy != "" && o.SizePolicy != SIZE_POLICY_FAIL && o.SizePolicy != SIZE_POLICY_TRUNCATE {
+		return fmt.Errorf("unsupported size policy '%s', expected one of %s, %s", o.SizePolicy, SIZE_POLICY_FAIL, SIZE_POLICY_TRUNCATE)
+	}
+	if o.MaxFileSize < 0 {
+		return fmt.Errorf("maximum file size must not be negative")
+	}
+	_, err := newFilter(o.Include, o.Exclude)
+	return err
+}
+
+func (o Options) format() string {
+	if o.Format == "" {
+		return FORMAT_ZIP
+	}
+	return o.Format
+}
+
+// Extension returns the file extension of the archive format including the dot
+func Extension(format string) string {
+	if format == "" {
+		format = FORMAT_ZIP
+	}
+	return "." + format
+}
+
+// FormatOf returns the archive format of a file by its extension, files with unknown extensions are zip files
+func FormatOf(path string) string {
+	switch {
+	case strings.HasSuffix(path, Extension(FORMAT_TAR_GZ)), strings.HasSuffix(path, ".tgz"):
+		return FORMAT_TAR_GZ
+	case strings.HasSuffix(path, Extension(FORMAT_TAR_ZST)):
+		return FORMAT_TAR_ZST
+	default:
+		return FORMAT_ZIP
+	}
+}
+
+func isFormat(format string) bool {
+	for _, f := range Formats {
+		if f == format {
+			return true
+		}
+	}
+	return false
+}
+
+func reproducibleTime() time.Time {
+	if epoch, err := strconv.ParseInt(os.Getenv("SOURCE_DATE_EPOCH"), 10, 64); err == nil {
+		return time.Unix(epoch, 0).UTC()
+	}
+	return REPRODUCIBLE_TIME
+}
+
+// normalizedMode keeps only whether a file is executable
+func normalizedMode(mode os.FileMode) os.FileMode {
+	if mode&0111 != 0 {
+		return 0755
+	}
+	return 0644
+}
@@ -0,0 +1,79 @@
+package zip
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"compress/gzip"
+	"io"
+	"os"
+
+	"github.com/klauspost/compress/zstd"
+)
+
+// ReadEntries calls the function for every file of the archive in the order of the archive, the format is detected by the extension
+func ReadEntries(path string, fn func(name string, content io.Reader) error) error {
+	format := FormatOf(path)
+	if format == FORMAT_ZIP {
+		return readZipEntries(path, fn)
+	}
+	file, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+	var decompressed io.Reader
+	if format == FORMAT_TAR_GZ {
+		gzipReader, err := gzip.NewReader(file)
+		if err != nil {
+			return err
+		}
+		defer gzipReader.Close()
+		decompressed = gzipReader
+	} else {
+		zstdReader, err := zstd.NewReader(file)
+		if err != nil {
+			return err
+		}
+		defer zstdReader.Close()
+		decompressed = zstdReader
+	}
+	reader := tar.NewReader(decompressed)
+	for {
+		header, err := reader.Next()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		if header.Typeflag != tar.TypeReg {
+			continue
+		}
+		if err := fn(header.Name, reader); err != nil {
+			return err
+		}
+	}
+}
+
+func readZipEntries(path string, fn func(name string, content io.Reader) error) error {
+	reader, err := zip.OpenReader(path)
+	if err != nil {
+		return err
+	}
+	defer reader.Close()
+	for _, file := range reader.File {
+		if file.FileInfo().IsDir() {
+			continue
+		}
+		content, err := file.Open()
+		if err != nil {
+			return err
+		}
+		err = fn(file.Name, content)
+		content.Close()
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
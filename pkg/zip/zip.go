@@ -1,9 +1,14 @@
 package zip
 
 import (
-	"archive/zip"
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
 	"os"
 	"path/filepath"
+	"time"
 
 	"github.com/B-S-F/onyx/pkg/logger"
 	"github.com/spf13/afero"
@@ -11,14 +16,21 @@ import (
 )
 
 type Zip struct {
-	fs     afero.Fs
-	logger logger.Logger
+	fs      afero.Fs
+	logger  logger.Logger
+	options Options
 }
 
 func New(fs afero.Fs) Zip {
+	return NewWithOptions(fs, Options{})
+}
+
+// NewWithOptions creates an archiver for the format and filters of the options
+func NewWithOptions(fs afero.Fs, options Options) Zip {
 	return Zip{
-		fs:     fs,
-		logger: logger.Get(),
+		fs:      fs,
+		logger:  logger.Get(),
+		options: options,
 	}
 }
 
@@ -28,35 +40,77 @@ func parentDir(path string) string {
 	return dir
 }
 
-func (z *Zip) Directory(path, output string) error {
-	z.logger.Debug("zipping", zap.String("path", path), zap.String("output", output))
-	zipFile, err := z.fs.Create(output)
+// Entry is a file which was written to the archive
+type Entry struct {
+	Name   string
+	Size   int64
+	SHA256 string
+	// Truncated is set if only the first bytes of the file were written because of the maximum file size
+	Truncated bool
+}
+
+// Appendix creates a file which is written after the files of the directory, e.g. a manifest of the written entries
+type Appendix func(entries []Entry) (name string, content []byte, err error)
+
+// Directory archives the files of the directory followed by the files of the appendices
+// The file contents are streamed, so the size of the files does not matter
+func (z *Zip) Directory(path, output string, appendices ...Appendix) error {
+	z.logger.Debug("archiving", zap.String("path", path), zap.String("output", output), zap.String("format", z.options.format()))
+	if err := z.options.Validate(); err != nil {
+		return err
+	}
+	filter, err := newFilter(z.options.Include, z.options.Exclude)
+	if err != nil {
+		return err
+	}
+	archiveFile, err := z.fs.Create(output)
+	if err != nil {
+		return err
+	}
+	defer archiveFile.Close()
+	writer, err := newArchiveWriter(archiveFile, z.options)
 	if err != nil {
 		return err
 	}
-	defer zipFile.Close()
-	zipWriter := zip.NewWriter(zipFile)
-	defer zipWriter.Close()
 	dir := parentDir(path)
 	z.logger.Debug("dir to remove from path", zap.String("dir", dir))
-	walk := newWalk(zipWriter, path)
+	walk := newWalk(writer, path, z.fs, z.options)
+	walk.filter = filter
 	err = afero.Walk(z.fs, path, walk.Function)
 	if err != nil {
+		writer.Close()
 		return err
 	}
-	return nil
+	for _, appendix := range appendices {
+		name, content, err := appendix(walk.entries)
+		if err != nil {
+			writer.Close()
+			return err
+		}
+		if err := walk.append(name, content); err != nil {
+			writer.Close()
+			return err
+		}
+	}
+	return writer.Close()
 }
 
 type walk struct {
 	basepath string
-	writer   *zip.Writer
+	writer   archiveWriter
+	fs       afero.Fs
+	options  Options
+	filter   filter
 	logger   logger.Logger
+	entries  []Entry
 }
 
-func newWalk(w *zip.Writer, basepath string) walk {
+func newWalk(w archiveWriter, basepath string, fs afero.Fs, options Options) walk {
 	return walk{
 		writer:   w,
 		basepath: basepath,
+		fs:       fs,
+		options:  options,
 		logger:   logger.Get(),
 	}
 }
@@ -75,22 +129,72 @@ func (w *walk) Function(path string, info os.FileInfo, err error) error {
 		w.logger.Debug("skipping directory", zap.String("path", path))
 		return nil
 	}
-	fileContent, err := os.ReadFile(path)
-	if err != nil {
-		return err
+	if info.Mode()&os.ModeSymlink != 0 {
+		// symlinks are archived as the file they point to
+		info, err = w.fs.Stat(path)
+		if err != nil {
+			return err
+		}
+		if info.IsDir() {
+			w.logger.Debug("skipping symlink to directory", zap.String("path", path))
+			return nil
+		}
+	}
+	name := filepath.ToSlash(relPath)
+	if !w.filter.matches(name) {
+		w.logger.Debug("skipping filtered file", zap.String("path", path))
+		return nil
 	}
-	zipFileHeader, err := zip.FileInfoHeader(info)
+
+	size := info.Size()
+	truncated := false
+	if max := w.options.MaxFileSize; max > 0 && size > max {
+		if w.options.SizePolicy != SIZE_POLICY_TRUNCATE {
+			return fmt.Errorf("file '%s' has %d bytes, which exceeds the maximum file size of %d bytes", name, size, max)
+		}
+		w.logger.Warnf("file '%s' has %d bytes and is truncated to the maximum file size of %d bytes", name, size, max)
+		size = max
+		truncated = true
+	}
+	file, err := w.fs.Open(path)
 	if err != nil {
 		return err
 	}
-	zipFileHeader.Name = relPath
-	zipFileWriter, err := w.writer.CreateHeader(zipFileHeader)
+	defer file.Close()
+	header := fileHeader{name: name, size: size, mode: info.Mode().Perm(), modified: info.ModTime()}
+	if w.options.Reproducible {
+		header.mode = normalizedMode(info.Mode())
+		header.modified = reproducibleTime()
+	}
+	return w.write(header, io.LimitReader(file, size), truncated)
+}
+
+func (w *walk) append(name string, content []byte) error {
+	for _, entry := range w.entries {
+		if entry.Name == name {
+			return fmt.Errorf("file '%s' already exists", name)
+		}
+	}
+	header := fileHeader{name: name, size: int64(len(content)), mode: 0644, modified: time.Now()}
+	if w.options.Reproducible {
+		header.modified = reproducibleTime()
+	}
+	return w.write(header, bytes.NewReader(content), false)
+}
+
+func (w *walk) write(header fileHeader, content io.Reader, truncated bool) error {
+	fileWriter, err := w.writer.create(header)
 	if err != nil {
 		return err
 	}
-	_, err = zipFileWriter.Write(fileContent)
+	digest := sha256.New()
+	size, err := io.Copy(io.MultiWriter(fileWriter, digest), content)
 	if err != nil {
 		return err
 	}
+	if size != header.size {
+		return fmt.Errorf("file '%s' changed while it was archived", header.name)
+	}
+	w.entries = append(w.entries, Entry{Name: header.name, Size: size, SHA256: hex.EncodeToString(digest.Sum(nil)), Truncated: truncated})
 	return nil
 }
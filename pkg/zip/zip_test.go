@@ -6,6 +6,9 @@ package zip
 import (
 	"archive/zip"
 	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
 	"os"
 	"path/filepath"
 	"testing"
@@ -199,7 +202,7 @@ func TestWalkFunction(t *testing.T) {
 			zipWriter := zip.NewWriter(zipFile)
 
 			// act
-			walkFunc := newWalk(zipWriter, zipDir)
+			walkFunc := newWalk(&zipArchive{writer: zipWriter}, zipDir, afero.NewOsFs(), Options{})
 			err = filepath.Walk(zipDir, walkFunc.Function)
 			zipWriter.Close()
 
@@ -223,3 +226,225 @@ func TestWalkFunction(t *testing.T) {
 		})
 	}
 }
+
+func TestDirectoryAppendix(t *testing.T) {
+	tmpDir := createTestFolder(t, map[string][]byte{
+		"file1.txt":        []byte("file1"),
+		"subdir/file2.txt": []byte("file2"),
+	})
+	zipper := &Zip{
+		fs:     afero.NewOsFs(),
+		logger: nopLogger,
+	}
+	zipFilePath := filepath.Join(t.TempDir(), "test.zip")
+	var got []Entry
+	appendix := func(entries []Entry) (string, []byte, error) {
+		got = append(got, entries...)
+		return "appendix.txt", []byte("appendix"), nil
+	}
+
+	err := zipper.Directory(tmpDir, zipFilePath, appendix)
+
+	assert.NoError(t, err)
+	assert.Equal(t, []Entry{
+		{Name: "file1.txt", Size: 5, SHA256: sha256Hex("file1")},
+		{Name: "subdir/file2.txt", Size: 5, SHA256: sha256Hex("file2")},
+	}, got)
+	zipReader, err := zip.OpenReader(zipFilePath)
+	assert.NoError(t, err)
+	defer zipReader.Close()
+	assert.Len(t, zipReader.File, 3)
+	assert.Equal(t, "appendix.txt", zipReader.File[2].Name)
+	assert.Equal(t, "appendix", readZipFile(t, zipReader.File[2]))
+
+	t.Run("should not zip an appendix twice", func(t *testing.T) {
+		err := zipper.Directory(tmpDir, zipFilePath, appendix, appendix)
+
+		assert.ErrorContains(t, err, "file 'appendix.txt' already exists")
+	})
+}
+
+func sha256Hex(content string) string {
+	digest := sha256.Sum256([]byte(content))
+	return hex.EncodeToString(digest[:])
+}
+
+func TestDirectoryFormats(t *testing.T) {
+	files := map[string][]byte{
+		"file1.txt":        []byte("file1"),
+		"subdir/file2.txt": bytes.Repeat([]byte("file2"), 1000),
+	}
+	for _, format := range Formats {
+		t.Run("should archive and read "+format, func(t *testing.T) {
+			tmpDir := createTestFolder(t, files)
+			zipper := &Zip{fs: afero.NewOsFs(), logger: nopLogger, options: Options{Format: format}}
+			output := filepath.Join(t.TempDir(), "evidence"+Extension(format))
+
+			err := zipper.Directory(tmpDir, output)
+
+			assert.NoError(t, err)
+			got := map[string][]byte{}
+			err = ReadEntries(output, func(name string, content io.Reader) error {
+				got[name], err = io.ReadAll(content)
+				return err
+			})
+			assert.NoError(t, err)
+			assert.Equal(t, files, got)
+		})
+
+		t.Run("should create reproducible "+format+" archives", func(t *testing.T) {
+			archive := func(modified time.Time, mode os.FileMode) []byte {
+				tmpDir := createTestFolder(t, files)
+				for name := range files {
+					path := filepath.Join(tmpDir, name)
+					assert.NoError(t, os.Chtimes(path, modified, modified))
+					assert.NoError(t, os.Chmod(path, mode))
+				}
+				zipper := &Zip{fs: afero.NewOsFs(), logger: nopLogger, options: Options{Format: format, Reproducible: true}}
+				output := filepath.Join(t.TempDir(), "evidence"+Extension(format))
+				appendix := func(entries []Entry) (string, []byte, error) {
+					return "appendix.txt", []byte("appendix"), nil
+				}
+				assert.NoError(t, zipper.Directory(tmpDir, output, appendix))
+				content, err := os.ReadFile(output)
+				assert.NoError(t, err)
+				return content
+			}
+
+			first := archive(time.Now().Add(-time.Hour), 0600)
+			second := archive(time.Now(), 0640)
+
+			assert.Equal(t, first, second)
+		})
+	}
+}
+
+func TestDirectoryFilter(t *testing.T) {
+	tmpDir := createTestFolder(t, map[string][]byte{
+		"qg-result.yaml":        []byte("result"),
+		"1_1/data.json":         []byte("{}"),
+		"1_1/report.pdf":        []byte("pdf"),
+		"1_1/tmp/cache.json":    []byte("{}"),
+		"1_1/tmp/download.part": []byte("part"),
+	})
+	testCases := map[string]struct {
+		include []string
+		exclude []string
+		want    []string
+	}{
+		"should archive all files without globs": {
+			want: []string{"1_1/data.json", "1_1/report.pdf", "1_1/tmp/cache.json", "1_1/tmp/download.part", "qg-result.yaml"},
+		},
+		"should archive included files in any directory": {
+			include: []string{"*.json", "qg-result.yaml"},
+			want:    []string{"1_1/data.json", "1_1/tmp/cache.json", "qg-result.yaml"},
+		},
+		"should not archive excluded files": {
+			exclude: []string{"**/tmp/**", "*.pdf"},
+			want:    []string{"1_1/data.json", "qg-result.yaml"},
+		},
+		"should apply excludes to included files": {
+			include: []string{"1_1/**"},
+			exclude: []string{"1_1/tmp/*.part"},
+			want:    []string{"1_1/data.json", "1_1/report.pdf", "1_1/tmp/cache.json"},
+		},
+	}
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			zipper := &Zip{fs: afero.NewOsFs(), logger: nopLogger, options: Options{Include: tc.include, Exclude: tc.exclude}}
+			output := filepath.Join(t.TempDir(), "evidence.zip")
+			var got []string
+			appendix := func(entries []Entry) (string, []byte, error) {
+				for _, entry := range entries {
+					got = append(got, entry.Name)
+				}
+				return "appendix.txt", nil, nil
+			}
+
+			err := zipper.Directory(tmpDir, output, appendix)
+
+			assert.NoError(t, err)
+			assert.Equal(t, tc.want, got)
+		})
+	}
+}
+
+func TestDirectoryMaxFileSize(t *testing.T) {
+	tmpDir := createTestFolder(t, map[string][]byte{
+		"large.txt": []byte("0123456789"),
+		"small.txt": []byte("01234"),
+	})
+
+	t.Run("should fail for files exceeding the maximum size", func(t *testing.T) {
+		zipper := &Zip{fs: afero.NewOsFs(), logger: nopLogger, options: Options{MaxFileSize: 5}}
+
+		err := zipper.Directory(tmpDir, filepath.Join(t.TempDir(), "evidence.zip"))
+
+		assert.ErrorContains(t, err, "file 'large.txt' has 10 bytes, which exceeds the maximum file size of 5 bytes")
+	})
+
+	t.Run("should truncate files exceeding the maximum size", func(t *testing.T) {
+		zipper := &Zip{fs: afero.NewOsFs(), logger: nopLogger, options: Options{MaxFileSize: 5, SizePolicy: SIZE_POLICY_TRUNCATE}}
+		output := filepath.Join(t.TempDir(), "evidence.tar.gz")
+		zipper.options.Format = FORMAT_TAR_GZ
+		var entries []Entry
+		appendix := func(written []Entry) (string, []byte, error) {
+			entries = written
+			return "appendix.txt", nil, nil
+		}
+
+		err := zipper.Directory(tmpDir, output, appendix)
+
+		assert.NoError(t, err)
+		assert.Equal(t, []Entry{
+			{Name: "large.txt", Size: 5, SHA256: sha256Hex("01234"), Truncated: true},
+			{Name: "small.txt", Size: 5, SHA256: sha256Hex("01234")},
+		}, entries)
+	})
+}
+
+func TestOptionsValidate(t *testing.T) {
+	testCases := map[string]struct {
+		options Options
+		wantErr string
+	}{
+		"should accept the defaults":    {options: Options{}},
+		"should accept tar.zst":         {options: Options{Format: FORMAT_TAR_ZST, SizePolicy: SIZE_POLICY_TRUNCATE}},
+		"should reject unknown formats": {options: Options{Format: "rar"}, wantErr: "unsupported archive format 'rar'"},
+		"should reject unknown size policies": {
+			options: Options{SizePolicy: "ignore"},
+			wantErr: "unsupported size policy 'ignore'",
+		},
+		"should reject invalid globs": {options: Options{Exclude: []string{"[abc"}}, wantErr: "invalid glob '[abc'"},
+	}
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			err := tc.options.Validate()
+			if tc.wantErr == "" {
+				assert.NoError(t, err)
+			} else {
+				assert.ErrorContains(t, err, tc.wantErr)
+			}
+		})
+	}
+}
+
+func TestDirectorySymlinks(t *testing.T) {
+	tmpDir := createTestFolder(t, map[string][]byte{"subdir/file.txt": []byte("file")})
+	assert.NoError(t, os.Symlink(filepath.Join(tmpDir, "subdir/file.txt"), filepath.Join(tmpDir, "link.txt")))
+	assert.NoError(t, os.Symlink(filepath.Join(tmpDir, "subdir"), filepath.Join(tmpDir, "linkdir")))
+	zipper := &Zip{fs: afero.NewOsFs(), logger: nopLogger, options: Options{Format: FORMAT_TAR_ZST}}
+	output := filepath.Join(t.TempDir(), "evidence.tar.zst")
+
+	err := zipper.Directory(tmpDir, output)
+
+	assert.NoError(t, err)
+	got := map[string]string{}
+	err = ReadEntries(output, func(name string, content io.Reader) error {
+		data, err := io.ReadAll(content)
+		got[name] = string(data)
+		return err
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]string{"link.txt": "file", "subdir/file.txt": "file"}, got)
+}